@@ -0,0 +1,101 @@
+// Package autodj keeps the music going once the playlist runs out by
+// picking tracks from the local library: at random, or preferring tracks
+// that share an artist, genre or decade with the one that just played.
+package autodj
+
+import (
+	"math/rand/v2"
+	"strings"
+
+	"cliamp/library"
+	"cliamp/playlist"
+)
+
+// sampleSize is how many random candidates have their tags read in similar
+// mode. Reading every file in a large library would take too long, and a
+// sample keeps some variety in what gets picked.
+const sampleSize = 24
+
+// Mode selects how the next track is chosen.
+type Mode int
+
+const (
+	Random  Mode = iota // any track from the library
+	Similar             // prefer the same artist, then genre, then decade
+)
+
+// ParseMode maps a config value ("random" or "similar") to a Mode,
+// defaulting to Random.
+func ParseMode(s string) Mode {
+	if strings.EqualFold(strings.TrimSpace(s), "similar") {
+		return Similar
+	}
+	return Random
+}
+
+func (m Mode) String() string {
+	if m == Similar {
+		return "similar"
+	}
+	return "random"
+}
+
+// DJ picks tracks from the library. It is safe for concurrent use.
+type DJ struct {
+	Mode Mode
+
+	lib *library.Library
+}
+
+// New returns a DJ that picks from lib.
+func New(lib *library.Library, mode Mode) *DJ {
+	return &DJ{Mode: mode, lib: lib}
+}
+
+// Pick returns a library track to play after last, skipping paths in
+// exclude (typically everything already in the playlist). It returns false
+// once the library has nothing left to offer. The first call walks the
+// library and may take a while, so call it off the UI goroutine.
+func (d *DJ) Pick(last playlist.Track, exclude map[string]bool) (playlist.Track, bool) {
+	var candidates []string
+	for _, f := range d.lib.Files() {
+		if !exclude[f.Path] {
+			candidates = append(candidates, f.Path)
+		}
+	}
+	if len(candidates) == 0 {
+		return playlist.Track{}, false
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if d.Mode == Random || last.Path == "" {
+		return playlist.TrackFromPath(candidates[0]), true
+	}
+
+	var best playlist.Track
+	bestScore := -1
+	for _, f := range candidates[:min(len(candidates), sampleSize)] {
+		t := playlist.TrackFromPath(f)
+		if s := similarity(last, t); s > bestScore {
+			best, bestScore = t, s
+		}
+	}
+	return best, true
+}
+
+// similarity scores how well t follows last: a shared artist counts most,
+// then genre, then release decade.
+func similarity(last, t playlist.Track) int {
+	score := 0
+	if last.Artist != "" && strings.EqualFold(last.Artist, t.Artist) {
+		score += 4
+	}
+	if last.Genre != "" && strings.EqualFold(last.Genre, t.Genre) {
+		score += 2
+	}
+	if last.Year > 0 && last.Year/10 == t.Year/10 {
+		score++
+	}
+	return score
+}
@@ -0,0 +1,49 @@
+package autodj
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cliamp/library"
+	"cliamp/playlist"
+)
+
+func TestPick(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"A - One.mp3", "A - Two.mp3", "B - Three.flac", "cover.jpg", ".hidden/C - Four.mp3"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("not audio"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := New(library.New([]string{dir}, nil), Similar)
+	if got := len(d.lib.Files()); got != 3 {
+		t.Fatalf("library has %d files, want 3", got)
+	}
+
+	last := playlist.Track{Path: filepath.Join(dir, "A - One.mp3"), Artist: "A"}
+	exclude := map[string]bool{last.Path: true}
+	for range 10 {
+		got, ok := d.Pick(last, exclude)
+		if !ok || got.Path != filepath.Join(dir, "A - Two.mp3") {
+			t.Fatalf("Pick = %q, %v; want the other track by the same artist", got.Path, ok)
+		}
+	}
+
+	exclude[filepath.Join(dir, "A - Two.mp3")] = true
+	exclude[filepath.Join(dir, "B - Three.flac")] = true
+	if _, ok := d.Pick(last, exclude); ok {
+		t.Fatal("Pick succeeded with the whole library excluded")
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if ParseMode(" Similar ") != Similar || ParseMode("random") != Random || ParseMode("") != Random {
+		t.Fatal("ParseMode mapped a value wrongly")
+	}
+}
@@ -0,0 +1,220 @@
+package cast
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	want := message{source: "sender-0", dest: "receiver-0", namespace: nsReceiver, payload: `{"type":"GET_STATUS"}`}
+	var buf bytes.Buffer
+	if err := writeMessage(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readMessage(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadMessage_RejectsOversizedFrame(t *testing.T) {
+	if _, err := readMessage(bytes.NewReader([]byte{0x7f, 0xff, 0xff, 0xff})); err == nil {
+		t.Error("oversized frame accepted")
+	}
+}
+
+func mustName(t *testing.T, s string) dnsmessage.Name {
+	t.Helper()
+	n, err := dnsmessage.NewName(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestBrowser_AssemblesDeviceAcrossPackets(t *testing.T) {
+	inst := "Chromecast-abc123._googlecast._tcp.local."
+	host := "abc123.local."
+	hdr := func(name string, typ dnsmessage.Type) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: mustName(t, name), Type: typ, Class: dnsmessage.ClassINET, TTL: 120}
+	}
+	pack := func(rrs ...dnsmessage.Resource) []byte {
+		msg := dnsmessage.Message{Header: dnsmessage.Header{Response: true}, Answers: rrs}
+		b, err := msg.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	b := newBrowser()
+	b.add(pack(
+		dnsmessage.Resource{Header: hdr(castService, dnsmessage.TypePTR), Body: &dnsmessage.PTRResource{PTR: mustName(t, inst)}},
+		dnsmessage.Resource{Header: hdr(inst, dnsmessage.TypeTXT), Body: &dnsmessage.TXTResource{TXT: []string{"id=abc123", "md=Google Home Mini", "fn=Kitchen"}}},
+	))
+	if got := b.devices(); len(got) != 0 {
+		t.Fatalf("device without address listed: %+v", got)
+	}
+	b.add(pack(
+		dnsmessage.Resource{Header: hdr(inst, dnsmessage.TypeSRV), Body: &dnsmessage.SRVResource{Target: mustName(t, host), Port: 8009}},
+		dnsmessage.Resource{Header: hdr(host, dnsmessage.TypeA), Body: &dnsmessage.AResource{A: [4]byte{192, 168, 1, 20}}},
+	))
+	b.add([]byte("garbage"))
+
+	want := []Device{{Name: "Kitchen", Model: "Google Home Mini", Host: "192.168.1.20", Port: 8009}}
+	if got := b.devices(); !reflect.DeepEqual(got, want) {
+		t.Errorf("devices = %+v, want %+v", got, want)
+	}
+	if got := want[0].Addr(); got != "192.168.1.20:8009" {
+		t.Errorf("Addr = %q", got)
+	}
+}
+
+// fakeDevice accepts one Cast connection and plays the receiver's side of
+// a launch. Every message the client sends is forwarded on the channel.
+func fakeDevice(t *testing.T) (string, chan message) {
+	t.Helper()
+	// Borrow httptest's self-signed certificate.
+	hs := httptest.NewTLSServer(http.NotFoundHandler())
+	hs.Close()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", hs.TLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	got := make(chan message, 32)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reply := func(src, ns string, payload any) {
+			data, _ := json.Marshal(payload)
+			writeMessage(conn, message{source: src, dest: senderID, namespace: ns, payload: string(data)})
+		}
+		for {
+			m, err := readMessage(conn)
+			if err != nil {
+				return
+			}
+			got <- m
+			var p map[string]any
+			json.Unmarshal([]byte(m.payload), &p)
+			switch p["type"] {
+			case "LAUNCH":
+				reply(receiverID, nsReceiver, map[string]any{
+					"type": "RECEIVER_STATUS",
+					"status": map[string]any{
+						"applications": []any{map[string]any{"appId": defaultReceiver, "sessionId": "s1", "transportId": "t1"}},
+						"volume":       map[string]any{"level": 0.4},
+					},
+				})
+			case "LOAD":
+				reply("t1", nsMedia, map[string]any{
+					"type": "MEDIA_STATUS", "status": []any{map[string]any{"mediaSessionId": 7, "playerState": "BUFFERING"}},
+				})
+			case "STOP":
+				reply(receiverID, nsReceiver, map[string]any{"type": "RECEIVER_STATUS", "status": map[string]any{}})
+			}
+		}
+	}()
+	return ln.Addr().String(), got
+}
+
+// next returns the next message in ns, skipping heartbeats and connects.
+func next(t *testing.T, ch chan message, ns string) map[string]any {
+	t.Helper()
+	for {
+		select {
+		case m := <-ch:
+			if m.namespace != ns {
+				continue
+			}
+			var p map[string]any
+			json.Unmarshal([]byte(m.payload), &p)
+			return p
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s message", ns)
+			return nil
+		}
+	}
+}
+
+func TestClient_LaunchLoadAndControl(t *testing.T) {
+	addr, got := fakeDevice(t)
+	c, err := Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if p := next(t, got, nsReceiver); p["type"] != "LAUNCH" || p["appId"] != defaultReceiver {
+		t.Errorf("first receiver message = %v, want LAUNCH", p)
+	}
+	if v := c.Volume(); v != 0.4 {
+		t.Errorf("Volume = %v, want the reported 0.4", v)
+	}
+
+	if err := c.Load(Media{URL: "http://10.0.0.2:4000/stream.wav", ContentType: "audio/wav", Live: true, Title: "cliamp"}); err != nil {
+		t.Fatal(err)
+	}
+	p := next(t, got, nsMedia)
+	media, _ := p["media"].(map[string]any)
+	if p["type"] != "LOAD" || media["contentId"] != "http://10.0.0.2:4000/stream.wav" || media["streamType"] != "LIVE" {
+		t.Errorf("LOAD = %v", p)
+	}
+
+	// Media commands need the session ID from MEDIA_STATUS.
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Pause() != nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p := next(t, got, nsMedia); p["type"] != "PAUSE" || p["mediaSessionId"] != float64(7) {
+		t.Errorf("PAUSE = %v", p)
+	}
+
+	c.SetVolume(1.5)
+	if p := next(t, got, nsReceiver); p["type"] != "SET_VOLUME" || p["volume"].(map[string]any)["level"] != float64(1) {
+		t.Errorf("SET_VOLUME = %v, want level clamped to 1", p)
+	}
+
+	c.Close()
+	if p := next(t, got, nsReceiver); p["type"] != "STOP" || p["sessionId"] != "s1" {
+		t.Errorf("Close sent %v, want STOP of the session", p)
+	}
+	select {
+	case <-c.Done():
+	default:
+		t.Error("Done not closed after Close")
+	}
+}
+
+func TestClient_SessionTakenOver(t *testing.T) {
+	addr, _ := fakeDevice(t)
+	c, err := Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Another sender closing the app shows up as a status without it.
+	if ok := c.receiverStatus(json.RawMessage(`{"applications":[]}`)); ok {
+		t.Error("status without the launched app should end the session")
+	}
+	if ok := c.receiverStatus(json.RawMessage(`{"applications":[{"appId":"CC1AD845","sessionId":"s1","transportId":"t1"}]}`)); !ok {
+		t.Error("status with the launched app should keep the session")
+	}
+}
@@ -0,0 +1,334 @@
+package cast
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// defaultReceiver is the app ID of the Default Media Receiver, which plays
+// any URL it is given.
+const defaultReceiver = "CC1AD845"
+
+const (
+	senderID       = "sender-0"
+	receiverID     = "receiver-0"
+	heartbeatEvery = 5 * time.Second
+	launchTimeout  = 20 * time.Second
+)
+
+// ErrClosed is reported once the device ended the session, for instance
+// because another app was cast to it.
+var ErrClosed = errors.New("cast session closed by the device")
+
+// Media describes what to load on the receiver.
+type Media struct {
+	URL         string
+	ContentType string // e.g. "audio/wav"
+	Live        bool   // an endless stream with no seekable timeline
+	Title       string
+	Artist      string
+}
+
+// Client is a session with the Default Media Receiver on one device. All
+// methods are safe for concurrent use.
+type Client struct {
+	conn net.Conn
+	wmu  sync.Mutex // serialises frames on conn
+
+	mu           sync.Mutex
+	reqID        int
+	transportID  string // receiver app's channel, known after launch
+	sessionID    string
+	mediaSession int
+	volume       float64
+	err          error
+
+	launched chan struct{}
+	done     chan struct{}
+}
+
+// Dial connects to the device at addr, launches the Default Media Receiver
+// and returns once it is ready to load media.
+func Dial(addr string) (*Client, error) {
+	// Cast devices present self-signed certificates.
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn, volume: -1, launched: make(chan struct{}), done: make(chan struct{})}
+	if err := c.send(receiverID, nsConnection, map[string]any{"type": "CONNECT"}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go c.readLoop()
+	go c.heartbeat()
+
+	if err := c.request(receiverID, nsReceiver, map[string]any{"type": "LAUNCH", "appId": defaultReceiver}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	select {
+	case <-c.launched:
+	case <-c.done:
+		return nil, c.Err()
+	case <-time.After(launchTimeout):
+		c.Close()
+		return nil, errors.New("cast: receiver did not start")
+	}
+	if err := c.send(c.transport(), nsConnection, map[string]any{"type": "CONNECT"}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Load starts playing media on the receiver, replacing what it was playing.
+func (c *Client) Load(m Media) error {
+	streamType := "BUFFERED"
+	if m.Live {
+		streamType = "LIVE"
+	}
+	return c.request(c.transport(), nsMedia, map[string]any{
+		"type":     "LOAD",
+		"autoplay": true,
+		"media": map[string]any{
+			"contentId":   m.URL,
+			"contentType": m.ContentType,
+			"streamType":  streamType,
+			"metadata": map[string]any{
+				"metadataType": 3, // MusicTrackMediaMetadata
+				"title":        m.Title,
+				"artist":       m.Artist,
+			},
+		},
+	})
+}
+
+// Play resumes paused media.
+func (c *Client) Play() error { return c.mediaCommand("PLAY", nil) }
+
+// Pause pauses the media.
+func (c *Client) Pause() error { return c.mediaCommand("PAUSE", nil) }
+
+// Seek jumps to secs into buffered (non-live) media.
+func (c *Client) Seek(secs float64) error {
+	return c.mediaCommand("SEEK", map[string]any{"currentTime": secs})
+}
+
+// SetVolume sets the device volume, from 0 to 1.
+func (c *Client) SetVolume(level float64) error {
+	level = max(0, min(1, level))
+	return c.request(receiverID, nsReceiver, map[string]any{
+		"type":   "SET_VOLUME",
+		"volume": map[string]any{"level": level},
+	})
+}
+
+// Volume returns the device volume last reported by the receiver, from 0
+// to 1, or -1 before the first report.
+func (c *Client) Volume() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.volume
+}
+
+// Done is closed when the session ends, by Close or by the device.
+func (c *Client) Done() <-chan struct{} { return c.done }
+
+// Err reports why the session ended, or nil while it is running.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close stops the receiver app and disconnects.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	session := c.sessionID
+	c.mu.Unlock()
+	select {
+	case <-c.done:
+	default:
+		if session != "" {
+			c.request(receiverID, nsReceiver, map[string]any{"type": "STOP", "sessionId": session})
+		}
+		c.send(receiverID, nsConnection, map[string]any{"type": "CLOSE"})
+	}
+	c.finish(nil)
+	return nil
+}
+
+func (c *Client) transport() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.transportID
+}
+
+// mediaCommand sends a command for the current media session.
+func (c *Client) mediaCommand(typ string, extra map[string]any) error {
+	c.mu.Lock()
+	id := c.mediaSession
+	c.mu.Unlock()
+	if id == 0 {
+		return errors.New("cast: no media loaded")
+	}
+	payload := map[string]any{"type": typ, "mediaSessionId": id}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	return c.request(c.transport(), nsMedia, payload)
+}
+
+// request sends payload with a fresh requestId. Responses are handled by
+// the read loop as status updates rather than matched to requests.
+func (c *Client) request(dest, ns string, payload map[string]any) error {
+	c.mu.Lock()
+	c.reqID++
+	payload["requestId"] = c.reqID
+	c.mu.Unlock()
+	return c.send(dest, ns, payload)
+}
+
+func (c *Client) send(dest, ns string, payload map[string]any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return writeMessage(c.conn, message{source: senderID, dest: dest, namespace: ns, payload: string(data)})
+}
+
+// finish ends the session once, recording err as the reason.
+func (c *Client) finish(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	c.err = err
+	close(c.done)
+	c.conn.Close()
+}
+
+func (c *Client) heartbeat() {
+	t := time.NewTicker(heartbeatEvery)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			if err := c.send(receiverID, nsHeartbeat, map[string]any{"type": "PING"}); err != nil {
+				c.finish(err)
+				return
+			}
+		}
+	}
+}
+
+// status is the union of the receiver and media status payloads.
+type status struct {
+	Type   string          `json:"type"`
+	Status json.RawMessage `json:"status"`
+	Reason string          `json:"reason"`
+}
+
+type receiverStatus struct {
+	Applications []struct {
+		AppID       string `json:"appId"`
+		SessionID   string `json:"sessionId"`
+		TransportID string `json:"transportId"`
+	} `json:"applications"`
+	Volume struct {
+		Level *float64 `json:"level"`
+	} `json:"volume"`
+}
+
+type mediaStatus []struct {
+	MediaSessionID int    `json:"mediaSessionId"`
+	PlayerState    string `json:"playerState"`
+	IdleReason     string `json:"idleReason"`
+}
+
+func (c *Client) readLoop() {
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(3 * heartbeatEvery))
+		m, err := readMessage(c.conn)
+		if err != nil {
+			select {
+			case <-c.done:
+			default:
+				c.finish(fmt.Errorf("cast: %w", err))
+			}
+			return
+		}
+		var st status
+		if json.Unmarshal([]byte(m.payload), &st) != nil {
+			continue
+		}
+		switch st.Type {
+		case "PING":
+			c.send(m.source, nsHeartbeat, map[string]any{"type": "PONG"})
+		case "CLOSE":
+			if m.source == receiverID || m.source == c.transport() {
+				c.finish(ErrClosed)
+				return
+			}
+		case "RECEIVER_STATUS":
+			if !c.receiverStatus(st.Status) {
+				c.finish(ErrClosed)
+				return
+			}
+		case "MEDIA_STATUS":
+			var ms mediaStatus
+			if json.Unmarshal(st.Status, &ms) == nil && len(ms) > 0 {
+				c.mu.Lock()
+				c.mediaSession = ms[0].MediaSessionID
+				c.mu.Unlock()
+			}
+		case "LOAD_FAILED", "LOAD_CANCELLED", "INVALID_REQUEST":
+			c.finish(fmt.Errorf("cast: %s %s", st.Type, st.Reason))
+			return
+		}
+	}
+}
+
+// receiverStatus records the app's transport and the device volume. It
+// reports false when the app the client launched is no longer running.
+func (c *Client) receiverStatus(raw json.RawMessage) bool {
+	var rs receiverStatus
+	if json.Unmarshal(raw, &rs) != nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if rs.Volume.Level != nil {
+		c.volume = *rs.Volume.Level
+	}
+	for _, app := range rs.Applications {
+		if app.AppID != defaultReceiver {
+			continue
+		}
+		if c.sessionID != "" && app.SessionID != c.sessionID {
+			break // our session was replaced by a new one
+		}
+		if c.transportID == "" {
+			c.sessionID = app.SessionID
+			c.transportID = app.TransportID
+			close(c.launched)
+		}
+		return true
+	}
+	// Before the launch completes, status without our app is expected.
+	return c.transportID == ""
+}
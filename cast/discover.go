@@ -0,0 +1,157 @@
+package cast
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// castService is the mDNS service type Cast devices advertise.
+const castService = "_googlecast._tcp.local."
+
+// Device is a Cast receiver found on the network.
+type Device struct {
+	Name  string // friendly name, e.g. "Kitchen speaker"
+	Model string // e.g. "Google Home Mini"; may be empty
+	Host  string // IP address
+	Port  int
+}
+
+// Addr returns the host:port of the device's Cast channel.
+func (d Device) Addr() string { return net.JoinHostPort(d.Host, strconv.Itoa(d.Port)) }
+
+// Discover asks the network for Cast devices and collects the answers for
+// timeout. Devices are sorted by name.
+func Discover(timeout time.Duration) ([]Device, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	query, err := browseQuery()
+	if err != nil {
+		return nil, err
+	}
+	group := &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	if _, err := conn.WriteToUDP(query, group); err != nil {
+		return nil, err
+	}
+
+	b := newBrowser()
+	deadline := time.Now().Add(timeout)
+	resent := false
+	buf := make([]byte, 9000)
+	for {
+		// Ask a second time halfway through, since mDNS is lossy.
+		wait := deadline
+		if !resent {
+			wait = time.Now().Add(timeout / 2)
+		}
+		conn.SetReadDeadline(wait)
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+				return nil, err
+			}
+			if resent || time.Now().After(deadline) {
+				break
+			}
+			resent = true
+			conn.WriteToUDP(query, group)
+			continue
+		}
+		b.add(buf[:n])
+	}
+	return b.devices(), nil
+}
+
+// browseQuery builds a PTR query for the Cast service.
+func browseQuery() ([]byte, error) {
+	name, err := dnsmessage.NewName(castService)
+	if err != nil {
+		return nil, err
+	}
+	msg := dnsmessage.Message{
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET}},
+	}
+	return msg.Pack()
+}
+
+// browser assembles devices from the PTR, SRV, TXT and A records of any
+// number of mDNS responses, which may arrive in any order.
+type browser struct {
+	instances map[string]bool
+	srv       map[string]dnsmessage.SRVResource
+	txt       map[string][]string
+	addrs     map[string]string
+}
+
+func newBrowser() *browser {
+	return &browser{
+		instances: make(map[string]bool),
+		srv:       make(map[string]dnsmessage.SRVResource),
+		txt:       make(map[string][]string),
+		addrs:     make(map[string]string),
+	}
+}
+
+// add records every resource in a response. Malformed packets and
+// unrelated records are ignored.
+func (b *browser) add(packet []byte) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(packet); err != nil {
+		return
+	}
+	all := append(append(msg.Answers, msg.Authorities...), msg.Additionals...)
+	for _, rr := range all {
+		name := strings.ToLower(rr.Header.Name.String())
+		switch body := rr.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if name == castService {
+				b.instances[strings.ToLower(body.PTR.String())] = true
+			}
+		case *dnsmessage.SRVResource:
+			b.srv[name] = *body
+		case *dnsmessage.TXTResource:
+			b.txt[name] = body.TXT
+		case *dnsmessage.AResource:
+			b.addrs[name] = net.IP(body.A[:]).String()
+		}
+	}
+}
+
+// devices returns the instances whose address is known.
+func (b *browser) devices() []Device {
+	var out []Device
+	for inst := range b.instances {
+		srv, ok := b.srv[inst]
+		if !ok {
+			continue
+		}
+		host, ok := b.addrs[strings.ToLower(srv.Target.String())]
+		if !ok {
+			continue
+		}
+		d := Device{Host: host, Port: int(srv.Port)}
+		for _, kv := range b.txt[inst] {
+			k, v, _ := strings.Cut(kv, "=")
+			switch k {
+			case "fn":
+				d.Name = v
+			case "md":
+				d.Model = v
+			}
+		}
+		if d.Name == "" {
+			d.Name, _, _ = strings.Cut(inst, ".")
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,114 @@
+// Package cast implements the sender side of the Google Cast protocol:
+// finding Chromecast devices on the local network and driving the Default
+// Media Receiver to play a URL.
+package cast
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Namespaces of the Cast channel messages the client uses.
+const (
+	nsConnection = "urn:x-cast:com.google.cast.tp.connection"
+	nsHeartbeat  = "urn:x-cast:com.google.cast.tp.heartbeat"
+	nsReceiver   = "urn:x-cast:com.google.cast.receiver"
+	nsMedia      = "urn:x-cast:com.google.cast.media"
+)
+
+// maxMessage bounds a single frame; real status messages are a few KB.
+const maxMessage = 64 << 10
+
+// message is a CastMessage with a UTF-8 (JSON) payload, the only kind the
+// media namespaces use.
+type message struct {
+	source, dest string
+	namespace    string
+	payload      string
+}
+
+// CastMessage field numbers from cast_channel.proto.
+const (
+	fieldProtocolVersion = 1
+	fieldSourceID        = 2
+	fieldDestinationID   = 3
+	fieldNamespace       = 4
+	fieldPayloadType     = 5
+	fieldPayloadUTF8     = 6
+)
+
+// writeMessage encodes m as a length-prefixed CastMessage.
+func writeMessage(w io.Writer, m message) error {
+	b := make([]byte, 4, 64+len(m.payload))
+	b = protowire.AppendTag(b, fieldProtocolVersion, protowire.VarintType)
+	b = protowire.AppendVarint(b, 0) // CASTV2_1_0
+	b = protowire.AppendTag(b, fieldSourceID, protowire.BytesType)
+	b = protowire.AppendString(b, m.source)
+	b = protowire.AppendTag(b, fieldDestinationID, protowire.BytesType)
+	b = protowire.AppendString(b, m.dest)
+	b = protowire.AppendTag(b, fieldNamespace, protowire.BytesType)
+	b = protowire.AppendString(b, m.namespace)
+	b = protowire.AppendTag(b, fieldPayloadType, protowire.VarintType)
+	b = protowire.AppendVarint(b, 0) // STRING
+	b = protowire.AppendTag(b, fieldPayloadUTF8, protowire.BytesType)
+	b = protowire.AppendString(b, m.payload)
+	binary.BigEndian.PutUint32(b, uint32(len(b)-4))
+	_, err := w.Write(b)
+	return err
+}
+
+// readMessage reads one length-prefixed CastMessage. Binary payloads are
+// returned with an empty payload.
+func readMessage(r io.Reader) (message, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return message{}, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if n > maxMessage {
+		return message{}, fmt.Errorf("cast: %d-byte message too large", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return message{}, err
+	}
+
+	var m message
+	for len(b) > 0 {
+		num, typ, tn := protowire.ConsumeTag(b)
+		if tn < 0 {
+			return message{}, errMalformed
+		}
+		b = b[tn:]
+		if typ == protowire.BytesType {
+			v, vn := protowire.ConsumeString(b)
+			if vn < 0 {
+				return message{}, errMalformed
+			}
+			switch num {
+			case fieldSourceID:
+				m.source = v
+			case fieldDestinationID:
+				m.dest = v
+			case fieldNamespace:
+				m.namespace = v
+			case fieldPayloadUTF8:
+				m.payload = v
+			}
+			b = b[vn:]
+			continue
+		}
+		vn := protowire.ConsumeFieldValue(num, typ, b)
+		if vn < 0 {
+			return message{}, errMalformed
+		}
+		b = b[vn:]
+	}
+	return m, nil
+}
+
+var errMalformed = errors.New("cast: malformed message")
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"cliamp/internal/appdir"
+	"cliamp/internal/tomlutil"
 )
 
 // configPath returns the path to the config file.
@@ -32,6 +33,7 @@ type NavidromeConfig struct {
 	Password         string
 	BrowseSort       string // album browse sort order, e.g. "alphabeticalByName"
 	ScrobbleDisabled bool   // true only when "scrobble = false" is explicitly set
+	LegacyAuth       bool   // "legacy_auth = true": send the password instead of a token
 }
 
 // IsSet reports whether all three Navidrome credentials are present.
@@ -99,6 +101,67 @@ func (y YouTubeMusicConfig) ResolveCredentials(fallbackFn func() (string, string
 	return "", ""
 }
 
+// ListenBrainzConfig holds the user token for ListenBrainz submissions.
+type ListenBrainzConfig struct {
+	Token string // user token from listenbrainz.org/settings
+	URL   string // API root for self-hosted servers; "" = api.listenbrainz.org
+}
+
+// IsSet reports whether a ListenBrainz token is configured.
+func (l ListenBrainzConfig) IsSet() bool {
+	return l.Token != ""
+}
+
+// AcoustIDConfig holds the application key for AcoustID lookups.
+type AcoustIDConfig struct {
+	Key string // application API key from acoustid.org/new-application
+}
+
+// IsSet reports whether an AcoustID key is configured.
+func (a AcoustIDConfig) IsSet() bool {
+	return a.Key != ""
+}
+
+// RemoteConfig holds listen addresses for the remote-control servers. Each
+// server stays off while its address is empty.
+type RemoteConfig struct {
+	HTTP           string // status API and WebSocket event stream, e.g. "127.0.0.1:8754"
+	MPD            string // MPD protocol server, e.g. "127.0.0.1:6600"
+	DLNA           string // UPnP/DLNA media renderer, e.g. ":49494"
+	DLNAName       string // name shown to DLNA control points; "" uses the host name
+	SocketDisabled bool   // true only when "socket = false" is explicitly set
+	Forward        string // what a second cliamp does with its files: "append" (default), "replace" or "off"
+	NowPlaying     string // file kept up to date with the current track; "" disables it
+	NowPlayingFmt  string // text template for NowPlaying; ignored for .json files
+	SignalUSR1     string // action on SIGUSR1: "toggle" (default), "next", "prev", "stop" or "off"
+	SignalUSR2     string // action on SIGUSR2: "next" (default), "toggle", "prev", "stop" or "off"
+}
+
+// SocketEnabled reports whether the control socket for `cliamp <command>`
+// is active. It is opt-out.
+func (r RemoteConfig) SocketEnabled() bool {
+	return !r.SocketDisabled
+}
+
+// IcecastConfig describes an Icecast mountpoint to broadcast the output to.
+// Broadcasting stays off while URL is empty.
+type IcecastConfig struct {
+	URL         string // mountpoint, e.g. "http://radio.example.com:8000/live.mp3"
+	User        string // source user; "" means "source"
+	Password    string // source password
+	Format      string // "mp3" (default) or "ogg"
+	Bitrate     int    // encoder bitrate in kbps; 0 means 128
+	Name        string // station name shown in directories and players
+	Description string
+	Genre       string
+	Public      bool // list the stream on public directories
+}
+
+// IsSet reports whether a mountpoint is configured.
+func (c IcecastConfig) IsSet() bool {
+	return c.URL != ""
+}
+
 // PlexConfig holds credentials for a Plex Media Server.
 // Both URL and Token must be non-empty for a client to be constructed.
 type PlexConfig struct {
@@ -113,25 +176,77 @@ func (p PlexConfig) IsSet() bool {
 
 // Config holds user preferences loaded from the config file.
 type Config struct {
-	Volume            float64            // dB, range [-30, +6]
-	EQ                [10]float64        // per-band gain in dB, range [-12, +12]
-	EQPreset          string             // preset name, or "" for custom
-	Repeat            string             // "off", "all", or "one"
-	Shuffle           bool
-	Mono              bool
-	SeekStepLarge     int                // seconds for Shift+Left/Right seek jumps
-	Provider          string             // default provider: "radio", "navidrome", "spotify", "ytmusic" (default "radio")
-	Theme             string             // theme name, or "" for ANSI default
-	Visualizer        string             // visualizer mode name, or "" for default (Bars)
-	SampleRate        int                // output sample rate: 22050, 44100, 48000, 96000, 192000
-	BufferMs          int                // speaker buffer in milliseconds (50–500)
-	ResampleQuality   int                // beep resample quality factor (1–4)
-	BitDepth          int                // PCM bit depth for FFmpeg output: 16 or 32
-	Compact           bool               // compact mode: cap frame width at 80 columns
-	Navidrome         NavidromeConfig    // optional Navidrome/Subsonic server credentials
-	Spotify           SpotifyConfig      // optional Spotify provider (requires Premium)
-	YouTubeMusic      YouTubeMusicConfig // optional YouTube Music provider
-	Plex              PlexConfig         // optional Plex Media Server credentials
+	Volume           float64     // dB, range [-30, +6]
+	EQ               [10]float64 // per-band gain in dB, range [-12, +12]
+	EQPreset         string      // preset name, or "" for custom
+	EQFreqs          [10]float64 // per-band center frequency in Hz, 0 for the band's default
+	EQQ              [10]float64 // per-band quality factor, 0 for the default
+	EQAutoPreamp     bool        // attenuate output by the EQ's peak boost to avoid clipping
+	Repeat           string      // "off", "all", or "one"
+	Shuffle          bool
+	ShuffleAlbums    bool    // shuffle whole albums, each in track order; implies Shuffle
+	SmartShuffle     bool    // weight track shuffle by the listening history
+	SmartRating      float64 // how strongly smart shuffle favours tracks heard through (0–4)
+	SmartRecency     float64 // how strongly smart shuffle holds back recent plays (0–4)
+	Mono             bool
+	Balance          float64            // stereo balance, range [-1 (left), +1 (right)]
+	StereoWiden      bool               // enable mid/side stereo widening
+	StereoWidth      int                // stereo width in percent (0–200, 100 = unchanged)
+	Crossfeed        bool               // headphone crossfeed
+	CrossfeedPreset  string             // crossfeed preset: "default", "cmoy", or "jmeier"
+	Reverb           bool               // room reverb effect
+	ReverbPreset     string             // reverb room model: "room", "hall", or "plate"
+	Karaoke          bool               // cancel centred vocals for singing along
+	NightMode        bool               // compress loud and lift quiet passages for low-volume listening
+	NightPreset      string             // night mode strength: "gentle" or "strong"
+	FadeMs           int                // pause/resume/stop fade length in milliseconds (0 disables)
+	Limiter          bool               // soft-knee limiter on the final output
+	SkipSilence      bool               // trim leading/trailing silence on local files
+	SilenceDB        float64            // silence threshold in dBFS (-90 to -20)
+	SilenceMinMs     int                // trailing silence that ends a track, in milliseconds
+	SeekStep         int                // seconds for Left/Right seeks (accelerates while held)
+	SeekStepLarge    int                // seconds for Shift+Left/Right seek jumps
+	ReplayStep       int                // seconds the replay key jumps back
+	AlarmFade        int                // seconds the alarm clock fades in from silence
+	Provider         string             // default provider: "radio", "podcasts", "navidrome", "spotify", "ytmusic" (default "radio")
+	Theme            string             // theme name, or "" for ANSI default
+	Visualizer       string             // visualizer mode name, or "" for default (Bars)
+	SampleRate       int                // output sample rate: 22050, 44100, 48000, 96000, 192000
+	BufferMs         int                // speaker buffer in milliseconds (20–500)
+	ResampleQuality  int                // beep resample quality factor (1–4)
+	BitDepth         int                // PCM bit depth for FFmpeg output: 16 or 32
+	DSPBypass        bool               // bypass all DSP and open the output at the first track's native rate
+	Compact          bool               // compact mode: cap frame width at 80 columns
+	ASCII            string             // "true", "false", or "" to detect from TERM and the locale
+	Accessible       bool               // screen reader mode: plain output, state changes announced as lines
+	Palette          string             // default theme's colors: "dark", "light", "high-contrast", "high-contrast-light"; "" or "auto" detects
+	VolumeDisplay    string             // volume readout: "db" (default) or "percent"
+	TimeDisplay      string             // time readout: "elapsed" (default), "remaining" or "both"
+	TerminalTitle    string             // terminal title format, "true" for the default; "" leaves it alone
+	TitleScroll      string             // long titles: "loop" (default), "bounce" or "off"
+	TitleScrollMs    int                // milliseconds per column a long title scrolls
+	TitleScrollPause int                // milliseconds a scrolling title rests at its start
+	FollowPlayback   bool               // keep the playing track centred in the playlist view
+	GroupAlbums      bool               // group the playlist under album headers
+	LevelMeter       bool               // show the output's peak level in dBFS
+	Watch            []string           // directories whose new audio files are appended to the playlist
+	Audiobooks       []string           // directories and files played in audiobook mode
+	Library          []string           // music directories Auto-DJ picks from
+	Exclude          []string           // glob patterns of files and folders left out of folder scans
+	OrganizePattern  string             // where organize moves files, e.g. "{artist}/{album}/{track} {title}.{ext}"
+	LogFile          string             // file the diagnostic log is appended to; "" disables it
+	LogLevel         string             // least severe level logged: "debug", "info", "warn" or "error"
+	AutoDJ           bool               // append library tracks when the playlist runs out
+	AutoDJMode       string             // Auto-DJ pick: "random" or "similar"
+	Navidrome        NavidromeConfig    // optional Navidrome/Subsonic server credentials
+	Spotify          SpotifyConfig      // optional Spotify provider (requires Premium)
+	YouTubeMusic     YouTubeMusicConfig // optional YouTube Music provider
+	Plex             PlexConfig         // optional Plex Media Server credentials
+	ListenBrainz     ListenBrainzConfig // optional ListenBrainz scrobbling
+	AcoustID         AcoustIDConfig     // optional AcoustID track identification
+	Remote           RemoteConfig       // optional remote-control servers
+	Icecast          IcecastConfig      // optional Icecast broadcast
+	Hooks            map[string]string  // [hooks] event name → shell command
 }
 
 // defaultConfig returns a Config with sensible defaults.
@@ -141,7 +256,20 @@ type Config struct {
 func defaultConfig() Config {
 	return Config{
 		Repeat:          "off",
+		EQAutoPreamp:    true,
+		FadeMs:          200,
+		SilenceDB:       -60,
+		SilenceMinMs:    1500,
+		SmartRating:     1,
+		SmartRecency:    1,
+		StereoWidth:     150,
+		SeekStep:        5,
 		SeekStepLarge:   30,
+		ReplayStep:      10,
+		AlarmFade:       60,
+		TitleScroll:     "loop",
+		Remote:          RemoteConfig{SignalUSR1: "toggle", SignalUSR2: "next"},
+		TitleScrollMs:   200,
 		SampleRate:      0,
 		BufferMs:        100,
 		ResampleQuality: 4,
@@ -197,7 +325,7 @@ func Load() (Config, error) {
 		val = strings.TrimSpace(val)
 
 		switch section {
-		case "navidrome":
+		case "navidrome", "subsonic":
 			switch key {
 			case "url":
 				cfg.Navidrome.URL = strings.Trim(val, `"'`)
@@ -210,6 +338,8 @@ func Load() (Config, error) {
 			case "scrobble":
 				// Opt-out: only mark disabled when the value is explicitly "false".
 				cfg.Navidrome.ScrobbleDisabled = strings.ToLower(val) == "false"
+			case "legacy_auth":
+				cfg.Navidrome.LegacyAuth = strings.ToLower(val) == "true"
 			}
 		case "spotify":
 			switch key {
@@ -236,6 +366,82 @@ func Load() (Config, error) {
 			case "token":
 				cfg.Plex.Token = strings.Trim(val, `"'`)
 			}
+		case "listenbrainz":
+			switch key {
+			case "token":
+				cfg.ListenBrainz.Token = strings.Trim(val, `"'`)
+			case "url":
+				cfg.ListenBrainz.URL = strings.Trim(val, `"'`)
+			}
+		case "acoustid":
+			if key == "key" {
+				cfg.AcoustID.Key = strings.Trim(val, `"'`)
+			}
+		case "hooks":
+			if cfg.Hooks == nil {
+				cfg.Hooks = make(map[string]string)
+			}
+			// Commands often contain quotes of their own, so take TOML
+			// literal strings verbatim and unescape basic strings.
+			if len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'' {
+				cfg.Hooks[key] = val[1 : len(val)-1]
+			} else {
+				cfg.Hooks[key] = tomlutil.Unquote(val)
+			}
+		case "remote":
+			switch key {
+			case "http":
+				cfg.Remote.HTTP = strings.Trim(val, `"'`)
+			case "mpd":
+				cfg.Remote.MPD = strings.Trim(val, `"'`)
+			case "dlna":
+				cfg.Remote.DLNA = strings.Trim(val, `"'`)
+			case "dlna_name":
+				cfg.Remote.DLNAName = tomlutil.Unquote(val)
+			case "socket":
+				cfg.Remote.SocketDisabled = strings.ToLower(val) == "false"
+			case "forward":
+				switch v := strings.ToLower(strings.Trim(val, `"'`)); v {
+				case "append", "replace", "off":
+					cfg.Remote.Forward = v
+				}
+			case "now_playing":
+				cfg.Remote.NowPlaying = tomlutil.Unquote(val)
+			case "now_playing_format":
+				cfg.Remote.NowPlayingFmt = tomlutil.Unquote(val)
+			case "signal_usr1", "signal_usr2":
+				switch v := strings.ToLower(strings.Trim(val, `"'`)); v {
+				case "toggle", "next", "prev", "stop", "off":
+					if key == "signal_usr1" {
+						cfg.Remote.SignalUSR1 = v
+					} else {
+						cfg.Remote.SignalUSR2 = v
+					}
+				}
+			}
+		case "icecast":
+			switch key {
+			case "url":
+				cfg.Icecast.URL = strings.Trim(val, `"'`)
+			case "user":
+				cfg.Icecast.User = strings.Trim(val, `"'`)
+			case "password":
+				cfg.Icecast.Password = strings.Trim(val, `"'`)
+			case "format":
+				cfg.Icecast.Format = strings.ToLower(strings.Trim(val, `"'`))
+			case "bitrate":
+				if v, err := strconv.Atoi(val); err == nil && v > 0 {
+					cfg.Icecast.Bitrate = v
+				}
+			case "name":
+				cfg.Icecast.Name = tomlutil.Unquote(val)
+			case "description":
+				cfg.Icecast.Description = tomlutil.Unquote(val)
+			case "genre":
+				cfg.Icecast.Genre = tomlutil.Unquote(val)
+			case "public":
+				cfg.Icecast.Public = strings.ToLower(val) == "true"
+			}
 		default:
 			switch key {
 			case "volume":
@@ -249,17 +455,87 @@ func Load() (Config, error) {
 					cfg.Repeat = strings.ToLower(val)
 				}
 			case "shuffle":
-				cfg.Shuffle = val == "true"
+				val = strings.Trim(val, `"'`)
+				cfg.Shuffle = val == "true" || val == "album"
+				cfg.ShuffleAlbums = val == "album"
+			case "smart_shuffle":
+				cfg.SmartShuffle = val == "true"
+			case "smart_shuffle_rating":
+				if v, err := strconv.ParseFloat(val, 64); err == nil {
+					cfg.SmartRating = v
+				}
+			case "smart_shuffle_recency":
+				if v, err := strconv.ParseFloat(val, 64); err == nil {
+					cfg.SmartRecency = v
+				}
 			case "mono":
 				cfg.Mono = val == "true"
+			case "balance":
+				if v, err := strconv.ParseFloat(val, 64); err == nil {
+					cfg.Balance = v
+				}
+			case "stereo_widen":
+				cfg.StereoWiden = val == "true"
+			case "stereo_width":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.StereoWidth = v
+				}
+			case "crossfeed":
+				cfg.Crossfeed = val == "true"
+			case "crossfeed_preset":
+				cfg.CrossfeedPreset = strings.ToLower(strings.Trim(val, `"'`))
+			case "reverb":
+				cfg.Reverb = val == "true"
+			case "reverb_preset":
+				cfg.ReverbPreset = strings.ToLower(strings.Trim(val, `"'`))
+			case "karaoke":
+				cfg.Karaoke = val == "true"
+			case "night_mode":
+				cfg.NightMode = val == "true"
+			case "night_preset":
+				cfg.NightPreset = strings.ToLower(strings.Trim(val, `"'`))
+			case "fade_ms":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.FadeMs = v
+				}
+			case "limiter":
+				cfg.Limiter = val == "true"
+			case "skip_silence":
+				cfg.SkipSilence = val == "true"
+			case "silence_threshold_db":
+				if v, err := strconv.ParseFloat(val, 64); err == nil {
+					cfg.SilenceDB = v
+				}
+			case "silence_min_ms":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.SilenceMinMs = v
+				}
+			case "seek_step_sec":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.SeekStep = v
+				}
 			case "seek_large_step_sec":
 				if v, err := strconv.Atoi(val); err == nil {
 					cfg.SeekStepLarge = v
 				}
+			case "replay_sec":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.ReplayStep = v
+				}
+			case "alarm_fade_sec":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.AlarmFade = v
+				}
 			case "eq":
 				cfg.EQ = parseEQ(val)
+			case "eq_freqs":
+				cfg.EQFreqs = parseEQShape(val, 20, 20000)
+			case "eq_q":
+				cfg.EQQ = parseEQShape(val, 0.1, 10)
 			case "eq_preset":
 				cfg.EQPreset = strings.Trim(val, `"'`)
+			case "eq_auto_preamp":
+				cfg.EQAutoPreamp = val != "false"
 			case "theme":
 				cfg.Theme = strings.Trim(val, `"'`)
 			case "provider":
@@ -282,8 +558,56 @@ func Load() (Config, error) {
 				if v, err := strconv.Atoi(val); err == nil {
 					cfg.BitDepth = v
 				}
+			case "dsp_bypass", "bit_perfect": // bit_perfect is the old name
+				cfg.DSPBypass = val == "true"
 			case "compact":
 				cfg.Compact = val == "true"
+			case "ascii":
+				cfg.ASCII = strings.ToLower(strings.Trim(val, `"'`))
+			case "accessible":
+				cfg.Accessible = val == "true"
+			case "palette":
+				cfg.Palette = strings.ToLower(strings.Trim(val, `"'`))
+			case "volume_display":
+				cfg.VolumeDisplay = strings.ToLower(strings.Trim(val, `"'`))
+			case "time_display":
+				cfg.TimeDisplay = strings.ToLower(strings.Trim(val, `"'`))
+			case "terminal_title":
+				cfg.TerminalTitle = tomlutil.Unquote(val)
+			case "title_scroll":
+				cfg.TitleScroll = strings.ToLower(strings.Trim(val, `"'`))
+			case "title_scroll_ms":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.TitleScrollMs = v
+				}
+			case "title_scroll_pause_ms":
+				if v, err := strconv.Atoi(val); err == nil {
+					cfg.TitleScrollPause = v
+				}
+			case "watch":
+				cfg.Watch = parseStringList(val)
+			case "audiobooks":
+				cfg.Audiobooks = parseStringList(val)
+			case "library":
+				cfg.Library = parseStringList(val)
+			case "exclude":
+				cfg.Exclude = parseStringList(val)
+			case "organize_pattern":
+				cfg.OrganizePattern = tomlutil.Unquote(val)
+			case "log_file":
+				cfg.LogFile = strings.Trim(val, `"'`)
+			case "log_level":
+				cfg.LogLevel = strings.ToLower(strings.Trim(val, `"'`))
+			case "follow_playback":
+				cfg.FollowPlayback = val == "true"
+			case "group_albums":
+				cfg.GroupAlbums = val == "true"
+			case "level_meter":
+				cfg.LevelMeter = val == "true"
+			case "auto_dj":
+				cfg.AutoDJ = val == "true"
+			case "auto_dj_mode":
+				cfg.AutoDJMode = strings.ToLower(strings.Trim(val, `"'`))
 			}
 		}
 	}
@@ -432,18 +756,57 @@ func SaveNavidromeSort(sortType string) error {
 type PlayerConfig interface {
 	SetVolume(db float64)
 	SetEQBand(band int, dB float64)
+	SetEQBandFreq(band int, hz float64)
+	SetEQBandQ(band int, q float64)
+	SetEQAutoPreamp(on bool)
+	SetBalance(b float64)
+	SetStereoWiden(on bool)
+	SetStereoWidth(w float64)
+	SetCrossfeed(on bool)
+	SetReverb(on bool)
+	SetKaraoke(on bool)
+	SetNightMode(on bool)
+	SetLimiter(on bool)
+	SetFadeDuration(d time.Duration)
+	SetDSPBypass(on bool)
+	SetSkipSilence(on bool)
+	SetSilenceThreshold(dB float64)
+	SetSilenceMinDuration(d time.Duration)
 	ToggleMono()
 }
 
 // PlaylistConfig is the subset of playlist controls needed to apply config.
 type PlaylistConfig interface {
 	CycleRepeat()
-	ToggleShuffle()
+	CycleShuffle()
 }
 
 // ApplyPlayer applies audio-engine settings from the config.
 func (c Config) ApplyPlayer(p PlayerConfig) {
 	p.SetVolume(c.Volume)
+	p.SetEQAutoPreamp(c.EQAutoPreamp)
+	p.SetBalance(c.Balance)
+	p.SetStereoWidth(float64(c.StereoWidth) / 100)
+	p.SetStereoWiden(c.StereoWiden)
+	p.SetCrossfeed(c.Crossfeed)
+	p.SetReverb(c.Reverb)
+	p.SetKaraoke(c.Karaoke)
+	p.SetNightMode(c.NightMode)
+	p.SetLimiter(c.Limiter)
+	p.SetFadeDuration(time.Duration(c.FadeMs) * time.Millisecond)
+	p.SetDSPBypass(c.DSPBypass)
+	p.SetSkipSilence(c.SkipSilence)
+	p.SetSilenceThreshold(c.SilenceDB)
+	p.SetSilenceMinDuration(time.Duration(c.SilenceMinMs) * time.Millisecond)
+	for i := range 10 {
+		// Presets set gains only, so a band's shape applies with any of them.
+		if c.EQFreqs[i] > 0 {
+			p.SetEQBandFreq(i, c.EQFreqs[i])
+		}
+		if c.EQQ[i] > 0 {
+			p.SetEQBandQ(i, c.EQQ[i])
+		}
+	}
 	if c.EQPreset == "" || c.EQPreset == "Custom" {
 		for i, gain := range c.EQ {
 			p.SetEQBand(i, gain)
@@ -463,22 +826,64 @@ func (c Config) ApplyPlaylist(pl PlaylistConfig) {
 		pl.CycleRepeat() // off -> all
 		pl.CycleRepeat() // all -> one
 	}
-	if c.Shuffle {
-		pl.ToggleShuffle()
+	switch {
+	case c.ShuffleAlbums:
+		pl.CycleShuffle() // off -> tracks
+		pl.CycleShuffle() // tracks -> albums
+	case c.Shuffle:
+		pl.CycleShuffle() // off -> tracks
 	}
 }
 
+// SeekStepDuration returns the configured Left/Right seek step.
+func (c Config) SeekStepDuration() time.Duration {
+	return time.Duration(c.SeekStep) * time.Second
+}
+
 // SeekStepLargeDuration returns the configured Shift+Left/Right seek jump.
 func (c Config) SeekStepLargeDuration() time.Duration {
 	return time.Duration(c.SeekStepLarge) * time.Second
 }
 
+// ReplayStepDuration returns how far the replay key jumps back.
+func (c Config) ReplayStepDuration() time.Duration {
+	return time.Duration(c.ReplayStep) * time.Second
+}
+
+// AlarmFadeDuration returns how long the alarm clock takes to fade in.
+func (c Config) AlarmFadeDuration() time.Duration {
+	return time.Duration(c.AlarmFade) * time.Second
+}
+
+// TitleScrollStep returns how long a scrolling title rests on each column.
+func (c Config) TitleScrollStep() time.Duration {
+	return time.Duration(c.TitleScrollMs) * time.Millisecond
+}
+
+// TitleScrollPauseDuration returns how long a scrolling title rests at its
+// start, and for "bounce" at its end too.
+func (c Config) TitleScrollPauseDuration() time.Duration {
+	return time.Duration(c.TitleScrollPause) * time.Millisecond
+}
+
 // clamp constrains all Config fields to their valid ranges.
 func (c *Config) clamp() {
 	c.Volume = max(min(c.Volume, 6), -30)
+	c.Balance = max(min(c.Balance, 1), -1)
+	c.StereoWidth = max(min(c.StereoWidth, 200), 0)
+	c.FadeMs = max(min(c.FadeMs, 2000), 0)
+	c.SilenceDB = max(min(c.SilenceDB, -20), -90)
+	c.SilenceMinMs = max(min(c.SilenceMinMs, 10000), 200)
+	c.SmartRating = max(min(c.SmartRating, 4), 0)
+	c.SmartRecency = max(min(c.SmartRecency, 4), 0)
+	c.SeekStep = max(min(c.SeekStep, 60), 1)
 	c.SeekStepLarge = max(min(c.SeekStepLarge, 600), 6)
+	c.ReplayStep = max(min(c.ReplayStep, 120), 1)
+	c.AlarmFade = max(min(c.AlarmFade, 1800), 0)
+	c.TitleScrollMs = max(min(c.TitleScrollMs, 5000), 50)
+	c.TitleScrollPause = max(min(c.TitleScrollPause, 30000), 0)
 	c.SampleRate = clampSampleRate(c.SampleRate)
-	c.BufferMs = max(min(c.BufferMs, 500), 50)
+	c.BufferMs = max(min(c.BufferMs, 500), 20)
 	c.ResampleQuality = max(min(c.ResampleQuality, 4), 1)
 	c.BitDepth = clampBitDepth(c.BitDepth)
 }
@@ -517,6 +922,25 @@ func abs(x int) int {
 }
 
 // parseEQ parses a TOML-style array like [0, 1.5, -2, ...] into 10 bands.
+// parseStringList parses a TOML array of strings such as ["a", "b"]. A bare
+// string is accepted as a one-element list.
+func parseStringList(val string) []string {
+	val = strings.TrimSpace(val)
+	if !strings.HasPrefix(val, "[") {
+		if s := tomlutil.Unquote(val); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	var out []string
+	for _, p := range strings.Split(strings.Trim(val, "[]"), ",") {
+		if s := tomlutil.Unquote(strings.TrimSpace(p)); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
 func parseEQ(val string) [10]float64 {
 	var bands [10]float64
 	val = strings.Trim(val, "[]")
@@ -531,3 +955,20 @@ func parseEQ(val string) [10]float64 {
 	}
 	return bands
 }
+
+// parseEQShape parses a TOML-style array of per-band center frequencies or
+// Qs, clamping each to [lo, hi]. Missing or invalid entries are 0, which
+// leaves that band at its default.
+func parseEQShape(val string, lo, hi float64) [10]float64 {
+	var out [10]float64
+	parts := strings.Split(strings.Trim(val, "[]"), ",")
+	for i, p := range parts {
+		if i >= 10 {
+			break
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(p), 64); err == nil && v > 0 {
+			out[i] = max(min(v, hi), lo)
+		}
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadEQShape(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(os.Getenv("HOME"), ".config", "cliamp", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	data := "eq_freqs = [60, 5, 0, 40000, x]\neq_q = [0.7, 0.01, 20]\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	wantFreqs := [10]float64{60, 20, 0, 20000}
+	if cfg.EQFreqs != wantFreqs {
+		t.Errorf("EQFreqs = %v, want %v", cfg.EQFreqs, wantFreqs)
+	}
+	wantQ := [10]float64{0.7, 0.1, 10}
+	if cfg.EQQ != wantQ {
+		t.Errorf("EQQ = %v, want %v", cfg.EQQ, wantQ)
+	}
+}
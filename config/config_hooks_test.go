@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadHooks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(os.Getenv("HOME"), ".config", "cliamp", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	data := `[hooks]
+track_start = 'notify-send "$CLIAMP_TITLE"'
+stop = "echo \"stopped\" >> /tmp/log"
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, want := cfg.Hooks["track_start"], `notify-send "$CLIAMP_TITLE"`; got != want {
+		t.Errorf("track_start = %q, want %q", got, want)
+	}
+	if got, want := cfg.Hooks["stop"], `echo "stopped" >> /tmp/log`; got != want {
+		t.Errorf("stop = %q, want %q", got, want)
+	}
+}
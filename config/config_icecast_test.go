@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadIcecastSection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(os.Getenv("HOME"), ".config", "cliamp", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	data := `[icecast]
+url = "http://radio.example.com:8000/live.mp3"
+password = "hackme"
+format = "OGG"
+bitrate = 192
+name = "Night \"Shift\""
+public = true
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := IcecastConfig{
+		URL:      "http://radio.example.com:8000/live.mp3",
+		Password: "hackme",
+		Format:   "ogg",
+		Bitrate:  192,
+		Name:     `Night "Shift"`,
+		Public:   true,
+	}
+	if cfg.Icecast != want {
+		t.Errorf("Icecast = %+v, want %+v", cfg.Icecast, want)
+	}
+}
@@ -69,3 +69,27 @@ func TestSeekStepLargeDuration(t *testing.T) {
 		t.Fatalf("SeekStepLargeDuration = %v, want %v", got, want)
 	}
 }
+
+func TestReplayStepDuration(t *testing.T) {
+	cfg := defaultConfig()
+	if got, want := cfg.ReplayStepDuration(), 10*time.Second; got != want {
+		t.Fatalf("default ReplayStepDuration = %v, want %v", got, want)
+	}
+	cfg.ReplayStep = 500
+	cfg.clamp()
+	if cfg.ReplayStep != 120 {
+		t.Fatalf("ReplayStep clamped = %d, want 120", cfg.ReplayStep)
+	}
+}
+
+func TestSeekStepDuration(t *testing.T) {
+	cfg := defaultConfig()
+	if got, want := cfg.SeekStepDuration(), 5*time.Second; got != want {
+		t.Fatalf("default SeekStepDuration = %v, want %v", got, want)
+	}
+	cfg.SeekStep = 0
+	cfg.clamp()
+	if cfg.SeekStep != 1 {
+		t.Fatalf("SeekStep clamped = %d, want 1", cfg.SeekStep)
+	}
+}
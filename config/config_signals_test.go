@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSignals(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Remote.SignalUSR1 != "toggle" || cfg.Remote.SignalUSR2 != "next" {
+		t.Fatalf("default signals = %q, %q, want toggle, next", cfg.Remote.SignalUSR1, cfg.Remote.SignalUSR2)
+	}
+
+	path := filepath.Join(os.Getenv("HOME"), ".config", "cliamp", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	data := `[remote]
+signal_usr1 = "prev"
+signal_usr2 = "dance"
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Remote.SignalUSR1 != "prev" || cfg.Remote.SignalUSR2 != "next" {
+		t.Errorf("signals = %q, %q, want prev, next", cfg.Remote.SignalUSR1, cfg.Remote.SignalUSR2)
+	}
+}
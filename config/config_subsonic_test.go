@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSubsonicSection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(os.Getenv("HOME"), ".config", "cliamp", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	data := `[subsonic]
+url = "https://airsonic.example.com"
+user = "alice"
+password = "secret"
+legacy_auth = true
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Navidrome.IsSet() || cfg.Navidrome.URL != "https://airsonic.example.com" {
+		t.Errorf("Navidrome = %+v, want credentials from [subsonic]", cfg.Navidrome)
+	}
+	if !cfg.Navidrome.LegacyAuth {
+		t.Error("legacy_auth = true not applied")
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadWatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(os.Getenv("HOME"), ".config", "cliamp", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	data := `watch = ["~/Music/Incoming", "/srv/music"]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if want := []string{"~/Music/Incoming", "/srv/music"}; !slices.Equal(cfg.Watch, want) {
+		t.Fatalf("Watch = %q, want %q", cfg.Watch, want)
+	}
+
+	_, ov, _, err := ParseFlags([]string{"--watch", "/a", "--watch", "/b"})
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	ov.Apply(&cfg)
+	if want := []string{"/a", "/b"}; !slices.Equal(cfg.Watch, want) {
+		t.Fatalf("Watch after --watch = %q, want %q", cfg.Watch, want)
+	}
+}
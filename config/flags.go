@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	"cliamp/playlist"
 )
 
 // Overrides holds CLI flag values. Nil pointers mean "not set".
@@ -14,14 +18,36 @@ type Overrides struct {
 	Mono            *bool
 	Provider        *string
 	Theme           *string
+	Palette         *string
 	Visualizer      *string
 	EQPreset        *string
 	SampleRate      *int
 	BufferMs        *int
 	ResampleQuality *int
 	BitDepth        *int
+	DSPBypass       *bool
 	Play            *bool
+	Record          *string        // WAV path to tee the output into; session only
+	HTTP            *string        // listen address for the status API and event stream
+	MPD             *string        // listen address for the MPD protocol server
+	DLNA            *string        // listen address for the DLNA media renderer
+	Icecast         *string        // Icecast mountpoint URL to broadcast to
+	Watch           []string       // directories to watch for new files; replaces the config list
+	Exclude         []string       // patterns left out of folder scans; added to the config list
+	Alarm           *string        // "HH:MM" at which to start playing; session only
+	StopAt          *string        // "HH:MM" at which to stop playing; session only
+	PProf           *string        // listen address for runtime profiles; session only
+	LogFile         *string        // file to append the diagnostic log to
+	LogLevel        *string        // "debug", "info", "warn" or "error"
+	Start           *time.Duration // play the named files from here; session only
+	End             *time.Duration // and stop them here; session only
+	Seek            *time.Duration // begin the first track here; session only
+	Loop            *int           // play the playlist this many times; session only
+	ExitAfter       *bool          // quit when the playlist ends; session only
+	StdinPaths      *byte          // separator of playlist paths read from stdin, '\n' or 0
 	Compact         *bool
+	ASCII           *bool
+	Accessible      *bool
 }
 
 // Apply merges non-nil overrides into cfg and clamps the result.
@@ -31,6 +57,7 @@ func (o Overrides) Apply(cfg *Config) {
 	}
 	if o.Shuffle != nil {
 		cfg.Shuffle = *o.Shuffle
+		cfg.ShuffleAlbums = cfg.ShuffleAlbums && *o.Shuffle
 	}
 	if o.Repeat != nil {
 		cfg.Repeat = *o.Repeat
@@ -44,6 +71,9 @@ func (o Overrides) Apply(cfg *Config) {
 	if o.Theme != nil {
 		cfg.Theme = *o.Theme
 	}
+	if o.Palette != nil {
+		cfg.Palette = strings.ToLower(*o.Palette)
+	}
 	if o.Visualizer != nil {
 		cfg.Visualizer = *o.Visualizer
 	}
@@ -62,15 +92,52 @@ func (o Overrides) Apply(cfg *Config) {
 	if o.BitDepth != nil {
 		cfg.BitDepth = *o.BitDepth
 	}
+	if o.DSPBypass != nil {
+		cfg.DSPBypass = *o.DSPBypass
+	}
 	if o.Compact != nil {
 		cfg.Compact = *o.Compact
 	}
+	if o.ASCII != nil {
+		if *o.ASCII {
+			cfg.ASCII = "true"
+		} else {
+			cfg.ASCII = "false"
+		}
+	}
+	if o.Accessible != nil {
+		cfg.Accessible = *o.Accessible
+	}
+	if o.HTTP != nil {
+		cfg.Remote.HTTP = *o.HTTP
+	}
+	if o.MPD != nil {
+		cfg.Remote.MPD = *o.MPD
+	}
+	if o.DLNA != nil {
+		cfg.Remote.DLNA = *o.DLNA
+	}
+	if o.Icecast != nil {
+		cfg.Icecast.URL = *o.Icecast
+	}
+	if len(o.Watch) > 0 {
+		cfg.Watch = o.Watch
+	}
+	cfg.Exclude = append(cfg.Exclude, o.Exclude...)
+	if o.LogFile != nil {
+		cfg.LogFile = *o.LogFile
+	}
+	if o.LogLevel != nil {
+		cfg.LogLevel = *o.LogLevel
+	}
 	cfg.clamp()
 }
 
 // ParseFlags parses CLI arguments into an action string, overrides, and
 // positional args. It handles flags intermixed with positional arguments
 // and correctly treats negative numbers as flag values rather than flags.
+// A long flag's value may follow it as the next argument or after "=", as
+// in "--repeat=all" or "--volume=-6".
 //
 // Returned action is one of "help", "version", "upgrade", or "" (run).
 func ParseFlags(args []string) (action string, ov Overrides, positional []string, err error) {
@@ -78,13 +145,21 @@ func ParseFlags(args []string) (action string, ov Overrides, positional []string
 	for i < len(args) {
 		arg := args[i]
 
-		// Non-flag argument → positional.
-		if !strings.HasPrefix(arg, "-") {
+		// Non-flag argument → positional. A lone "-" means stdin.
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
 			positional = append(positional, arg)
 			i++
 			continue
 		}
 
+		// "--flag=value" is read as "--flag value"; the value must then
+		// be consumed by the flag.
+		inline := -1
+		if name, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(arg, "--") {
+			args = slices.Concat(args[:i], []string{name, value}, args[i+1:])
+			arg, inline = name, i+1
+		}
+
 		switch arg {
 		// Action flags — return immediately.
 		case "--help", "-h":
@@ -97,14 +172,34 @@ func ParseFlags(args []string) (action string, ov Overrides, positional []string
 		// Boolean flags.
 		case "--shuffle":
 			ov.Shuffle = ptrBool(true)
+		case "--no-shuffle":
+			ov.Shuffle = ptrBool(false)
 		case "--mono":
 			ov.Mono = ptrBool(true)
 		case "--no-mono":
 			ov.Mono = ptrBool(false)
+		case "--stdin":
+			if ov.StdinPaths == nil {
+				sep := byte('\n')
+				ov.StdinPaths = &sep
+			}
+		case "-0", "--null":
+			sep := byte(0)
+			ov.StdinPaths = &sep
+		case "--exit-after":
+			ov.ExitAfter = ptrBool(true)
 		case "--auto-play":
 			ov.Play = ptrBool(true)
 		case "--compact":
 			ov.Compact = ptrBool(true)
+		case "--ascii":
+			ov.ASCII = ptrBool(true)
+		case "--no-ascii":
+			ov.ASCII = ptrBool(false)
+		case "--accessible":
+			ov.Accessible = ptrBool(true)
+		case "--dsp-bypass", "--bit-perfect": // --bit-perfect is the old name
+			ov.DSPBypass = ptrBool(true)
 		// Key-value flags.
 		case "--provider":
 			v, e := requireNextString(args, &i, arg)
@@ -113,9 +208,9 @@ func ParseFlags(args []string) (action string, ov Overrides, positional []string
 			}
 			v = strings.ToLower(v)
 			switch v {
-			case "radio", "navidrome", "spotify", "yt", "youtube", "ytmusic":
+			case "radio", "podcasts", "navidrome", "spotify", "yt", "youtube", "ytmusic":
 			default:
-				return "", ov, nil, fmt.Errorf("flag --provider value must be radio, navidrome, spotify, yt, youtube, or ytmusic (got %q)", v)
+				return "", ov, nil, fmt.Errorf("flag --provider value must be radio, podcasts, navidrome, spotify, yt, youtube, or ytmusic (got %q)", v)
 			}
 			ov.Provider = &v
 		case "--volume":
@@ -142,6 +237,12 @@ func ParseFlags(args []string) (action string, ov Overrides, positional []string
 				return "", ov, nil, e
 			}
 			ov.Theme = &v
+		case "--palette":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.Palette = &v
 		case "--visualizer":
 			v, e := requireNextString(args, &i, arg)
 			if e != nil {
@@ -166,12 +267,132 @@ func ParseFlags(args []string) (action string, ov Overrides, positional []string
 				return "", ov, nil, e
 			}
 			ov.BufferMs = &v
+		case "--buffer":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ms, e := parseBufferMs(v)
+			if e != nil {
+				return "", ov, nil, fmt.Errorf("flag --buffer: %w", e)
+			}
+			ov.BufferMs = &ms
 		case "--resample-quality":
 			v, e := requireNextInt(args, &i, arg)
 			if e != nil {
 				return "", ov, nil, e
 			}
 			ov.ResampleQuality = &v
+		case "--record":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.Record = &v
+		case "--http":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.HTTP = &v
+		case "--mpd":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.MPD = &v
+		case "--dlna":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.DLNA = &v
+		case "--icecast":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.Icecast = &v
+		case "--watch":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.Watch = append(ov.Watch, v)
+		case "--exclude":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.Exclude = append(ov.Exclude, v)
+		case "--alarm":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			if _, e := time.Parse("15:04", v); e != nil {
+				return "", ov, nil, fmt.Errorf("flag --alarm value must be a 24-hour time like 07:30 (got %q)", v)
+			}
+			ov.Alarm = &v
+		case "--start", "--end", "--seek":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			d, e := playlist.ParseClock(v)
+			if e != nil {
+				return "", ov, nil, fmt.Errorf("flag %s: %w", arg, e)
+			}
+			switch arg {
+			case "--start":
+				ov.Start = &d
+			case "--end":
+				ov.End = &d
+			default:
+				ov.Seek = &d
+			}
+		case "--loop":
+			v, e := requireNextInt(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			if v < 1 {
+				return "", ov, nil, fmt.Errorf("flag --loop must be at least 1 (got %d)", v)
+			}
+			ov.Loop = &v
+		case "--stop-at":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			if _, e := time.Parse("15:04", v); e != nil {
+				return "", ov, nil, fmt.Errorf("flag --stop-at value must be a 24-hour time like 23:00 (got %q)", v)
+			}
+			ov.StopAt = &v
+		case "--log-file":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.LogFile = &v
+		case "--log-level":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			v = strings.ToLower(v)
+			switch v {
+			case "debug", "info", "warn", "error":
+			default:
+				return "", ov, nil, fmt.Errorf("flag --log-level value must be debug, info, warn, or error (got %q)", v)
+			}
+			ov.LogLevel = &v
+		case "--pprof":
+			v, e := requireNextString(args, &i, arg)
+			if e != nil {
+				return "", ov, nil, e
+			}
+			ov.PProf = &v
 		case "--bit-depth":
 			v, e := requireNextInt(args, &i, arg)
 			if e != nil {
@@ -182,8 +403,17 @@ func ParseFlags(args []string) (action string, ov Overrides, positional []string
 		default:
 			return "", ov, nil, fmt.Errorf("unknown flag: %s", arg)
 		}
+		if i < inline {
+			return "", ov, nil, fmt.Errorf("flag %s does not take a value", arg)
+		}
 		i++
 	}
+	if ov.StdinPaths != nil && slices.Contains(positional, "-") {
+		return "", ov, nil, fmt.Errorf("flag --stdin reads paths from stdin, so it cannot also play audio piped in with -")
+	}
+	if ov.Start != nil && ov.End != nil && *ov.End <= *ov.Start {
+		return "", ov, nil, fmt.Errorf("flag --end must be after --start")
+	}
 	return "", ov, positional, nil
 }
 
@@ -223,4 +453,17 @@ func requireNextInt(args []string, idx *int, flag string) (int, error) {
 	return v, nil
 }
 
+// parseBufferMs accepts a plain millisecond count ("50") or a Go duration
+// ("50ms", "0.2s") and returns the buffer length in milliseconds.
+func parseBufferMs(v string) (int, error) {
+	if n, err := strconv.Atoi(v); err == nil {
+		return n, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid buffer length %q (use e.g. 50 or 50ms)", v)
+	}
+	return int(d.Milliseconds()), nil
+}
+
 func ptrBool(v bool) *bool { return &v }
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFlagsBuffer(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"--buffer", "30"}, 30},
+		{[]string{"--buffer", "50ms"}, 50},
+		{[]string{"--buffer", "0.2s"}, 200},
+		{[]string{"--buffer-ms", "80"}, 80},
+	} {
+		_, ov, _, err := ParseFlags(tc.args)
+		if err != nil {
+			t.Fatalf("ParseFlags(%v): %v", tc.args, err)
+		}
+		if ov.BufferMs == nil || *ov.BufferMs != tc.want {
+			t.Fatalf("ParseFlags(%v) BufferMs = %v, want %d", tc.args, ov.BufferMs, tc.want)
+		}
+	}
+
+	if _, _, _, err := ParseFlags([]string{"--buffer", "fast"}); err == nil {
+		t.Fatal("ParseFlags(--buffer fast) succeeded, want error")
+	}
+}
+
+func TestParseFlagsStdin(t *testing.T) {
+	_, _, positional, err := ParseFlags([]string{"--auto-play", "-"})
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if len(positional) != 1 || positional[0] != "-" {
+		t.Fatalf("positional = %v, want [-]", positional)
+	}
+}
+
+func TestParseFlagsAlarm(t *testing.T) {
+	_, ov, _, err := ParseFlags([]string{"--alarm", "07:30"})
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if ov.Alarm == nil || *ov.Alarm != "07:30" {
+		t.Fatalf("Alarm = %v, want 07:30", ov.Alarm)
+	}
+
+	for _, v := range []string{"7am", "25:00", "07:60"} {
+		if _, _, _, err := ParseFlags([]string{"--alarm", v}); err == nil {
+			t.Fatalf("ParseFlags(--alarm %s) succeeded, want error", v)
+		}
+	}
+}
+
+func TestParseFlagsInlineValues(t *testing.T) {
+	_, ov, positional, err := ParseFlags([]string{"--shuffle", "--repeat=all", "--volume=-6", "--eq-preset=rock", "a=b.mp3"})
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if ov.Shuffle == nil || !*ov.Shuffle {
+		t.Errorf("Shuffle = %v, want true", ov.Shuffle)
+	}
+	if ov.Repeat == nil || *ov.Repeat != "all" {
+		t.Errorf("Repeat = %v, want all", ov.Repeat)
+	}
+	if ov.Volume == nil || *ov.Volume != -6 {
+		t.Errorf("Volume = %v, want -6", ov.Volume)
+	}
+	if ov.EQPreset == nil || *ov.EQPreset != "rock" {
+		t.Errorf("EQPreset = %v, want rock", ov.EQPreset)
+	}
+	if len(positional) != 1 || positional[0] != "a=b.mp3" {
+		t.Errorf("positional = %v, want [a=b.mp3]", positional)
+	}
+
+	if _, _, _, err := ParseFlags([]string{"--shuffle=yes"}); err == nil {
+		t.Error("ParseFlags(--shuffle=yes) succeeded, want error")
+	}
+
+	cfg := Config{Shuffle: true, ShuffleAlbums: true}
+	_, ov, _, _ = ParseFlags([]string{"--no-shuffle"})
+	ov.Apply(&cfg)
+	if cfg.Shuffle || cfg.ShuffleAlbums {
+		t.Errorf("after --no-shuffle Shuffle = %v, ShuffleAlbums = %v, want both false", cfg.Shuffle, cfg.ShuffleAlbums)
+	}
+}
+
+func TestParseFlagsSeek(t *testing.T) {
+	_, ov, _, err := ParseFlags([]string{"--seek", "12:34"})
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if want := 12*time.Minute + 34*time.Second; ov.Seek == nil || *ov.Seek != want {
+		t.Fatalf("Seek = %v, want %v", ov.Seek, want)
+	}
+	if _, _, _, err := ParseFlags([]string{"--seek", "12:99"}); err == nil {
+		t.Fatal("ParseFlags(--seek 12:99) succeeded, want error")
+	}
+}
+
+func TestParseFlagsLogLevel(t *testing.T) {
+	_, ov, _, err := ParseFlags([]string{"--log-file", "/tmp/cliamp.log", "--log-level", "DEBUG"})
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if ov.LogLevel == nil || *ov.LogLevel != "debug" {
+		t.Fatalf("LogLevel = %v, want debug", ov.LogLevel)
+	}
+	if _, _, _, err := ParseFlags([]string{"--log-level", "verbose"}); err == nil {
+		t.Fatal("ParseFlags(--log-level verbose) succeeded, want error")
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cliamp/internal/fingerprint"
+	"cliamp/resolve"
+)
+
+const dupesUsage = "usage: cliamp dupes [--threshold <0-1>] <file|folder>..."
+
+// runDupes implements `cliamp dupes`: it fingerprints local files and
+// lists the groups that sound like the same recording, whatever their
+// format, bitrate or tags. Only files of about the same length are
+// compared, so a live take and the studio one stay apart.
+func runDupes(args []string, w io.Writer) error {
+	threshold := 0.8
+	var paths []string
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--threshold" && i+1 < len(args):
+			i++
+			v, err := strconv.ParseFloat(args[i], 64)
+			if err != nil || v <= 0.5 || v > 1 {
+				return fmt.Errorf("dupes: --threshold must be above 0.5 and at most 1 (got %q)", args[i])
+			}
+			threshold = v
+		case len(a) > 1 && a[0] == '-':
+			return errors.New(dupesUsage)
+		default:
+			paths = append(paths, a)
+		}
+	}
+	if len(paths) == 0 {
+		return errors.New(dupesUsage)
+	}
+	resolved, err := resolve.Args(paths)
+	if err != nil {
+		return err
+	}
+	var files []string
+	seen := make(map[string]bool)
+	for _, t := range resolved.Tracks {
+		if !t.Stream && !t.Ranged() && !seen[t.Path] {
+			seen[t.Path] = true
+			files = append(files, t.Path)
+		}
+	}
+	if len(files) < 2 {
+		return errors.New("dupes: need at least two local audio files")
+	}
+
+	prints := make([]fingerprint.Print, len(files))
+	errs := make([]error, len(files))
+	ch := make(chan int)
+	var wg sync.WaitGroup
+	for range min(len(files), runtime.NumCPU()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				prints[i], errs[i] = fingerprint.Compute(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	// Compare each file with those of about its length, shortest first,
+	// and join the alike into groups.
+	var order []int
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(w, "skipped %s: %v\n", files[i], err)
+			continue
+		}
+		order = append(order, i)
+	}
+	slices.SortFunc(order, func(a, b int) int { return cmp.Compare(prints[a].Duration, prints[b].Duration) })
+	group := make([]int, len(files))
+	for i := range group {
+		group[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if group[i] != i {
+			group[i] = root(group[i])
+		}
+		return group[i]
+	}
+	alike := make(map[int]float64) // lowest similarity within each group
+	for n, i := range order {
+		for _, j := range order[n+1:] {
+			if prints[j].Duration-prints[i].Duration > max(3*time.Second, prints[i].Duration/50) {
+				break
+			}
+			if s := fingerprint.Similarity(prints[i], prints[j]); s >= threshold {
+				ri, rj := root(i), root(j)
+				low := min(s, lowest(alike, ri), lowest(alike, rj))
+				delete(alike, ri)
+				delete(alike, rj)
+				group[rj] = ri
+				alike[ri] = low
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for _, i := range order {
+		groups[root(i)] = append(groups[root(i)], i)
+	}
+	var roots []int
+	for r, g := range groups {
+		if len(g) > 1 {
+			roots = append(roots, r)
+		}
+	}
+	if len(roots) == 0 {
+		fmt.Fprintf(w, "no duplicates among %d files\n", len(order))
+		return nil
+	}
+	slices.SortFunc(roots, func(a, b int) int { return strings.Compare(files[groups[a][0]], files[groups[b][0]]) })
+	for _, r := range roots {
+		g := groups[r]
+		slices.SortFunc(g, func(a, b int) int { return strings.Compare(files[a], files[b]) })
+		fmt.Fprintf(w, "%d copies, %.0f%% alike:\n", len(g), alike[r]*100)
+		for _, i := range g {
+			fmt.Fprintf(w, "  %s  (%s)\n", files[i], describeFile(files[i], prints[i].Duration))
+		}
+	}
+	fmt.Fprintf(w, "%d groups of duplicates among %d files\n", len(roots), len(order))
+	return nil
+}
+
+// lowest returns the lowest similarity recorded for group r, 1 for a
+// file on its own.
+func lowest(alike map[int]float64, r int) float64 {
+	if s, ok := alike[r]; ok {
+		return s
+	}
+	return 1
+}
+
+// describeFile says what tells copies apart: format, length and size.
+func describeFile(path string, d time.Duration) string {
+	desc := strings.ToUpper(strings.TrimPrefix(filepath.Ext(path), "."))
+	desc += fmt.Sprintf(", %d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+	if info, err := os.Stat(path); err == nil {
+		desc += fmt.Sprintf(", %.1f MB", float64(info.Size())/(1<<20))
+	}
+	return desc
+}
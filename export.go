@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"cliamp/external/local"
+	"cliamp/playlist"
+)
+
+const exportUsage = "usage: cliamp export <playlist> [--paths absolute|relative|uri] [--separator unix|windows] [-o <file.m3u>]"
+
+// runExport implements `cliamp export`: a local playlist written as M3U,
+// to a file with -o or else to w. Relative paths start from the file's
+// folder, or from the working directory when writing to w.
+func runExport(args []string, w io.Writer) error {
+	var name, out string
+	var opt playlist.M3UOptions
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--paths" || a == "--separator" || a == "-o" {
+			if i+1 >= len(args) {
+				return errors.New(exportUsage)
+			}
+			i++
+		}
+		switch a {
+		case "--paths":
+			style, err := playlist.ParsePathStyle(args[i])
+			if err != nil {
+				return fmt.Errorf("export: %w", err)
+			}
+			opt.Paths = style
+		case "--separator":
+			switch args[i] {
+			case "unix":
+				opt.Separator = '/'
+			case "windows":
+				opt.Separator = '\\'
+			default:
+				return fmt.Errorf("export: invalid separator %q (want unix or windows)", args[i])
+			}
+		case "-o":
+			out = args[i]
+		default:
+			if name != "" || len(a) > 1 && a[0] == '-' {
+				return errors.New(exportUsage)
+			}
+			name = a
+		}
+	}
+	if name == "" {
+		return errors.New(exportUsage)
+	}
+
+	prov := local.New()
+	if prov == nil || !prov.HasPlaylist(name) {
+		return fmt.Errorf("export: no playlist %q", name)
+	}
+	tracks, err := prov.Tracks(name)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	base := "."
+	if out != "" {
+		base = filepath.Dir(out)
+	}
+	if opt.Base, err = filepath.Abs(base); err != nil {
+		return err
+	}
+	if out == "" {
+		return playlist.WriteM3U(w, tracks, opt)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := playlist.WriteM3U(f, tracks, opt); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,168 @@
+// Package acoustid identifies recordings by their Chromaprint fingerprint
+// through the AcoustID web service, which links fingerprints to
+// MusicBrainz recordings.
+package acoustid
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultURL is the AcoustID API root.
+const DefaultURL = "https://api.acoustid.org"
+
+// apiClient is used for all AcoustID API calls with a finite timeout.
+var apiClient = &http.Client{Timeout: 15 * time.Second}
+
+// Client looks recordings up with one application API key.
+type Client struct {
+	baseURL string
+	key     string // application key from acoustid.org/new-application
+}
+
+// NewClient returns a Client using the application API key.
+func NewClient(key string) *Client {
+	return &Client{baseURL: DefaultURL, key: key}
+}
+
+// Match is a recording a fingerprint may belong to.
+type Match struct {
+	Score  float64 // how well the fingerprint matched, 0–1
+	Title  string
+	Artist string
+	Album  string // "" when the recording is on no release
+	Year   int    // of the album's first release; 0 when unknown
+}
+
+// Identify fingerprints the file at path and returns the recordings it
+// may be, best first. Fingerprinting runs fpcalc, Chromaprint's
+// command-line tool, which must be installed.
+func (c *Client) Identify(path string) ([]Match, error) {
+	fp, secs, err := Fingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Lookup(fp, secs)
+}
+
+// Fingerprint runs fpcalc on the file at path, returning the fingerprint
+// and the file's length in seconds.
+func Fingerprint(path string) (string, int, error) {
+	if _, err := exec.LookPath("fpcalc"); err != nil {
+		return "", 0, errors.New("identifying tracks requires fpcalc (Chromaprint)")
+	}
+	out, err := exec.Command("fpcalc", "-json", path).Output()
+	if err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+			return "", 0, fmt.Errorf("fpcalc: %s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", 0, fmt.Errorf("fpcalc: %w", err)
+	}
+	var res struct {
+		Duration    float64 `json:"duration"`
+		Fingerprint string  `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil || res.Fingerprint == "" {
+		return "", 0, errors.New("fpcalc: no fingerprint")
+	}
+	return res.Fingerprint, int(res.Duration), nil
+}
+
+type lookupResponse struct {
+	Status string `json:"status"`
+	Error  struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Results []struct {
+		Score      float64 `json:"score"`
+		Recordings []struct {
+			Title   string `json:"title"`
+			Artists []struct {
+				Name       string `json:"name"`
+				JoinPhrase string `json:"joinphrase"`
+			} `json:"artists"`
+			ReleaseGroups []struct {
+				Title    string `json:"title"`
+				Type     string `json:"type"`
+				Releases []struct {
+					Date struct {
+						Year int `json:"year"`
+					} `json:"date"`
+				} `json:"releases"`
+			} `json:"releasegroups"`
+		} `json:"recordings"`
+	} `json:"results"`
+}
+
+// Lookup asks AcoustID which recordings a fingerprint belongs to, best
+// first. Recordings are listed once, on their album rather than on a
+// single or compilation when there is one.
+func (c *Client) Lookup(fingerprint string, secs int) ([]Match, error) {
+	form := url.Values{
+		"client":      {c.key},
+		"format":      {"json"},
+		"meta":        {"recordings releasegroups releases"},
+		"duration":    {strconv.Itoa(secs)},
+		"fingerprint": {fingerprint},
+	}
+	resp, err := apiClient.PostForm(c.baseURL+"/v2/lookup", form)
+	if err != nil {
+		return nil, fmt.Errorf("acoustid: %w", err)
+	}
+	defer resp.Body.Close()
+	var res lookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("acoustid: %s", resp.Status)
+	}
+	if res.Status != "ok" {
+		return nil, fmt.Errorf("acoustid: %s", res.Error.Message)
+	}
+
+	var matches []Match
+	seen := make(map[string]bool)
+	for _, r := range res.Results {
+		for _, rec := range r.Recordings {
+			if rec.Title == "" {
+				continue
+			}
+			var artist strings.Builder
+			for _, a := range rec.Artists {
+				artist.WriteString(a.Name + a.JoinPhrase)
+			}
+			m := Match{Score: r.Score, Title: rec.Title, Artist: artist.String()}
+			rank := 0
+			for _, g := range rec.ReleaseGroups {
+				gr := 1
+				if g.Type == "Album" {
+					gr = 2
+				}
+				if gr <= rank {
+					continue
+				}
+				rank, m.Album, m.Year = gr, g.Title, 0
+				for _, rel := range g.Releases {
+					if y := rel.Date.Year; y > 0 && (m.Year == 0 || y < m.Year) {
+						m.Year = y
+					}
+				}
+			}
+			key := strings.ToLower(m.Artist + "\x00" + m.Title + "\x00" + m.Album)
+			if !seen[key] {
+				seen[key] = true
+				matches = append(matches, m)
+			}
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b Match) int { return cmp.Compare(b.Score, a.Score) })
+	return matches, nil
+}
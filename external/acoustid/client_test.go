@@ -0,0 +1,57 @@
+package acoustid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/lookup" {
+			t.Errorf("path = %q, want /v2/lookup", r.URL.Path)
+		}
+		if r.FormValue("client") != "k" || r.FormValue("fingerprint") != "AQAA" || r.FormValue("duration") != "241" {
+			t.Errorf("form = %v", r.Form)
+		}
+		w.Write([]byte(`{"status": "ok", "results": [
+			{"score": 0.6, "recordings": [{"title": "Other", "artists": [{"name": "B"}]}]},
+			{"score": 0.95, "recordings": [
+				{"title": "Song", "artists": [{"name": "A", "joinphrase": " feat. "}, {"name": "C"}],
+				 "releasegroups": [
+					{"title": "Hits", "type": "Compilation", "releases": [{"date": {"year": 2001}}]},
+					{"title": "Record", "type": "Album", "releases": [{"date": {"year": 1999}}, {"date": {"year": 1998}}]}]},
+				{"title": "Song", "artists": [{"name": "A", "joinphrase": " feat. "}, {"name": "C"}],
+				 "releasegroups": [{"title": "Record", "type": "Album"}]}]}]}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{baseURL: srv.URL, key: "k"}
+	got, err := c.Lookup("AQAA", 241)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Lookup = %+v, want 2 matches", got)
+	}
+	want := Match{Score: 0.95, Title: "Song", Artist: "A feat. C", Album: "Record", Year: 1998}
+	if got[0] != want {
+		t.Errorf("best = %+v, want %+v", got[0], want)
+	}
+	if got[1].Title != "Other" || got[1].Album != "" {
+		t.Errorf("second = %+v", got[1])
+	}
+}
+
+func TestLookup_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status": "error", "error": {"code": 4, "message": "invalid API key"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := (&Client{baseURL: srv.URL, key: "bad"}).Lookup("AQAA", 1)
+	if err == nil || err.Error() != "acoustid: invalid API key" {
+		t.Errorf("err = %v, want the service's message", err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package listenbrainz submits listens to ListenBrainz (or a compatible
+// server) using a user token.
+package listenbrainz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cliamp/playlist"
+)
+
+// DefaultURL is the public ListenBrainz API root.
+const DefaultURL = "https://api.listenbrainz.org"
+
+// apiClient is used for all ListenBrainz API calls with a finite timeout.
+var apiClient = &http.Client{Timeout: 15 * time.Second}
+
+// Client submits listens for one user.
+type Client struct {
+	baseURL string
+	token   string // user token from listenbrainz.org/settings
+}
+
+// NewClient returns a Client for the given API root and user token. An empty
+// baseURL selects DefaultURL.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), token: token}
+}
+
+type submission struct {
+	ListenType string   `json:"listen_type"`
+	Payload    []listen `json:"payload"`
+}
+
+type listen struct {
+	ListenedAt    int64         `json:"listened_at,omitempty"`
+	TrackMetadata trackMetadata `json:"track_metadata"`
+}
+
+type trackMetadata struct {
+	ArtistName     string         `json:"artist_name"`
+	TrackName      string         `json:"track_name"`
+	ReleaseName    string         `json:"release_name,omitempty"`
+	AdditionalInfo additionalInfo `json:"additional_info"`
+}
+
+type additionalInfo struct {
+	DurationMs       int    `json:"duration_ms,omitempty"`
+	TrackNumber      int    `json:"tracknumber,omitempty"`
+	MediaPlayer      string `json:"media_player"`
+	SubmissionClient string `json:"submission_client"`
+}
+
+// NowPlaying marks track as currently playing. ListenBrainz does not store
+// these as listens.
+func (c *Client) NowPlaying(track playlist.Track) error {
+	if !submittable(track) {
+		return nil
+	}
+	return c.submit(submission{
+		ListenType: "playing_now",
+		Payload:    []listen{{TrackMetadata: metadata(track)}},
+	})
+}
+
+// Scrobble records a completed listen that started at listenedAt.
+func (c *Client) Scrobble(track playlist.Track, listenedAt time.Time) error {
+	if !submittable(track) {
+		return nil
+	}
+	return c.submit(submission{
+		ListenType: "single",
+		Payload:    []listen{{ListenedAt: listenedAt.Unix(), TrackMetadata: metadata(track)}},
+	})
+}
+
+// submittable reports whether track has the artist and title ListenBrainz
+// requires.
+func submittable(track playlist.Track) bool {
+	return track.Artist != "" && track.Title != ""
+}
+
+func metadata(track playlist.Track) trackMetadata {
+	return trackMetadata{
+		ArtistName:  track.Artist,
+		TrackName:   track.Title,
+		ReleaseName: track.Album,
+		AdditionalInfo: additionalInfo{
+			DurationMs:       track.DurationSecs * 1000,
+			TrackNumber:      track.TrackNumber,
+			MediaPlayer:      "cliamp",
+			SubmissionClient: "cliamp",
+		},
+	}
+}
+
+func (c *Client) submit(s submission) error {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/1/submit-listens", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("listenbrainz: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{Code: resp.StatusCode, Status: resp.Status, Msg: strings.TrimSpace(string(msg))}
+	}
+	return nil
+}
+
+// StatusError is a submission the server answered with an error status.
+type StatusError struct {
+	Code   int
+	Status string
+	Msg    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("listenbrainz: %s: %s", e.Status, e.Msg)
+}
+
+// Rejected reports whether resending the same submission cannot succeed:
+// a client error such as a bad token, as opposed to rate limiting or a
+// server fault.
+func (e *StatusError) Rejected() bool {
+	return e.Code >= 400 && e.Code < 500 && e.Code != http.StatusTooManyRequests
+}
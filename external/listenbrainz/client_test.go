@@ -0,0 +1,96 @@
+package listenbrainz
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cliamp/playlist"
+)
+
+func TestScrobble_SendsSingleListen(t *testing.T) {
+	var got submission
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/submit-listens" {
+			t.Errorf("path = %q, want /1/submit-listens", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Token test-token" {
+			t.Errorf("Authorization = %q, want %q", auth, "Token test-token")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	at := time.Unix(1700000000, 0)
+	track := playlist.Track{Title: "Song", Artist: "Band", Album: "Record", DurationSecs: 200, TrackNumber: 3}
+	if err := NewClient(srv.URL, "test-token").Scrobble(track, at); err != nil {
+		t.Fatalf("Scrobble() unexpected error: %v", err)
+	}
+
+	if got.ListenType != "single" || len(got.Payload) != 1 {
+		t.Fatalf("submission = %+v, want one single listen", got)
+	}
+	l := got.Payload[0]
+	if l.ListenedAt != at.Unix() {
+		t.Errorf("listened_at = %d, want %d", l.ListenedAt, at.Unix())
+	}
+	md := l.TrackMetadata
+	if md.ArtistName != "Band" || md.TrackName != "Song" || md.ReleaseName != "Record" {
+		t.Errorf("track_metadata = %+v", md)
+	}
+	if md.AdditionalInfo.DurationMs != 200000 || md.AdditionalInfo.TrackNumber != 3 {
+		t.Errorf("additional_info = %+v", md.AdditionalInfo)
+	}
+}
+
+func TestNowPlaying_OmitsListenedAt(t *testing.T) {
+	var raw map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&raw)
+	}))
+	defer srv.Close()
+
+	if err := NewClient(srv.URL, "t").NowPlaying(playlist.Track{Title: "Song", Artist: "Band"}); err != nil {
+		t.Fatalf("NowPlaying() unexpected error: %v", err)
+	}
+	if raw["listen_type"] != "playing_now" {
+		t.Fatalf("listen_type = %v, want playing_now", raw["listen_type"])
+	}
+	payload := raw["payload"].([]any)[0].(map[string]any)
+	if _, ok := payload["listened_at"]; ok {
+		t.Error("playing_now payload must not include listened_at")
+	}
+}
+
+func TestScrobble_SkipsTracksWithoutArtist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request for a track without artist")
+	}))
+	defer srv.Close()
+
+	if err := NewClient(srv.URL, "t").Scrobble(playlist.Track{Title: "Song"}, time.Now()); err != nil {
+		t.Fatalf("Scrobble() unexpected error: %v", err)
+	}
+}
+
+func TestScrobble_ReportsHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Invalid authorization token."}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL, "bad").Scrobble(playlist.Track{Title: "Song", Artist: "Band"}, time.Now())
+	if err == nil {
+		t.Fatal("Scrobble() expected error on 401, got nil")
+	}
+	var se *StatusError
+	if !errors.As(err, &se) || !se.Rejected() {
+		t.Errorf("error %v does not report a rejected submission", err)
+	}
+}
@@ -113,8 +113,10 @@ func (p *Provider) AddTrack(playlistName string, track playlist.Track) error {
 	return nil
 }
 
-// savePlaylist overwrites the named playlist with the given tracks.
-func (p *Provider) savePlaylist(name string, tracks []playlist.Track) error {
+// SavePlaylist overwrites the named playlist with the given tracks. The
+// new file is written alongside and renamed over the old one, so a failed
+// write never leaves the playlist half saved.
+func (p *Provider) SavePlaylist(name string, tracks []playlist.Track) error {
 	if err := os.MkdirAll(p.dir, 0o755); err != nil {
 		return err
 	}
@@ -123,11 +125,12 @@ func (p *Provider) savePlaylist(name string, tracks []playlist.Track) error {
 	if err != nil {
 		return err
 	}
-	f, err := os.Create(path)
+	f, err := os.CreateTemp(p.dir, name+"-*.tmp")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer os.Remove(f.Name())
+	f.Chmod(0o644)
 
 	for i, t := range tracks {
 		if i > 0 {
@@ -135,7 +138,59 @@ func (p *Provider) savePlaylist(name string, tracks []playlist.Track) error {
 		}
 		writeTrack(f, t)
 	}
-	return nil
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// RenamePaths points the playlists at files that have moved, given the
+// absolute path each had and the one it has now, and returns how many
+// playlists changed.
+func (p *Provider) RenamePaths(moved map[string]string) (int, error) {
+	lists, err := p.Playlists()
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for _, info := range lists {
+		tracks, err := p.Tracks(info.ID)
+		if err != nil {
+			return changed, err
+		}
+		dirty := false
+		for i, t := range tracks {
+			if t.Stream {
+				continue
+			}
+			abs, err := filepath.Abs(t.Path)
+			if err != nil {
+				continue
+			}
+			if to, ok := moved[abs]; ok {
+				tracks[i].Path = to
+				dirty = true
+			}
+		}
+		if !dirty {
+			continue
+		}
+		if err := p.SavePlaylist(info.ID, tracks); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// HasPlaylist reports whether a playlist of that name exists.
+func (p *Provider) HasPlaylist(name string) bool {
+	path, err := p.safePath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
 }
 
 // DeletePlaylist removes the TOML file for the named playlist.
@@ -161,7 +216,7 @@ func (p *Provider) RemoveTrack(name string, index int) error {
 	if len(tracks) == 0 {
 		return p.DeletePlaylist(name)
 	}
-	return p.savePlaylist(name, tracks)
+	return p.SavePlaylist(name, tracks)
 }
 
 // writeTrack writes a single [[track]] TOML section to w.
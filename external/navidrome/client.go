@@ -90,10 +90,13 @@ type Album struct {
 }
 
 // NavidromeClient implements playlist.Provider for a Navidrome/Subsonic server.
+// It speaks the plain Subsonic API, so Airsonic, Gonic and other compatible
+// servers work as well.
 type NavidromeClient struct {
-	url      string
-	user     string
-	password string
+	url           string
+	user          string
+	password      string
+	legacyAuth    bool // send the hex-encoded password instead of a salted token
 	mu            sync.Mutex
 	playlistCache []playlist.PlaylistInfo
 	trackCache    map[string][]playlist.Track
@@ -122,7 +125,9 @@ func NewFromConfig(cfg config.NavidromeConfig) *NavidromeClient {
 	if !cfg.IsSet() {
 		return nil
 	}
-	return New(cfg.URL, cfg.User, cfg.Password)
+	c := New(cfg.URL, cfg.User, cfg.Password)
+	c.legacyAuth = cfg.LegacyAuth
+	return c
 }
 
 func (c *NavidromeClient) Name() string {
@@ -149,24 +154,32 @@ func checkSubsonicError(status string, apiErr *subsonicError) error {
 }
 
 func (c *NavidromeClient) buildURL(endpoint string, params url.Values) string {
-	// Use crypto/rand for the salt as recommended by the Subsonic API spec.
-	// MD5 is required by the protocol — not a choice.
-	saltBytes := make([]byte, 8)
-	if _, err := io.ReadFull(rand.Reader, saltBytes); err != nil {
-		// Fallback to timestamp if crypto/rand fails (should never happen).
-		saltBytes = []byte(fmt.Sprintf("%d", time.Now().UnixNano()))
-	}
-	salt := hex.EncodeToString(saltBytes)
-	hash := md5.Sum([]byte(c.password + salt))
-	token := hex.EncodeToString(hash[:])
-
 	if params == nil {
 		params = url.Values{}
 	}
 	params.Set("u", c.user)
-	params.Set("t", token)
-	params.Set("s", salt)
-	params.Set("v", "1.0.0")
+	if c.legacyAuth {
+		// Servers that store passwords hashed (Airsonic with LDAP or
+		// non-legacy password encoding) cannot verify tokens and only
+		// accept the password itself, hex-encoded to keep it out of plain
+		// sight in logs.
+		params.Set("p", "enc:"+hex.EncodeToString([]byte(c.password)))
+	} else {
+		// Use crypto/rand for the salt as recommended by the Subsonic API spec.
+		// MD5 is required by the protocol — not a choice.
+		saltBytes := make([]byte, 8)
+		if _, err := io.ReadFull(rand.Reader, saltBytes); err != nil {
+			// Fallback to timestamp if crypto/rand fails (should never happen).
+			saltBytes = []byte(fmt.Sprintf("%d", time.Now().UnixNano()))
+		}
+		salt := hex.EncodeToString(saltBytes)
+		hash := md5.Sum([]byte(c.password + salt))
+		params.Set("t", hex.EncodeToString(hash[:]))
+		params.Set("s", salt)
+	}
+	// 1.13.0 is the first protocol version with token authentication;
+	// strict servers refuse t/s from clients announcing an older one.
+	params.Set("v", "1.13.0")
 	params.Set("c", "cliamp")
 	params.Set("f", "json")
 
@@ -390,6 +403,7 @@ type subsonicSong struct {
 	Album       string `json:"album"`
 	Year        int    `json:"year"`
 	TrackNumber int    `json:"track"`
+	DiscNumber  int    `json:"discNumber"`
 	Genre       string `json:"genre"`
 	Duration    int    `json:"duration"`
 }
@@ -403,6 +417,7 @@ func (c *NavidromeClient) songToTrack(s subsonicSong) playlist.Track {
 		Album:        s.Album,
 		Year:         s.Year,
 		TrackNumber:  s.TrackNumber,
+		DiscNumber:   s.DiscNumber,
 		Genre:        s.Genre,
 		Stream:       true,
 		DurationSecs: s.Duration,
@@ -443,21 +458,28 @@ func (c *NavidromeClient) streamURL(id string) string {
 
 // Scrobble reports playback of a track to the Subsonic server.
 // If submission is false, it registers a "now playing" notification only.
-// If submission is true, it records a full play (updates play count, last.fm, etc.).
-// The call is best-effort: errors are silently discarded.
-func (c *NavidromeClient) Scrobble(id string, submission bool) {
+// If submission is true, it records a full play (updates play count, last.fm, etc.)
+// that started at at, or now when at is zero.
+func (c *NavidromeClient) Scrobble(id string, submission bool, at time.Time) error {
 	params := url.Values{
 		"id":         {id},
 		"submission": {fmt.Sprintf("%t", submission)},
 	}
 	if submission {
-		// Pass the current wall-clock time in milliseconds as required by
-		// the spec for submission=true (Subsonic API 1.8.0+).
-		params.Set("time", fmt.Sprintf("%d", time.Now().UnixMilli()))
+		// The time in milliseconds is required by the spec for
+		// submission=true (Subsonic API 1.8.0+).
+		if at.IsZero() {
+			at = time.Now()
+		}
+		params.Set("time", fmt.Sprintf("%d", at.UnixMilli()))
 	}
 	resp, err := httpClient.Get(c.buildURL("scrobble", params))
 	if err != nil {
-		return // fire-and-forget; ignore network errors
+		return err
 	}
 	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("navidrome: scrobble: %s", resp.Status)
+	}
+	return nil
 }
@@ -0,0 +1,62 @@
+package navidrome
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cliamp/config"
+)
+
+func TestPlaylists_TokenAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/rest/getPlaylists" {
+			t.Errorf("path = %q, want /rest/getPlaylists", r.URL.Path)
+		}
+		if q.Get("u") != "alice" || q.Get("t") == "" || q.Get("s") == "" || q.Get("p") != "" {
+			t.Errorf("auth params = %v, want u, t and s without p", q)
+		}
+		if q.Get("v") != "1.13.0" || q.Get("c") != "cliamp" || q.Get("f") != "json" {
+			t.Errorf("client params = %v", q)
+		}
+		w.Write([]byte(`{"subsonic-response":{"status":"ok","playlists":{"playlist":[{"id":"7","name":"Mix","songCount":12}]}}}`))
+	}))
+	defer srv.Close()
+
+	lists, err := New(srv.URL, "alice", "secret").Playlists()
+	if err != nil {
+		t.Fatalf("Playlists: %v", err)
+	}
+	if len(lists) != 1 || lists[0].ID != "7" || lists[0].Name != "Mix" || lists[0].TrackCount != 12 {
+		t.Errorf("Playlists = %+v", lists)
+	}
+}
+
+func TestPlaylists_LegacyAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("p") != "enc:736563726574" || q.Get("t") != "" || q.Get("s") != "" {
+			t.Errorf("auth params = %v, want hex-encoded p only", q)
+		}
+		w.Write([]byte(`{"subsonic-response":{"status":"ok","playlists":{}}}`))
+	}))
+	defer srv.Close()
+
+	c := NewFromConfig(config.NavidromeConfig{URL: srv.URL, User: "alice", Password: "secret", LegacyAuth: true})
+	if _, err := c.Playlists(); err != nil {
+		t.Fatalf("Playlists: %v", err)
+	}
+}
+
+func TestPlaylists_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"subsonic-response":{"status":"failed","error":{"code":40,"message":"Wrong username or password"}}}`))
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, "alice", "wrong").Playlists()
+	if err == nil || err.Error() != "navidrome: Wrong username or password (code 40)" {
+		t.Errorf("err = %v, want the server's message", err)
+	}
+}
@@ -0,0 +1,159 @@
+package podcast
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cliamp/playlist"
+)
+
+// Feed is a parsed podcast RSS feed.
+type Feed struct {
+	Title    string
+	Episodes []Episode
+}
+
+// Episode is one item of a feed that carries an audio enclosure.
+type Episode struct {
+	Title        string
+	URL          string // enclosure URL
+	Published    time.Time
+	DurationSecs int
+}
+
+// FetchFeed downloads and parses the feed at feedURL.
+func FetchFeed(client *http.Client, feedURL string) (*Feed, error) {
+	resp, err := client.Get(feedURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http status %s", resp.Status)
+	}
+	return ParseFeed(resp.Body)
+}
+
+// ParseFeed reads an RSS document. Items without an enclosure are skipped.
+func ParseFeed(r io.Reader) (*Feed, error) {
+	var rss struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title     string `xml:"title"`
+				PubDate   string `xml:"pubDate"`
+				Duration  string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+				Enclosure struct {
+					URL  string `xml:"url,attr"`
+					Type string `xml:"type,attr"`
+				} `xml:"enclosure"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.NewDecoder(r).Decode(&rss); err != nil {
+		return nil, fmt.Errorf("parsing feed: %w", err)
+	}
+
+	f := &Feed{Title: strings.TrimSpace(rss.Channel.Title)}
+	for _, item := range rss.Channel.Items {
+		if item.Enclosure.URL == "" {
+			continue
+		}
+		f.Episodes = append(f.Episodes, Episode{
+			Title:        strings.TrimSpace(item.Title),
+			URL:          item.Enclosure.URL,
+			Published:    parsePubDate(item.PubDate),
+			DurationSecs: parseDuration(item.Duration),
+		})
+	}
+	return f, nil
+}
+
+// Tracks returns the episodes as streamable playlist tracks, with the
+// podcast title as the artist.
+func (f *Feed) Tracks() []playlist.Track {
+	tracks := make([]playlist.Track, 0, len(f.Episodes))
+	for _, e := range f.Episodes {
+		tracks = append(tracks, e.track(f.Title))
+	}
+	return tracks
+}
+
+func (e Episode) track(podcast string) playlist.Track {
+	t := playlist.Track{
+		Path:         e.URL,
+		Title:        e.Title,
+		Artist:       podcast,
+		Stream:       true,
+		DurationSecs: e.DurationSecs,
+	}
+	if !e.Published.IsZero() {
+		t.Year = e.Published.Year()
+	}
+	return t
+}
+
+// parsePubDate accepts the RFC 822 dates RSS requires, with or without the
+// weekday and with either a numeric or named zone.
+func parsePubDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, "2 Jan 2006 15:04:05 -0700", "2 Jan 2006 15:04:05 MST"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseDuration parses an <itunes:duration> value into seconds.
+// Accepts "HH:MM:SS", "MM:SS", or a plain seconds string (integer or float).
+// Returns 0 for any invalid or negative input.
+func parseDuration(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	// parseSec handles fractional seconds (e.g. "61.5" → 61).
+	parseSec := func(s string) (int, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		return int(f), err
+	}
+
+	parts := strings.Split(s, ":")
+	var result int
+	switch len(parts) {
+	case 1:
+		n, err := parseSec(parts[0])
+		if err != nil {
+			return 0
+		}
+		result = n
+	case 2:
+		m, err1 := strconv.Atoi(parts[0])
+		sec, err2 := parseSec(parts[1])
+		if err1 != nil || err2 != nil {
+			return 0
+		}
+		result = m*60 + sec
+	case 3:
+		h, err1 := strconv.Atoi(parts[0])
+		m, err2 := strconv.Atoi(parts[1])
+		sec, err3 := parseSec(parts[2])
+		if err1 != nil || err2 != nil || err3 != nil {
+			return 0
+		}
+		result = h*3600 + m*60 + sec
+	default:
+		return 0
+	}
+	if result < 0 {
+		return 0
+	}
+	return result
+}
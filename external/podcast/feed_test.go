@@ -0,0 +1,102 @@
+package podcast
+
+import (
+	"strings"
+	"testing"
+)
+
+const testFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel>
+  <title> Test Show </title>
+  <item>
+    <title>Episode 2</title>
+    <pubDate>Tue, 06 Oct 2026 08:00:00 +0000</pubDate>
+    <itunes:duration>1:02:03</itunes:duration>
+    <enclosure url="https://cdn.example.com/ep2.mp3" type="audio/mpeg"/>
+  </item>
+  <item>
+    <title>Announcement without audio</title>
+  </item>
+  <item>
+    <title>Episode 1</title>
+    <pubDate>29 Sep 2026 08:00:00 GMT</pubDate>
+    <itunes:duration>2700</itunes:duration>
+    <enclosure url="https://cdn.example.com/ep1.m4a" type="audio/mp4"/>
+  </item>
+</channel>
+</rss>`
+
+func TestParseFeed(t *testing.T) {
+	f, err := ParseFeed(strings.NewReader(testFeed))
+	if err != nil {
+		t.Fatalf("ParseFeed: %v", err)
+	}
+	if f.Title != "Test Show" {
+		t.Errorf("Title = %q, want %q", f.Title, "Test Show")
+	}
+	if len(f.Episodes) != 2 {
+		t.Fatalf("got %d episodes, want 2 (item without enclosure skipped)", len(f.Episodes))
+	}
+	e := f.Episodes[0]
+	if e.Title != "Episode 2" || e.URL != "https://cdn.example.com/ep2.mp3" || e.DurationSecs != 3723 {
+		t.Errorf("episode 0 = %+v", e)
+	}
+	if e.Published.Year() != 2026 || e.Published.Month() != 10 || e.Published.Day() != 6 {
+		t.Errorf("Published = %v, want 2026-10-06", e.Published)
+	}
+	if f.Episodes[1].Published.IsZero() {
+		t.Error("pubDate without weekday was not parsed")
+	}
+
+	tracks := f.Tracks()
+	if tracks[1].Artist != "Test Show" || !tracks[1].Stream || tracks[1].Year != 2026 || tracks[1].DurationSecs != 2700 {
+		t.Errorf("track 1 = %+v", tracks[1])
+	}
+}
+
+func TestParseFeed_Invalid(t *testing.T) {
+	if _, err := ParseFeed(strings.NewReader("<rss><channel>")); err == nil {
+		t.Error("expected error for truncated feed")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		// Plain seconds
+		{"3600", 3600},
+		{"90", 90},
+		{"0", 0},
+		// Fractional seconds
+		{"3661.5", 3661},
+		{"90.9", 90},
+		// MM:SS
+		{"1:30", 90},
+		{"87:05", 5225},
+		// HH:MM:SS
+		{"1:27:05", 5225},
+		{"0:01:30", 90},
+		// Whitespace
+		{" 3600 ", 3600},
+		// Empty
+		{"", 0},
+		// Invalid — return 0
+		{"abc", 0},
+		{"12:xx", 0},
+		{"1:2:xx", 0},
+		// Negative — clamp to 0
+		{"-1", 0},
+		{"0:-10", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parseDuration(tt.input)
+			if got != tt.want {
+				t.Errorf("parseDuration(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
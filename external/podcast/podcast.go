@@ -0,0 +1,327 @@
+// Package podcast implements a playlist.Provider for subscribed podcast
+// feeds. Subscriptions are kept in ~/.config/cliamp/podcasts.toml,
+// downloaded episodes in ~/.config/cliamp/podcasts/, and the position
+// reached in each episode in ~/.config/cliamp/podcast_positions.json.
+package podcast
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cliamp/internal/appdir"
+	"cliamp/internal/httpclient"
+	"cliamp/internal/tomlutil"
+	"cliamp/playlist"
+)
+
+const (
+	subscriptionsFile = "podcasts.toml"
+	positionsFile     = "podcast_positions.json"
+	downloadsDir      = "podcasts"
+)
+
+// feedClient is used for feed requests. Episode downloads use the
+// streaming client instead, since long episodes can take minutes.
+var feedClient = &http.Client{Timeout: 30 * time.Second}
+
+// Provider serves each subscribed podcast as a playlist of its episodes.
+// It is safe for concurrent use: Tracks and Download run in tea.Cmd
+// goroutines while the UI queries positions.
+type Provider struct {
+	mu        sync.Mutex
+	dir       string // config directory; "" when it cannot be determined
+	subs      []subscription
+	positions map[string]int  // episode key → seconds
+	known     map[string]bool // episode keys seen in fetched feeds
+}
+
+type subscription struct {
+	title string
+	url   string
+}
+
+// New loads subscriptions and saved positions from the config directory.
+func New() *Provider {
+	dir, err := appdir.Dir()
+	if err != nil {
+		dir = ""
+	}
+	return newProvider(dir)
+}
+
+func newProvider(dir string) *Provider {
+	p := &Provider{dir: dir, positions: make(map[string]int), known: make(map[string]bool)}
+	if dir == "" {
+		return p
+	}
+	if subs, err := loadSubscriptions(filepath.Join(dir, subscriptionsFile)); err == nil {
+		p.subs = subs
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, positionsFile)); err == nil {
+		json.Unmarshal(data, &p.positions)
+	}
+	return p
+}
+
+func (p *Provider) Name() string { return "Podcasts" }
+
+// Playlists lists the subscribed podcasts. The ID is the feed URL.
+func (p *Provider) Playlists() ([]playlist.PlaylistInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]playlist.PlaylistInfo, 0, len(p.subs))
+	for _, s := range p.subs {
+		out = append(out, playlist.PlaylistInfo{ID: s.url, Name: s.title})
+	}
+	return out, nil
+}
+
+// Tracks fetches the feed and returns its episodes, newest first as
+// published. Episodes that have been downloaded play from disk.
+func (p *Provider) Tracks(feedURL string) ([]playlist.Track, error) {
+	feed, err := FetchFeed(feedClient, feedURL)
+	if err != nil {
+		return nil, err
+	}
+	tracks := feed.Tracks()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range tracks {
+		p.known[episodeKey(tracks[i].Path)] = true
+		if local := p.localPath(tracks[i].Path); local != "" {
+			if _, err := os.Stat(local); err == nil {
+				tracks[i].Path = local
+				tracks[i].Stream = false
+			}
+		}
+	}
+	return tracks, nil
+}
+
+// Subscribe fetches the feed to learn its title and adds it to the
+// subscriptions. Subscribing twice to the same URL is a no-op.
+func (p *Provider) Subscribe(feedURL string) (string, error) {
+	feed, err := FetchFeed(feedClient, feedURL)
+	if err != nil {
+		return "", err
+	}
+	title := feed.Title
+	if title == "" {
+		title = feedURL
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, s := range p.subs {
+		if s.url == feedURL {
+			return s.title, nil
+		}
+	}
+	p.subs = append(p.subs, subscription{title: title, url: feedURL})
+	return title, p.saveSubscriptions()
+}
+
+// Unsubscribe removes the feed. Downloaded episodes are kept.
+func (p *Provider) Unsubscribe(feedURL string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, s := range p.subs {
+		if s.url == feedURL {
+			p.subs = append(p.subs[:i], p.subs[i+1:]...)
+			return p.saveSubscriptions()
+		}
+	}
+	return nil
+}
+
+// IsEpisode reports whether path is an episode of a subscribed podcast,
+// streamed or downloaded.
+func (p *Provider) IsEpisode(path string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.known[episodeKey(path)]
+}
+
+// Download saves a streamed episode to the podcasts directory and returns
+// the local path. An episode that is already on disk is not fetched again.
+func (p *Provider) Download(episodeURL string) (string, error) {
+	dest := p.localPath(episodeURL)
+	if dest == "" {
+		return "", errors.New("podcast: no download directory")
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", err
+	}
+
+	resp, err := httpclient.Streaming.Get(episodeURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("http status %s", resp.Status)
+	}
+
+	// Write to a temporary name so an interrupted download is never
+	// mistaken for a finished one.
+	tmp := dest + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return dest, os.Rename(tmp, dest)
+}
+
+// Position returns the saved position in seconds for the episode at path,
+// or 0 when there is none.
+func (p *Provider) Position(path string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.positions[episodeKey(path)]
+}
+
+// SetPosition records how far playback got in the episode at path. A
+// non-positive secs forgets the position, e.g. after the episode finished.
+// Errors are ignored so a failed write never disrupts playback.
+func (p *Provider) SetPosition(path string, secs int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := episodeKey(path)
+	if secs > 0 {
+		p.positions[key] = secs
+	} else if _, ok := p.positions[key]; ok {
+		delete(p.positions, key)
+	} else {
+		return
+	}
+	if p.dir == "" {
+		return
+	}
+	data, err := json.Marshal(p.positions)
+	if err != nil {
+		return
+	}
+	_ = os.MkdirAll(p.dir, 0o755)
+	_ = os.WriteFile(filepath.Join(p.dir, positionsFile), data, 0o600)
+}
+
+// episodeKey identifies an episode by a hash of its enclosure URL. A
+// downloaded file is named after that hash, so both forms share one key
+// and a position saved while streaming carries over to the download.
+func episodeKey(path string) string {
+	if !playlist.IsURL(path) {
+		base := filepath.Base(path)
+		return strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	sum := sha1.Sum([]byte(path))
+	return hex.EncodeToString(sum[:8])
+}
+
+// localPath returns where the episode at episodeURL is stored once
+// downloaded, or "" when there is no config directory.
+func (p *Provider) localPath(episodeURL string) string {
+	if p.dir == "" {
+		return ""
+	}
+	ext := ".mp3"
+	if u, err := url.Parse(episodeURL); err == nil {
+		if e := strings.ToLower(filepath.Ext(u.Path)); e != "" && len(e) <= 5 {
+			ext = e
+		}
+	}
+	return filepath.Join(p.dir, downloadsDir, episodeKey(episodeURL)+ext)
+}
+
+func (p *Provider) saveSubscriptions() error {
+	if p.dir == "" {
+		return errors.New("podcast: no config directory")
+	}
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for i, s := range p.subs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[[podcast]]\ntitle = %q\nurl = %q\n", s.title, s.url)
+	}
+	return os.WriteFile(filepath.Join(p.dir, subscriptionsFile), []byte(b.String()), 0o644)
+}
+
+// loadSubscriptions parses a TOML file with [[podcast]] sections.
+func loadSubscriptions(path string) ([]subscription, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var subs []subscription
+	var current *subscription
+	flush := func() {
+		if current != nil && current.url != "" {
+			if current.title == "" {
+				current.title = current.url
+			}
+			subs = append(subs, *current)
+		}
+	}
+
+	for _, rawLine := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(rawLine)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "[[podcast]]" {
+			flush()
+			current = &subscription{}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		val = tomlutil.Unquote(strings.TrimSpace(val))
+
+		switch strings.TrimSpace(key) {
+		case "title":
+			current.title = val
+		case "url":
+			current.url = val
+		}
+	}
+	flush()
+	return subs, nil
+}
@@ -0,0 +1,129 @@
+package podcast
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func feedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.xml":
+			w.Write([]byte(strings.ReplaceAll(testFeed, "https://cdn.example.com", "http://"+r.Host)))
+		case "/ep2.mp3":
+			w.Write([]byte("episode audio"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSubscribe_PersistsAndLists(t *testing.T) {
+	srv := feedServer(t)
+	dir := t.TempDir()
+
+	p := newProvider(dir)
+	title, err := p.Subscribe(srv.URL + "/feed.xml")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if title != "Test Show" {
+		t.Errorf("title = %q, want %q", title, "Test Show")
+	}
+	if _, err := p.Subscribe(srv.URL + "/feed.xml"); err != nil {
+		t.Fatalf("second Subscribe: %v", err)
+	}
+
+	lists, _ := newProvider(dir).Playlists()
+	if len(lists) != 1 || lists[0].Name != "Test Show" || lists[0].ID != srv.URL+"/feed.xml" {
+		t.Fatalf("reloaded playlists = %+v, want the one subscription", lists)
+	}
+
+	if err := p.Unsubscribe(srv.URL + "/feed.xml"); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if lists, _ := newProvider(dir).Playlists(); len(lists) != 0 {
+		t.Errorf("after Unsubscribe playlists = %+v, want none", lists)
+	}
+}
+
+func TestDownload_TracksPlayFromDisk(t *testing.T) {
+	srv := feedServer(t)
+	p := newProvider(t.TempDir())
+
+	tracks, err := p.Tracks(srv.URL + "/feed.xml")
+	if err != nil {
+		t.Fatalf("Tracks: %v", err)
+	}
+	episodeURL := tracks[0].Path
+	if !p.IsEpisode(episodeURL) {
+		t.Error("fetched episode not recognised")
+	}
+
+	local, err := p.Download(episodeURL)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if data, _ := os.ReadFile(local); string(data) != "episode audio" {
+		t.Errorf("downloaded file = %q", data)
+	}
+	if !p.IsEpisode(local) {
+		t.Error("downloaded episode not recognised")
+	}
+
+	tracks, _ = p.Tracks(srv.URL + "/feed.xml")
+	if tracks[0].Path != local || tracks[0].Stream {
+		t.Errorf("after download track = %+v, want local non-stream %s", tracks[0], local)
+	}
+	if tracks[1].Path == local || !tracks[1].Stream {
+		t.Errorf("undownloaded episode = %+v, want stream", tracks[1])
+	}
+}
+
+func TestPosition_SharedByStreamAndDownloadAndPersisted(t *testing.T) {
+	srv := feedServer(t)
+	dir := t.TempDir()
+	p := newProvider(dir)
+
+	tracks, _ := p.Tracks(srv.URL + "/feed.xml")
+	episodeURL := tracks[0].Path
+	p.SetPosition(episodeURL, 754)
+
+	local, err := p.Download(episodeURL)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if got := newProvider(dir).Position(local); got != 754 {
+		t.Errorf("Position(download) after reload = %d, want 754", got)
+	}
+
+	p.SetPosition(local, 0)
+	if got := newProvider(dir).Position(episodeURL); got != 0 {
+		t.Errorf("Position after clearing = %d, want 0", got)
+	}
+}
+
+func TestLoadSubscriptions_SkipsEntriesWithoutURL(t *testing.T) {
+	path := t.TempDir() + "/podcasts.toml"
+	os.WriteFile(path, []byte(`# my shows
+[[podcast]]
+title = "No URL"
+
+[[podcast]]
+url = "https://example.com/feed.xml"
+`), 0o644)
+
+	subs, err := loadSubscriptions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].url != "https://example.com/feed.xml" || subs[0].title != subs[0].url {
+		t.Errorf("subs = %+v, want one entry titled by its URL", subs)
+	}
+}
@@ -0,0 +1,134 @@
+// Package hooks runs user-configured shell commands when playback events
+// happen, passing track metadata in environment variables.
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"cliamp/playlist"
+)
+
+// Events a hook can be attached to. The names double as the keys under
+// [hooks] in config.toml.
+const (
+	TrackStart  = "track_start"
+	TrackEnd    = "track_end"
+	Pause       = "pause"
+	Resume      = "resume"
+	Stop        = "stop"
+	PlaylistEnd = "playlist_end"
+)
+
+// Events lists every supported event.
+var Events = []string{TrackStart, TrackEnd, Pause, Resume, Stop, PlaylistEnd}
+
+const (
+	// timeout kills a hook that runs longer than this.
+	timeout = 30 * time.Second
+	// queueSize bounds how many hooks may wait to run; further events are
+	// dropped until the queue drains.
+	queueSize = 32
+)
+
+type job struct {
+	cmd string
+	env []string
+}
+
+// Runner executes hooks one at a time in a background goroutine, in the
+// order the events happened, so a slow script never stalls the UI.
+type Runner struct {
+	cmds  map[string]string
+	queue chan job
+	done  chan struct{}
+}
+
+// New returns a Runner for the given event → command map, or nil when no
+// command is configured. A nil Runner ignores all events.
+func New(cmds map[string]string) *Runner {
+	r := &Runner{cmds: make(map[string]string), queue: make(chan job, queueSize), done: make(chan struct{})}
+	for _, ev := range Events {
+		if c := cmds[ev]; c != "" {
+			r.cmds[ev] = c
+		}
+	}
+	if len(r.cmds) == 0 {
+		return nil
+	}
+	go r.work()
+	return r
+}
+
+// Run queues the hook for event, if one is configured. pos is how far into
+// the track playback was; it is only meaningful for end, pause and stop.
+func (r *Runner) Run(event string, t playlist.Track, pos time.Duration) {
+	if r == nil {
+		return
+	}
+	c, ok := r.cmds[event]
+	if !ok {
+		return
+	}
+	select {
+	case r.queue <- job{cmd: c, env: Env(event, t, pos)}:
+	default:
+	}
+}
+
+// Close waits for the queued hooks to finish, so that one fired just before
+// exit (playlist_end with --exit-after) still runs. No events may be
+// queued after Close.
+func (r *Runner) Close() {
+	if r == nil {
+		return
+	}
+	close(r.queue)
+	<-r.done
+}
+
+func (r *Runner) work() {
+	defer close(r.done)
+	for j := range r.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		cmd := shellCommand(ctx, j.cmd)
+		cmd.Env = append(os.Environ(), j.env...)
+		cmd.Run() // output is discarded; a failing hook must not disturb the TUI
+		cancel()
+	}
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// Env returns the CLIAMP_* variables describing event and track.
+func Env(event string, t playlist.Track, pos time.Duration) []string {
+	env := []string{
+		"CLIAMP_EVENT=" + event,
+		"CLIAMP_PATH=" + t.Path,
+		"CLIAMP_TITLE=" + t.Title,
+		"CLIAMP_ARTIST=" + t.Artist,
+		"CLIAMP_ALBUM=" + t.Album,
+		"CLIAMP_GENRE=" + t.Genre,
+		"CLIAMP_DURATION=" + strconv.Itoa(t.DurationSecs),
+		"CLIAMP_POSITION=" + strconv.Itoa(int(pos.Seconds())),
+	}
+	if t.TrackNumber > 0 {
+		env = append(env, "CLIAMP_TRACK_NUMBER="+strconv.Itoa(t.TrackNumber))
+	}
+	if t.Year > 0 {
+		env = append(env, "CLIAMP_YEAR="+strconv.Itoa(t.Year))
+	}
+	if t.Stream {
+		env = append(env, "CLIAMP_STREAM=1")
+	}
+	return env
+}
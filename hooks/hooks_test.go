@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"cliamp/playlist"
+)
+
+func TestNew_NilWithoutCommands(t *testing.T) {
+	if r := New(map[string]string{"unknown_event": "true", TrackStart: ""}); r != nil {
+		t.Error("New() with no usable commands should return nil")
+	}
+	var r *Runner
+	r.Run(TrackStart, playlist.Track{}, 0) // must not panic
+}
+
+func TestEnv(t *testing.T) {
+	track := playlist.Track{Path: "/m/a.flac", Title: "Song", Artist: "Band", Album: "Record", TrackNumber: 4, DurationSecs: 241}
+	env := Env(TrackEnd, track, 83*time.Second+600*time.Millisecond)
+	for _, want := range []string{
+		"CLIAMP_EVENT=track_end", "CLIAMP_PATH=/m/a.flac", "CLIAMP_TITLE=Song", "CLIAMP_ARTIST=Band",
+		"CLIAMP_ALBUM=Record", "CLIAMP_TRACK_NUMBER=4", "CLIAMP_DURATION=241", "CLIAMP_POSITION=83",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("Env() missing %q in %q", want, env)
+		}
+	}
+	if slices.Contains(env, "CLIAMP_STREAM=1") {
+		t.Error("Env() marks a local file as a stream")
+	}
+}
+
+func TestRunner_RunsInOrder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "log")
+	r := New(map[string]string{
+		TrackStart: `echo "start $CLIAMP_TITLE" >> ` + out,
+		TrackEnd:   `echo "end $CLIAMP_TITLE" >> ` + out,
+	})
+	r.Run(TrackStart, playlist.Track{Title: "A"}, 0)
+	r.Run(Pause, playlist.Track{Title: "A"}, 0) // not configured
+	r.Run(TrackEnd, playlist.Track{Title: "A"}, 0)
+	r.Run(TrackStart, playlist.Track{Title: "B"}, 0)
+
+	want := "start A\nend A\nstart B\n"
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(out)
+		if string(data) == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("hook log = %q, want %q", data, want)
+		}
+		if !strings.HasPrefix(want, string(data)) {
+			t.Fatalf("hook log = %q, want %q", data, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
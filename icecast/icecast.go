@@ -0,0 +1,200 @@
+// Package icecast implements the source side of the Icecast protocol:
+// pushing an encoded stream to a mountpoint and updating the song title
+// listeners see.
+package icecast
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config describes a mountpoint and how the stream is listed on it.
+type Config struct {
+	URL         string // e.g. "http://radio.example.com:8000/live.mp3"; may carry user:password
+	User        string // defaults to "source"
+	Password    string
+	ContentType string // "audio/mpeg" or "application/ogg"
+	Name        string
+	Description string
+	Genre       string
+	Bitrate     int // kbps, shown in directory listings
+	Public      bool
+}
+
+// ErrAuth is returned when the server rejects the source credentials.
+var ErrAuth = errors.New("icecast: wrong source password")
+
+const dialTimeout = 10 * time.Second
+
+// endpoint resolves the server address, mountpoint and credentials.
+func (c Config) endpoint() (u *url.URL, user, password string, err error) {
+	u, err = url.Parse(c.URL)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "icecast" {
+		return nil, "", "", fmt.Errorf("icecast: unsupported URL scheme %q", u.Scheme)
+	}
+	if u.Host == "" || u.Path == "" || u.Path == "/" {
+		return nil, "", "", errors.New("icecast: URL needs a host and a mountpoint, e.g. http://host:8000/live.mp3")
+	}
+	user, password = c.User, c.Password
+	if u.User != nil {
+		user = u.User.Username()
+		if p, ok := u.User.Password(); ok {
+			password = p
+		}
+	}
+	if user == "" {
+		user = "source"
+	}
+	return u, user, password, nil
+}
+
+func basicAuth(user, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+}
+
+// hostPort adds the default Icecast port when the URL has none.
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "8000")
+}
+
+// Dial opens the mountpoint for streaming. Whatever is written to the
+// returned connection is relayed to listeners, so it must be encoded data
+// in the configured format, written in real time.
+func Dial(c Config) (net.Conn, error) {
+	u, user, password, err := c.endpoint()
+	if err != nil {
+		return nil, err
+	}
+	d := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	if u.Scheme == "https" {
+		conn, err = tls.DialWithDialer(d, "tcp", hostPort(u), &tls.Config{ServerName: u.Hostname()})
+	} else {
+		conn, err = d.Dial("tcp", hostPort(u))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Icecast 2.4+ takes a PUT with an endless body. Expect: 100-continue
+	// lets it refuse before any audio is sent.
+	var b strings.Builder
+	fmt.Fprintf(&b, "PUT %s HTTP/1.1\r\n", u.EscapedPath())
+	fmt.Fprintf(&b, "Host: %s\r\n", u.Host)
+	fmt.Fprintf(&b, "Authorization: %s\r\n", basicAuth(user, password))
+	fmt.Fprintf(&b, "User-Agent: cliamp\r\n")
+	fmt.Fprintf(&b, "Content-Type: %s\r\n", c.ContentType)
+	fmt.Fprintf(&b, "Expect: 100-continue\r\n")
+	for _, h := range [][2]string{
+		{"Ice-Name", c.Name},
+		{"Ice-Description", c.Description},
+		{"Ice-Genre", c.Genre},
+	} {
+		if h[1] != "" {
+			fmt.Fprintf(&b, "%s: %s\r\n", h[0], headerValue(h[1]))
+		}
+	}
+	if c.Bitrate > 0 {
+		fmt.Fprintf(&b, "Ice-Bitrate: %d\r\nIce-Audio-Info: bitrate=%d\r\n", c.Bitrate, c.Bitrate)
+	}
+	fmt.Fprintf(&b, "Ice-Public: %d\r\n\r\n", boolInt(c.Public))
+
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("icecast: %w", err)
+	}
+	resp.Body.Close()
+	conn.SetDeadline(time.Time{})
+	switch {
+	case resp.StatusCode == http.StatusContinue || resp.StatusCode == http.StatusOK:
+		return conn, nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		conn.Close()
+		return nil, ErrAuth
+	default:
+		conn.Close()
+		return nil, fmt.Errorf("icecast: server refused the stream: %s", resp.Status)
+	}
+}
+
+// UpdateMetadata sets the song title shown to listeners of the mountpoint.
+// Icecast supports this for MP3 and AAC streams; Ogg streams carry their
+// titles in-band.
+func UpdateMetadata(c Config, song string) error {
+	u, user, password, err := c.endpoint()
+	if err != nil {
+		return err
+	}
+	scheme := "http"
+	if u.Scheme == "https" {
+		scheme = "https"
+	}
+	q := url.Values{"mount": {u.Path}, "mode": {"updinfo"}, "song": {song}}
+	admin := scheme + "://" + hostPort(u) + "/admin/metadata?" + q.Encode()
+	req, err := http.NewRequest(http.MethodGet, admin, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", basicAuth(user, password))
+	req.Header.Set("User-Agent", "cliamp")
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return ErrAuth
+	}
+	return fmt.Errorf("icecast: metadata update: %s", resp.Status)
+}
+
+var metadataClient = &http.Client{Timeout: dialTimeout}
+
+// headerValue keeps user-supplied text from breaking the request headers.
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ContentType returns the MIME type of an encoder format: "mp3" or "ogg".
+func ContentType(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "mp3":
+		return "audio/mpeg", nil
+	case "ogg", "vorbis":
+		return "application/ogg", nil
+	}
+	return "", fmt.Errorf("icecast: unknown format %q (want mp3 or ogg)", format)
+}
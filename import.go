@@ -0,0 +1,351 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cliamp/external/local"
+	"cliamp/internal/history"
+	"cliamp/internal/itunes"
+	"cliamp/internal/mpdfiles"
+	"cliamp/playlist"
+)
+
+const importUsage = `usage: cliamp import itunes [--map <from>=<to>]... <Library.xml>
+       cliamp import mpd [--music-dir <dir>] [--playlist-dir <dir>] [--sticker-file <file>]`
+
+// runImport implements `cliamp import`: the playlists of another player
+// become local playlists, rated tracks are gathered in one playlist per
+// star rating, and play counts go into the listening history. Tracks whose
+// files are missing are left out and counted.
+func runImport(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(importUsage)
+	}
+	var im *imported
+	var err error
+	switch args[0] {
+	case "itunes":
+		im, err = importITunes(args[1:])
+	case "mpd":
+		im, err = importMPD(args[1:])
+	default:
+		return errors.New(importUsage)
+	}
+	if err != nil {
+		return err
+	}
+	return im.save(w)
+}
+
+// imported is what an importer gathered, ready to be saved.
+type imported struct {
+	source      string // names the rating playlists: "iTunes 5 stars"
+	playlists   []importedPlaylist
+	rated       [6][]playlist.Track // by stars, 1–5
+	plays       []importedPlays
+	found       int
+	missing     int
+	missingHint string
+}
+
+type importedPlaylist struct {
+	name   string
+	tracks []playlist.Track
+}
+
+// importedPlays is a track's play count and when it was last played.
+type importedPlays struct {
+	track playlist.Track
+	n     int
+	last  time.Time
+}
+
+// save writes the playlists, skipping names that are taken, and adds the
+// plays to the history as listens at the last play date. Tracks the
+// history already knows are left alone, so importing again adds nothing
+// twice, and plays with no date at all are dropped.
+func (im *imported) save(w io.Writer) error {
+	// Star ratings become playlists of their own, best first.
+	lists := im.playlists
+	for stars := 5; stars >= 1; stars-- {
+		tracks := im.rated[stars]
+		slices.SortFunc(tracks, func(a, b playlist.Track) int {
+			return cmp.Or(cmp.Compare(a.Artist, b.Artist), cmp.Compare(a.Album, b.Album), cmp.Compare(a.TrackNumber, b.TrackNumber), cmp.Compare(a.Path, b.Path))
+		})
+		name := fmt.Sprintf("%s %d star", im.source, stars)
+		if stars > 1 {
+			name += "s"
+		}
+		lists = append(lists, importedPlaylist{name, tracks})
+	}
+
+	prov := local.New()
+	if prov == nil {
+		return errors.New("import: no config directory for playlists")
+	}
+	saved, skipped := 0, 0
+	for _, pl := range lists {
+		name := strings.NewReplacer("/", "-", "\\", "-").Replace(strings.TrimSpace(pl.name))
+		if len(pl.tracks) == 0 || name == "" || name == "." || name == ".." {
+			continue
+		}
+		if prov.HasPlaylist(name) {
+			skipped++
+			continue
+		}
+		if err := prov.SavePlaylist(name, pl.tracks); err != nil {
+			return fmt.Errorf("import: saving playlist %q: %w", name, err)
+		}
+		saved++
+	}
+
+	hist := history.New()
+	known, err := hist.Entries()
+	if err != nil {
+		return err
+	}
+	seen := history.ByTrack(known)
+	var listens []history.Entry
+	for _, p := range im.plays {
+		if _, ok := seen[p.track.Path]; ok || p.last.IsZero() {
+			continue
+		}
+		t := p.track
+		for range p.n {
+			listens = append(listens, history.Entry{Time: p.last, Path: t.Path, Title: t.Title, Artist: t.Artist, Album: t.Album, Secs: t.DurationSecs})
+		}
+	}
+	slices.SortFunc(listens, func(a, b history.Entry) int { return a.Time.Compare(b.Time) })
+	hist.Add(listens...)
+
+	fmt.Fprintf(w, "%d of %d tracks found, %d playlists saved, %d listens added to the history\n",
+		im.found, im.found+im.missing, saved, len(listens))
+	if skipped > 0 {
+		fmt.Fprintf(w, "%d playlists skipped: a playlist of the same name exists\n", skipped)
+	}
+	if im.missing > 0 {
+		fmt.Fprintf(w, "%d tracks missing%s\n", im.missing, im.missingHint)
+	}
+	return nil
+}
+
+// importITunes reads an iTunes or Apple Music Library.xml. --map rewrites
+// a path prefix, for a library exported on another machine.
+func importITunes(args []string) (*imported, error) {
+	var maps [][2]string
+	var file string
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--map" && i+1 < len(args):
+			i++
+			from, to, ok := strings.Cut(args[i], "=")
+			if !ok || from == "" {
+				return nil, fmt.Errorf("import: --map needs <from>=<to> (got %q)", args[i])
+			}
+			maps = append(maps, [2]string{from, to})
+		case strings.HasPrefix(a, "-") || file != "":
+			return nil, errors.New(importUsage)
+		default:
+			file = a
+		}
+	}
+	if file == "" {
+		return nil, errors.New(importUsage)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	lib, err := itunes.Read(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("import %s: %w", file, err)
+	}
+
+	im := &imported{source: "iTunes", missingHint: "; use --map to point the library's folder to where the files are now"}
+	found := make(map[int]playlist.Track)
+	for id, t := range lib.Tracks {
+		if t.Path == "" {
+			continue
+		}
+		path := t.Path
+		for _, m := range maps {
+			if rest, ok := strings.CutPrefix(path, m[0]); ok {
+				path = m[1] + rest
+				break
+			}
+		}
+		path = filepath.FromSlash(path)
+		if _, err := os.Stat(path); err != nil {
+			im.missing++
+			continue
+		}
+		tr := playlist.Track{
+			Path:         path,
+			Title:        t.Name,
+			Artist:       t.Artist,
+			Album:        t.Album,
+			Genre:        t.Genre,
+			Year:         t.Year,
+			TrackNumber:  t.TrackNumber,
+			DurationSecs: int(t.Duration.Seconds()),
+		}
+		found[id] = tr
+		im.found++
+		if t.Rating > 0 {
+			im.rated[t.Rating] = append(im.rated[t.Rating], tr)
+		}
+		if t.Plays > 0 {
+			last := t.LastPlayed
+			if last.IsZero() {
+				last = t.Added
+			}
+			im.plays = append(im.plays, importedPlays{tr, t.Plays, last})
+		}
+	}
+	for _, pl := range lib.Playlists {
+		ipl := importedPlaylist{name: pl.Name}
+		for _, id := range pl.Tracks {
+			if t, ok := found[id]; ok {
+				ipl.tracks = append(ipl.tracks, t)
+			}
+		}
+		im.playlists = append(im.playlists, ipl)
+	}
+	return im, nil
+}
+
+// importMPD reads MPD's stored playlists and, when there is a sticker
+// database, the song stickers clients such as myMPD and Cantata keep:
+// "rating" (0–10), "playCount" and "lastPlayed" (Unix time). The locations
+// come from mpd.conf unless given.
+func importMPD(args []string) (*imported, error) {
+	conf, confErr := mpdfiles.FindConfig()
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return nil, errors.New(importUsage)
+		}
+		switch args[i] {
+		case "--music-dir":
+			conf.MusicDir = args[i+1]
+		case "--playlist-dir":
+			conf.PlaylistDir = args[i+1]
+		case "--sticker-file":
+			conf.StickerFile = args[i+1]
+		default:
+			return nil, errors.New(importUsage)
+		}
+	}
+	if conf.MusicDir == "" {
+		if confErr != nil {
+			return nil, fmt.Errorf("import: %w; give --music-dir", confErr)
+		}
+		return nil, errors.New("import: mpd.conf sets no music_directory; give --music-dir")
+	}
+
+	im := &imported{source: "MPD", missingHint: "; use --music-dir if the music folder moved"}
+	tracks := make(map[string]playlist.Track) // by MPD URI
+	lost := make(map[string]bool)
+	track := func(uri string) (playlist.Track, bool) {
+		if t, ok := tracks[uri]; ok {
+			return t, true
+		}
+		if lost[uri] {
+			return playlist.Track{}, false
+		}
+		if playlist.IsURL(uri) {
+			return playlist.TrackFromPath(uri), true
+		}
+		path := uri
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(conf.MusicDir, filepath.FromSlash(uri))
+		}
+		if _, err := os.Stat(path); err != nil {
+			lost[uri] = true
+			im.missing++
+			return playlist.Track{}, false
+		}
+		t := playlist.TrackFromPath(path)
+		tracks[uri] = t
+		im.found++
+		return t, true
+	}
+
+	if conf.PlaylistDir != "" {
+		lists, err := mpdfiles.Playlists(conf.PlaylistDir)
+		if err != nil {
+			return nil, fmt.Errorf("import: %w", err)
+		}
+		for _, pl := range lists {
+			ipl := importedPlaylist{name: pl.Name}
+			for _, e := range pl.Entries {
+				if t, ok := track(e); ok {
+					ipl.tracks = append(ipl.tracks, t)
+				}
+			}
+			im.playlists = append(im.playlists, ipl)
+		}
+	}
+
+	if conf.StickerFile != "" {
+		stickers, err := mpdfiles.Stickers(conf.StickerFile)
+		if err != nil {
+			return nil, fmt.Errorf("import: stickers: %w", err)
+		}
+		plays := make(map[string]*importedPlays)
+		var order []string
+		for _, s := range stickers {
+			n, err := strconv.Atoi(s.Value)
+			if err != nil || n <= 0 {
+				continue
+			}
+			if s.Name != "rating" && s.Name != "playCount" && s.Name != "lastPlayed" {
+				continue
+			}
+			t, ok := track(s.URI)
+			if !ok || t.Stream {
+				continue
+			}
+			if s.Name == "rating" {
+				stars := min((n+1)/2, 5)
+				im.rated[stars] = append(im.rated[stars], t)
+				continue
+			}
+			p := plays[s.URI]
+			if p == nil {
+				p = &importedPlays{track: t}
+				plays[s.URI] = p
+				order = append(order, s.URI)
+			}
+			if s.Name == "playCount" {
+				p.n = n
+			} else {
+				p.last = time.Unix(int64(n), 0)
+			}
+		}
+		for _, uri := range order {
+			p := plays[uri]
+			if p.n > 0 && p.last.IsZero() {
+				// A play count without lastPlayed: the file's modification
+				// time is the best guess MPD leaves.
+				if info, err := os.Stat(p.track.Path); err == nil {
+					p.last = info.ModTime()
+				}
+			}
+			if p.n > 0 {
+				im.plays = append(im.plays, *p)
+			}
+		}
+	}
+	return im, nil
+}
@@ -0,0 +1,130 @@
+package appdir
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Store is a map kept as one JSON file in the config directory, for the
+// small settings cliamp remembers per track. It is safe for concurrent use.
+type Store[V any] struct {
+	mu   sync.Mutex
+	path string // "" keeps the store in memory only
+	m    map[string]V
+}
+
+// OpenStore loads the store kept in file in the config directory.
+func OpenStore[V any](file string) *Store[V] {
+	dir, err := Dir()
+	if err != nil {
+		dir = ""
+	}
+	return LoadStore[V](dir, file)
+}
+
+// LoadStore loads the store kept in file in dir. An empty dir keeps it in
+// memory only.
+func LoadStore[V any](dir, file string) *Store[V] {
+	s := &Store[V]{m: make(map[string]V)}
+	if dir != "" {
+		s.path = filepath.Join(dir, file)
+		if data, err := os.ReadFile(s.path); err == nil {
+			json.Unmarshal(data, &s.m)
+		}
+	}
+	return s
+}
+
+// Get returns the value kept under key.
+func (s *Store[V]) Get(key string) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+// Set keeps v under key.
+func (s *Store[V]) Set(key string, v V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = v
+	s.save()
+}
+
+// Delete drops the value kept under key.
+func (s *Store[V]) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.m[key]; ok {
+		delete(s.m, key)
+		s.save()
+	}
+}
+
+// Update replaces the value under key with what fn makes of it, in one
+// step. fn is passed the current value and whether there is one, and
+// returns the new value and whether to keep it.
+func (s *Store[V]) Update(key string, fn func(v V, ok bool) (V, bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.m[key]
+	if v, keep := fn(old, ok); keep {
+		s.m[key] = v
+	} else {
+		delete(s.m, key)
+	}
+	s.save()
+}
+
+// Rename moves what is kept for each file in moved, old path → new path,
+// to its new path, and saves the store. An entry already kept for a new
+// path is replaced. Unlike the other changes, a failed write is reported,
+// so that files being organized can be moved back.
+func (s *Store[V]) Rename(moved map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for from, to := range moved {
+		from, to = TrackKey(from), TrackKey(to)
+		if v, ok := s.m[from]; ok && from != to {
+			s.m[to] = v
+			delete(s.m, from)
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	return s.save()
+}
+
+// save writes the store to disk. Callers other than Rename ignore the
+// error so a failed write never disrupts playback. Caller holds mu.
+func (s *Store[V]) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+// TrackKey identifies a track in the stores: URLs as they are, local files
+// by absolute path so a file is found however it was opened.
+func TrackKey(path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
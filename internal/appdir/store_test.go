@@ -0,0 +1,57 @@
+package appdir
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	s := LoadStore[int](dir, "test.json")
+	s.Set("a", 1)
+	s.Set("b", 2)
+	s.Update("b", func(v int, ok bool) (int, bool) { return v + 1, ok })
+	s.Update("c", func(v int, ok bool) (int, bool) { return 0, false })
+
+	r := LoadStore[int](dir, "test.json")
+	if v, ok := r.Get("a"); !ok || v != 1 {
+		t.Fatalf("reloaded a = %v, %v; want 1", v, ok)
+	}
+	if v, _ := r.Get("b"); v != 3 {
+		t.Fatalf("updated b = %v, want 3", v)
+	}
+	if _, ok := r.Get("c"); ok {
+		t.Fatal("Update kept a value fn dropped")
+	}
+
+	s.Delete("a")
+	if _, ok := LoadStore[int](dir, "test.json").Get("a"); ok {
+		t.Fatal("a kept after Delete")
+	}
+
+	if _, ok := LoadStore[int]("", "test.json").Get("b"); ok {
+		t.Fatal("an in-memory store read the file")
+	}
+}
+
+func TestStoreRename(t *testing.T) {
+	dir := t.TempDir()
+	from, to := filepath.Join(dir, "old.flac"), filepath.Join(dir, "new", "song.flac")
+	s := LoadStore[string](dir, "test.json")
+	s.Set(TrackKey(from), "kept")
+	s.Set(TrackKey(filepath.Join(dir, "other.flac")), "other")
+
+	if err := s.Rename(map[string]string{from: to, filepath.Join(dir, "none.flac"): to}); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	r := LoadStore[string](dir, "test.json")
+	if v, _ := r.Get(TrackKey(to)); v != "kept" {
+		t.Fatalf("value at the new path = %q, want kept", v)
+	}
+	if _, ok := r.Get(TrackKey(from)); ok {
+		t.Fatal("value still kept at the old path")
+	}
+	if v, _ := r.Get(TrackKey(filepath.Join(dir, "other.flac"))); v != "other" {
+		t.Fatal("Rename touched another track")
+	}
+}
@@ -0,0 +1,150 @@
+// Package audiobook decides which files are audiobooks and remembers how
+// far each one has been listened to, in
+// ~/.config/cliamp/audiobook_positions.json.
+package audiobook
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cliamp/internal/appdir"
+)
+
+const positionsFile = "audiobook_positions.json"
+
+// Rewind is how far before the saved position a book picks up again, so
+// the listener hears the last sentence or two before where they left off.
+const Rewind = 30 * time.Second
+
+// Library holds the configured audiobook locations and saved positions.
+// It is safe for concurrent use.
+type Library struct {
+	mu        sync.Mutex
+	dir       string         // config directory; "" disables saving
+	roots     []string       // directories and files marked as audiobooks
+	positions map[string]int // absolute file path → seconds
+}
+
+// New loads saved positions from the config directory. roots lists the
+// directories (covering everything below them) and single files to treat
+// as audiobooks; a leading "~/" is expanded.
+func New(roots []string) *Library {
+	dir, err := appdir.Dir()
+	if err != nil {
+		dir = ""
+	}
+	return newLibrary(dir, roots)
+}
+
+func newLibrary(dir string, roots []string) *Library {
+	l := &Library{dir: dir, positions: make(map[string]int)}
+	for _, r := range roots {
+		if r = strings.TrimSpace(r); r != "" {
+			l.roots = append(l.roots, absPath(r))
+		}
+	}
+	if dir != "" {
+		if data, err := os.ReadFile(filepath.Join(dir, positionsFile)); err == nil {
+			json.Unmarshal(data, &l.positions)
+		}
+	}
+	return l
+}
+
+// Is reports whether path is an audiobook: an .m4b file, or a file at or
+// under one of the configured roots. Remote URLs never are.
+func (l *Library) Is(path string) bool {
+	if l == nil || path == "" || strings.Contains(path, "://") {
+		return false
+	}
+	if strings.EqualFold(filepath.Ext(path), ".m4b") {
+		return true
+	}
+	p := absPath(path)
+	for _, r := range l.roots {
+		if p == r || strings.HasPrefix(p, r+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Position returns the saved position in seconds for path, or 0.
+func (l *Library) Position(path string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.positions[absPath(path)]
+}
+
+// SetPosition records how far playback got in path. A non-positive secs
+// forgets the position, e.g. after the book finished. Errors are ignored
+// so a failed write never disrupts playback.
+func (l *Library) SetPosition(path string, secs int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := absPath(path)
+	if secs > 0 {
+		if l.positions[key] == secs {
+			return
+		}
+		l.positions[key] = secs
+	} else if _, ok := l.positions[key]; ok {
+		delete(l.positions, key)
+	} else {
+		return
+	}
+	if l.dir == "" {
+		return
+	}
+	data, err := json.Marshal(l.positions)
+	if err != nil {
+		return
+	}
+	_ = os.MkdirAll(l.dir, 0o755)
+	_ = os.WriteFile(filepath.Join(l.dir, positionsFile), data, 0o600)
+}
+
+// Rename moves the saved positions of files that moved, old path → new
+// path. A failed write is reported, unlike in SetPosition.
+func (l *Library) Rename(moved map[string]string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for from, to := range moved {
+		from, to = absPath(from), absPath(to)
+		if secs, ok := l.positions[from]; ok && from != to {
+			l.positions[to] = secs
+			delete(l.positions, from)
+			n++
+		}
+	}
+	if n == 0 || l.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(l.positions)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(l.dir, positionsFile), data, 0o600)
+}
+
+// absPath expands "~/" and makes path absolute and clean, so one file
+// always maps to the same key however it was opened.
+func absPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
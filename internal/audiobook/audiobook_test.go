@@ -0,0 +1,55 @@
+package audiobook
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIs(t *testing.T) {
+	root := t.TempDir()
+	books := filepath.Join(root, "Books")
+	lecture := filepath.Join(root, "lecture.mp3")
+	l := newLibrary("", []string{books, lecture})
+
+	for path, want := range map[string]bool{
+		filepath.Join(books, "Dune", "01.mp3"):   true,
+		lecture:                                  true,
+		filepath.Join(root, "Books2", "a.mp3"):   false,
+		filepath.Join(root, "song.mp3"):          false,
+		filepath.Join(root, "anything.M4B"):      true,
+		"https://example.com/books/chapter1.mp3": false,
+	} {
+		if got := l.Is(path); got != want {
+			t.Errorf("Is(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestPositionPersisted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "book.m4b")
+
+	l := newLibrary(dir, nil)
+	l.SetPosition(path, 1234)
+	if got := newLibrary(dir, nil).Position(path); got != 1234 {
+		t.Fatalf("reloaded position = %d, want 1234", got)
+	}
+	l.SetPosition(path, 0)
+	if got := newLibrary(dir, nil).Position(path); got != 0 {
+		t.Fatalf("position after finish = %d, want 0", got)
+	}
+}
+
+func TestRename(t *testing.T) {
+	dir := t.TempDir()
+	path, to := filepath.Join(dir, "book.m4b"), filepath.Join(dir, "Author", "book.m4b")
+	l := newLibrary(dir, nil)
+	l.SetPosition(path, 600)
+	if err := l.Rename(map[string]string{path: to}); err != nil {
+		t.Fatal(err)
+	}
+	r := newLibrary(dir, nil)
+	if r.Position(to) != 600 || r.Position(path) != 0 {
+		t.Fatalf("positions after Rename: new %d, old %d; want 600, 0", r.Position(to), r.Position(path))
+	}
+}
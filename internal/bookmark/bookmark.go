@@ -0,0 +1,66 @@
+// Package bookmark stores named positions inside tracks, such as the
+// drops in a DJ mix or the topics of a lecture, in
+// ~/.config/cliamp/bookmarks.json.
+package bookmark
+
+import (
+	"cmp"
+	"slices"
+
+	"cliamp/internal/appdir"
+)
+
+const bookmarksFile = "bookmarks.json"
+
+// Mark is a named position in a track.
+type Mark struct {
+	Name string `json:"name"`
+	Secs int    `json:"secs"`
+}
+
+// Store holds the bookmarks of every track, sorted by position, by track
+// key. It is safe for concurrent use.
+type Store struct {
+	marks *appdir.Store[[]Mark]
+}
+
+// New loads saved bookmarks from the config directory.
+func New() *Store {
+	return &Store{marks: appdir.OpenStore[[]Mark](bookmarksFile)}
+}
+
+func newStore(dir string) *Store {
+	return &Store{marks: appdir.LoadStore[[]Mark](dir, bookmarksFile)}
+}
+
+// Marks returns the bookmarks of path sorted by position.
+func (s *Store) Marks(path string) []Mark {
+	marks, _ := s.marks.Get(appdir.TrackKey(path))
+	return slices.Clone(marks)
+}
+
+// Add bookmarks path at m.Secs, replacing a mark already at that second.
+func (s *Store) Add(path string, m Mark) {
+	s.marks.Update(appdir.TrackKey(path), func(marks []Mark, _ bool) ([]Mark, bool) {
+		marks = slices.DeleteFunc(marks, func(o Mark) bool { return o.Secs == m.Secs })
+		marks = append(marks, m)
+		slices.SortFunc(marks, func(a, b Mark) int { return cmp.Compare(a.Secs, b.Secs) })
+		return marks, true
+	})
+}
+
+// Remove deletes the i-th bookmark of path, as ordered by Marks.
+func (s *Store) Remove(path string, i int) {
+	s.marks.Update(appdir.TrackKey(path), func(marks []Mark, ok bool) ([]Mark, bool) {
+		if i < 0 || i >= len(marks) {
+			return marks, ok
+		}
+		marks = slices.Delete(marks, i, i+1)
+		return marks, len(marks) > 0
+	})
+}
+
+// Rename moves the bookmarks of files that moved, old path → new path.
+func (s *Store) Rename(moved map[string]string) error {
+	return s.marks.Rename(moved)
+}
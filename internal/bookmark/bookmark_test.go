@@ -0,0 +1,21 @@
+package bookmark
+
+import "testing"
+
+func TestMarksSortedAndReplaced(t *testing.T) {
+	s := newStore("")
+	s.Add("mix.flac", Mark{Name: "drop", Secs: 300})
+	s.Add("mix.flac", Mark{Name: "intro", Secs: 10})
+	s.Add("mix.flac", Mark{Name: "the drop", Secs: 300}) // replaces "drop"
+
+	marks := s.Marks("mix.flac")
+	if len(marks) != 2 || marks[0].Name != "intro" || marks[1].Name != "the drop" {
+		t.Fatalf("marks = %+v", marks)
+	}
+	s.Remove("mix.flac", 5) // out of range
+	s.Remove("mix.flac", 0)
+	s.Remove("mix.flac", 0)
+	if marks := s.Marks("mix.flac"); len(marks) != 0 {
+		t.Fatalf("marks after removing all = %+v", marks)
+	}
+}
@@ -0,0 +1,179 @@
+// Package fingerprint computes acoustic fingerprints of recordings, in
+// the manner of Chromaprint, so that `cliamp dupes` can find the same
+// recording stored twice in different formats or bitrates.
+//
+// A fingerprint holds 32 bits per frame of audio. Each bit says whether
+// the energy difference between two neighbouring frequency bands rose or
+// fell since the previous frame, which survives lossy encoding, resampling
+// and changes of level.
+package fingerprint
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	rate      = 11025 // samples per second audio is decoded at
+	frameSize = 2048  // samples per spectrum, a power of two
+	hop       = 128   // samples between frames, about 12 ms
+	bands     = 33    // frequency bands, giving 32 bits a frame
+	loHz      = 300
+	hiHz      = 2000
+	maxFrames = 5000 // fingerprint the first minute or so
+)
+
+// Print is the fingerprint of a recording and its length.
+type Print struct {
+	Frames   []uint32
+	Duration time.Duration
+}
+
+// Compute decodes the file at path with ffmpeg and fingerprints it.
+func Compute(path string) (Print, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return Print{}, errors.New("fingerprinting requires ffmpeg")
+	}
+	cmd := exec.Command("ffmpeg", "-nostdin", "-v", "error", "-i", path,
+		"-map", "0:a:0", "-f", "f32le", "-ac", "1", "-ar", fmt.Sprint(rate), "pipe:1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return Print{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return Print{}, err
+	}
+	// Only the start is fingerprinted, but the whole file is decoded for
+	// its length, which tags do not always give.
+	limit := (maxFrames-1)*hop + frameSize
+	var samples []float64
+	total := 0
+	r := bufio.NewReaderSize(out, 64*1024)
+	buf := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			break
+		}
+		if total < limit {
+			samples = append(samples, float64(math.Float32frombits(binary.LittleEndian.Uint32(buf))))
+		}
+		total++
+	}
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Print{}, fmt.Errorf("ffmpeg: %s", msg)
+		}
+		return Print{}, fmt.Errorf("ffmpeg: %w", err)
+	}
+	return Print{
+		Frames:   frames(samples),
+		Duration: time.Duration(total) * time.Second / rate,
+	}, nil
+}
+
+// frames fingerprints mono samples at rate.
+func frames(samples []float64) []uint32 {
+	// Band edges, spaced evenly on a log scale, as FFT bin indexes.
+	var edges [bands + 1]int
+	for i := range edges {
+		hz := loHz * math.Pow(hiHz/loHz, float64(i)/bands)
+		edges[i] = int(hz * frameSize / rate)
+	}
+	window := make([]float64, frameSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/(frameSize-1))
+	}
+
+	var out []uint32
+	var prev [bands]float64
+	spec := make([]complex128, frameSize)
+	for n := 0; n*hop+frameSize <= len(samples); n++ {
+		for i := range spec {
+			spec[i] = complex(samples[n*hop+i]*window[i], 0)
+		}
+		fft(spec)
+		var energy [bands]float64
+		for b := range bands {
+			for k := edges[b]; k < max(edges[b+1], edges[b]+1); k++ {
+				energy[b] += real(spec[k])*real(spec[k]) + imag(spec[k])*imag(spec[k])
+			}
+		}
+		if n > 0 {
+			var f uint32
+			for b := range bands - 1 {
+				if (energy[b]-energy[b+1])-(prev[b]-prev[b+1]) > 0 {
+					f |= 1 << b
+				}
+			}
+			out = append(out, f)
+		}
+		prev = energy
+	}
+	return out
+}
+
+// fft transforms x in place; len(x) is a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	shift := bits.UintSize - bits.TrailingZeros(uint(n))
+	for i := range x {
+		if j := int(bits.Reverse(uint(i)) >> shift); j > i {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			t := complex(1, 0)
+			for j := range size / 2 {
+				a, b := start+j, start+j+size/2
+				u, v := x[a], x[b]*t
+				x[a], x[b] = u+v, u-v
+				t *= w
+			}
+		}
+	}
+}
+
+// maxShift is how far apart, in frames, two copies of a recording may
+// start: about two seconds of silence more or less at the beginning.
+const maxShift = 2 * rate / hop
+
+// minOverlap is the fewest frames two fingerprints are compared over.
+const minOverlap = 400
+
+// Similarity compares two fingerprints, aligning them within a couple of
+// seconds: 1 for identical audio, about 0.5 for unrelated recordings, and
+// 0 when either is too short to tell.
+func Similarity(a, b Print) float64 {
+	best := 0.0
+	for shift := -maxShift; shift <= maxShift; shift++ {
+		x, y := a.Frames, b.Frames
+		if shift > 0 {
+			x = x[min(shift, len(x)):]
+		} else {
+			y = y[min(-shift, len(y)):]
+		}
+		n := min(len(x), len(y))
+		if n < minOverlap {
+			continue
+		}
+		diff := 0
+		for i := range n {
+			diff += bits.OnesCount32(x[i] ^ y[i])
+		}
+		best = max(best, 1-float64(diff)/float64(32*n))
+	}
+	return best
+}
@@ -0,0 +1,55 @@
+package fingerprint
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// melody returns secs of random chords of plucked, harmonic-rich notes,
+// a new one every 150 ms.
+func melody(seed int64, secs float64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	s := make([]float64, int(secs*rate))
+	var freqs [4]float64
+	start := 0
+	for i := range s {
+		if i%(rate*15/100) == 0 {
+			for j := range freqs {
+				freqs[j] = 100 + rng.Float64()*400
+			}
+			start = i
+		}
+		env := math.Exp(-float64(i-start) / (rate / 10))
+		for _, f := range freqs {
+			for h := 1.0; h <= 8 && f*h < rate/2; h++ {
+				s[i] += env * 0.1 / h * math.Sin(2*math.Pi*f*h*float64(i)/rate)
+			}
+		}
+	}
+	return s
+}
+
+func TestSimilarity(t *testing.T) {
+	orig := Print{Frames: frames(melody(1, 30))}
+
+	// Another encoding: quieter, a little noise, and 23 ms of encoder delay.
+	rng := rand.New(rand.NewSource(9))
+	src := melody(1, 30)
+	dup := append(make([]float64, 256), src...)
+	for i := range dup {
+		dup[i] = dup[i]*0.5 + rng.NormFloat64()*0.005
+	}
+	if got := Similarity(orig, Print{Frames: frames(dup)}); got < 0.9 {
+		t.Errorf("similarity of a copy = %.2f, want at least 0.9", got)
+	}
+
+	other := Print{Frames: frames(melody(2, 30))}
+	if got := Similarity(orig, other); got > 0.65 {
+		t.Errorf("similarity of another recording = %.2f, want at most 0.65", got)
+	}
+
+	if got := Similarity(orig, Print{Frames: frames(melody(1, 1))}); got != 0 {
+		t.Errorf("similarity to a 1 s print = %.2f, want 0", got)
+	}
+}
@@ -0,0 +1,316 @@
+// Package history logs what was listened to, and for how long, in
+// ~/.config/cliamp/history.jsonl, and summarises and exports that log.
+//
+// The log is JSON Lines, one listen per line, so recording a listen is a
+// single append however long the history grows.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cliamp/internal/appdir"
+)
+
+const historyFile = "history.jsonl"
+
+// Entry is one listen.
+type Entry struct {
+	Time   time.Time `json:"time"` // when listening started
+	Path   string    `json:"path"`
+	Title  string    `json:"title,omitempty"`
+	Artist string    `json:"artist,omitempty"`
+	Album  string    `json:"album,omitempty"`
+	Secs   int       `json:"secs"` // seconds actually heard, pauses excluded
+}
+
+// Log appends listens to the history file. It is safe for concurrent use.
+type Log struct {
+	mu   sync.Mutex
+	path string // "" disables recording
+}
+
+// New opens the history log in the config directory.
+func New() *Log {
+	dir, err := appdir.Dir()
+	if err != nil {
+		return &Log{}
+	}
+	return &Log{path: filepath.Join(dir, historyFile)}
+}
+
+// Add appends entries to the log. Errors are ignored so a failed write
+// never disrupts playback.
+func (l *Log) Add(entries ...Entry) {
+	if l == nil || l.path == "" || len(entries) == 0 {
+		return
+	}
+	var data []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		data = append(append(data, line...), '\n')
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = os.MkdirAll(filepath.Dir(l.path), 0o755)
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	f.Write(data)
+	f.Close()
+}
+
+// Entries reads the whole log, oldest first. A missing log is empty;
+// lines that do not parse are skipped.
+func (l *Log) Entries() ([]Entry, error) {
+	if l == nil || l.path == "" {
+		return nil, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.Path != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+// Rename points the listens of files that moved, old absolute path → new,
+// at their new path. The log is rewritten into a new file that replaces it
+// only once complete, so a failure leaves it as it was; other lines are
+// kept byte for byte.
+func (l *Log) Rename(moved map[string]string) error {
+	if l == nil || l.path == "" || len(moved) == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	changed := false
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		var e Entry
+		if json.Unmarshal(line, &e) == nil && e.Path != "" && !strings.Contains(e.Path, "://") {
+			if abs, err := filepath.Abs(e.Path); err == nil {
+				if to, ok := moved[abs]; ok {
+					e.Path = to
+					if b, err := json.Marshal(e); err == nil {
+						line = append(b, '\n')
+						changed = true
+					}
+				}
+			}
+		}
+		out.Write(line)
+	}
+	if !changed {
+		return nil
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, out.Bytes(), 0o600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// Count is one row of a top list.
+type Count struct {
+	Name  string
+	Plays int
+	Secs  int
+}
+
+// Stats summarises a history.
+type Stats struct {
+	Plays    int
+	Listened time.Duration
+	Week     time.Duration // listened in the seven days before now
+	Since    time.Time     // first listen
+	Artists  []Count       // by plays, most first
+	Tracks   []Count
+}
+
+// Summarize totals entries and keeps the top n artists and tracks.
+func Summarize(entries []Entry, n int, now time.Time) Stats {
+	var st Stats
+	artists := make(map[string]*Count)
+	tracks := make(map[string]*Count)
+	tally := func(m map[string]*Count, name string, secs int) {
+		c, ok := m[strings.ToLower(name)]
+		if !ok {
+			c = &Count{Name: name}
+			m[strings.ToLower(name)] = c
+		}
+		c.Plays++
+		c.Secs += secs
+	}
+	for _, e := range entries {
+		d := time.Duration(e.Secs) * time.Second
+		st.Plays++
+		st.Listened += d
+		if now.Sub(e.Time) < 7*24*time.Hour {
+			st.Week += d
+		}
+		if st.Since.IsZero() || e.Time.Before(st.Since) {
+			st.Since = e.Time
+		}
+		if e.Artist != "" {
+			tally(artists, e.Artist, e.Secs)
+		}
+		tally(tracks, e.label(), e.Secs)
+	}
+	st.Artists = top(artists, n)
+	st.Tracks = top(tracks, n)
+	return st
+}
+
+// Plays counts the listens of path in entries and returns the latest.
+func Plays(entries []Entry, path string) (n int, last time.Time) {
+	for _, e := range entries {
+		if e.Path == path {
+			n++
+			if e.Time.After(last) {
+				last = e.Time
+			}
+		}
+	}
+	return n, last
+}
+
+// Listening sums up the listens of one track.
+type Listening struct {
+	Plays int
+	Secs  int       // seconds heard over all listens
+	Last  time.Time // when the latest listen started
+}
+
+// ByTrack sums up entries per track path.
+func ByTrack(entries []Entry) map[string]Listening {
+	m := make(map[string]Listening)
+	for _, e := range entries {
+		l := m[e.Path]
+		l.Plays++
+		l.Secs += e.Secs
+		if e.Time.After(l.Last) {
+			l.Last = e.Time
+		}
+		m[e.Path] = l
+	}
+	return m
+}
+
+// label names the track of e as "Artist - Title", falling back to the
+// file name.
+func (e Entry) label() string {
+	title := e.Title
+	if title == "" {
+		title = filepath.Base(e.Path)
+	}
+	if e.Artist != "" {
+		return e.Artist + " - " + title
+	}
+	return title
+}
+
+func top(m map[string]*Count, n int) []Count {
+	out := make([]Count, 0, len(m))
+	for _, c := range m {
+		out = append(out, *c)
+	}
+	slices.SortFunc(out, func(a, b Count) int {
+		return cmp.Or(cmp.Compare(b.Plays, a.Plays), cmp.Compare(b.Secs, a.Secs), cmp.Compare(a.Name, b.Name))
+	})
+	return out[:min(n, len(out))]
+}
+
+// WriteJSON exports entries as a JSON array.
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteCSV exports entries as CSV with a header row. Times are RFC 3339.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "artist", "title", "album", "seconds", "path"})
+	for _, e := range entries {
+		cw.Write([]string{e.Time.Format(time.RFC3339), e.Artist, e.Title, e.Album, strconv.Itoa(e.Secs), e.Path})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSummary prints st for humans: totals, then the top artists and
+// tracks.
+func WriteSummary(w io.Writer, st Stats) error {
+	if st.Plays == 0 {
+		_, err := io.WriteString(w, "No listening history yet.\n")
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s listened over %d plays since %s (%s in the last 7 days)\n",
+		Hours(st.Listened), st.Plays, st.Since.Format("2006-01-02"), Hours(st.Week))
+	for _, list := range []struct {
+		title string
+		rows  []Count
+	}{{"Top artists", st.Artists}, {"Top tracks", st.Tracks}} {
+		if len(list.rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "\n%s:\n", list.title)
+		for i, c := range list.rows {
+			fmt.Fprintf(bw, "%3d. %s  (%d plays, %s)\n", i+1, c.Name, c.Plays, Hours(time.Duration(c.Secs)*time.Second))
+		}
+	}
+	return bw.Flush()
+}
+
+// Hours renders a listening total as "3h 05m", or "12m" under an hour.
+func Hours(d time.Duration) string {
+	m := int(d.Minutes())
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh %02dm", m/60, m%60)
+}
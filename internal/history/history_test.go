@@ -0,0 +1,72 @@
+package history
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogAndSummarize(t *testing.T) {
+	l := &Log{path: filepath.Join(t.TempDir(), historyFile)}
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	l.Add(Entry{Time: now.Add(-30 * 24 * time.Hour), Path: "/m/a.mp3", Title: "Song A", Artist: "Band", Secs: 200})
+	l.Add(Entry{Time: now.Add(-2 * time.Hour), Path: "/m/a.mp3", Title: "Song A", Artist: "band", Secs: 180})
+	l.Add(Entry{Time: now.Add(-time.Hour), Path: "/m/b.mp3", Secs: 60})
+
+	entries, err := l.Entries()
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Entries = %d, %v; want 3 entries", len(entries), err)
+	}
+
+	st := Summarize(entries, 5, now)
+	if st.Plays != 3 || st.Listened != 440*time.Second || st.Week != 240*time.Second {
+		t.Fatalf("totals = %d plays, %v, week %v", st.Plays, st.Listened, st.Week)
+	}
+	if len(st.Artists) != 1 || st.Artists[0].Plays != 2 || st.Artists[0].Secs != 380 {
+		t.Fatalf("Artists = %+v, want Band twice", st.Artists)
+	}
+	if n, last := Plays(entries, "/m/a.mp3"); n != 2 || !last.Equal(now.Add(-2*time.Hour)) {
+		t.Fatalf("Plays = %d, %v; want 2, the later listen", n, last)
+	}
+	if len(st.Tracks) != 2 || st.Tracks[0].Name != "Band - Song A" || st.Tracks[1].Name != "b.mp3" {
+		t.Fatalf("Tracks = %+v", st.Tracks)
+	}
+
+	var b bytes.Buffer
+	if err := WriteCSV(&b, entries[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "time,artist,title,album,seconds,path\n2024-05-11T12:00:00Z,Band,Song A,,200,/m/a.mp3\n"; b.String() != want {
+		t.Fatalf("CSV = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	if err := WriteSummary(&b, st); err != nil || !strings.Contains(b.String(), "7m listened over 3 plays") {
+		t.Fatalf("summary = %q, %v", b.String(), err)
+	}
+}
+
+func TestEntriesMissingLog(t *testing.T) {
+	l := &Log{path: filepath.Join(t.TempDir(), historyFile)}
+	if entries, err := l.Entries(); err != nil || entries != nil {
+		t.Fatalf("Entries = %v, %v; want empty", entries, err)
+	}
+}
+
+func TestRename(t *testing.T) {
+	dir := t.TempDir()
+	l := &Log{path: filepath.Join(dir, historyFile)}
+	old, other := filepath.Join(dir, "a.mp3"), filepath.Join(dir, "b.mp3")
+	l.Add(Entry{Path: old, Secs: 10}, Entry{Path: other, Secs: 20}, Entry{Path: old, Secs: 30})
+
+	to := filepath.Join(dir, "Band", "a.mp3")
+	if err := l.Rename(map[string]string{old: to}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := l.Entries()
+	if len(entries) != 3 || entries[0].Path != to || entries[1].Path != other || entries[2].Path != to || entries[2].Secs != 30 {
+		t.Fatalf("entries after Rename = %+v", entries)
+	}
+}
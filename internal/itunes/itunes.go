@@ -0,0 +1,239 @@
+// Package itunes reads the library export of iTunes and Apple Music, the
+// Library.xml property list, for `cliamp import itunes`.
+package itunes
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Track is one song of the library.
+type Track struct {
+	ID          int
+	Name        string
+	Artist      string
+	Album       string
+	Genre       string
+	Year        int
+	TrackNumber int
+	Duration    time.Duration
+	Path        string // local file; "" for streams and cloud-only tracks
+	Plays       int
+	LastPlayed  time.Time // zero when never played
+	Added       time.Time
+	Rating      int // stars, 0–5; ratings iTunes derived from the album are left out
+}
+
+// Playlist is a user playlist, its tracks in order.
+type Playlist struct {
+	Name   string
+	Tracks []int // Track IDs
+}
+
+// Library is the content of a Library.xml.
+type Library struct {
+	Tracks    map[int]Track
+	Playlists []Playlist
+}
+
+// Read parses a Library.xml. The built-in playlists (the whole library,
+// Music, Podcasts and so on) and playlist folders are skipped.
+func Read(r io.Reader) (*Library, error) {
+	d := xml.NewDecoder(r)
+	root, err := decodePlist(d)
+	if err != nil {
+		return nil, err
+	}
+	top, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("not an iTunes library: the plist holds no dictionary")
+	}
+	tracks, ok := top["Tracks"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("not an iTunes library: no Tracks")
+	}
+
+	lib := &Library{Tracks: make(map[int]Track, len(tracks))}
+	for _, v := range tracks {
+		if t, ok := v.(map[string]any); ok {
+			tr := trackFrom(t)
+			lib.Tracks[tr.ID] = tr
+		}
+	}
+	lists, _ := top["Playlists"].([]any)
+	for _, v := range lists {
+		p, ok := v.(map[string]any)
+		if !ok || p["Master"] == true || p["Folder"] == true || p["Visible"] == false || p["Distinguished Kind"] != nil {
+			continue
+		}
+		pl := Playlist{Name: str(p["Name"])}
+		items, _ := p["Playlist Items"].([]any)
+		for _, it := range items {
+			if it, ok := it.(map[string]any); ok {
+				if id := num(it["Track ID"]); id != 0 {
+					pl.Tracks = append(pl.Tracks, id)
+				}
+			}
+		}
+		lib.Playlists = append(lib.Playlists, pl)
+	}
+	return lib, nil
+}
+
+func trackFrom(t map[string]any) Track {
+	tr := Track{
+		ID:          num(t["Track ID"]),
+		Name:        str(t["Name"]),
+		Artist:      str(t["Artist"]),
+		Album:       str(t["Album"]),
+		Genre:       str(t["Genre"]),
+		Year:        num(t["Year"]),
+		TrackNumber: num(t["Track Number"]),
+		Duration:    time.Duration(num(t["Total Time"])) * time.Millisecond,
+		Plays:       num(t["Play Count"]),
+	}
+	tr.LastPlayed, _ = t["Play Date UTC"].(time.Time)
+	tr.Added, _ = t["Date Added"].(time.Time)
+	if t["Rating Computed"] != true {
+		tr.Rating = min(max(num(t["Rating"])/20, 0), 5)
+	}
+	if t["Track Type"] != "URL" && t["Track Type"] != "Remote" {
+		tr.Path = FilePath(str(t["Location"]))
+	}
+	return tr
+}
+
+// FilePath turns a Location, a file:// URL such as
+// "file://localhost/Users/me/Music/a%20b.m4a" or
+// "file://localhost/C:/Users/me/Music/a.mp3", into a local path. Anything
+// else yields "".
+func FilePath(location string) string {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	p := u.Path
+	// A Windows drive letter follows the root slash: "/C:/Users/...".
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return p
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func num(v any) int {
+	n, _ := v.(int64)
+	return int(n)
+}
+
+// decodePlist decodes the first value inside a <plist> element.
+func decodePlist(d *xml.Decoder) (any, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("not a property list")
+			}
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local != "plist" {
+			return decodeValue(d, se)
+		}
+	}
+}
+
+// decodeValue decodes the property list value that start opens: a dict as
+// map[string]any, an array as []any, integers as int64, reals as float64,
+// dates as time.Time, true and false as bool, strings and anything else
+// (data, for one) as its text.
+func decodeValue(d *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		m := make(map[string]any)
+		key := ""
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				if tok.Name.Local == "key" {
+					if key, err = text(d); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				v, err := decodeValue(d, tok)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+			case xml.EndElement:
+				return m, nil
+			}
+		}
+	case "array":
+		var a []any
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				v, err := decodeValue(d, tok)
+				if err != nil {
+					return nil, err
+				}
+				a = append(a, v)
+			case xml.EndElement:
+				return a, nil
+			}
+		}
+	case "true", "false":
+		return start.Name.Local == "true", d.Skip()
+	}
+	s, err := text(d)
+	if err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "integer":
+		n, _ := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		return n, nil
+	case "real":
+		f, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, nil
+	case "date":
+		t, _ := time.Parse(time.RFC3339, strings.TrimSpace(s))
+		return t, nil
+	}
+	return s, nil
+}
+
+// text reads the character data up to the end of the current element.
+func text(d *xml.Decoder) (string, error) {
+	var b strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			b.Write(tok)
+		case xml.EndElement:
+			return b.String(), nil
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package itunes
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const library = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple Computer//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Major Version</key><integer>1</integer>
+	<key>Tracks</key>
+	<dict>
+		<key>101</key>
+		<dict>
+			<key>Track ID</key><integer>101</integer>
+			<key>Name</key><string>Angel</string>
+			<key>Artist</key><string>Massive Attack</string>
+			<key>Album</key><string>Mezzanine</string>
+			<key>Total Time</key><integer>379000</integer>
+			<key>Track Number</key><integer>1</integer>
+			<key>Play Count</key><integer>12</integer>
+			<key>Play Date UTC</key><date>2024-03-01T20:15:00Z</date>
+			<key>Rating</key><integer>80</integer>
+			<key>Track Type</key><string>File</string>
+			<key>Location</key><string>file://localhost/Users/me/Music/Massive%20Attack/01%20Angel.m4a</string>
+		</dict>
+		<key>102</key>
+		<dict>
+			<key>Track ID</key><integer>102</integer>
+			<key>Name</key><string>Teardrop</string>
+			<key>Rating</key><integer>60</integer>
+			<key>Rating Computed</key><true/>
+			<key>Location</key><string>file://localhost/C:/Users/me/Music/Teardrop.mp3</string>
+		</dict>
+		<key>103</key>
+		<dict>
+			<key>Track ID</key><integer>103</integer>
+			<key>Name</key><string>Radio</string>
+			<key>Track Type</key><string>URL</string>
+			<key>Location</key><string>http://radio.example.com/stream</string>
+		</dict>
+	</dict>
+	<key>Playlists</key>
+	<array>
+		<dict>
+			<key>Name</key><string>Library</string>
+			<key>Master</key><true/>
+			<key>Playlist Items</key>
+			<array><dict><key>Track ID</key><integer>101</integer></dict></array>
+		</dict>
+		<dict>
+			<key>Name</key><string>Music</string>
+			<key>Distinguished Kind</key><integer>4</integer>
+		</dict>
+		<dict>
+			<key>Name</key><string>Night</string>
+			<key>Playlist Items</key>
+			<array>
+				<dict><key>Track ID</key><integer>102</integer></dict>
+				<dict><key>Track ID</key><integer>101</integer></dict>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`
+
+func TestRead(t *testing.T) {
+	lib, err := Read(strings.NewReader(library))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lib.Tracks) != 3 {
+		t.Fatalf("read %d tracks, want 3", len(lib.Tracks))
+	}
+	angel := lib.Tracks[101]
+	if angel.Path != "/Users/me/Music/Massive Attack/01 Angel.m4a" || angel.Artist != "Massive Attack" ||
+		angel.Duration != 379*time.Second || angel.Plays != 12 || angel.Rating != 4 ||
+		!angel.LastPlayed.Equal(time.Date(2024, 3, 1, 20, 15, 0, 0, time.UTC)) {
+		t.Errorf("track 101 = %+v", angel)
+	}
+	if tr := lib.Tracks[102]; tr.Path != "C:/Users/me/Music/Teardrop.mp3" || tr.Rating != 0 {
+		t.Errorf("track 102 = %+v, want a Windows path and no computed rating", tr)
+	}
+	if tr := lib.Tracks[103]; tr.Path != "" {
+		t.Errorf("stream track path = %q, want none", tr.Path)
+	}
+	if len(lib.Playlists) != 1 || lib.Playlists[0].Name != "Night" || len(lib.Playlists[0].Tracks) != 2 || lib.Playlists[0].Tracks[0] != 102 {
+		t.Errorf("playlists = %+v, want only Night with 102, 101", lib.Playlists)
+	}
+
+	if _, err := Read(strings.NewReader("<html></html>")); err == nil {
+		t.Error("Read of a non-library succeeded")
+	}
+}
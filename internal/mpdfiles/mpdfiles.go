@@ -0,0 +1,145 @@
+// Package mpdfiles reads what an MPD installation keeps on disk: the paths
+// in mpd.conf, the stored playlists and the sticker database, for
+// `cliamp import mpd`.
+package mpdfiles
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Config holds the locations mpd.conf names. Empty fields were not set.
+type Config struct {
+	MusicDir    string
+	PlaylistDir string
+	StickerFile string
+}
+
+// configPaths are where MPD looks for its configuration, in order.
+var configPaths = []string{"~/.config/mpd/mpd.conf", "~/.mpdconf", "~/.mpd/mpd.conf", "/etc/mpd.conf"}
+
+// FindConfig reads the first mpd.conf MPD itself would use. It returns
+// an error when there is none.
+func FindConfig() (Config, error) {
+	for _, p := range configPaths {
+		f, err := os.Open(expandHome(p))
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		return ReadConfig(f), nil
+	}
+	return Config{}, errors.New("no mpd.conf found")
+}
+
+// ReadConfig picks music_directory, playlist_directory and sticker_file
+// out of an mpd.conf. A leading "~/" is expanded.
+func ReadConfig(r io.Reader) Config {
+	var c Config
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		i := strings.IndexAny(line, " \t")
+		if i < 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val := line[:i], expandHome(strings.Trim(strings.TrimSpace(line[i:]), `"`))
+		switch key {
+		case "music_directory":
+			c.MusicDir = val
+		case "playlist_directory":
+			c.PlaylistDir = val
+		case "sticker_file":
+			c.StickerFile = val
+		}
+	}
+	return c
+}
+
+// Playlist is one stored playlist. Entries are as MPD wrote them: paths
+// relative to the music directory, absolute paths or URLs.
+type Playlist struct {
+	Name    string
+	Entries []string
+}
+
+// Playlists reads the .m3u files in dir, sorted by name.
+func Playlists(dir string) ([]Playlist, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.m3u"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	var lists []Playlist
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		pl := Playlist{Name: strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))}
+		for line := range strings.Lines(string(data)) {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				pl.Entries = append(pl.Entries, line)
+			}
+		}
+		lists = append(lists, pl)
+	}
+	return lists, nil
+}
+
+// Sticker is one song sticker: a name and value MPD clients attached to
+// the song at URI, relative to the music directory.
+type Sticker struct {
+	URI   string
+	Name  string
+	Value string
+}
+
+// Stickers reads the song stickers from the SQLite database at file. It
+// runs the sqlite3 command-line tool, which must be installed.
+func Stickers(file string) ([]Sticker, error) {
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+	bin, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return nil, errors.New("reading MPD stickers requires sqlite3")
+	}
+	// Tabs and newlines inside values would break the rows apart; MPD
+	// clients do not store them in URIs, names or the values cliamp
+	// reads.
+	out, err := exec.Command(bin, "-readonly", "-separator", "\t", file,
+		"SELECT uri, name, value FROM sticker WHERE type = 'song'").Output()
+	if err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+			return nil, fmt.Errorf("sqlite3: %s", bytes.TrimSpace(ee.Stderr))
+		}
+		return nil, err
+	}
+	var stickers []Sticker
+	for line := range strings.Lines(string(out)) {
+		f := strings.Split(strings.TrimRight(line, "\r\n"), "\t")
+		if len(f) == 3 {
+			stickers = append(stickers, Sticker{URI: f[0], Name: f[1], Value: f[2]})
+		}
+	}
+	return stickers, nil
+}
+
+func expandHome(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return p
+}
@@ -0,0 +1,51 @@
+package mpdfiles
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadConfig(t *testing.T) {
+	conf := `# An mpd.conf
+music_directory		"/srv/music"
+playlist_directory "/var/lib/mpd/playlists"
+#sticker_file "/nowhere"
+sticker_file "/var/lib/mpd/sticker.sql"
+audio_output {
+	type "pulse"
+}
+`
+	got := ReadConfig(strings.NewReader(conf))
+	want := Config{MusicDir: "/srv/music", PlaylistDir: "/var/lib/mpd/playlists", StickerFile: "/var/lib/mpd/sticker.sql"}
+	if got != want {
+		t.Errorf("ReadConfig = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlaylists(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.m3u":     "#EXTM3U\nArtist/Album/01.flac\n\n/abs/02.mp3\r\nhttp://radio.example/stream\n",
+		"a.m3u":     "x.ogg\n",
+		"notes.txt": "ignored\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lists, err := Playlists(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 2 || lists[0].Name != "a" || lists[1].Name != "b" {
+		t.Fatalf("Playlists = %+v, want a and b", lists)
+	}
+	want := []string{"Artist/Album/01.flac", "/abs/02.mp3", "http://radio.example/stream"}
+	if !slices.Equal(lists[1].Entries, want) {
+		t.Errorf("b entries = %q, want %q", lists[1].Entries, want)
+	}
+}
@@ -0,0 +1,253 @@
+// Package organize renames and moves audio files into folders named after
+// their tags, for `cliamp organize` and the track info view.
+package organize
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cliamp/internal/appdir"
+	"cliamp/internal/fileutil"
+	"cliamp/playlist"
+)
+
+// DefaultPattern is the layout used when none is configured.
+const DefaultPattern = "{artist}/{album}/{track} {title}.{ext}"
+
+// Move is one file to rename.
+type Move struct {
+	From, To string
+}
+
+// Path returns where pattern puts the local file of t below root. Fields
+// are {artist}, {album}, {title}, {track} and {disc} (two digits), {year},
+// {genre} and {ext}; "/" in the pattern separates folders. Missing artist
+// and album tags read "Unknown Artist" and "Unknown Album", a missing
+// title the file's name, and other missing fields are left out along with
+// the spaces and dashes around them.
+func Path(pattern, root string, t playlist.Track) (string, error) {
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+	ext := strings.TrimPrefix(filepath.Ext(t.Path), ".")
+	values := map[string]string{
+		"artist": or(t.Artist, "Unknown Artist"),
+		"album":  or(t.Album, "Unknown Album"),
+		"title":  or(t.Title, strings.TrimSuffix(filepath.Base(t.Path), filepath.Ext(t.Path))),
+		"track":  number(t.TrackNumber),
+		"disc":   number(t.DiscNumber),
+		"year":   "",
+		"genre":  t.Genre,
+		"ext":    strings.ToLower(ext),
+	}
+	if t.Year != 0 {
+		values["year"] = strconv.Itoa(t.Year)
+	}
+
+	var parts []string
+	for _, seg := range strings.Split(filepath.ToSlash(pattern), "/") {
+		var b strings.Builder
+		for seg != "" {
+			open := strings.IndexByte(seg, '{')
+			if open < 0 {
+				b.WriteString(seg)
+				break
+			}
+			end := strings.IndexByte(seg[open:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unclosed { in pattern %q", pattern)
+			}
+			name := seg[open+1 : open+end]
+			v, ok := values[strings.ToLower(name)]
+			if !ok {
+				return "", fmt.Errorf("unknown field {%s} in pattern", name)
+			}
+			b.WriteString(seg[:open])
+			b.WriteString(clean(v))
+			seg = seg[open+end+1:]
+		}
+		if part := tidy(b.String()); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("pattern %q gives an empty name", pattern)
+	}
+	return filepath.Join(append([]string{root}, parts...)...), nil
+}
+
+func or(s, fallback string) string {
+	if strings.TrimSpace(s) == "" {
+		return fallback
+	}
+	return s
+}
+
+func number(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%02d", n)
+}
+
+// clean makes a tag value safe as part of a file name on any system.
+func clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < ' ':
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// tidy collapses the runs of spaces left by empty fields and trims the
+// separators and dots around a name, so "{track} - {title}" without a
+// track number reads just the title and no name starts hidden.
+func tidy(s string) string {
+	return strings.Trim(strings.Join(strings.Fields(s), " "), " -_.")
+}
+
+// Plan works out where pattern puts each local track below root, leaving
+// out streams, files split by a cue sheet, files already in place and
+// files cliamp keeps in its config directory, such as downloaded podcast
+// episodes, which are found there by name. Two files given the same name
+// is an error, naming both.
+func Plan(pattern, root string, tracks []playlist.Track) ([]Move, error) {
+	var moves []Move
+	taken := make(map[string]string)
+	seen := make(map[string]bool)
+	own, _ := appdir.Dir()
+	for _, t := range tracks {
+		if t.Stream || t.Ranged() || t.Path == "" {
+			continue
+		}
+		from, err := filepath.Abs(t.Path)
+		if err != nil {
+			return nil, err
+		}
+		if own != "" && strings.HasPrefix(from, own+string(filepath.Separator)) {
+			continue
+		}
+		if seen[from] {
+			continue
+		}
+		seen[from] = true
+		to, err := Path(pattern, root, t)
+		if err != nil {
+			return nil, err
+		}
+		if to, err = filepath.Abs(to); err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(to)
+		if prev, ok := taken[key]; ok && prev != from {
+			return nil, fmt.Errorf("%s and %s would both be %s", prev, from, to)
+		}
+		taken[key] = from
+		if to != from {
+			moves = append(moves, Move{From: from, To: to})
+		}
+	}
+	return moves, nil
+}
+
+// ErrExists reports a move onto a file that is already there.
+var ErrExists = errors.New("destination already exists")
+
+// Do moves the file, creating the destination's folders. It never replaces
+// a file, and copies and then removes the original when the two folders
+// are on different filesystems. A copy whose original cannot be removed is
+// deleted again, so a failed move never leaves the file twice.
+func (m Move) Do() error {
+	if _, err := os.Lstat(m.To); err == nil {
+		return fmt.Errorf("%s: %w", m.To, ErrExists)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.To), 0o755); err != nil {
+		return err
+	}
+	err := os.Rename(m.From, m.To)
+	if err == nil || !crossDevice(err) {
+		return err
+	}
+	if err := fileutil.CopyFile(m.From, m.To); err != nil {
+		return err
+	}
+	if err := os.Remove(m.From); err != nil {
+		os.Remove(m.To)
+		return err
+	}
+	return nil
+}
+
+// undo moves the file back, for Apply rolling back.
+func (m Move) undo() error {
+	return Move{From: m.To, To: m.From}.Do()
+}
+
+// Index is something that remembers files by their path, such as the saved
+// playlists or the per-track gains, for Apply to point at moved files.
+// Rename takes old absolute path → new absolute path.
+type Index struct {
+	Name   string // what it is, for errors: "playlists", "bookmarks"
+	Rename func(moved map[string]string) error
+}
+
+// Apply moves each file and then points every index at the new paths.
+// done is called for every move with its error, nil for a file that moved;
+// a file that cannot be moved stays where it is and out of the indexes.
+// When an index cannot be updated, the files are moved back and the
+// indexes already updated are pointed back at them, so either everything
+// changes or nothing does. The error then names the index, and also every
+// file left at its new path if moving back failed too.
+func Apply(moves []Move, indexes []Index, done func(Move, error)) error {
+	var ok []Move
+	moved := make(map[string]string, len(moves))
+	for _, m := range moves {
+		err := m.Do()
+		if done != nil {
+			done(m, err)
+		}
+		if err == nil {
+			ok = append(ok, m)
+			moved[m.From] = m.To
+		}
+	}
+	if len(ok) == 0 {
+		return nil
+	}
+	for i, ix := range indexes {
+		err := ix.Rename(moved)
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("updating %s: %w", ix.Name, err)
+		back := make(map[string]string, len(ok))
+		var stuck []string
+		for _, m := range ok {
+			if uerr := m.undo(); uerr != nil {
+				stuck = append(stuck, m.To)
+				continue
+			}
+			back[m.To] = m.From
+		}
+		// The failed index may have been partly updated, so it is pointed
+		// back as well.
+		for _, prev := range indexes[:i+1] {
+			if rerr := prev.Rename(back); rerr != nil {
+				err = fmt.Errorf("%w; pointing %s back: %v", err, prev.Name, rerr)
+			}
+		}
+		if len(stuck) > 0 {
+			return fmt.Errorf("%w; these files could not be moved back: %s", err, strings.Join(stuck, ", "))
+		}
+		return fmt.Errorf("%w; the files were moved back", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package organize
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cliamp/playlist"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		track   playlist.Track
+		want    string
+	}{
+		{"", playlist.Track{Path: "/in/x.FLAC", Artist: "Air", Album: "Moon Safari", Title: "La femme d'argent", TrackNumber: 1},
+			"Air/Moon Safari/01 La femme d'argent.flac"},
+		{"{artist}/{album}/{track} - {title}.{ext}", playlist.Track{Path: "/in/untitled.mp3"},
+			"Unknown Artist/Unknown Album/untitled.mp3"},
+		{"{artist}/{year} {album}/{disc}-{track} {title}.{ext}", playlist.Track{Path: "/in/a.ogg", Artist: "AC/DC", Album: "Back in Black", Title: "Hells Bells?", Year: 1980, DiscNumber: 1, TrackNumber: 1},
+			"AC_DC/1980 Back in Black/01-01 Hells Bells_.ogg"},
+		{"{Artist}/{title}.{ext}", playlist.Track{Path: "/in/b.mp3", Artist: "..", Title: "Intro"},
+			"Intro.mp3"},
+	}
+	for _, tt := range tests {
+		got, err := Path(tt.pattern, "/music", tt.track)
+		if err != nil {
+			t.Fatalf("Path(%q): %v", tt.pattern, err)
+		}
+		if want := filepath.Join("/music", filepath.FromSlash(tt.want)); got != want {
+			t.Errorf("Path(%q) = %q, want %q", tt.pattern, got, want)
+		}
+	}
+	for _, bad := range []string{"{artist}/{bitrate}.{ext}", "{artist/{title}"} {
+		if _, err := Path(bad, "/music", playlist.Track{Path: "/in/a.mp3"}); err == nil {
+			t.Errorf("Path(%q) gave no error", bad)
+		}
+	}
+}
+
+func TestPlanRejectsClashes(t *testing.T) {
+	tracks := []playlist.Track{
+		{Path: "/in/a.mp3", Artist: "A", Title: "Song"},
+		{Path: "/in/b.mp3", Artist: "A", Title: "Song"},
+	}
+	if _, err := Plan("{artist}/{title}.{ext}", "/music", tracks); err == nil {
+		t.Fatal("two files given one name should be an error")
+	}
+	moves, err := Plan("{artist}/{title}.{ext}", "/music", []playlist.Track{tracks[0], {Path: "http://x/a.mp3", Stream: true}})
+	if err != nil || len(moves) != 1 {
+		t.Fatalf("Plan = %v, %v; want one move", moves, err)
+	}
+}
+
+func TestMoveDoesNotReplace(t *testing.T) {
+	dir := t.TempDir()
+	from := filepath.Join(dir, "a.mp3")
+	to := filepath.Join(dir, "Artist", "Album", "01 A.mp3")
+	if err := os.WriteFile(from, []byte("one"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Move{From: from, To: to}).Do(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(to); err != nil || string(data) != "one" {
+		t.Fatalf("moved file reads %q, %v", data, err)
+	}
+	if err := os.WriteFile(from, []byte("two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Move{From: from, To: to}).Do(); !errors.Is(err, ErrExists) {
+		t.Fatalf("moving onto a file: err = %v, want ErrExists", err)
+	}
+	if data, _ := os.ReadFile(to); string(data) != "one" {
+		t.Fatalf("existing file was replaced: %q", data)
+	}
+}
+
+func TestPlanSkipsConfigDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	episode := filepath.Join(home, ".config", "cliamp", "podcasts", "0123abcd.mp3")
+	moves, err := Plan("{title}.{ext}", filepath.Join(home, "Music"), []playlist.Track{{Path: episode, Title: "Episode"}})
+	if err != nil || len(moves) != 0 {
+		t.Fatalf("Plan = %v, %v; want a downloaded episode left alone", moves, err)
+	}
+}
+
+func TestApplyRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	from := filepath.Join(dir, "a.mp3")
+	to := filepath.Join(dir, "Artist", "a.mp3")
+	if err := os.WriteFile(from, []byte("one"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first index takes the move; the second fails, so both end up
+	// pointing at the file where it started.
+	index := map[string]string{from: "kept"}
+	rename := func(moved map[string]string) error {
+		for old, now := range moved {
+			if v, ok := index[old]; ok {
+				index[now] = v
+				delete(index, old)
+			}
+		}
+		return nil
+	}
+	failing := func(map[string]string) error { return errors.New("disk full") }
+	err := Apply([]Move{{From: from, To: to}}, []Index{{"playlists", rename}, {"bookmarks", failing}}, nil)
+	if err == nil {
+		t.Fatal("Apply succeeded with a failing index")
+	}
+	if _, serr := os.Stat(from); serr != nil {
+		t.Fatalf("file not moved back: %v (Apply: %v)", serr, err)
+	}
+	if _, serr := os.Stat(to); serr == nil {
+		t.Fatal("file left at its new path")
+	}
+	if index[from] != "kept" || len(index) != 1 {
+		t.Fatalf("index after rollback = %v, want it back at the old path", index)
+	}
+
+	var reported []error
+	if err := Apply([]Move{{From: from, To: to}}, []Index{{"playlists", rename}}, func(_ Move, err error) { reported = append(reported, err) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(reported) != 1 || reported[0] != nil || index[to] != "kept" {
+		t.Fatalf("reported %v, index %v; want one move, the index at the new path", reported, index)
+	}
+	if crossDevice(os.ErrPermission) {
+		t.Error("a permission error was taken for a cross-device rename")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package organize
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errNotSameDevice is ERROR_NOT_SAME_DEVICE, Windows' EXDEV.
+const errNotSameDevice = syscall.Errno(17)
+
+// crossDevice reports whether a rename failed only because the two paths
+// are on different drives.
+func crossDevice(err error) bool {
+	return errors.Is(err, errNotSameDevice)
+}
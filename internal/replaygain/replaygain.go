@@ -0,0 +1,227 @@
+// Package replaygain measures the loudness of audio files and writes
+// ReplayGain tags, for `cliamp scan-gain`. Loudness follows ITU-R BS.1770
+// as EBU R128 uses it, and gains aim at ReplayGain 2.0's -18 LUFS.
+package replaygain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os/exec"
+	"strings"
+
+	"cliamp/internal/tagwrite"
+)
+
+// Reference is the loudness gains bring a track to, in LUFS.
+const Reference = -18.0
+
+// rate is the sample rate files are decoded at for measuring.
+const rate = 48000
+
+// Result is the measurement of a track, or of an album of them.
+type Result struct {
+	Peak   float64   // largest sample, 1 at full scale
+	blocks []float64 // mean square of each 400 ms gating block
+}
+
+// Loudness returns the integrated loudness in LUFS, or -Inf for silence
+// and tracks shorter than one gating block.
+func (r Result) Loudness() float64 {
+	// Absolute gate at -70 LUFS, then a relative gate 10 LU below the
+	// loudness of what passed the first.
+	abs := blockMean(r.blocks, math.Pow(10, (-70+0.691)/10))
+	if abs == 0 {
+		return math.Inf(-1)
+	}
+	return lufs(blockMean(r.blocks, abs*math.Pow(10, -10.0/10)))
+}
+
+// Gain returns the ReplayGain in dB: what brings the result to Reference.
+func (r Result) Gain() float64 {
+	return Reference - r.Loudness()
+}
+
+// Album combines the results of an album's tracks, measuring them as one.
+func Album(tracks []Result) Result {
+	var a Result
+	for _, t := range tracks {
+		a.Peak = max(a.Peak, t.Peak)
+		a.blocks = append(a.blocks, t.blocks...)
+	}
+	return a
+}
+
+func lufs(ms float64) float64 {
+	return -0.691 + 10*math.Log10(ms)
+}
+
+// blockMean averages the blocks louder than gate, 0 when there are none.
+func blockMean(blocks []float64, gate float64) float64 {
+	sum, n := 0.0, 0
+	for _, b := range blocks {
+		if b > gate {
+			sum += b
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// Meter measures stereo audio at 48 kHz as it is written.
+type Meter struct {
+	filters [2][2]biquad // per channel: the two K-weighting stages
+	sum     float64      // weighted square sum of the current 100 ms
+	n       int          // samples in the current 100 ms
+	parts   []float64    // mean squares of each 100 ms so far
+	peak    float64
+}
+
+// NewMeter returns a meter for 48 kHz stereo.
+func NewMeter() *Meter {
+	m := &Meter{}
+	for ch := range m.filters {
+		m.filters[ch] = kWeighting(rate)
+	}
+	return m
+}
+
+// Write measures samples, left and right in [-1, 1].
+func (m *Meter) Write(samples [][2]float64) {
+	const step = rate / 10
+	for _, s := range samples {
+		for ch, v := range s {
+			m.peak = max(m.peak, math.Abs(v))
+			v = m.filters[ch][1].process(m.filters[ch][0].process(v))
+			m.sum += v * v
+		}
+		if m.n++; m.n == step {
+			m.parts = append(m.parts, m.sum/step)
+			m.sum, m.n = 0, 0
+		}
+	}
+}
+
+// Result returns the measurement of everything written. Gating blocks are
+// 400 ms long and overlap by three quarters.
+func (m *Meter) Result() Result {
+	r := Result{Peak: m.peak}
+	for i := 3; i < len(m.parts); i++ {
+		r.blocks = append(r.blocks, (m.parts[i-3]+m.parts[i-2]+m.parts[i-1]+m.parts[i])/4)
+	}
+	return r
+}
+
+// biquad is a second-order IIR filter in direct form I.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// kWeighting returns BS.1770's pre-filter, a high shelf modelling the
+// head, and its high-pass, designed for sample rate sr.
+func kWeighting(sr float64) [2]biquad {
+	k := math.Tan(math.Pi * 1681.974450955533 / sr)
+	q := 0.7071752369554196
+	vh := math.Pow(10, 3.999843853973347/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+	k = math.Tan(math.Pi * 38.13547087602444 / sr)
+	q = 0.5003270373238773
+	a0 = 1 + k/q + k*k
+	highpass := biquad{
+		b0: 1, b1: -2, b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+	return [2]biquad{shelf, highpass}
+}
+
+// Analyze decodes the file at path with ffmpeg and measures it.
+func Analyze(path string) (Result, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return Result{}, errors.New("scanning gain requires ffmpeg")
+	}
+	cmd := exec.Command("ffmpeg", "-nostdin", "-v", "error", "-i", path,
+		"-map", "0:a:0", "-f", "f32le", "-ac", "2", "-ar", fmt.Sprint(rate), "pipe:1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return Result{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return Result{}, err
+	}
+	m := NewMeter()
+	r := bufio.NewReaderSize(out, 64*1024)
+	buf := make([]byte, 8)
+	samples := make([][2]float64, 0, 4096)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			break
+		}
+		samples = append(samples, [2]float64{
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[0:4]))),
+			float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[4:8]))),
+		})
+		if len(samples) == cap(samples) {
+			m.Write(samples)
+			samples = samples[:0]
+		}
+	}
+	m.Write(samples)
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Result{}, fmt.Errorf("ffmpeg: %s", msg)
+		}
+		return Result{}, fmt.Errorf("ffmpeg: %w", err)
+	}
+	return m.Result(), nil
+}
+
+// Tags are the ReplayGain tags of a file. An album gain of NaN leaves the
+// album tags out.
+type Tags struct {
+	TrackGain, TrackPeak float64
+	AlbumGain, AlbumPeak float64
+}
+
+// fields returns the tags as tagwrite.Write takes them.
+func (t Tags) fields() []string {
+	f := []string{
+		"REPLAYGAIN_TRACK_GAIN=" + fmt.Sprintf("%.2f dB", t.TrackGain),
+		"REPLAYGAIN_TRACK_PEAK=" + fmt.Sprintf("%.6f", t.TrackPeak),
+	}
+	if !math.IsNaN(t.AlbumGain) {
+		f = append(f,
+			"REPLAYGAIN_ALBUM_GAIN="+fmt.Sprintf("%.2f dB", t.AlbumGain),
+			"REPLAYGAIN_ALBUM_PEAK="+fmt.Sprintf("%.6f", t.AlbumPeak))
+	}
+	return f
+}
+
+// WriteTags sets the ReplayGain tags of the file at path.
+func WriteTags(path string, t Tags) error {
+	return tagwrite.Write(path, t.fields())
+}
@@ -0,0 +1,63 @@
+package replaygain
+
+import (
+	"math"
+	"testing"
+)
+
+// sine returns secs of a stereo sine at freq Hz and amp full scale.
+func sine(freq, amp, secs float64) [][2]float64 {
+	s := make([][2]float64, int(secs*rate))
+	for i := range s {
+		v := amp * math.Sin(2*math.Pi*freq*float64(i)/rate)
+		s[i] = [2]float64{v, v}
+	}
+	return s
+}
+
+func TestLoudness(t *testing.T) {
+	// EBU Tech 3341, case 1: a stereo 1 kHz sine at -23 dBFS is -23 LUFS.
+	m := NewMeter()
+	m.Write(sine(1000, math.Pow(10, -23.0/20), 20))
+	r := m.Result()
+	if got := r.Loudness(); math.Abs(got+23) > 0.1 {
+		t.Errorf("loudness = %.2f LUFS, want -23", got)
+	}
+	if got := r.Gain(); math.Abs(got-5) > 0.1 {
+		t.Errorf("gain = %.2f dB, want 5", got)
+	}
+	if math.Abs(r.Peak-math.Pow(10, -23.0/20)) > 1e-4 {
+		t.Errorf("peak = %v", r.Peak)
+	}
+
+	silent := NewMeter()
+	silent.Write(make([][2]float64, rate*2))
+	if got := silent.Result().Loudness(); !math.IsInf(got, -1) {
+		t.Errorf("silence loudness = %v, want -Inf", got)
+	}
+}
+
+func TestAlbum(t *testing.T) {
+	// EBU Tech 3341, case 3: -36, -23 and -36 dBFS for 10, 60 and 10
+	// seconds; the relative gate leaves the quiet parts out.
+	var tracks []Result
+	for _, part := range []struct{ db, secs float64 }{{-36, 10}, {-23, 60}, {-36, 10}} {
+		m := NewMeter()
+		m.Write(sine(1000, math.Pow(10, part.db/20), part.secs))
+		tracks = append(tracks, m.Result())
+	}
+	a := Album(tracks)
+	if got := a.Loudness(); math.Abs(got+23) > 0.1 {
+		t.Errorf("album loudness = %.2f LUFS, want -23", got)
+	}
+	if a.Peak != tracks[1].Peak {
+		t.Errorf("album peak = %v, want %v", a.Peak, tracks[1].Peak)
+	}
+}
+
+func TestTagFields(t *testing.T) {
+	got := Tags{TrackGain: -6.421, TrackPeak: 0.98, AlbumGain: math.NaN()}.fields()
+	if len(got) != 2 || got[0] != "REPLAYGAIN_TRACK_GAIN=-6.42 dB" || got[1] != "REPLAYGAIN_TRACK_PEAK=0.980000" {
+		t.Errorf("fields = %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"cliamp/internal/appdir"
 )
@@ -43,6 +44,32 @@ func Save(path string, positionSec int) {
 	_ = os.WriteFile(f, data, 0o600)
 }
 
+// Rename points the resume state at the new path of a file that moved, old
+// path → new path.
+func Rename(moved map[string]string) error {
+	s := Load()
+	if s.Path == "" || strings.Contains(s.Path, "://") {
+		return nil
+	}
+	abs, err := filepath.Abs(s.Path)
+	if err != nil {
+		return nil
+	}
+	to, ok := moved[abs]
+	if !ok {
+		return nil
+	}
+	f, err := stateFile()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(State{Path: to, PositionSec: s.PositionSec})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f, data, 0o600)
+}
+
 // Load reads the resume state from disk. Returns a zero State if the file
 // does not exist or cannot be parsed.
 func Load() State {
@@ -0,0 +1,224 @@
+// Package scrobblequeue keeps listens that could not be submitted, for
+// example while offline, in a journal under ~/.config/cliamp and resends
+// them with backoff until the service accepts them.
+//
+// Each service has its own journal, scrobbles-<name>.jsonl, one listen per
+// line, so listens survive a restart and are sent by the next session.
+package scrobblequeue
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cliamp/internal/appdir"
+	"cliamp/playlist"
+)
+
+// Scrobbler submits listens to one service. It matches ui.Scrobbler.
+type Scrobbler interface {
+	NowPlaying(track playlist.Track) error
+	Scrobble(track playlist.Track, listenedAt time.Time) error
+}
+
+// Retry delays: the first resend waits minBackoff, and each failure after
+// that doubles the wait up to maxBackoff.
+var (
+	minBackoff = 30 * time.Second
+	maxBackoff = 15 * time.Minute
+)
+
+// maxPending caps the journal so a service that never comes back cannot
+// grow it without bound; the oldest listens are dropped first.
+const maxPending = 5000
+
+type entry struct {
+	Track playlist.Track `json:"track"`
+	At    time.Time      `json:"listened_at"`
+}
+
+// Queue wraps a Scrobbler. Now-playing notifications pass straight through;
+// listens that fail are journaled and resent in order. It is safe for
+// concurrent use.
+type Queue struct {
+	next Scrobbler
+	name string // service name, for the log
+	path string // "" keeps the queue in memory only
+
+	mu       sync.Mutex
+	pending  []entry
+	flushing bool
+}
+
+// Wrap returns a Queue for s whose journal is named after name, and starts
+// resending anything left in the journal by an earlier session.
+func Wrap(name string, s Scrobbler) *Queue {
+	path := ""
+	if dir, err := appdir.Dir(); err == nil {
+		path = filepath.Join(dir, "scrobbles-"+name+".jsonl")
+	}
+	q := newQueue(path, s)
+	q.name = name
+	return q
+}
+
+func newQueue(path string, s Scrobbler) *Queue {
+	q := &Queue{next: s, path: path}
+	q.pending = q.load()
+	if len(q.pending) > 0 {
+		q.flushing = true
+		go q.flush()
+	}
+	return q
+}
+
+// NowPlaying forwards to the wrapped Scrobbler. A now-playing notice is
+// stale by the time it could be resent, so failures are not queued.
+func (q *Queue) NowPlaying(track playlist.Track) error {
+	err := q.next.NowPlaying(track)
+	if err != nil {
+		slog.Warn("now playing failed", "service", q.name, "title", track.Title, "err", err)
+	}
+	return err
+}
+
+// Scrobble submits a listen, or queues it when the service cannot be
+// reached. Listens queue behind earlier ones to keep their order. Only a
+// listen the service rejected outright is dropped and its error returned.
+func (q *Queue) Scrobble(track playlist.Track, listenedAt time.Time) error {
+	q.mu.Lock()
+	waiting := len(q.pending) > 0
+	q.mu.Unlock()
+	if !waiting {
+		err := q.next.Scrobble(track, listenedAt)
+		switch {
+		case err == nil:
+			slog.Debug("scrobbled", "service", q.name, "artist", track.Artist, "title", track.Title)
+			return nil
+		case rejected(err):
+			slog.Warn("scrobble rejected", "service", q.name, "artist", track.Artist, "title", track.Title, "err", err)
+			return err
+		}
+		slog.Warn("scrobble queued", "service", q.name, "title", track.Title, "err", err)
+	}
+	q.push(entry{Track: track, At: listenedAt})
+	return nil
+}
+
+// Pending reports how many listens are waiting to be resent.
+func (q *Queue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+func (q *Queue) push(e entry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, e)
+	if over := len(q.pending) - maxPending; over > 0 {
+		q.pending = q.pending[over:]
+	}
+	q.save()
+	if !q.flushing {
+		q.flushing = true
+		go q.flush()
+	}
+}
+
+// flush resends queued listens oldest first until none are left, backing
+// off while the service stays unreachable.
+func (q *Queue) flush() {
+	wait := minBackoff
+	for {
+		time.Sleep(wait)
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.flushing = false
+			q.mu.Unlock()
+			return
+		}
+		e := q.pending[0]
+		q.mu.Unlock()
+
+		err := q.next.Scrobble(e.Track, e.At)
+		if err != nil && !rejected(err) {
+			wait = min(wait*2, maxBackoff)
+			slog.Debug("scrobble resend failed", "service", q.name, "retry_in", wait, "err", err)
+			continue
+		}
+		if err != nil {
+			slog.Warn("queued scrobble rejected", "service", q.name, "title", e.Track.Title, "err", err)
+		} else {
+			slog.Debug("queued scrobble sent", "service", q.name, "title", e.Track.Title)
+		}
+		q.mu.Lock()
+		q.pending = q.pending[1:]
+		q.save()
+		q.mu.Unlock()
+		// Connectivity is back: send the rest without waiting.
+		wait = 0
+	}
+}
+
+// rejected reports whether err says the service refused the listen, so
+// resending it is pointless.
+func rejected(err error) bool {
+	var r interface{ Rejected() bool }
+	return errors.As(err, &r) && r.Rejected()
+}
+
+func (q *Queue) load() []entry {
+	if q.path == "" {
+		return nil
+	}
+	f, err := os.Open(q.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var entries []entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		var e entry
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// save rewrites the journal from pending, removing it once empty. The
+// caller holds q.mu. Errors are ignored: the listens are still held in
+// memory for this session.
+func (q *Queue) save() {
+	if q.path == "" {
+		return
+	}
+	if len(q.pending) == 0 {
+		os.Remove(q.path)
+		return
+	}
+	var buf []byte
+	for _, e := range q.pending {
+		data, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	_ = os.MkdirAll(filepath.Dir(q.path), 0o755)
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		os.Remove(tmp)
+	}
+}
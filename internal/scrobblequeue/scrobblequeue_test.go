@@ -0,0 +1,84 @@
+package scrobblequeue
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"cliamp/playlist"
+)
+
+func init() {
+	minBackoff, maxBackoff = time.Millisecond, 5*time.Millisecond
+}
+
+type fakeScrobbler struct {
+	mu      sync.Mutex
+	offline bool
+	got     []string
+}
+
+func (f *fakeScrobbler) NowPlaying(playlist.Track) error { return nil }
+
+func (f *fakeScrobbler) Scrobble(t playlist.Track, _ time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.offline {
+		return errors.New("no route to host")
+	}
+	f.got = append(f.got, t.Title)
+	return nil
+}
+
+type rejectedErr struct{}
+
+func (rejectedErr) Error() string  { return "bad token" }
+func (rejectedErr) Rejected() bool { return true }
+
+type rejecter struct{}
+
+func (rejecter) NowPlaying(playlist.Track) error          { return nil }
+func (rejecter) Scrobble(playlist.Track, time.Time) error { return rejectedErr{} }
+
+func TestQueueResendsAfterOutage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scrobbles-test.jsonl")
+	f := &fakeScrobbler{offline: true}
+	q := newQueue(path, f)
+	for _, title := range []string{"One", "Two"} {
+		if err := q.Scrobble(playlist.Track{Title: title}, time.Now()); err != nil {
+			t.Fatalf("Scrobble while offline: %v", err)
+		}
+	}
+	if q.Pending() != 2 {
+		t.Fatalf("Pending = %d, want 2", q.Pending())
+	}
+
+	// A new session finds the journal and sends it once back online.
+	f2 := &fakeScrobbler{}
+	q2 := newQueue(path, f2)
+	deadline := time.Now().Add(2 * time.Second)
+	for q2.Pending() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	f2.mu.Lock()
+	got := f2.got
+	f2.mu.Unlock()
+	if len(got) != 2 || got[0] != "One" || got[1] != "Two" {
+		t.Fatalf("resent %v, want [One Two] in order", got)
+	}
+	if q2.load() != nil {
+		t.Error("journal not cleared after flushing")
+	}
+}
+
+func TestQueueDropsRejected(t *testing.T) {
+	q := newQueue("", rejecter{})
+	if err := q.Scrobble(playlist.Track{Title: "One"}, time.Now()); err == nil {
+		t.Fatal("rejected listen reported no error")
+	}
+	if q.Pending() != 0 {
+		t.Fatalf("Pending = %d, want rejected listen dropped", q.Pending())
+	}
+}
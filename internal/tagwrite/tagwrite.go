@@ -0,0 +1,59 @@
+// Package tagwrite writes tags into audio files with ffmpeg, for the
+// commands and views that change a file's tags.
+package tagwrite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Writable reports whether Write can tag the file at path: the formats
+// whose containers hold free-form tags.
+func Writable(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mp3", ".flac", ".ogg", ".opus", ".m4a", ".m4b", ".wma", ".webm":
+		return true
+	}
+	return false
+}
+
+// Write sets tags of the file at path, each field a "key=value" such as
+// "artist=Nina Simone", keeping its audio and other tags. An empty value
+// removes the tag. ffmpeg copies the file with the new tags beside it,
+// which then replaces the original.
+func Write(path string, fields []string) error {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return errors.New("writing tags requires ffmpeg")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(path), ".cliamp-tags-"+filepath.Base(path))
+	args := []string{"-nostdin", "-v", "error", "-y", "-i", path, "-map", "0", "-c", "copy", "-map_metadata", "0"}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m4a", ".m4b":
+		args = append(args, "-movflags", "use_metadata_tags")
+	}
+	for _, f := range fields {
+		args = append(args, "-metadata", f)
+	}
+	args = append(args, tmp)
+	if out, err := exec.Command("ffmpeg", args...).CombinedOutput(); err != nil {
+		os.Remove(tmp)
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("ffmpeg: %s", msg)
+		}
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+	os.Chmod(tmp, info.Mode().Perm())
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Package trackeq remembers the EQ curve chosen for a track or an album,
+// to apply again whenever it plays, in ~/.config/cliamp/track_eq.json.
+package trackeq
+
+import (
+	"strings"
+
+	"cliamp/internal/appdir"
+)
+
+const eqFile = "track_eq.json"
+
+// Curve is a remembered EQ setting.
+type Curve struct {
+	Preset string      `json:"preset,omitempty"` // preset name, "" or "Custom" for a hand-made curve
+	Bands  [10]float64 `json:"bands"`
+}
+
+// Store holds the curves of every track and album, by appdir.TrackKey or
+// AlbumKey. It is safe for concurrent use.
+type Store struct {
+	curves *appdir.Store[Curve]
+}
+
+// New loads saved curves from the config directory.
+func New() *Store {
+	return &Store{curves: appdir.OpenStore[Curve](eqFile)}
+}
+
+func newStore(dir string) *Store {
+	return &Store{curves: appdir.LoadStore[Curve](dir, eqFile)}
+}
+
+// AlbumKey identifies an album by its artist and title, in any case. It is
+// "" for a track without an album tag.
+func AlbumKey(artist, album string) string {
+	if strings.TrimSpace(album) == "" {
+		return ""
+	}
+	return "album:" + strings.ToLower(strings.TrimSpace(artist)) + " - " + strings.ToLower(strings.TrimSpace(album))
+}
+
+// Curve returns the curve remembered under key.
+func (s *Store) Curve(key string) (Curve, bool) {
+	return s.curves.Get(key)
+}
+
+// Set remembers c under key.
+func (s *Store) Set(key string, c Curve) {
+	if key == "" {
+		return
+	}
+	s.curves.Set(key, c)
+}
+
+// Forget drops the curve remembered under key.
+func (s *Store) Forget(key string) {
+	s.curves.Delete(key)
+}
+
+// Rename moves the curves of files remembered by track that moved, old path → new path.
+func (s *Store) Rename(moved map[string]string) error {
+	return s.curves.Rename(moved)
+}
@@ -0,0 +1,18 @@
+package trackeq
+
+import "testing"
+
+func TestAlbumKey(t *testing.T) {
+	s := newStore("")
+	s.Set(AlbumKey("Miles Davis", "Kind of Blue"), Curve{Bands: [10]float64{3}})
+	if got, ok := s.Curve(AlbumKey("miles davis", "Kind Of Blue ")); !ok || got.Bands[0] != 3 {
+		t.Fatalf("album curve by other case = %+v, %v", got, ok)
+	}
+	if AlbumKey("Artist", "") != "" {
+		t.Error("a track without an album has an album key")
+	}
+	s.Set("", Curve{})
+	if _, ok := s.Curve(""); ok {
+		t.Error("a curve was kept under an empty key")
+	}
+}
@@ -0,0 +1,46 @@
+// Package trackgain stores per-track gain offsets, for files that are
+// always too quiet or too loud, in ~/.config/cliamp/track_gain.json.
+package trackgain
+
+import "cliamp/internal/appdir"
+
+const gainFile = "track_gain.json"
+
+// MaxDB bounds an offset either way.
+const MaxDB = 12
+
+// Store holds the gain offsets of every track, in dB by track key. It is
+// safe for concurrent use.
+type Store struct {
+	gains *appdir.Store[float64]
+}
+
+// New loads saved offsets from the config directory.
+func New() *Store {
+	return &Store{gains: appdir.OpenStore[float64](gainFile)}
+}
+
+func newStore(dir string) *Store {
+	return &Store{gains: appdir.LoadStore[float64](dir, gainFile)}
+}
+
+// Gain returns the offset of path in dB, 0 when none is set.
+func (s *Store) Gain(path string) float64 {
+	db, _ := s.gains.Get(appdir.TrackKey(path))
+	return db
+}
+
+// Set remembers the offset of path, clamped to ±MaxDB. Zero forgets it.
+func (s *Store) Set(path string, db float64) {
+	db = max(min(db, MaxDB), -MaxDB)
+	if db == 0 {
+		s.gains.Delete(appdir.TrackKey(path))
+		return
+	}
+	s.gains.Set(appdir.TrackKey(path), db)
+}
+
+// Rename moves the offsets of files that moved, old path → new path.
+func (s *Store) Rename(moved map[string]string) error {
+	return s.gains.Rename(moved)
+}
@@ -0,0 +1,15 @@
+package trackgain
+
+import "testing"
+
+func TestSetClampsAndClears(t *testing.T) {
+	s := newStore("")
+	s.Set("loud.mp3", -40)
+	if got := s.Gain("loud.mp3"); got != -MaxDB {
+		t.Fatalf("clamped gain = %v, want %v", got, -MaxDB)
+	}
+	s.Set("loud.mp3", 0)
+	if got := s.Gain("loud.mp3"); got != 0 {
+		t.Fatalf("gain after clearing = %v", got)
+	}
+}
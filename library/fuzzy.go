@@ -0,0 +1,64 @@
+package library
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// matchAll scores name against every term, failing if any term does not
+// match. name and terms are lower case.
+func matchAll(name string, terms []string) (int, bool) {
+	total := 0
+	for _, t := range terms {
+		s, ok := fuzzyScore(name, t)
+		if !ok {
+			return 0, false
+		}
+		total += s
+	}
+	// Between equal matches, prefer the shorter name.
+	return total - utf8.RuneCountInString(name)/8, true
+}
+
+// fuzzyScore reports whether the characters of term appear in name in
+// order, and how well: a contiguous run scores best, especially at the
+// start of a word, and scattered characters score a point each plus a
+// bonus for landing on word starts or following the previous match.
+func fuzzyScore(name, term string) (int, bool) {
+	if i := strings.Index(name, term); i >= 0 {
+		s := 10 * utf8.RuneCountInString(term)
+		if wordStart(name, i) {
+			s += 10
+		}
+		return s, true
+	}
+
+	score, pos := 0, 0
+	for n, r := range []rune(term) {
+		i := strings.IndexRune(name[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		score++
+		switch {
+		case n > 0 && i == 0:
+			score += 3
+		case wordStart(name, pos+i):
+			score += 2
+		}
+		pos += i + utf8.RuneLen(r)
+	}
+	return score, true
+}
+
+// wordStart reports whether byte offset i of s begins a word.
+func wordStart(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch s[i-1] {
+	case '/', ' ', '-', '_', '.', '(', '[':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+// Package library indexes the audio files under the user's music folders,
+// for the library finder and Auto-DJ.
+package library
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"cliamp/player"
+	"cliamp/playlist"
+)
+
+// Library is the set of audio files under a list of folders. The folders
+// are walked once, on first use. It is safe for concurrent use.
+type Library struct {
+	mu      sync.Mutex
+	dirs    []string
+	exclude playlist.Excludes
+	files   []File
+	scanned bool
+}
+
+// File is one audio file in the library.
+type File struct {
+	Path string
+	Name string // path below its library folder, without the extension
+}
+
+// New returns a Library for the given folders, leaving out the files and
+// folders the exclude patterns match; a leading "~/" is expanded to the
+// home directory.
+func New(dirs, exclude []string) *Library {
+	l := &Library{exclude: exclude}
+	for _, dir := range dirs {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			l.dirs = append(l.dirs, expandHome(dir))
+		}
+	}
+	return l
+}
+
+// Files returns every audio file in the library. The first call walks the
+// folders and may take a while, so call it off the UI goroutine.
+func (l *Library) Files() []File {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.scanned {
+		return l.files
+	}
+	l.scanned = true
+	for _, dir := range l.dirs {
+		filepath.WalkDir(dir, func(p string, e fs.DirEntry, err error) error {
+			if err != nil || p == dir {
+				return nil
+			}
+			name, err := filepath.Rel(dir, p)
+			if err != nil {
+				name = filepath.Base(p)
+			}
+			if e.IsDir() {
+				if strings.HasPrefix(e.Name(), ".") || l.exclude.Match(name, true) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if player.SupportedExts[strings.ToLower(filepath.Ext(p))] && !l.exclude.Match(name, false) {
+				l.files = append(l.files, File{Path: p, Name: strings.TrimSuffix(name, filepath.Ext(name))})
+			}
+			return nil
+		})
+	}
+	return l.files
+}
+
+// Dirs returns the library's folders, with "~/" expanded.
+func (l *Library) Dirs() []string { return l.dirs }
+
+// Rename updates the index for files that have moved, given the absolute
+// path each had and the one it has now. Files moved out of every library
+// folder drop out of it.
+func (l *Library) Rename(moved map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	files := make([]File, 0, len(l.files))
+	for _, f := range l.files {
+		abs, err := filepath.Abs(f.Path)
+		to, ok := moved[abs]
+		if err != nil || !ok {
+			files = append(files, f)
+			continue
+		}
+		for _, dir := range l.dirs {
+			absDir, err := filepath.Abs(dir)
+			if err != nil {
+				continue
+			}
+			if name, err := filepath.Rel(absDir, to); err == nil && !strings.HasPrefix(name, "..") {
+				files = append(files, File{Path: to, Name: strings.TrimSuffix(name, filepath.Ext(name))})
+				break
+			}
+		}
+	}
+	l.files = files
+}
+
+// Find returns up to n files whose names fuzzy-match query, best first.
+// Every space-separated word of query must match.
+func (l *Library) Find(query string, n int) []File {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	type scored struct {
+		File
+		score int
+	}
+	var hits []scored
+	for _, f := range l.Files() {
+		if s, ok := matchAll(strings.ToLower(f.Name), terms); ok {
+			hits = append(hits, scored{f, s})
+		}
+	}
+	slices.SortStableFunc(hits, func(a, b scored) int { return b.score - a.score })
+	out := make([]File, 0, min(n, len(hits)))
+	for _, h := range hits[:min(n, len(hits))] {
+		out = append(out, h.File)
+	}
+	return out
+}
+
+func expandHome(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return p
+}
@@ -0,0 +1,89 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"Boards of Canada/Geogaddi/03 Music Is Math.flac",
+		"Boards of Canada/Geogaddi/05 Sunshine Recorder.flac",
+		"Massive Attack/Mezzanine/01 Angel.mp3",
+		"Massive Attack/Mezzanine/cover.jpg",
+		".trash/Angel.mp3",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := New([]string{dir}, nil)
+	if got := len(l.Files()); got != 3 {
+		t.Fatalf("library has %d files, want 3", got)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"angel", []string{"Massive Attack/Mezzanine/01 Angel"}},
+		{"boc math", []string{"Boards of Canada/Geogaddi/03 Music Is Math"}},
+		{"geogaddi", []string{"Boards of Canada/Geogaddi/03 Music Is Math", "Boards of Canada/Geogaddi/05 Sunshine Recorder"}},
+		{"sunrec", []string{"Boards of Canada/Geogaddi/05 Sunshine Recorder"}},
+		{"zzz", nil},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got := l.Find(tt.query, 10)
+		if len(got) != len(tt.want) {
+			t.Errorf("Find(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i].Name != filepath.FromSlash(tt.want[i]) {
+				t.Errorf("Find(%q)[%d] = %q, want %q", tt.query, i, got[i].Name, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFindRanksContiguousFirst(t *testing.T) {
+	l := &Library{scanned: true, files: []File{
+		{Path: "/a", Name: "Red Hot Chili Peppers/Mother's Milk/Taste the Pain"},
+		{Path: "/b", Name: "Idlewild/Remote Part/In Remote Part"},
+	}}
+	got := l.Find("remote", 2)
+	if len(got) != 2 || got[0].Path != "/b" {
+		t.Fatalf("Find(remote) = %v, want the track with the word first", got)
+	}
+}
+
+func TestRename(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "in", "a.mp3")
+	if err := os.MkdirAll(filepath.Dir(old), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(old, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l := New([]string{dir}, nil)
+	l.Files()
+
+	moved := filepath.Join(dir, "Air", "Moon Safari", "01 La femme.mp3")
+	l.Rename(map[string]string{old: moved})
+	files := l.Files()
+	if len(files) != 1 || files[0].Path != moved || files[0].Name != filepath.Join("Air", "Moon Safari", "01 La femme") {
+		t.Fatalf("after rename files = %+v", files)
+	}
+	l.Rename(map[string]string{moved: "/elsewhere/a.mp3"})
+	if files := l.Files(); len(files) != 0 {
+		t.Fatalf("a file moved out of the library is still listed: %+v", files)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// openLog directs the structured log to path, appending, at level "debug",
+// "info" (the default), "warn" or "error". Without a path, log records are
+// discarded, since anything written to stderr would tear the TUI.
+func openLog(path, level string) (io.Closer, error) {
+	if path == "" {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return io.NopCloser(nil), nil
+	}
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(strings.ToUpper(level))); level != "" && err != nil {
+		return nil, fmt.Errorf("log level must be debug, info, warn or error (got %q)", level)
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: lvl})))
+	slog.Info("cliamp started", "version", version, "pid", os.Getpid())
+	return f, nil
+}
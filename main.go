@@ -2,27 +2,46 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"cliamp/autodj"
 	"cliamp/config"
+	"cliamp/external/acoustid"
+	"cliamp/external/listenbrainz"
 	"cliamp/external/local"
 	"cliamp/external/navidrome"
 	"cliamp/external/plex"
+	"cliamp/external/podcast"
 	"cliamp/external/radio"
 	"cliamp/external/spotify"
 	"cliamp/external/ytmusic"
+	"cliamp/hooks"
+	"cliamp/internal/audiobook"
+	"cliamp/internal/bookmark"
+	"cliamp/internal/history"
 	"cliamp/internal/resume"
+	"cliamp/internal/scrobblequeue"
+	"cliamp/internal/trackeq"
+	"cliamp/internal/trackgain"
+	"cliamp/library"
+	"cliamp/mediakeys"
 	"cliamp/mpris"
 	"cliamp/player"
 	"cliamp/playlist"
+	"cliamp/remote"
 	"cliamp/resolve"
 	"cliamp/theme"
 	"cliamp/ui"
 	"cliamp/upgrade"
+	"cliamp/watch"
 )
 
 // version is set at build time via -ldflags "-X main.version=vX.Y.Z".
@@ -35,10 +54,42 @@ func run(overrides config.Overrides, positional []string) error {
 	}
 	overrides.Apply(&cfg)
 
-	// Build provider list: Radio is always available, Navidrome and Spotify if configured.
+	logFile, err := openLog(cfg.LogFile, cfg.LogLevel)
+	if err != nil {
+		return fmt.Errorf("log: %w", err)
+	}
+	defer logFile.Close()
+
+	if overrides.StdinPaths != nil {
+		paths, err := resolve.ReadPaths(os.Stdin, *overrides.StdinPaths)
+		if err != nil {
+			return fmt.Errorf("reading paths from stdin: %w", err)
+		}
+		positional = append(positional, paths...)
+	}
+
+	// A second cliamp given files hands them to the instance that is
+	// already running instead of competing with it for the audio device.
+	if done, err := forwardArgs(cfg.Remote, positional); done {
+		return err
+	}
+
+	if overrides.PProf != nil {
+		srv, err := listenPProf(*overrides.PProf)
+		if err != nil {
+			return fmt.Errorf("pprof: %w", err)
+		}
+		defer srv.Close()
+	}
+
+	// Build provider list: Radio and Podcasts are always available, Navidrome and Spotify if configured.
 	radioProv := radio.New()
+	podcastProv := podcast.New()
 	var providers []ui.ProviderEntry
-	providers = append(providers, ui.ProviderEntry{Key: "radio", Name: "Radio", Provider: radioProv})
+	providers = append(providers,
+		ui.ProviderEntry{Key: "radio", Name: "Radio", Provider: radioProv},
+		ui.ProviderEntry{Key: "podcasts", Name: "Podcasts", Provider: podcastProv},
+	)
 
 	var navClient *navidrome.NavidromeClient
 	if c := navidrome.NewFromConfig(cfg.Navidrome); c != nil {
@@ -127,10 +178,24 @@ func run(overrides config.Overrides, positional []string) error {
 		positional = []string{prefix + query}
 	}
 
+	resolve.SetExclude(cfg.Exclude)
 	resolved, err := resolve.Args(positional)
 	if err != nil {
 		return err
 	}
+	// --start and --end clip the local files given on the command line,
+	// except those annotated with a range of their own.
+	for i, t := range resolved.Tracks {
+		if t.Stream || t.Ranged() {
+			continue
+		}
+		if overrides.Start != nil {
+			resolved.Tracks[i].Start = *overrides.Start
+		}
+		if overrides.End != nil {
+			resolved.Tracks[i].End = *overrides.End
+		}
+	}
 
 	// Determine default provider key.
 	defaultProvider := cfg.Provider
@@ -145,11 +210,24 @@ func run(overrides config.Overrides, positional []string) error {
 
 	pl := playlist.New()
 	pl.Add(resolved.Tracks...)
+	if overrides.Loop != nil {
+		pl.SetLoops(*overrides.Loop)
+	}
 
 	// Resolve sample rate: 0 means auto-detect from the system's default
 	// output audio device (e.g. 48 kHz for USB-C headphones). Falls back
 	// to 44100 Hz if detection is unavailable or returns an unusable value.
+	// With the DSP bypassed the first local track's native rate wins instead,
+	// since the output cannot be reopened once playback has started.
 	sampleRate := cfg.SampleRate
+	if sampleRate == 0 && cfg.DSPBypass {
+		for _, t := range resolved.Tracks {
+			if !t.Stream {
+				sampleRate = player.ProbeSampleRate(t.Path)
+				break
+			}
+		}
+	}
 	if sampleRate == 0 {
 		if detected := player.DeviceSampleRate(); detected > 0 {
 			sampleRate = detected
@@ -158,11 +236,18 @@ func run(overrides config.Overrides, positional []string) error {
 		}
 	}
 
+	// Bypassing the DSP is for untouched output, so FFmpeg-decoded formats
+	// keep their full precision too.
+	bitDepth := cfg.BitDepth
+	if cfg.DSPBypass {
+		bitDepth = 32
+	}
+
 	p, err := player.New(player.Quality{
 		SampleRate:      sampleRate,
 		BufferMs:        cfg.BufferMs,
 		ResampleQuality: cfg.ResampleQuality,
-		BitDepth:        cfg.BitDepth,
+		BitDepth:        bitDepth,
 	})
 	if err != nil {
 		return fmt.Errorf("player: %w", err)
@@ -176,18 +261,84 @@ func run(overrides config.Overrides, positional []string) error {
 	}
 
 	cfg.ApplyPlayer(p)
+	p.SetCrossfeedPreset(player.ParseCrossfeedPreset(cfg.CrossfeedPreset))
+	p.SetReverbPreset(player.ParseReverbPreset(cfg.ReverbPreset))
+	p.SetNightPreset(player.ParseNightPreset(cfg.NightPreset))
+	if overrides.Record != nil {
+		if err := p.StartRecording(*overrides.Record); err != nil {
+			return err
+		}
+		defer func() {
+			if err := p.StopRecording(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}()
+	}
+	if cfg.Icecast.IsSet() {
+		if err := p.StartBroadcast(player.BroadcastConfig{
+			URL:         cfg.Icecast.URL,
+			User:        cfg.Icecast.User,
+			Password:    cfg.Icecast.Password,
+			Format:      cfg.Icecast.Format,
+			Bitrate:     cfg.Icecast.Bitrate,
+			Name:        cfg.Icecast.Name,
+			Description: cfg.Icecast.Description,
+			Genre:       cfg.Icecast.Genre,
+			Public:      cfg.Icecast.Public,
+		}); err != nil {
+			return err
+		}
+		defer p.StopBroadcast()
+	}
 	cfg.ApplyPlaylist(pl)
 
 	themes := theme.LoadAll()
 
 	m := ui.NewModel(p, pl, providers, defaultProvider, localProv, themes, cfg.Navidrome, navClient)
+	if cfg.ListenBrainz.IsSet() {
+		m.AddScrobbler(scrobblequeue.Wrap("listenbrainz", listenbrainz.NewClient(cfg.ListenBrainz.URL, cfg.ListenBrainz.Token)))
+	}
+	if cfg.AcoustID.IsSet() {
+		m.SetAcoustID(acoustid.NewClient(cfg.AcoustID.Key))
+	}
+	m.SetOrganizePattern(cfg.OrganizePattern)
+	if m.SetTerminalTitle(cfg.TerminalTitle) {
+		ui.PushTerminalTitle(os.Stdout)
+		defer ui.PopTerminalTitle(os.Stdout)
+	}
+	hookRunner := hooks.New(cfg.Hooks)
+	defer hookRunner.Close()
+	m.SetHooks(hookRunner)
+	m.SetPodcasts(podcastProv)
+	m.SetAudiobooks(audiobook.New(cfg.Audiobooks))
+	m.SetBookmarks(bookmark.New())
+	gains := trackgain.New()
+	p.SetTrackGainLookup(gains.Gain)
+	m.SetTrackGains(gains)
+	m.SetTrackEQs(trackeq.New())
+	m.SetHistory(history.New())
+	m.SetSmartShuffle(cfg.SmartShuffle, cfg.SmartRating, cfg.SmartRecency)
+	m.SetSeekStep(cfg.SeekStepDuration())
 	m.SetSeekStepLarge(cfg.SeekStepLargeDuration())
+	m.SetReplayStep(cfg.ReplayStepDuration())
 	m.SetPendingURLs(resolved.Pending)
 	if len(resolved.Tracks) == 0 && len(resolved.Pending) == 0 {
 		m.StartInProvider()
 	}
 	if cfg.EQPreset != "" && cfg.EQPreset != "Custom" {
-		m.SetEQPreset(cfg.EQPreset)
+		if !m.SetEQPreset(cfg.EQPreset) && overrides.EQPreset != nil {
+			return fmt.Errorf("flag --eq-preset: unknown preset %q", cfg.EQPreset)
+		}
+	}
+	// The default theme's colors suit the terminal's background unless
+	// a palette is chosen; detection has to finish before the UI starts
+	// reading the terminal.
+	palette := cfg.Palette
+	if palette == "" || palette == "auto" {
+		palette = ui.DetectPalette()
+	}
+	if !m.SetPalette(palette) && overrides.Palette != nil {
+		return fmt.Errorf("flag --palette: unknown palette %q", palette)
 	}
 	if cfg.Theme != "" {
 		m.SetTheme(cfg.Theme)
@@ -198,22 +349,123 @@ func run(overrides config.Overrides, positional []string) error {
 	if overrides.Play != nil && *overrides.Play {
 		m.SetAutoPlay(true)
 	}
+	if overrides.ExitAfter != nil {
+		m.SetExitAtEnd(*overrides.ExitAfter)
+	}
+	m.SetAlarmFade(cfg.AlarmFadeDuration())
+	lib := library.New(libraryDirs(cfg.Library, positional), cfg.Exclude)
+	m.SetLibrary(lib)
+	m.SetAutoDJ(autodj.New(lib, autodj.ParseMode(cfg.AutoDJMode)), cfg.AutoDJ)
+	if overrides.Alarm != nil {
+		if err := m.SetAlarm(*overrides.Alarm); err != nil {
+			return err
+		}
+	}
+	if overrides.StopAt != nil {
+		if err := m.SetStopAt(*overrides.StopAt); err != nil {
+			return err
+		}
+	}
+	m.SetFollow(cfg.FollowPlayback)
+	m.SetVolumeDisplay(cfg.VolumeDisplay)
+	m.SetTimeDisplay(cfg.TimeDisplay)
+	m.SetTitleScroll(cfg.TitleScroll, cfg.TitleScrollStep(), cfg.TitleScrollPauseDuration())
+	m.SetGroupAlbums(cfg.GroupAlbums)
+	m.SetLevelMeter(cfg.LevelMeter)
+	if cfg.ASCII == "true" || cfg.ASCII != "false" && ui.DetectASCII() {
+		m.SetASCII(true)
+	}
 	if cfg.Compact {
 		m.SetCompact(true)
 	}
+	if cfg.Accessible {
+		m.SetAccessible(true)
+	}
 
 	// PositionSec == 0 is indistinguishable from "never played"; skip resume.
 	if rs := resume.Load(); rs.Path != "" && rs.PositionSec > 0 {
 		m.SetResume(rs.Path, rs.PositionSec)
 	}
+	if overrides.Seek != nil {
+		if track, idx := pl.Current(); idx >= 0 && !track.Stream {
+			m.SetResume(track.Path, int(overrides.Seek.Seconds()))
+		}
+	}
 
-	prog := tea.NewProgram(m, tea.WithAltScreen())
+	var hub *remote.Hub
+	if cfg.Remote.HTTP != "" || cfg.Remote.MPD != "" || cfg.Remote.DLNA != "" || cfg.Remote.SocketEnabled() || cfg.Remote.NowPlaying != "" {
+		hub = remote.NewHub()
+		m.SetRemote(hub)
+	}
+	if cfg.Remote.NowPlaying != "" {
+		defer remote.WriteNowPlaying(cfg.Remote.NowPlaying, cfg.Remote.NowPlayingFmt, hub).Close()
+	}
+	if cfg.Remote.HTTP != "" {
+		srv, err := remote.ListenHTTP(cfg.Remote.HTTP, hub)
+		if err != nil {
+			return fmt.Errorf("remote: %w", err)
+		}
+		defer srv.Close()
+	}
+
+	// Signals are relayed to the model as a quit, so they save the session
+	// like q does; see relaySignals.
+	opts := []tea.ProgramOption{tea.WithoutSignalHandler()}
+	// Accessible mode draws in the normal screen, so the lines it
+	// announces stay in the scrollback for a screen reader.
+	if !cfg.Accessible {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	// When audio or a path list is piped in, stdin is taken; read keys
+	// from the terminal.
+	if overrides.StdinPaths != nil || slices.ContainsFunc(resolved.Tracks, func(t playlist.Track) bool { return playlist.IsStdin(t.Path) }) {
+		opts = append(opts, tea.WithInputTTY())
+	}
+	prog := tea.NewProgram(m, opts...)
+
+	if cfg.Remote.MPD != "" {
+		srv, err := remote.ListenMPD(cfg.Remote.MPD, hub, func(msg interface{}) { prog.Send(msg) })
+		if err != nil {
+			return fmt.Errorf("mpd: %w", err)
+		}
+		defer srv.Close()
+	}
+
+	if cfg.Remote.DLNA != "" {
+		r, err := remote.ListenDLNA(cfg.Remote.DLNA, cfg.Remote.DLNAName, hub, func(msg interface{}) { prog.Send(msg) })
+		if err != nil {
+			return fmt.Errorf("dlna: %w", err)
+		}
+		defer r.Close()
+	}
+
+	// Control socket for `cliamp pause`, `cliamp next`, etc. Like MPRIS,
+	// failure (e.g. another instance owns it) just leaves it off.
+	if cfg.Remote.SocketEnabled() {
+		if path, err := remote.SocketPath(); err == nil {
+			if srv, err := remote.ListenSocket(path, hub, func(msg interface{}) { prog.Send(msg) }); err == nil {
+				defer srv.Close()
+			}
+		}
+	}
+
+	if w := watch.Start(cfg.Watch, func(msg interface{}) { prog.Send(msg) }); w != nil {
+		defer w.Close()
+	}
 
 	if svc, err := mpris.New(func(msg interface{}) { prog.Send(msg) }); err == nil && svc != nil {
 		defer svc.Close()
 		go prog.Send(mpris.InitMsg{Svc: svc})
 	}
 
+	// macOS and Windows have no MPRIS; listen for hardware media keys
+	// directly. They arrive as the same messages MPRIS would send.
+	if keys, err := mediakeys.Start(func(msg interface{}) { prog.Send(msg) }); err == nil && keys != nil {
+		defer keys.Close()
+	}
+
+	defer relaySignals(prog, controlSignals(cfg.Remote))()
+
 	finalModel, err := prog.Run()
 	if err != nil {
 		return err
@@ -237,31 +489,77 @@ func run(overrides config.Overrides, positional []string) error {
 
 const helpText = `cliamp — retro terminal music player
 
-Usage: cliamp [flags] <file|folder|url> [...]
+Usage: cliamp [flags] <file|folder|url|-> [...]
+       cliamp <command> [args]
+
+Commands (control a running instance):
+  play, pause, toggle     Resume, pause, or toggle playback
+  next, prev, stop        Change track or stop
+  add <file|folder|url>   Append to the playlist
+  open <file|folder|url>  Replace the playlist and play
+  status [--json]         Print the current track and position, or the full status as JSON
+  quit                    Close the player
+  history [--json|--csv]  Print listening stats, or export the history
+  import itunes <xml>     Import playlists, ratings and play counts from an iTunes Library.xml
+  import mpd              Import MPD's playlists, and ratings and play counts from its stickers
+  export <playlist>       Write a saved playlist as M3U (--paths absolute|relative|uri,
+                          --separator unix|windows, -o <file>)
+  scan-gain <file|folder> Write ReplayGain track and album tags (--no-album, --dry-run; needs ffmpeg)
+  organize <file|folder>  Move files into folders named by their tags (--pattern, --to, --dry-run)
+  dupes <file|folder>     List files that are the same recording, by acoustic fingerprint (needs ffmpeg)
+  statusline              Print a line for status bars each time the track or state changes
+                          (--format text|waybar|json, --template <text>, --once)
 
 Playback:
   --volume <dB>           Volume in dB, range [-30, +6] (e.g. --volume -5)
-  --shuffle
+  --shuffle/--no-shuffle  Start with shuffle on or off
   --repeat <off|all|one>
   --mono / --no-mono
   --auto-play             Start playback immediately
+  --alarm <HH:MM>         Stay idle until this time, then play the playlist fading in
+  --stop-at <HH:MM>       Stop playing at this time (quit instead with --exit-after)
+  --loop <n>              Play the playlist n times, then stop (overrides --repeat)
+  --exit-after            Quit when the playlist has finished playing
+  --start <time>          Play the given files from this position (ss, mm:ss or hh:mm:ss)
+  --end <time>            Stop the given files at this position; add #t=1:00,2:30 to a path for one file
+  --seek <time>           Begin the first track at this position (e.g. --seek 12:34)
 
 Audio engine:
   --sample-rate <Hz>      Output sample rate (0=auto, 22050, 44100, 48000, 96000, 192000)
-  --buffer-ms <ms>        Speaker buffer in milliseconds (50–500)
+  --buffer <ms>           Speaker buffer, e.g. 50 or 50ms (20–500; alias: --buffer-ms)
   --resample-quality <n>  Resample quality factor (1–4)
   --bit-depth <n>         PCM bit depth: 16 (default) or 32 (lossless)
+  --record <file.wav>     Record the processed output (after EQ and volume) to a WAV file
+  --dsp-bypass            Bypass EQ/volume/effects; output at the first track's native rate
+  --icecast <url>         Broadcast the output to an Icecast mountpoint (e.g. http://source:pw@host:8000/live.mp3)
+
+Remote control:
+  --http <addr>           Serve status and a WebSocket event stream (e.g. 127.0.0.1:8754)
+  --mpd <addr>            Accept MPD clients such as mpc or ncmpcpp (e.g. 127.0.0.1:6600)
+  --dlna <addr>           Act as a DLNA/UPnP renderer phones can cast to (e.g. :49494)
+
+Library:
+  --stdin                 Also read paths to play from stdin, one per line (e.g. find ... | cliamp --stdin)
+  -0, --null              Like --stdin, with paths separated by NUL bytes (find -print0)
+  --watch <dir>           Append audio files added to dir while running (repeatable)
+  --exclude <pattern>     Skip matching files and folders when scanning (e.g. '*.live.*', podcasts/; repeatable)
 
 Provider:
-  --provider <name>       Default provider: radio, navidrome, plex, spotify, yt, youtube, ytmusic (default: radio)
+  --provider <name>       Default provider: radio, podcasts, navidrome, plex, spotify, yt, youtube, ytmusic (default: radio)
 
 Appearance:
   --compact               Compact mode (cap width at 80 columns)
+  --ascii, --no-ascii     Draw with plain ASCII only, or always use Unicode glyphs (default: detect)
+  --accessible            Screen reader mode: plain text, no animation, changes announced as lines
   --theme <name>          UI theme name
+  --palette <name>        Colors of the default theme: auto, dark, light, high-contrast, high-contrast-light
   --visualizer <mode>     Visualizer mode (Bars, Bricks, Columns, Wave, Scatter, Flame, Retro, Pulse, Matrix, Binary, None)
   --eq-preset <name>      EQ preset name (e.g. "Bass Boost")
 
 General:
+  --log-file <path>       Append a diagnostic log to path, for bug reports
+  --log-level <level>     Least severe level logged: debug, info (default), warn or error
+  --pprof <addr>          Serve Go runtime profiles under /debug/pprof/ (e.g. localhost:6060)
   -h, --help              Show this help message
   -v, --version           Show the current version
   --upgrade               Upgrade cliamp to the latest release
@@ -272,7 +570,9 @@ Examples:
   cliamp track.mp3 --repeat all --mono
   cliamp --auto-play --shuffle ~/Music
   cliamp --eq-preset "Bass Boost" ~/Music
+  cliamp --shuffle --repeat=all --volume=-6 --eq-preset=rock ~/Music
   cliamp https://example.com/song.mp3
+  cat mix.mp3 | cliamp -                 # play audio piped into stdin
   cliamp http://radio.example.com/stream.m3u
   cliamp search "rick astley"            # search YouTube
   cliamp search-sc "lofi beats"            # search SoundCloud
@@ -288,7 +588,100 @@ Playlists: ~/.config/cliamp/playlists/*.toml
 Formats:   mp3, wav, flac, ogg, m4a, aac, opus, wma (aac/opus/wma need ffmpeg)
 SoundCloud/YouTube/Bandcamp require yt-dlp`
 
+// forwardArgs sends file arguments to a running instance over the control
+// socket, as configured by [remote] forward. It reports whether they were
+// taken; when no instance answers this one starts as usual. Searches and
+// piped stdin only make sense in this process and are never forwarded.
+func forwardArgs(rc config.RemoteConfig, args []string) (bool, error) {
+	if !rc.SocketEnabled() || rc.Forward == "off" || len(args) == 0 ||
+		args[0] == "search" || args[0] == "search-sc" || slices.ContainsFunc(args, playlist.IsStdin) {
+		return false, nil
+	}
+	err := remote.Forward(args, rc.Forward == "replace")
+	if errors.Is(err, remote.ErrNotRunning) {
+		return false, nil
+	}
+	if err == nil {
+		fmt.Println("Sent to the running cliamp")
+	}
+	return true, err
+}
+
+// libraryDirs returns the folders of the music library, searched by the
+// finder and picked from by Auto-DJ: the configured library, else the
+// folders given on the command line, else ~/Music.
+func libraryDirs(library, args []string) []string {
+	if len(library) > 0 {
+		return library
+	}
+	var dirs []string
+	for _, a := range args {
+		if info, err := os.Stat(a); err == nil && info.IsDir() {
+			dirs = append(dirs, a)
+		}
+	}
+	if len(dirs) == 0 {
+		dirs = []string{"~/Music"}
+	}
+	return dirs
+}
+
+// runHistory implements `cliamp history`: a summary of the listening
+// history, or the full log with --json or --csv.
+func runHistory(args []string, w io.Writer) error {
+	entries, err := history.New().Entries()
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(args) == 0:
+		return history.WriteSummary(w, history.Summarize(entries, 10, time.Now()))
+	case args[0] == "--json":
+		return history.WriteJSON(w, entries)
+	case args[0] == "--csv":
+		return history.WriteCSV(w, entries)
+	}
+	return fmt.Errorf("usage: cliamp history [--json | --csv]")
+}
+
+// subcommands are the one-shot commands that run instead of the player,
+// keyed by their first argument; each gets the arguments after it.
+var subcommands = map[string]func(args []string, w io.Writer) error{
+	"history":    runHistory,
+	"import":     runImport,
+	"export":     runExport,
+	"scan-gain":  runScanGain,
+	"organize":   runOrganize,
+	"dupes":      runDupes,
+	"statusline": remote.RunStatusline,
+}
+
 func main() {
+	// `cliamp history`, `cliamp organize`, ... run once and exit, unless a
+	// file of that name is in the working directory.
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if _, err := os.Stat(os.Args[1]); err != nil {
+				if err := run(os.Args[2:], os.Stdout); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				return
+			}
+		}
+	}
+	// `cliamp next`, `cliamp status`, ... drive an instance that is already
+	// running. A file of the same name in the working directory still plays.
+	if len(os.Args) > 1 && remote.IsCommand(os.Args[1]) {
+		if _, err := os.Stat(os.Args[1]); err != nil {
+			if err := remote.RunCommand(os.Args[1:], os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	action, overrides, positional, err := config.ParseFlags(os.Args[1:])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,42 @@
+// Package mediakeys listens for hardware media keys (play/pause, next,
+// previous, stop) system-wide on platforms without MPRIS, so they work even
+// when the terminal running Cliamp is not focused.
+//
+// Key presses are delivered as the same mpris message types the D-Bus
+// service sends on Linux, so the TUI handles them through one dispatcher.
+package mediakeys
+
+import "cliamp/mpris"
+
+// key identifies a media key independent of the platform's key codes.
+type key int
+
+const (
+	keyPlayPause key = iota
+	keyNext
+	keyPrev
+	keyStop
+	keyFastForward
+	keyRewind
+)
+
+// seekStep is how far the fast-forward and rewind keys seek.
+const seekStep = 5_000_000 // microseconds
+
+// dispatch translates a key press into the matching message.
+func dispatch(send func(interface{}), k key) {
+	switch k {
+	case keyPlayPause:
+		send(mpris.PlayPauseMsg{})
+	case keyNext:
+		send(mpris.NextMsg{})
+	case keyPrev:
+		send(mpris.PrevMsg{})
+	case keyStop:
+		send(mpris.StopMsg{})
+	case keyFastForward:
+		send(mpris.SeekMsg{Offset: seekStep})
+	case keyRewind:
+		send(mpris.SeekMsg{Offset: -seekStep})
+	}
+}
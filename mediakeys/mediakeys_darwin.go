@@ -0,0 +1,93 @@
+//go:build darwin && !ios
+
+package mediakeys
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit -framework ApplicationServices
+#include "mediakeys_darwin.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+)
+
+// NX_KEYTYPE_* codes from IOKit/hidsystem/ev_keymap.h.
+const (
+	nxKeyPlay     = 16
+	nxKeyNext     = 17
+	nxKeyPrevious = 18
+	nxKeyFast     = 19
+	nxKeyRewind   = 20
+)
+
+// active is the send function of the running listener; the event tap
+// callback has no user data pointer back into Go.
+var active atomic.Pointer[func(interface{})]
+
+//export goMediaKey
+func goMediaKey(code C.int) {
+	send := active.Load()
+	if send == nil {
+		return
+	}
+	switch code {
+	case nxKeyPlay:
+		dispatch(*send, keyPlayPause)
+	case nxKeyNext:
+		dispatch(*send, keyNext)
+	case nxKeyPrevious:
+		dispatch(*send, keyPrev)
+	case nxKeyFast:
+		dispatch(*send, keyFastForward)
+	case nxKeyRewind:
+		dispatch(*send, keyRewind)
+	}
+}
+
+// Listener owns the Quartz event tap and the run loop serving it.
+type Listener struct {
+	done chan struct{}
+}
+
+// Start installs a session event tap for the media keys. macOS asks the
+// user to grant Cliamp's terminal Accessibility (Input Monitoring) access
+// the first time; until then Start returns an error.
+func Start(send func(interface{})) (*Listener, error) {
+	l := &Listener{done: make(chan struct{})}
+	ready := make(chan error, 1)
+
+	go func() {
+		// The tap is attached to this thread's run loop.
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(l.done)
+
+		if C.mediakeysInit() != 0 {
+			ready <- errors.New("mediakeys: event tap unavailable (grant the terminal Accessibility access)")
+			return
+		}
+		active.Store(&send)
+		ready <- nil
+		C.mediakeysRun()
+	}()
+
+	if err := <-ready; err != nil {
+		<-l.done
+		return nil, err
+	}
+	return l, nil
+}
+
+// Close removes the event tap and stops its run loop.
+func (l *Listener) Close() {
+	if l == nil {
+		return
+	}
+	active.Store(nil)
+	C.mediakeysStop()
+	<-l.done
+}
@@ -0,0 +1,10 @@
+// mediakeys_darwin.h — Quartz event tap for hardware media keys.
+
+#ifndef CLIAMP_MEDIAKEYS_DARWIN_H
+#define CLIAMP_MEDIAKEYS_DARWIN_H
+
+int mediakeysInit(void);
+void mediakeysRun(void);
+void mediakeysStop(void);
+
+#endif
@@ -0,0 +1,79 @@
+// mediakeys_darwin.m — Quartz event tap for hardware media keys.
+//
+// Media keys arrive as NX_SYSDEFINED events with subtype 8; data1 packs the
+// NX_KEYTYPE_* code in its high word and the key state in the low word.
+// Handled keys are swallowed so Music.app does not launch alongside Cliamp.
+
+//go:build darwin && !ios
+
+#import <AppKit/AppKit.h>
+#include <IOKit/hidsystem/ev_keymap.h>
+#include "mediakeys_darwin.h"
+
+extern void goMediaKey(int code);
+
+static CFMachPortRef tap;
+static CFRunLoopSourceRef source;
+static CFRunLoopRef loop;
+
+static CGEventRef mediaKeyCallback(CGEventTapProxy proxy, CGEventType type, CGEventRef event, void *refcon) {
+    // macOS disables taps that are slow to respond; turn it back on.
+    if (type == kCGEventTapDisabledByTimeout || type == kCGEventTapDisabledByUserInput) {
+        CGEventTapEnable(tap, true);
+        return event;
+    }
+    if (type != NX_SYSDEFINED) {
+        return event;
+    }
+    NSEvent *ev = [NSEvent eventWithCGEvent:event];
+    if (ev == nil || ev.subtype != 8) {
+        return event;
+    }
+    long data = ev.data1;
+    int code = (int)((data & 0xFFFF0000) >> 16);
+    bool down = ((data & 0xFF00) >> 8) == 0xA;
+    switch (code) {
+    case NX_KEYTYPE_PLAY:
+    case NX_KEYTYPE_NEXT:
+    case NX_KEYTYPE_PREVIOUS:
+    case NX_KEYTYPE_FAST:
+    case NX_KEYTYPE_REWIND:
+        if (down) {
+            goMediaKey(code);
+        }
+        return NULL;
+    }
+    return event;
+}
+
+// mediakeysInit creates the tap on the calling thread. Returns -1 when the
+// process lacks Accessibility permission.
+int mediakeysInit(void) {
+    tap = CGEventTapCreate(kCGSessionEventTap, kCGHeadInsertEventTap,
+        kCGEventTapOptionDefault, CGEventMaskBit(NX_SYSDEFINED),
+        mediaKeyCallback, NULL);
+    if (tap == NULL) {
+        return -1;
+    }
+    source = CFMachPortCreateRunLoopSource(kCFAllocatorDefault, tap, 0);
+    loop = CFRunLoopGetCurrent();
+    CFRunLoopAddSource(loop, source, kCFRunLoopCommonModes);
+    CGEventTapEnable(tap, true);
+    return 0;
+}
+
+// mediakeysRun serves the tap until mediakeysStop is called.
+void mediakeysRun(void) {
+    CFRunLoopRun();
+    CFRunLoopRemoveSource(loop, source, kCFRunLoopCommonModes);
+    CFRelease(source);
+    CFMachPortInvalidate(tap);
+    CFRelease(tap);
+}
+
+void mediakeysStop(void) {
+    if (loop != NULL) {
+        CGEventTapEnable(tap, false);
+        CFRunLoopStop(loop);
+    }
+}
@@ -0,0 +1,14 @@
+//go:build (!darwin && !windows) || ios
+
+package mediakeys
+
+// Listener is a no-op on platforms where media keys arrive through MPRIS.
+type Listener struct{}
+
+// Start returns nil: Linux and the BSDs receive media keys via MPRIS.
+func Start(send func(interface{})) (*Listener, error) {
+	return nil, nil
+}
+
+// Close is a no-op.
+func (l *Listener) Close() {}
@@ -0,0 +1,112 @@
+//go:build windows
+
+package mediakeys
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                 = syscall.NewLazyDLL("user32.dll")
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procRegisterHotKey     = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = user32.NewProc("UnregisterHotKey")
+	procGetMessageW        = user32.NewProc("GetMessageW")
+	procPostThreadMessageW = user32.NewProc("PostThreadMessageW")
+	procGetCurrentThreadID = kernel32.NewProc("GetCurrentThreadId")
+)
+
+const (
+	wmQuit      = 0x0012
+	wmHotkey    = 0x0312
+	modNoRepeat = 0x4000
+)
+
+// hotkeys maps hotkey IDs (index+1) to virtual-key codes and keys.
+var hotkeys = []struct {
+	vk  uintptr
+	key key
+}{
+	{0xB3, keyPlayPause}, // VK_MEDIA_PLAY_PAUSE
+	{0xB0, keyNext},      // VK_MEDIA_NEXT_TRACK
+	{0xB1, keyPrev},      // VK_MEDIA_PREV_TRACK
+	{0xB2, keyStop},      // VK_MEDIA_STOP
+}
+
+// winMsg mirrors the Win32 MSG structure.
+type winMsg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+	private uint32
+}
+
+// Listener owns the global hotkey registrations and their message loop.
+type Listener struct {
+	tid  uintptr
+	done chan struct{}
+}
+
+// Start registers the media keys as global hotkeys. Keys already claimed by
+// another application are skipped; an error is returned only if none could
+// be registered.
+func Start(send func(interface{})) (*Listener, error) {
+	l := &Listener{done: make(chan struct{})}
+	ready := make(chan error, 1)
+
+	go func() {
+		// Hotkeys without a window are bound to the registering thread, so
+		// registration and the message loop must share one OS thread.
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(l.done)
+
+		l.tid, _, _ = procGetCurrentThreadID.Call()
+		registered := 0
+		for i, hk := range hotkeys {
+			if r, _, _ := procRegisterHotKey.Call(0, uintptr(i+1), modNoRepeat, hk.vk); r != 0 {
+				registered++
+			}
+		}
+		if registered == 0 {
+			ready <- errors.New("mediakeys: media keys are registered by another application")
+			return
+		}
+		ready <- nil
+
+		var m winMsg
+		for {
+			r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(r) <= 0 { // WM_QUIT or error
+				break
+			}
+			if m.message == wmHotkey && m.wParam >= 1 && int(m.wParam) <= len(hotkeys) {
+				dispatch(send, hotkeys[m.wParam-1].key)
+			}
+		}
+		for i := range hotkeys {
+			procUnregisterHotKey.Call(0, uintptr(i+1))
+		}
+	}()
+
+	if err := <-ready; err != nil {
+		<-l.done
+		return nil, err
+	}
+	return l, nil
+}
+
+// Close unregisters the hotkeys and stops the message loop.
+func (l *Listener) Close() {
+	if l == nil {
+		return
+	}
+	procPostThreadMessageW.Call(l.tid, wmQuit, 0, 0)
+	<-l.done
+}
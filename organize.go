@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"cliamp/config"
+	"cliamp/external/local"
+	"cliamp/internal/audiobook"
+	"cliamp/internal/bookmark"
+	"cliamp/internal/history"
+	"cliamp/internal/organize"
+	"cliamp/internal/resume"
+	"cliamp/internal/trackeq"
+	"cliamp/internal/trackgain"
+	"cliamp/library"
+	"cliamp/resolve"
+)
+
+const organizeUsage = "usage: cliamp organize [--pattern <pattern>] [--to <folder>] [--dry-run] <file|folder>..."
+
+// runOrganize implements `cliamp organize`: it moves local files into
+// folders named after their tags, below --to or else the first library
+// folder, and points the saved playlists, bookmarks, per-track gains and
+// EQ curves, audiobook positions, history and resume point at their new
+// paths. --dry-run prints the moves without making them.
+func runOrganize(args []string, w io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	pattern, root, dryRun := cfg.OrganizePattern, "", false
+	var paths []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--pattern" || a == "--to" {
+			if i+1 >= len(args) {
+				return errors.New(organizeUsage)
+			}
+			i++
+		}
+		switch a {
+		case "--pattern":
+			pattern = args[i]
+		case "--to":
+			root = args[i]
+		case "--dry-run":
+			dryRun = true
+		default:
+			if len(a) > 1 && a[0] == '-' {
+				return errors.New(organizeUsage)
+			}
+			paths = append(paths, a)
+		}
+	}
+	if len(paths) == 0 {
+		return errors.New(organizeUsage)
+	}
+	if root == "" {
+		dirs := library.New(libraryDirs(cfg.Library, nil), nil).Dirs()
+		if len(dirs) == 0 {
+			return errors.New("organize: no library folder; choose one with --to")
+		}
+		root = dirs[0]
+	}
+
+	resolved, err := resolve.Args(paths)
+	if err != nil {
+		return err
+	}
+	moves, err := organize.Plan(pattern, root, resolved.Tracks)
+	if err != nil {
+		return fmt.Errorf("organize: %w", err)
+	}
+	if len(moves) == 0 {
+		fmt.Fprintln(w, "Nothing to move.")
+		return nil
+	}
+
+	if dryRun {
+		for _, m := range moves {
+			fmt.Fprintf(w, "%s\n     -> %s\n", m.From, m.To)
+		}
+		return nil
+	}
+
+	playlists := 0
+	indexes := []organize.Index{
+		{Name: "bookmarks", Rename: bookmark.New().Rename},
+		{Name: "track gains", Rename: trackgain.New().Rename},
+		{Name: "track EQ curves", Rename: trackeq.New().Rename},
+		{Name: "audiobook positions", Rename: audiobook.New(nil).Rename},
+		{Name: "history", Rename: history.New().Rename},
+		{Name: "resume point", Rename: resume.Rename},
+	}
+	if prov := local.New(); prov != nil {
+		indexes = append([]organize.Index{{Name: "playlists", Rename: func(moved map[string]string) error {
+			n, err := prov.RenamePaths(moved)
+			playlists += n
+			return err
+		}}}, indexes...)
+	}
+	failed := 0
+	err = organize.Apply(moves, indexes, func(m organize.Move, err error) {
+		if err != nil {
+			fmt.Fprintf(w, "failed  %s: %v\n", m.From, err)
+			failed++
+			return
+		}
+		fmt.Fprintf(w, "%s\n     -> %s\n", m.From, m.To)
+	})
+	if err != nil {
+		return fmt.Errorf("organize: %w", err)
+	}
+	switch {
+	case playlists == 1:
+		fmt.Fprintln(w, "Updated 1 playlist.")
+	case playlists > 1:
+		fmt.Fprintf(w, "Updated %d playlists.\n", playlists)
+	}
+	if failed > 0 {
+		return fmt.Errorf("organize: %d of %d files failed", failed, len(moves))
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"cliamp/icecast"
+)
+
+// broadcastQueue is the number of sample blocks buffered between the audio
+// thread and the encoder before blocks start being dropped.
+const broadcastQueue = 64
+
+// BroadcastConfig describes an Icecast mountpoint to stream the output to.
+type BroadcastConfig struct {
+	URL         string // http://host:8000/mount, optionally with user:password
+	User        string // defaults to "source"
+	Password    string
+	Format      string // "mp3" (default) or "ogg"
+	Bitrate     int    // kbps; 0 means 128
+	Name        string
+	Description string
+	Genre       string
+	Public      bool
+}
+
+// broadcaster encodes the processed output with ffmpeg and relays the
+// encoded stream to an Icecast server. It is fed by the recorder tee, so
+// listeners hear exactly what the local speaker plays.
+type broadcaster struct {
+	pcmQueue
+	sr    int
+	ice   icecast.Config
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	conn  net.Conn
+
+	done      chan struct{}
+	closeOnce sync.Once
+	errOnce   sync.Once
+	err       error // first failure; read after done is closed
+
+	titleMu sync.Mutex
+	title   string // last song title sent to the server
+}
+
+// StartBroadcast begins streaming the output to an Icecast mountpoint.
+// Any broadcast already running is stopped first. ffmpeg does the
+// encoding, so it must be installed.
+func (p *Player) StartBroadcast(cfg BroadcastConfig) error {
+	p.StopBroadcast()
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return fmt.Errorf("broadcast: ffmpeg is required to encode the stream — %s", ffmpegInstallHint())
+	}
+	contentType, err := icecast.ContentType(cfg.Format)
+	if err != nil {
+		return err
+	}
+	bitrate := cfg.Bitrate
+	if bitrate <= 0 {
+		bitrate = 128
+	}
+	ice := icecast.Config{
+		URL:         cfg.URL,
+		User:        cfg.User,
+		Password:    cfg.Password,
+		ContentType: contentType,
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		Genre:       cfg.Genre,
+		Bitrate:     bitrate,
+		Public:      cfg.Public,
+	}
+	conn, err := icecast.Dial(ice)
+	if err != nil {
+		return fmt.Errorf("broadcast: %w", err)
+	}
+
+	codec, muxer := "libmp3lame", "mp3"
+	if contentType == "application/ogg" {
+		codec, muxer = "libvorbis", "ogg"
+	}
+	cmd := exec.Command("ffmpeg",
+		"-f", "s16le", "-ar", strconv.Itoa(int(p.sr)), "-ac", "2", "-i", "pipe:0",
+		"-c:a", codec, "-b:a", strconv.Itoa(bitrate)+"k",
+		"-f", muxer, "-loglevel", "error", "pipe:1",
+	)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("broadcast: %w", err)
+	}
+	cmd.Stdout = conn
+	if err := cmd.Start(); err != nil {
+		conn.Close()
+		return fmt.Errorf("broadcast: %w", err)
+	}
+
+	b := &broadcaster{
+		pcmQueue: newPCMQueue(broadcastQueue),
+		sr:       int(p.sr),
+		ice:      ice,
+		cmd:      cmd,
+		stdin:    stdin,
+		conn:     conn,
+		done:     make(chan struct{}),
+	}
+	go b.feed()
+	go b.wait()
+	p.bcast.Store(b)
+	p.rec.bcast.Store(b)
+	go p.watchBroadcast(b)
+	return nil
+}
+
+// feed writes queued PCM into the encoder. While nothing is playing it
+// sends silence, so the server does not time the source out and listeners
+// stay connected.
+func (b *broadcaster) feed() {
+	silence := make([]byte, b.sr/4*4) // 250 ms
+	idle := time.NewTimer(castIdle)
+	defer idle.Stop()
+	for {
+		buf, pooled := silence, false
+		select {
+		case <-b.done:
+			return
+		case buf = <-b.ch:
+			pooled = true
+		case <-idle.C:
+		}
+		_, err := b.stdin.Write(buf)
+		if pooled {
+			b.recycle(buf)
+		}
+		if err != nil {
+			b.fail(err)
+			return
+		}
+		idle.Reset(castIdle)
+	}
+}
+
+// wait ends the broadcast when the encoder exits, which it does when the
+// server drops the connection and its output fails.
+func (b *broadcaster) wait() {
+	err := b.cmd.Wait()
+	if err == nil {
+		err = errBroadcastEnded
+	}
+	b.fail(err)
+}
+
+var errBroadcastEnded = errors.New("broadcast: connection to the server closed")
+
+// fail records the first error and shuts the broadcast down.
+func (b *broadcaster) fail(err error) {
+	b.errOnce.Do(func() { b.err = err })
+	b.close()
+}
+
+func (b *broadcaster) close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		b.stdin.Close()
+		b.conn.Close()
+	})
+}
+
+// watchBroadcast detaches a broadcast that failed on its own so the error
+// can be reported.
+func (p *Player) watchBroadcast(b *broadcaster) {
+	<-b.done
+	if p.bcast.CompareAndSwap(b, nil) {
+		p.rec.bcast.Store(nil)
+		p.mu.Lock()
+		p.bcastErr = fmt.Errorf("broadcast stopped: %w", b.err)
+		p.mu.Unlock()
+	}
+}
+
+// StopBroadcast ends the broadcast, if any.
+func (p *Player) StopBroadcast() {
+	b := p.bcast.Swap(nil)
+	if b == nil {
+		return
+	}
+	p.rec.bcast.Store(nil)
+	b.close()
+}
+
+// Broadcasting reports whether the output is being streamed to Icecast.
+func (p *Player) Broadcasting() bool {
+	return p.bcast.Load() != nil
+}
+
+// BroadcastErr reports, once, why a broadcast stopped on its own.
+func (p *Player) BroadcastErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.bcastErr
+	p.bcastErr = nil
+	return err
+}
+
+// SetBroadcastTitle updates the song title listeners see, usually
+// "Artist - Title". Repeated calls with the same title are ignored, so it
+// can be called on every tick. The update is sent in the background.
+func (p *Player) SetBroadcastTitle(title string) {
+	b := p.bcast.Load()
+	// Ogg streams carry titles in-band; the server only takes updates for MP3.
+	if b == nil || title == "" || b.ice.ContentType != "audio/mpeg" {
+		return
+	}
+	b.titleMu.Lock()
+	defer b.titleMu.Unlock()
+	if title == b.title {
+		return
+	}
+	b.title = title
+	go icecast.UpdateMetadata(b.ice, title)
+}
@@ -0,0 +1,306 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cliamp/cast"
+)
+
+// castQueue is the number of sample blocks buffered between the audio
+// thread and the HTTP stream before blocks start being dropped.
+const castQueue = 64
+
+// castIdle is how long the stream may go without audio before silence is
+// sent to keep the receiver's connection alive while paused or stopped.
+// It is well above the largest speaker buffer (500 ms).
+const castIdle = 750 * time.Millisecond
+
+// castOutput sends the processed output to a Cast device instead of the
+// local speaker. The receiver pulls it as an endless WAV stream from a
+// small HTTP server; the local tap keeps pacing the pipeline in real time
+// but outputs silence.
+type castOutput struct {
+	name   string
+	title  string // metadata shown on the device
+	artist string
+	client *cast.Client
+	srv    *http.Server
+	url    string
+
+	pcmQueue
+	gen atomic.Int64
+	sr  int
+
+	lastLevel atomic.Uint64 // device volume last sent or seen, Float64bits
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// serveStream writes the output as WAV until the receiver disconnects or a
+// newer request (after a reload) takes over the stream.
+func (c *castOutput) serveStream(w http.ResponseWriter, r *http.Request) {
+	gen := c.gen.Add(1)
+	w.Header().Set("Content-Type", "audio/wav")
+	w.Header().Set("Cache-Control", "no-store")
+	// An endless stream has no real size; use the largest the header allows.
+	h := wavHeader(c.sr, math.MaxUint32-36)
+	if _, err := w.Write(h[:]); err != nil {
+		return
+	}
+	flusher, _ := w.(http.Flusher)
+	silence := make([]byte, c.sr/4*4) // 250 ms
+	idle := time.NewTimer(castIdle)
+	defer idle.Stop()
+	for {
+		buf, pooled := silence, false
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c.done:
+			return
+		case buf = <-c.ch:
+			pooled = true
+		case <-idle.C:
+		}
+		if c.gen.Load() != gen {
+			if pooled {
+				c.recycle(buf)
+			}
+			return
+		}
+		_, err := w.Write(buf)
+		if pooled {
+			c.recycle(buf)
+		}
+		if err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		idle.Reset(castIdle)
+	}
+}
+
+// load (re)starts playback of the stream on the receiver. A fresh URL
+// makes the receiver drop what it has buffered, so a seek is heard
+// promptly.
+func (c *castOutput) load() error {
+	return c.client.Load(cast.Media{
+		URL:         c.url + "?n=" + strconv.FormatInt(c.gen.Load()+1, 10),
+		ContentType: "audio/wav",
+		Live:        true,
+		Title:       c.title,
+		Artist:      c.artist,
+	})
+}
+
+func (c *castOutput) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.client.Close()
+		c.srv.Close()
+	})
+}
+
+// castLevel maps a volume in dB to the receiver's 0–1 scale, on the same
+// curve MPRIS uses, so full volume (+6 dB) is 1.
+func castLevel(db float64) float64 {
+	if db <= -30 {
+		return 0
+	}
+	return max(0, min(1, math.Pow(10, (db-6)/20)))
+}
+
+// castDB is the inverse of castLevel.
+func castDB(level float64) float64 {
+	if level <= 0 {
+		return -30
+	}
+	return max(-30, min(6, 20*math.Log10(level)+6))
+}
+
+// StartCast moves the output to dev. Playback carries on from where it
+// is; the local speaker goes silent until StopCast. title and artist are
+// shown on the device. Any earlier cast is ended first.
+func (p *Player) StartCast(dev cast.Device, title, artist string) error {
+	p.StopCast()
+
+	// Serve the stream on the address the device can reach us at.
+	probe, err := net.Dial("udp", dev.Addr())
+	if err != nil {
+		return fmt.Errorf("cast: %w", err)
+	}
+	local := probe.LocalAddr().(*net.UDPAddr).IP
+	probe.Close()
+	ln, err := net.Listen("tcp", net.JoinHostPort(local.String(), "0"))
+	if err != nil {
+		return fmt.Errorf("cast: %w", err)
+	}
+
+	client, err := cast.Dial(dev.Addr())
+	if err != nil {
+		ln.Close()
+		return fmt.Errorf("cast: %w", err)
+	}
+	c := &castOutput{
+		name:     dev.Name,
+		title:    title,
+		artist:   artist,
+		client:   client,
+		url:      "http://" + ln.Addr().String() + "/stream.wav",
+		pcmQueue: newPCMQueue(castQueue),
+		sr:       int(p.sr),
+		done:     make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream.wav", c.serveStream)
+	c.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go c.srv.Serve(ln)
+
+	level := castLevel(p.Volume())
+	if p.muted.Load() {
+		level = 0
+	}
+	c.lastLevel.Store(math.Float64bits(level))
+	client.SetVolume(level)
+	if err := c.load(); err != nil {
+		c.close()
+		return fmt.Errorf("cast: %w", err)
+	}
+
+	p.attachCast(c)
+	go p.watchCast(c)
+	return nil
+}
+
+// attachCast switches the pipeline to c: the stream gets the output at
+// unity volume, since the device applies the volume, and the speaker is
+// silenced.
+func (p *Player) attachCast(c *castOutput) {
+	p.castOut.Store(c)
+	p.castVolume.Store(true)
+	p.mu.Lock()
+	tap := p.tap
+	p.mu.Unlock()
+	if tap != nil {
+		tap.mute.Store(true)
+	}
+	p.rec.cast.Store(c)
+}
+
+// detachCast returns output to the speaker if c is the active cast. It
+// reports whether it was.
+func (p *Player) detachCast(c *castOutput) bool {
+	if c == nil || !p.castOut.CompareAndSwap(c, nil) {
+		return false
+	}
+	p.rec.cast.Store(nil)
+	p.castVolume.Store(false)
+	p.mu.Lock()
+	tap := p.tap
+	p.mu.Unlock()
+	if tap != nil {
+		tap.mute.Store(false)
+	}
+	return true
+}
+
+// StopCast ends the cast session, if any, and resumes local output.
+func (p *Player) StopCast() {
+	if c := p.castOut.Load(); p.detachCast(c) {
+		c.close()
+	}
+}
+
+// CastTarget returns the name of the device being cast to, or "".
+func (p *Player) CastTarget() string {
+	if c := p.castOut.Load(); c != nil {
+		return c.name
+	}
+	return ""
+}
+
+// CastErr reports, once, why a cast session ended on the device's side.
+// Output has already fallen back to the local speaker by then.
+func (p *Player) CastErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.castErr
+	p.castErr = nil
+	return err
+}
+
+var errCastEnded = errors.New("cast session ended")
+
+// watchCast falls back to local output when the session dies and mirrors
+// volume changes made on the device (or its app) into the player.
+func (p *Player) watchCast(c *castOutput) {
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.client.Done():
+			if p.detachCast(c) {
+				err := c.client.Err()
+				if err == nil {
+					err = errCastEnded
+				}
+				p.mu.Lock()
+				p.castErr = err
+				p.mu.Unlock()
+			}
+			c.close()
+			return
+		case <-t.C:
+			level := c.client.Volume()
+			last := math.Float64frombits(c.lastLevel.Load())
+			if level >= 0 && math.Abs(level-last) > 0.005 {
+				c.lastLevel.Store(math.Float64bits(level))
+				p.volume.Store(math.Float64bits(castDB(level)))
+			}
+		}
+	}
+}
+
+// castSetVolume forwards a volume change to the device.
+func (p *Player) castSetVolume(db float64) {
+	p.castSetLevel(castLevel(db))
+}
+
+// castSetLevel sets the receiver's volume level in [0, 1].
+func (p *Player) castSetLevel(level float64) {
+	if c := p.castOut.Load(); c != nil {
+		c.lastLevel.Store(math.Float64bits(level))
+		go c.client.SetVolume(level)
+	}
+}
+
+// castSetPaused pauses or resumes the receiver along with the player.
+func (p *Player) castSetPaused(paused bool) {
+	if c := p.castOut.Load(); c != nil {
+		if paused {
+			go c.client.Pause()
+		} else {
+			go c.client.Play()
+		}
+	}
+}
+
+// castFlush reloads the stream after a seek so the device does not play
+// out seconds of audio buffered from before the jump.
+func (p *Player) castFlush() {
+	if c := p.castOut.Load(); c != nil {
+		go c.load()
+	}
+}
@@ -0,0 +1,55 @@
+package player
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCastLevelRoundTrip(t *testing.T) {
+	if got := castLevel(6); got != 1 {
+		t.Errorf("castLevel(+6) = %v, want 1", got)
+	}
+	if got := castLevel(-30); got != 0 {
+		t.Errorf("castLevel(-30) = %v, want 0", got)
+	}
+	for _, db := range []float64{-24, -6, 0, 3} {
+		if got := castDB(castLevel(db)); math.Abs(got-db) > 1e-9 {
+			t.Errorf("castDB(castLevel(%v)) = %v", db, got)
+		}
+	}
+}
+
+func TestCastStreamServesWAV(t *testing.T) {
+	c := &castOutput{pcmQueue: newPCMQueue(castQueue), sr: 44100, done: make(chan struct{})}
+	srv := httptest.NewServer(http.HandlerFunc(c.serveStream))
+	defer srv.Close()
+	defer close(c.done)
+
+	c.push([][2]float64{{0.5, -0.5}, {1, -1}})
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "audio/wav" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	data := make([]byte, wavHeaderSize+8)
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		t.Fatal(err)
+	}
+	if string(data[:4]) != "RIFF" || binary.LittleEndian.Uint32(data[24:]) != 44100 {
+		t.Fatalf("bad header % x", data[:wavHeaderSize])
+	}
+	if l := int16(binary.LittleEndian.Uint16(data[wavHeaderSize:])); l != 16384 {
+		t.Errorf("first sample = %d, want 16384", l)
+	}
+	if r := int16(binary.LittleEndian.Uint16(data[wavHeaderSize+6:])); r != -32767 {
+		t.Errorf("last sample = %d, want -32767", r)
+	}
+}
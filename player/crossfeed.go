@@ -0,0 +1,111 @@
+package player
+
+import (
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+// CrossfeedPreset names a crossfeed cutoff/level pair, following the
+// presets popularized by Bauer's bs2b library.
+type CrossfeedPreset int
+
+const (
+	CrossfeedDefault CrossfeedPreset = iota // 700 Hz, 4.5 dB — closest to Bauer's original
+	CrossfeedCmoy                           // 700 Hz, 6.0 dB — Chu Moy's circuit
+	CrossfeedJmeier                         // 650 Hz, 9.5 dB — Jan Meier's, subtle
+	numCrossfeedPresets
+)
+
+var crossfeedParams = [numCrossfeedPresets]struct {
+	name   string
+	cutoff float64 // Hz
+	feedDB float64 // attenuation of the crossfed signal
+}{
+	{"default", 700, 4.5},
+	{"cmoy", 700, 6.0},
+	{"jmeier", 650, 9.5},
+}
+
+// String returns the preset's config name.
+func (c CrossfeedPreset) String() string {
+	if c < 0 || c >= numCrossfeedPresets {
+		return crossfeedParams[CrossfeedDefault].name
+	}
+	return crossfeedParams[c].name
+}
+
+// Next returns the following preset, wrapping around.
+func (c CrossfeedPreset) Next() CrossfeedPreset {
+	return (c + 1) % numCrossfeedPresets
+}
+
+// ParseCrossfeedPreset maps a config name to a preset. Unknown names fall
+// back to CrossfeedDefault.
+func ParseCrossfeedPreset(name string) CrossfeedPreset {
+	for i, p := range crossfeedParams {
+		if strings.EqualFold(p.name, name) {
+			return CrossfeedPreset(i)
+		}
+	}
+	return CrossfeedDefault
+}
+
+// crossfeed mixes a low-passed copy of each channel into the other, the way
+// sound from a loudspeaker reaches both ears, which removes the "inside the
+// head" image of hard-panned headphone mixes. The direct path gets a matching
+// treble lift so mono content passes through with a flat response.
+type crossfeed struct {
+	enabled *atomic.Bool
+	preset  *atomic.Int32
+	sr      float64
+
+	cur        CrossfeedPreset
+	inited     bool
+	a, g       float64    // one-pole lowpass coefficient and linear feed gain
+	lp         [2]float64 // lowpass state per channel
+	wasEnabled bool
+}
+
+func newCrossfeed(enabled *atomic.Bool, preset *atomic.Int32, sr float64) *crossfeed {
+	return &crossfeed{enabled: enabled, preset: preset, sr: sr}
+}
+
+func (c *crossfeed) Name() string { return EffectCrossfeed }
+
+func (c *crossfeed) calcCoeffs(p CrossfeedPreset) {
+	if c.inited && p == c.cur {
+		return
+	}
+	c.cur = p
+	c.inited = true
+	params := crossfeedParams[p]
+	c.a = 1 - math.Exp(-2*math.Pi*params.cutoff/c.sr)
+	c.g = math.Pow(10, -params.feedDB/20)
+}
+
+func (c *crossfeed) Process(samples [][2]float64) {
+	if !c.enabled.Load() {
+		c.wasEnabled = false
+		return
+	}
+	if !c.wasEnabled {
+		// Start from a clean filter state so re-enabling doesn't pop.
+		c.lp = [2]float64{}
+		c.wasEnabled = true
+	}
+	p := CrossfeedPreset(c.preset.Load())
+	if p < 0 || p >= numCrossfeedPresets {
+		p = CrossfeedDefault
+	}
+	c.calcCoeffs(p)
+
+	norm := 1 / (1 + c.g)
+	for i := range samples {
+		l, r := samples[i][0], samples[i][1]
+		c.lp[0] += c.a * (l - c.lp[0])
+		c.lp[1] += c.a * (r - c.lp[1])
+		samples[i][0] = (l + c.g*(l-c.lp[0]) + c.g*c.lp[1]) * norm
+		samples[i][1] = (r + c.g*(r-c.lp[1]) + c.g*c.lp[0]) * norm
+	}
+}
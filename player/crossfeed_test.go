@@ -0,0 +1,54 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCrossfeedMonoIsUnchanged(t *testing.T) {
+	const sr = 44100
+	in := make([][2]float64, 2048)
+	for i := range in {
+		v := 0.5 * math.Sin(2*math.Pi*3000*float64(i)/sr)
+		in[i] = [2]float64{v, v}
+	}
+
+	var on atomic.Bool
+	on.Store(true)
+	var preset atomic.Int32
+	cf := newCrossfeed(&on, &preset, sr)
+
+	out := append([][2]float64(nil), in...)
+	cf.Process(out)
+	for i := range out {
+		for ch := range 2 {
+			if d := math.Abs(out[i][ch] - in[i][ch]); d > 1e-9 {
+				t.Fatalf("sample %d ch %d = %v, want %v", i, ch, out[i][ch], in[i][ch])
+			}
+		}
+	}
+}
+
+func TestCrossfeedBleedsHardPannedBass(t *testing.T) {
+	const sr = 44100
+	in := make([][2]float64, 4096)
+	for i := range in {
+		in[i] = [2]float64{0.5 * math.Sin(2*math.Pi*100*float64(i)/sr), 0}
+	}
+
+	var on atomic.Bool
+	on.Store(true)
+	var preset atomic.Int32
+	cf := newCrossfeed(&on, &preset, sr)
+
+	out := append([][2]float64(nil), in...)
+	cf.Process(out)
+	peak := 0.0
+	for i := len(out) / 2; i < len(out); i++ {
+		peak = max(peak, math.Abs(out[i][1]))
+	}
+	if peak < 0.05 {
+		t.Fatalf("right channel peak = %.3f, want audible bass bleed", peak)
+	}
+}
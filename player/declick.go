@@ -0,0 +1,56 @@
+package player
+
+import (
+	"math"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// seekCrossfade is the overlap used to hide the discontinuity at a seek.
+const seekCrossfade = 8 * time.Millisecond
+
+// primeMargin is how much of the track must be left to play for a seek to
+// prime the declick; closer to the end there is no tail worth crossfading.
+const primeMargin = 2 * seekCrossfade
+
+// declick sits directly above the gapless streamer and smooths over jumps in
+// the source signal. Before a seek, prime captures a few milliseconds of the
+// audio that would have played next; after the seek, the first samples of
+// the new position are crossfaded against that tail, so the waveform never
+// jumps abruptly and no click is heard.
+type declick struct {
+	s    beep.Streamer
+	tail [][2]float64 // pre-seek audio, allocated once
+	n    int          // valid samples in tail
+	pos  int          // crossfade progress into tail
+}
+
+func newDeclick(s beep.Streamer, sr beep.SampleRate) *declick {
+	return &declick{s: s, tail: make([][2]float64, max(1, sr.N(seekCrossfade)))}
+}
+
+// prime reads the upcoming tail from the current track's stream ahead of a
+// seek. Reading the track rather than the gapless streamer means a tail cut
+// short by the end of the track just crossfades over less, instead of
+// starting the next track. The caller must hold the speaker lock so the
+// audio thread isn't streaming concurrently.
+func (d *declick) prime(track beep.Streamer) {
+	n, _ := track.Stream(d.tail)
+	d.n = n
+	d.pos = 0
+}
+
+func (d *declick) Stream(samples [][2]float64) (int, bool) {
+	n, ok := d.s.Stream(samples)
+	for i := 0; i < n && d.pos < d.n; i++ {
+		// Raised-cosine ramp: new signal rises as the old tail falls.
+		r := 0.5 - 0.5*math.Cos(math.Pi*float64(d.pos)/float64(d.n))
+		samples[i][0] = samples[i][0]*r + d.tail[d.pos][0]*(1-r)
+		samples[i][1] = samples[i][1]*r + d.tail[d.pos][1]*(1-r)
+		d.pos++
+	}
+	return n, ok
+}
+
+func (d *declick) Err() error { return d.s.Err() }
@@ -0,0 +1,26 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/gopxl/beep/v2"
+)
+
+func TestDeclickPrimeStaysInTrack(t *testing.T) {
+	// A track with less left than the crossfade, and another queued after it.
+	cur := &pcm{data: [][2]float64{{0.5, 0.5}, {0.25, 0.25}}}
+	next := &pcm{data: make([][2]float64, 1000)}
+	g := &gaplessStreamer{current: cur, next: next}
+	d := newDeclick(g, beep.SampleRate(44100))
+
+	d.prime(cur)
+	if d.n != 2 {
+		t.Errorf("primed %d samples, want the 2 left in the track", d.n)
+	}
+	if !g.TakeNext(next) {
+		t.Error("priming started the next track")
+	}
+	if g.Drained() {
+		t.Error("priming drained the gapless streamer")
+	}
+}
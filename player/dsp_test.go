@@ -0,0 +1,78 @@
+package player
+
+import (
+	"slices"
+	"testing"
+)
+
+// gainEffect is a minimal third-party Effect used to exercise the chain API.
+type gainEffect struct {
+	name string
+	gain float64
+}
+
+func (g *gainEffect) Name() string { return g.name }
+
+func (g *gainEffect) Process(samples [][2]float64) {
+	for i := range samples {
+		samples[i][0] *= g.gain
+		samples[i][1] *= g.gain
+	}
+}
+
+func TestDSPChainEditing(t *testing.T) {
+	p := &Player{dsp: newDSPChain(nil, []Effect{
+		&gainEffect{name: EffectEQ, gain: 1},
+		&gainEffect{name: EffectVolume, gain: 1},
+		&gainEffect{name: EffectLimiter, gain: 1},
+	})}
+
+	p.InsertEffect("", &gainEffect{name: "custom", gain: 1})
+	if got, want := p.Effects(), []string{EffectEQ, EffectVolume, "custom", EffectLimiter}; !slices.Equal(got, want) {
+		t.Fatalf("after insert: %v, want %v", got, want)
+	}
+
+	p.InsertEffect(EffectEQ, &gainEffect{name: "custom", gain: 2})
+	if got, want := p.Effects(), []string{"custom", EffectEQ, EffectVolume, EffectLimiter}; !slices.Equal(got, want) {
+		t.Fatalf("after re-insert: %v, want %v", got, want)
+	}
+
+	p.SetEffectOrder([]string{EffectVolume, "missing", EffectEQ})
+	if got, want := p.Effects(), []string{EffectVolume, EffectEQ, "custom", EffectLimiter}; !slices.Equal(got, want) {
+		t.Fatalf("after reorder: %v, want %v", got, want)
+	}
+
+	if !p.RemoveEffect("custom") || p.RemoveEffect("custom") {
+		t.Fatal("RemoveEffect should succeed exactly once")
+	}
+	if got, want := p.Effects(), []string{EffectVolume, EffectEQ, EffectLimiter}; !slices.Equal(got, want) {
+		t.Fatalf("after remove: %v, want %v", got, want)
+	}
+}
+
+func TestDSPChainStreamsThroughNodes(t *testing.T) {
+	src := &fillStreamer{v: 0.25}
+	c := newDSPChain(src, []Effect{&gainEffect{name: "a", gain: 2}, &gainEffect{name: "b", gain: 3}})
+	buf := make([][2]float64, 8)
+	n, ok := c.Stream(buf)
+	if n != len(buf) || !ok {
+		t.Fatalf("Stream = (%d, %v)", n, ok)
+	}
+	for i := range buf {
+		if buf[i][0] != 1.5 || buf[i][1] != 1.5 {
+			t.Fatalf("sample %d = %v, want 1.5", i, buf[i])
+		}
+	}
+}
+
+// fillStreamer yields a constant value forever.
+type fillStreamer struct{ v float64 }
+
+func (f *fillStreamer) Stream(samples [][2]float64) (int, bool) {
+	for i := range samples {
+		samples[i] = [2]float64{f.v, f.v}
+	}
+	return len(samples), true
+}
+
+func (f *fillStreamer) Err() error { return nil }
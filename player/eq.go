@@ -3,47 +3,83 @@ package player
 import (
 	"math"
 	"sync/atomic"
+)
+
+// DefaultEQFreqs are the center frequencies the 10 EQ bands start at.
+var DefaultEQFreqs = [10]float64{70, 180, 320, 600, 1000, 3000, 6000, 12000, 14000, 16000}
+
+// DefaultEQQ is the quality factor every EQ band starts at.
+const DefaultEQQ = 1.4
 
-	"github.com/gopxl/beep/v2"
+// Limits for a band's center frequency in Hz and its quality factor.
+const (
+	MinEQFreq = 20.0
+	MaxEQFreq = 20000.0
+	MinEQQ    = 0.1
+	MaxEQQ    = 10.0
 )
 
-// eqFreqs are the center frequencies for the 10-band parametric equalizer.
-var eqFreqs = [10]float64{70, 180, 320, 600, 1000, 3000, 6000, 12000, 14000, 16000}
+// eqNode is the 10-band equalizer Effect: a cascade of peaking biquads.
+type eqNode struct {
+	bands [10]*biquad
+}
+
+func newEQNode(gains, freqs, qs *[10]atomic.Uint64, sr float64) *eqNode {
+	e := &eqNode{}
+	for i := range e.bands {
+		e.bands[i] = newBiquad(&freqs[i], &qs[i], &gains[i], sr)
+	}
+	return e
+}
+
+func (e *eqNode) Name() string { return EffectEQ }
+
+func (e *eqNode) Process(samples [][2]float64) {
+	for _, b := range e.bands {
+		b.process(samples)
+	}
+}
 
 // biquad implements a second-order IIR peaking equalizer per the Audio EQ Cookbook.
-// Each filter reads its gain from a shared pointer, so EQ changes take
-// effect on the next Stream() call without rebuilding the pipeline.
+// Each filter reads its center frequency, Q and gain from shared pointers,
+// so EQ changes take effect on the next block without rebuilding the
+// pipeline.
 type biquad struct {
-	s    beep.Streamer
-	freq float64
-	q    float64
+	freq *atomic.Uint64 // points to Player.eqFreqs[i], stores Float64bits
+	q    *atomic.Uint64 // points to Player.eqQs[i], stores Float64bits
 	gain *atomic.Uint64 // points to Player.eqBands[i], stores Float64bits
 	sr   float64
 	// Per-channel filter state
 	x1, x2 [2]float64
 	y1, y2 [2]float64
 	// Cached coefficients
-	lastGain           float64
-	b0, b1, b2, a1, a2 float64
-	inited             bool
+	lastFreq, lastQ, lastGain float64
+	b0, b1, b2, a1, a2        float64
+	inited                    bool
 }
 
-func newBiquad(s beep.Streamer, freq, q float64, gain *atomic.Uint64, sr float64) *biquad {
-	return &biquad{s: s, freq: freq, q: q, gain: gain, sr: sr}
+func newBiquad(freq, q, gain *atomic.Uint64, sr float64) *biquad {
+	return &biquad{freq: freq, q: q, gain: gain, sr: sr}
 }
 
-func (b *biquad) calcCoeffs(dB float64) {
-	if b.inited && dB == b.lastGain {
+// eqCenter keeps a band's center frequency below Nyquist, where the
+// peaking filter would otherwise fold over, at low output rates.
+func eqCenter(freq, sr float64) float64 {
+	return min(freq, sr*0.45)
+}
+
+func (b *biquad) calcCoeffs(freq, q, dB float64) {
+	if b.inited && freq == b.lastFreq && q == b.lastQ && dB == b.lastGain {
 		return
 	}
-	b.lastGain = dB
+	b.lastFreq, b.lastQ, b.lastGain = freq, q, dB
 	b.inited = true
 
 	a := math.Pow(10, dB/40)
-	w0 := 2 * math.Pi * b.freq / b.sr
+	w0 := 2 * math.Pi * eqCenter(freq, b.sr) / b.sr
 	sinW0 := math.Sin(w0)
 	cosW0 := math.Cos(w0)
-	alpha := sinW0 / (2 * b.q)
+	alpha := sinW0 / (2 * q)
 
 	b0 := 1 + alpha*a
 	b1 := -2 * cosW0
@@ -59,18 +95,17 @@ func (b *biquad) calcCoeffs(dB float64) {
 	b.a2 = a2 / a0
 }
 
-func (b *biquad) Stream(samples [][2]float64) (int, bool) {
-	n, ok := b.s.Stream(samples)
+func (b *biquad) process(samples [][2]float64) {
 	dB := math.Float64frombits(b.gain.Load())
 
 	// Skip processing when gain is effectively zero
 	if dB > -0.1 && dB < 0.1 {
-		return n, ok
+		return
 	}
 
-	b.calcCoeffs(dB)
+	b.calcCoeffs(math.Float64frombits(b.freq.Load()), math.Float64frombits(b.q.Load()), dB)
 
-	for i := range n {
+	for i := range samples {
 		for ch := range 2 {
 			x := samples[i][ch]
 			y := b.b0*x + b.b1*b.x1[ch] + b.b2*b.x2[ch] - b.a1*b.y1[ch] - b.a2*b.y2[ch]
@@ -81,7 +116,50 @@ func (b *biquad) Stream(samples [][2]float64) (int, bool) {
 			samples[i][ch] = y
 		}
 	}
-	return n, ok
 }
 
-func (b *biquad) Err() error { return b.s.Err() }
+// peakingResponse returns the magnitude response in dB of a single peaking
+// biquad (same coefficients as calcCoeffs) at frequency f.
+func peakingResponse(freq, q, dB, sr, f float64) float64 {
+	a := math.Pow(10, dB/40)
+	w0 := 2 * math.Pi * eqCenter(freq, sr) / sr
+	alpha := math.Sin(w0) / (2 * q)
+	cosW0 := math.Cos(w0)
+
+	b0, b1, b2 := 1+alpha*a, -2*cosW0, 1-alpha*a
+	a0, a1, a2 := 1+alpha/a, -2*cosW0, 1-alpha/a
+
+	// Evaluate H(e^jw) = (b0 + b1 z^-1 + b2 z^-2) / (a0 + a1 z^-1 + a2 z^-2).
+	w := 2 * math.Pi * f / sr
+	c1, s1 := math.Cos(w), math.Sin(w)
+	c2, s2 := math.Cos(2*w), math.Sin(2*w)
+	numRe, numIm := b0+b1*c1+b2*c2, -(b1*s1 + b2*s2)
+	denRe, denIm := a0+a1*c1+a2*c2, -(a1*s1 + a2*s2)
+	num := numRe*numRe + numIm*numIm
+	den := denRe*denRe + denIm*denIm
+	return 10 * math.Log10(num/den)
+}
+
+// eqPeakGain returns the highest boost in dB of the whole 10-band cascade
+// with the given gains, center frequencies and Qs,
+// sampled on a logarithmic grid across the audible range. Overlapping bands
+// add up, so this is usually larger than the biggest single band gain.
+// Returns 0 when the curve never rises above unity.
+func eqPeakGain(bands, freqs, qs [10]float64, sr float64) float64 {
+	const points = 96
+	lo, hi := 20.0, min(20000, sr*0.45)
+	peak := 0.0
+	for i := range points {
+		f := lo * math.Pow(hi/lo, float64(i)/(points-1))
+		sum := 0.0
+		for b, dB := range bands {
+			// Match biquad.process, which bypasses near-zero bands.
+			if dB > -0.1 && dB < 0.1 {
+				continue
+			}
+			sum += peakingResponse(freqs[b], qs[b], dB, sr, f)
+		}
+		peak = max(peak, sum)
+	}
+	return peak
+}
@@ -0,0 +1,99 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+// defaultQs is every band at DefaultEQQ.
+func defaultQs() [10]float64 {
+	var qs [10]float64
+	for i := range qs {
+		qs[i] = DefaultEQQ
+	}
+	return qs
+}
+
+func TestEQPeakGain(t *testing.T) {
+	const sr = 44100
+	freqs, qs := DefaultEQFreqs, defaultQs()
+
+	var flat [10]float64
+	if got := eqPeakGain(flat, freqs, qs, sr); got != 0 {
+		t.Fatalf("flat EQ peak = %.2f, want 0", got)
+	}
+
+	var cut [10]float64
+	for i := range cut {
+		cut[i] = -6
+	}
+	if got := eqPeakGain(cut, freqs, qs, sr); got != 0 {
+		t.Fatalf("all-cut EQ peak = %.2f, want 0", got)
+	}
+
+	var single [10]float64
+	single[4] = 6
+	if got := eqPeakGain(single, freqs, qs, sr); got < 5.5 || got > 6.01 {
+		t.Fatalf("single +6dB band peak = %.2f, want ~6", got)
+	}
+
+	// Adjacent boosted bands overlap, so the cascade peaks above any one band.
+	var adjacent [10]float64
+	adjacent[0], adjacent[1], adjacent[2] = 6, 6, 6
+	if got := eqPeakGain(adjacent, freqs, qs, sr); got <= 6 {
+		t.Fatalf("adjacent +6dB bands peak = %.2f, want > 6", got)
+	}
+}
+
+func TestEQBandShape(t *testing.T) {
+	const sr = 44100
+	var bands [10]float64
+	bands[4] = 6
+	freqs, qs := DefaultEQFreqs, defaultQs()
+
+	// The boost follows the band to its new center frequency.
+	freqs[4] = 2500
+	if got := peakingResponse(freqs[4], qs[4], 6, sr, 2500); math.Abs(got-6) > 0.01 {
+		t.Fatalf("response at the moved center = %.2f dB, want 6", got)
+	}
+	if got := peakingResponse(freqs[4], qs[4], 6, sr, 1000); got > 3 {
+		t.Fatalf("response at the old center = %.2f dB, want well under 6", got)
+	}
+
+	// A narrower band leaves a neighbouring frequency closer to unity.
+	wide := peakingResponse(freqs[4], 0.5, 6, sr, 1500)
+	narrow := peakingResponse(freqs[4], 8, 6, sr, 1500)
+	if narrow >= wide || narrow > 1 {
+		t.Fatalf("1.5 kHz response: Q 8 = %.2f dB, Q 0.5 = %.2f dB; want the narrow band lower, under 1 dB", narrow, wide)
+	}
+
+	// Two bands moved onto one frequency add up there.
+	freqs[5] = 2500
+	bands[5] = 6
+	if got := eqPeakGain(bands, freqs, qs, sr); got < 11.5 || got > 12.01 {
+		t.Fatalf("stacked bands peak = %.2f, want ~12", got)
+	}
+}
+
+func BenchmarkEQ(b *testing.B) {
+	var gains [10]atomic.Uint64
+	for i := range gains {
+		gains[i].Store(math.Float64bits(float64(i%5) - 2))
+	}
+	var freqs, qs [10]atomic.Uint64
+	for i := range freqs {
+		freqs[i].Store(math.Float64bits(DefaultEQFreqs[i]))
+		qs[i].Store(math.Float64bits(DefaultEQQ))
+	}
+	eq := newEQNode(&gains, &freqs, &qs, 44100)
+	block := make([][2]float64, 512)
+	for i := range block {
+		v := math.Sin(float64(i) / 5)
+		block[i] = [2]float64{v, v}
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		eq.Process(block)
+	}
+}
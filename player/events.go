@@ -0,0 +1,98 @@
+package player
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// EventKind says what an Event reports.
+type EventKind int
+
+const (
+	EventTrackStarted EventKind = iota // the first samples of a track were played
+	EventGapless                       // the track ended and the preloaded next one took over
+	EventDrained                       // the track ended with nothing queued after it
+	EventPaused
+	EventResumed
+	EventPosition // playback crossed a whole second
+)
+
+// Event is a change in playback state, sent on the Events channel.
+type Event struct {
+	Kind     EventKind
+	Track    uint64        // Serial of the track the event concerns
+	Position time.Duration // playback position of that track
+}
+
+// eventBuffer is how many events may wait for a reader. When it is full,
+// a new EventPosition is dropped, since the next second brings another;
+// any other event makes room by dropping queued EventPositions instead,
+// so the UI does not miss a track boundary unless a whole buffer of them
+// sits unread. Nothing blocks the audio thread.
+const eventBuffer = 64
+
+// pipelineSerial numbers pipelines as they are handed to the speaker.
+var pipelineSerial atomic.Uint64
+
+// Events returns the channel playback events are sent on. There is one
+// channel per Player, meant for a single reader.
+func (p *Player) Events() <-chan Event {
+	return p.events
+}
+
+// Serial returns the number of the current track, as carried by its
+// events, or 0 when nothing is loaded.
+func (p *Player) Serial() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil {
+		return 0
+	}
+	return p.current.serial
+}
+
+// emit sends e without blocking; it is called from the audio thread.
+func (p *Player) emit(e Event) {
+	p.emitMu.Lock()
+	defer p.emitMu.Unlock()
+	select {
+	case p.events <- e:
+		return
+	default:
+	}
+	if e.Kind == EventPosition {
+		return
+	}
+	// Take the queue out and put it back without its position updates. The
+	// reader may take events meanwhile, which only leaves more room.
+	var queued [eventBuffer]Event
+	n := 0
+	for n < len(queued) {
+		select {
+		case queued[n] = <-p.events:
+			n++
+			continue
+		default:
+		}
+		break
+	}
+	for _, q := range queued[:n] {
+		if q.Kind != EventPosition {
+			p.events <- q
+		}
+	}
+	select {
+	case p.events <- e:
+	default:
+	}
+}
+
+// emitFor sends an event about the track playing s, the positionTracker
+// the gapless streamer holds.
+func (p *Player) emitFor(kind EventKind, s any) {
+	pt, ok := s.(*positionTracker)
+	if !ok {
+		return
+	}
+	p.emit(Event{Kind: kind, Track: pt.tp.serial, Position: pt.tp.position()})
+}
@@ -0,0 +1,27 @@
+package player
+
+import "testing"
+
+func TestEmitKeepsBoundaryEventsWhenFull(t *testing.T) {
+	p := &Player{events: make(chan Event, eventBuffer)}
+	p.emit(Event{Kind: EventTrackStarted, Track: 1})
+	for range eventBuffer * 2 {
+		p.emit(Event{Kind: EventPosition, Track: 1})
+	}
+	p.emit(Event{Kind: EventGapless, Track: 2})
+	p.emit(Event{Kind: EventDrained, Track: 2})
+
+	var kinds []EventKind
+	for len(p.events) > 0 {
+		kinds = append(kinds, (<-p.events).Kind)
+	}
+	want := []EventKind{EventTrackStarted, EventGapless, EventDrained}
+	if len(kinds) != len(want) {
+		t.Fatalf("got events %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("got events %v, want %v", kinds, want)
+		}
+	}
+}
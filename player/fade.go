@@ -0,0 +1,128 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// defaultFade is the ramp length used for pause, resume and stop.
+const defaultFade = 200 * time.Millisecond
+
+// fader is the transport envelope that sits between the DSP chain and the
+// tap. It ramps the output towards a target gain (0 or 1) instead of cutting
+// it abruptly, which removes the click heard when pausing mid-waveform.
+//
+// The UI thread sets the target with fadeTo; the ramp itself runs on the
+// audio thread. When a fade-out reaches silence the optional onSilent
+// callback fires on the audio thread, with the speaker lock held by the
+// mixer — so it may touch state guarded by speaker.Lock (e.g. ctrl.Paused)
+// but must not take the lock itself.
+type fader struct {
+	s        beep.Streamer
+	sr       float64
+	duration atomic.Int64  // ramp length in nanoseconds; 0 disables fading
+	target   atomic.Uint64 // Float64bits of the target gain, 0 or 1
+	onSilent atomic.Pointer[func()]
+	slow     atomic.Int64 // length of the ramp in progress when it overrides duration
+	armed    atomic.Int64 // fade-in length for the next track start (FadeInNext)
+
+	gain float64 // current envelope value, audio thread only
+}
+
+func newFader(s beep.Streamer, sr float64) *fader {
+	f := &fader{s: s, sr: sr, gain: 1}
+	f.duration.Store(int64(defaultFade))
+	f.target.Store(math.Float64bits(1))
+	return f
+}
+
+// fadeTo starts a ramp towards target (0 or 1). onSilent, if non-nil, runs
+// once when a fade-out completes; it replaces any callback still pending.
+func (f *fader) fadeTo(target float64, onSilent func()) {
+	f.slow.Store(0)
+	if onSilent != nil {
+		f.onSilent.Store(&onSilent)
+	} else {
+		f.onSilent.Store(nil)
+	}
+	f.target.Store(math.Float64bits(target))
+}
+
+// snap jumps the envelope straight to gain with no ramp and drops any
+// pending callback. The caller must hold the speaker lock.
+func (f *fader) snap(gain float64) {
+	f.onSilent.Store(nil)
+	f.slow.Store(0)
+	f.target.Store(math.Float64bits(gain))
+	f.gain = gain
+}
+
+// start sets the envelope for a newly started track: full level, or
+// silence rising over the armed fade-in when one is pending. The caller must
+// hold the speaker lock once the speaker is running.
+func (f *fader) start() {
+	d := f.armed.Swap(0)
+	if d <= 0 {
+		f.snap(1)
+		return
+	}
+	f.snap(0)
+	f.slow.Store(d)
+	f.target.Store(math.Float64bits(1))
+}
+
+// enabled reports whether fades are configured.
+func (f *fader) enabled() bool {
+	return f.duration.Load() > 0
+}
+
+func (f *fader) Stream(samples [][2]float64) (int, bool) {
+	n, ok := f.s.Stream(samples)
+	target := math.Float64frombits(f.target.Load())
+
+	if f.gain == target {
+		if target == 0 {
+			clear(samples[:n])
+			f.fireSilent()
+		}
+		return n, ok
+	}
+
+	d := time.Duration(f.duration.Load())
+	if slow := f.slow.Load(); slow > 0 {
+		d = time.Duration(slow)
+	}
+	step := 1.0
+	if d > 0 {
+		step = 1 / (d.Seconds() * f.sr)
+	}
+	for i := range n {
+		if f.gain < target {
+			f.gain = min(target, f.gain+step)
+		} else {
+			f.gain = max(target, f.gain-step)
+		}
+		// Squared ramp: perceptually smoother than linear at low levels.
+		g := f.gain * f.gain
+		samples[i][0] *= g
+		samples[i][1] *= g
+	}
+	if f.gain == target {
+		f.slow.Store(0)
+	}
+	if f.gain == 0 {
+		f.fireSilent()
+	}
+	return n, ok
+}
+
+func (f *fader) fireSilent() {
+	if cb := f.onSilent.Swap(nil); cb != nil {
+		(*cb)()
+	}
+}
+
+func (f *fader) Err() error { return f.s.Err() }
@@ -0,0 +1,36 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFaderArmedFadeIn(t *testing.T) {
+	f := newFader(&fillStreamer{v: 1}, 1000)
+	f.armed.Store(int64(time.Second))
+	f.start()
+
+	buf := make([][2]float64, 500)
+	f.Stream(buf)
+	if buf[0][0] > 0.01 {
+		t.Fatalf("first sample = %v, want near silence", buf[0][0])
+	}
+	if g := buf[499][0]; g < 0.2 || g > 0.3 {
+		t.Fatalf("gain half way = %v, want about 0.25 (squared ramp)", g)
+	}
+
+	f.Stream(buf)
+	if buf[499][0] != 1 {
+		t.Fatalf("gain after ramp = %v, want 1", buf[499][0])
+	}
+	if f.slow.Load() != 0 {
+		t.Fatal("slow ramp should clear once full level is reached")
+	}
+
+	// Without an armed fade-in a track starts at full level.
+	f.start()
+	f.Stream(buf)
+	if buf[0][0] != 1 {
+		t.Fatalf("unarmed start = %v, want 1", buf[0][0])
+	}
+}
@@ -15,10 +15,11 @@ import (
 // When no audio is available, it fills silence.
 type gaplessStreamer struct {
 	mu      sync.Mutex
-	current beep.Streamer // active track (decoded + resampled)
-	next    beep.Streamer // preloaded next track
-	drained atomic.Bool   // true when current exhausts with no next
-	onSwap  func()        // called (in goroutine) on gapless transition
+	current beep.Streamer       // active track (decoded + resampled)
+	next    beep.Streamer       // preloaded next track
+	drained atomic.Bool         // true when current exhausts with no next
+	onSwap  func()              // called (in goroutine) on gapless transition
+	onDrain func(beep.Streamer) // called on the audio thread with the track that drained
 }
 
 // Stream reads samples from the current track. On exhaustion, it seamlessly
@@ -60,6 +61,9 @@ func (g *gaplessStreamer) Stream(samples [][2]float64) (int, bool) {
 		} else {
 			// No next track — we've drained
 			g.drained.Store(true)
+			if g.onDrain != nil {
+				g.onDrain(cur)
+			}
 		}
 	}
 
@@ -80,6 +84,18 @@ func (g *gaplessStreamer) SetNext(s beep.Streamer) {
 	g.next = s
 }
 
+// TakeNext unqueues s if it is still the preloaded next track and reports
+// whether it was. It fails once a transition has already started playing s.
+func (g *gaplessStreamer) TakeNext(s beep.Streamer) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s == nil || g.next != s {
+		return false
+	}
+	g.next = nil
+	return true
+}
+
 // Replace interrupts the current track and starts a new one immediately.
 // Used for manual skip/prev/select operations.
 func (g *gaplessStreamer) Replace(s beep.Streamer) {
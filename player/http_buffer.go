@@ -0,0 +1,337 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// errNoRanges is returned by newHTTPBuffer when the server cannot serve
+// byte ranges, so the caller should fall back to plain streaming.
+var errNoRanges = errors.New("http buffer: server does not support range requests")
+
+// maxHTTPBufferSize caps the in-memory copy of a buffered HTTP file, which
+// is allocated in full up front and held twice while the next track is
+// preloaded. Anything larger is streamed instead.
+const maxHTTPBufferSize = 256 << 20
+
+// httpReadAhead is how far ahead of the active download a read may land
+// before the download is restarted at the read position with a new range
+// request instead of waiting for the bytes to arrive in order.
+const httpReadAhead = 512 * 1024
+
+// bufferedExts are the formats played through an httpBuffer. Their native
+// decoders seek through an io.ReadSeeker without reading the whole file
+// first; MP3 is left out because go-mp3 scans every frame on open.
+var bufferedExts = map[string]bool{
+	".flac": true,
+	".wav":  true,
+	".ogg":  true,
+}
+
+// span is a half-open byte range [start, end) that has been downloaded.
+type span struct{ start, end int64 }
+
+// httpBuffer is an io.ReadSeekCloser over a remote file that is downloaded
+// in the background and kept in memory. Unlike navBuffer it is sparse: a
+// read far past the download position restarts the download there with a
+// Range request, so seeking to the end of a long track is immediate. Once
+// a download runs into bytes it already has, it moves on to the next gap
+// until the whole file is held.
+//
+// Lock ordering: httpBuffer.mu is a leaf lock, like navBuffer.mu.
+type httpBuffer struct {
+	url       string
+	mu        sync.Mutex
+	cond      *sync.Cond
+	data      []byte // len(data) == total; only bytes inside have are valid
+	have      []span // downloaded ranges, sorted and non-overlapping
+	total     int64
+	pos       int64 // read cursor
+	fetchAt   int64 // next byte the active download will write; -1 when idle
+	gen       int   // bumped on every restart so stale downloads stop
+	ctx       context.Context
+	cancel    context.CancelFunc // ends every download when the buffer closes
+	stopFetch context.CancelFunc // aborts the active download's request
+	closed    bool
+	err       error        // last download error; cleared by a new request
+	errAt     int64        // offset the failed download stopped at
+	bytesIn   atomic.Int64 // total bytes received; safe for unsynchronised UI reads
+}
+
+// newHTTPBuffer opens rawURL with a ranged request and starts downloading
+// it in the background. It returns errNoRanges when the server answers
+// without a 206 and a total size, or the file exceeds maxHTTPBufferSize.
+func newHTTPBuffer(rawURL string) (*httpBuffer, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fetchCtx, stopFetch := context.WithCancel(ctx)
+	abort := func() { stopFetch(); cancel() }
+	resp, err := rangeGet(fetchCtx, rawURL, 0)
+	if err != nil {
+		abort()
+		return nil, err
+	}
+	total := contentRangeTotal(resp.Header.Get("Content-Range"))
+	if resp.StatusCode != http.StatusPartialContent || total <= 0 || total > maxHTTPBufferSize {
+		resp.Body.Close()
+		abort()
+		return nil, errNoRanges
+	}
+
+	b := &httpBuffer{
+		url:       rawURL,
+		data:      make([]byte, total),
+		total:     total,
+		ctx:       ctx,
+		cancel:    cancel,
+		stopFetch: stopFetch,
+	}
+	b.cond = sync.NewCond(&b.mu)
+	go b.download(resp.Body, 0, 0)
+	return b, nil
+}
+
+// rangeGet requests rawURL from byte offset onwards.
+func rangeGet(ctx context.Context, rawURL string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http buffer request: %w", err)
+	}
+	req.Header.Set("User-Agent", "cliamp/1.0 (https://github.com/bjarneo/cliamp)")
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http buffer connect: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("http buffer: http status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// contentRangeTotal returns the complete length from a Content-Range header
+// such as "bytes 0-999/1000", or -1 when it is absent or unknown ("*").
+func contentRangeTotal(h string) int64 {
+	i := strings.LastIndexByte(h, '/')
+	if !strings.HasPrefix(h, "bytes ") || i < 0 {
+		return -1
+	}
+	n, err := strconv.ParseInt(h[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// download copies body into data starting at off. It stops when gen is
+// superseded, on error, or when it reaches bytes that are already held,
+// and then moves on to the next gap.
+func (b *httpBuffer) download(body io.ReadCloser, off int64, gen int) {
+	defer body.Close()
+
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(chunk)
+		b.mu.Lock()
+		if b.gen != gen || b.closed {
+			b.mu.Unlock()
+			return
+		}
+		if n > 0 {
+			// Never write past EOF or over bytes a Read may be copying.
+			end, _ := b.gapAt(off)
+			n = int(min(int64(n), end-off))
+			copy(b.data[off:], chunk[:n])
+			b.addSpan(off, off+int64(n))
+			off += int64(n)
+			b.fetchAt = off
+			b.bytesIn.Add(int64(n))
+		}
+		_, open := b.gapAt(off)
+		if err != nil && open {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF // server ended the body early
+			}
+			b.err, b.errAt = err, off
+			b.fetchAt = -1
+			b.mu.Unlock()
+			b.cond.Broadcast()
+			return
+		}
+		if err != nil || !open {
+			b.fetchAt = -1
+			b.fillNextGap()
+			b.mu.Unlock()
+			b.cond.Broadcast()
+			return
+		}
+		b.mu.Unlock()
+		b.cond.Broadcast()
+	}
+}
+
+// gapAt reports whether off lies in a missing range, and if so where the
+// gap ends (the start of the next held span, or total).
+func (b *httpBuffer) gapAt(off int64) (end int64, open bool) {
+	if off >= b.total {
+		return b.total, false
+	}
+	for _, s := range b.have {
+		if off >= s.start && off < s.end {
+			return s.end, false
+		}
+		if s.start > off {
+			return s.start, true
+		}
+	}
+	return b.total, true
+}
+
+// addSpan records [start, end) as held, merging neighbouring spans.
+func (b *httpBuffer) addSpan(start, end int64) {
+	if start >= end {
+		return
+	}
+	out := b.have[:0:0]
+	for _, s := range b.have {
+		if s.end < start || s.start > end {
+			out = append(out, s)
+			continue
+		}
+		start, end = min(start, s.start), max(end, s.end)
+	}
+	i := 0
+	for i < len(out) && out[i].start < start {
+		i++
+	}
+	out = append(out, span{})
+	copy(out[i+1:], out[i:])
+	out[i] = span{start, end}
+	b.have = out
+}
+
+// fillNextGap starts a download at the first missing byte at or after the
+// read cursor, wrapping to the start of the file. Caller holds mu.
+func (b *httpBuffer) fillNextGap() {
+	for _, off := range []int64{b.pos, 0} {
+		for off < b.total {
+			end, open := b.gapAt(off)
+			if open {
+				b.fetch(off)
+				return
+			}
+			off = end
+		}
+	}
+}
+
+// fetch restarts the background download at off, abandoning the one in
+// flight. Caller holds mu.
+func (b *httpBuffer) fetch(off int64) {
+	b.stopFetch()
+	b.gen++
+	gen := b.gen
+	b.fetchAt = off
+	b.err = nil
+	ctx, cancel := context.WithCancel(b.ctx)
+	b.stopFetch = cancel
+	go func() {
+		resp, err := rangeGet(ctx, b.url, off)
+		if err == nil && resp.StatusCode != http.StatusPartialContent {
+			resp.Body.Close()
+			err = errNoRanges
+		}
+		if err != nil {
+			b.mu.Lock()
+			if b.gen == gen {
+				b.err, b.errAt = err, off
+				b.fetchAt = -1
+			}
+			b.mu.Unlock()
+			b.cond.Broadcast()
+			return
+		}
+		b.download(resp.Body, off, gen)
+	}()
+}
+
+// Read implements io.Reader. It blocks until the byte at the cursor has
+// been downloaded, restarting the download there if it is too far off.
+func (b *httpBuffer) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		if b.closed {
+			return 0, fmt.Errorf("http buffer: closed")
+		}
+		if b.pos >= b.total {
+			return 0, io.EOF
+		}
+		if end, open := b.gapAt(b.pos); !open {
+			n := copy(p, b.data[b.pos:end])
+			b.pos += int64(n)
+			return n, nil
+		}
+		if b.err != nil && (b.errAt > b.pos || b.pos-b.errAt > httpReadAhead) {
+			// The failed download was not heading for the cursor, so a
+			// seek has moved on from it: try again from here.
+			b.fetch(b.pos)
+		}
+		if b.err != nil {
+			return 0, b.err
+		}
+		if b.fetchAt < 0 || b.fetchAt > b.pos || b.pos-b.fetchAt > httpReadAhead {
+			b.fetch(b.pos)
+		}
+		b.cond.Wait()
+	}
+}
+
+// Seek implements io.Seeker. It only moves the cursor; the next Read
+// fetches the target if it has not been downloaded yet.
+func (b *httpBuffer) Seek(offset int64, whence int) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = b.pos + offset
+	case io.SeekEnd:
+		target = b.total + offset
+	default:
+		return 0, fmt.Errorf("http buffer: invalid whence %d", whence)
+	}
+	b.pos = max(0, min(target, b.total))
+	return b.pos, nil
+}
+
+// Close stops the download and unblocks all waiters.
+func (b *httpBuffer) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	cancel := b.cancel
+	b.mu.Unlock()
+	cancel()
+	b.cond.Broadcast()
+	return nil
+}
+
+// Buffered returns the downloaded ranges as fractions of the file.
+func (b *httpBuffer) Buffered() [][2]float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([][2]float64, len(b.have))
+	for i, s := range b.have {
+		out[i] = [2]float64{float64(s.start) / float64(b.total), float64(s.end) / float64(b.total)}
+	}
+	return out
+}
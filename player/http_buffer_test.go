@@ -0,0 +1,125 @@
+package player
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPBufferReadAndSeek(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 128*1024) // 2 MiB
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "track.flac", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	b, err := newHTTPBuffer(srv.URL + "/track.flac")
+	if err != nil {
+		t.Fatalf("newHTTPBuffer: %v", err)
+	}
+	defer b.Close()
+
+	// A jump near the end is served by a fresh range request.
+	off := int64(len(content) - 100)
+	if _, err := b.Seek(off, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	tail, err := io.ReadAll(b)
+	if err != nil {
+		t.Fatalf("read tail: %v", err)
+	}
+	if !bytes.Equal(tail, content[off:]) {
+		t.Fatalf("tail mismatch: got %d bytes", len(tail))
+	}
+
+	if _, err := b.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	all, err := io.ReadAll(b)
+	if err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+	if !bytes.Equal(all, content) {
+		t.Fatalf("content mismatch: got %d bytes, want %d", len(all), len(content))
+	}
+	if got := b.Buffered(); len(got) != 1 || got[0] != [2]float64{0, 1} {
+		t.Fatalf("Buffered = %v, want [[0 1]]", got)
+	}
+}
+
+func TestHTTPBufferRetriesAfterSeek(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 128*1024) // 2 MiB
+	tail := int64(len(content) - 100)
+	var failed atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Range") {
+		case "bytes=0-":
+			// Send a little and stall, so the rest stays a gap.
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(content[:64*1024])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		case fmt.Sprintf("bytes=%d-", tail):
+			if !failed.Swap(true) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		http.ServeContent(w, r, "track.flac", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	b, err := newHTTPBuffer(srv.URL + "/track.flac")
+	if err != nil {
+		t.Fatalf("newHTTPBuffer: %v", err)
+	}
+	defer b.Close()
+
+	b.Seek(tail, io.SeekStart)
+	if _, err := b.Read(make([]byte, 10)); err == nil {
+		t.Fatal("read at the failing offset succeeded, want its error")
+	}
+
+	// A seek elsewhere must not keep returning the old error.
+	off := int64(len(content) / 2)
+	b.Seek(off, io.SeekStart)
+	rest, err := io.ReadAll(b)
+	if err != nil {
+		t.Fatalf("read after seek: %v", err)
+	}
+	if !bytes.Equal(rest, content[off:]) {
+		t.Fatalf("content mismatch: got %d bytes, want %d", len(rest), len(content)-int(off))
+	}
+}
+
+func TestHTTPBufferNoRanges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, strings.NewReader("not ranged"))
+	}))
+	defer srv.Close()
+
+	if _, err := newHTTPBuffer(srv.URL + "/track.flac"); !errors.Is(err, errNoRanges) {
+		t.Fatalf("newHTTPBuffer err = %v, want errNoRanges", err)
+	}
+}
+
+func TestContentRangeTotal(t *testing.T) {
+	for h, want := range map[string]int64{
+		"bytes 0-999/1000": 1000,
+		"bytes 0-999/*":    -1,
+		"":                 -1,
+	} {
+		if got := contentRangeTotal(h); got != want {
+			t.Errorf("contentRangeTotal(%q) = %d, want %d", h, got, want)
+		}
+	}
+}
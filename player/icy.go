@@ -3,6 +3,7 @@ package player
 import (
 	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 // Compile-time interface check.
@@ -79,6 +80,9 @@ func (ir *icyReader) consumeMeta() error {
 
 // parseStreamTitle extracts the StreamTitle value from ICY metadata.
 // Format: "StreamTitle='Artist - Title';StreamUrl='...';..."
+//
+// The protocol predates UTF-8 and many stations still send Latin-1, so
+// titles that are not valid UTF-8 are decoded as ISO-8859-1.
 func parseStreamTitle(meta string) string {
 	const prefix = "StreamTitle='"
 	i := strings.Index(meta, prefix)
@@ -94,5 +98,19 @@ func parseStreamTitle(meta string) string {
 			return ""
 		}
 	}
-	return rest[:j]
+	title := rest[:j]
+	if !utf8.ValidString(title) {
+		title = latin1ToUTF8(title)
+	}
+	return strings.TrimSpace(title)
+}
+
+// latin1ToUTF8 maps each ISO-8859-1 byte to the code point of the same value.
+func latin1ToUTF8(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+	for i := 0; i < len(s); i++ {
+		b.WriteRune(rune(s[i]))
+	}
+	return b.String()
 }
@@ -0,0 +1,55 @@
+package player
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestParseStreamTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		meta string
+		want string
+	}{
+		{"artist and title", "StreamTitle='Band - Song';StreamUrl='';", "Band - Song"},
+		{"missing semicolon", "StreamTitle='Band - Song'", "Band - Song"},
+		{"apostrophe in title", "StreamTitle='Band - Don't Stop';", "Band - Don't Stop"},
+		{"padding trimmed", "StreamTitle=' Band - Song  ';", "Band - Song"},
+		{"utf-8 kept", "StreamTitle='Sigur Rós - Hoppípolla';", "Sigur Rós - Hoppípolla"},
+		{"latin-1 decoded", "StreamTitle='Sigur R\xf3s - Hopp\xedpolla';", "Sigur Rós - Hoppípolla"},
+		{"no title", "StreamUrl='http://x';", ""},
+	}
+	for _, tt := range tests {
+		if got := parseStreamTitle(tt.meta); got != tt.want {
+			t.Errorf("%s: parseStreamTitle() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIcyReader_StripsMetadata(t *testing.T) {
+	meta := []byte("StreamTitle='A - B';")
+	block := make([]byte, 32) // length byte 2 → 32 bytes
+	copy(block, meta)
+
+	var stream bytes.Buffer
+	stream.WriteString("abcd")
+	stream.WriteByte(2)
+	stream.Write(block)
+	stream.WriteString("efgh")
+	stream.WriteByte(0) // empty metadata block
+	stream.WriteString("ij")
+
+	var titles []string
+	r := newIcyReader(io.NopCloser(&stream), 4, func(s string) { titles = append(titles, s) })
+	audio, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(audio) != "abcdefghij" {
+		t.Errorf("audio = %q, want %q", audio, "abcdefghij")
+	}
+	if len(titles) != 1 || titles[0] != "A - B" {
+		t.Errorf("titles = %q, want [A - B]", titles)
+	}
+}
@@ -0,0 +1,83 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Karaoke band: the part of the centre that is cancelled. Lead vocals sit
+// in it; the kick and bass below it, usually panned centre too, are kept,
+// as is the air of cymbals above it.
+const (
+	karaokeLowHz  = 150.0
+	karaokeHighHz = 7000.0
+)
+
+// karaokeNode reduces lead vocals by cancelling what the two channels have
+// in common, as vocals are mixed to the centre: the mid (L+R) signal is
+// band-limited to the vocal range and subtracted from both channels, which
+// leaves the side (L−R) signal and the centred bass. It is bypassed when
+// enabled is false.
+type karaokeNode struct {
+	enabled *atomic.Bool
+	hp, lp  passFilter
+}
+
+func newKaraoke(enabled *atomic.Bool, sr float64) *karaokeNode {
+	return &karaokeNode{
+		enabled: enabled,
+		hp:      newPassFilter(karaokeLowHz, sr, true),
+		lp:      newPassFilter(karaokeHighHz, sr, false),
+	}
+}
+
+func (k *karaokeNode) Name() string { return EffectKaraoke }
+
+func (k *karaokeNode) Process(samples [][2]float64) {
+	if !k.enabled.Load() {
+		k.hp.reset()
+		k.lp.reset()
+		return
+	}
+	for i := range samples {
+		mid := (samples[i][0] + samples[i][1]) / 2
+		vocal := k.lp.process(k.hp.process(mid))
+		samples[i][0] -= vocal
+		samples[i][1] -= vocal
+	}
+}
+
+// passFilter is a mono second-order Butterworth high- or low-pass filter,
+// per the Audio EQ Cookbook.
+type passFilter struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func newPassFilter(freq, sr float64, high bool) passFilter {
+	w0 := 2 * math.Pi * freq / sr
+	cosW0 := math.Cos(w0)
+	alpha := math.Sin(w0) / math.Sqrt2 // sin(w0) / 2Q, for Q = 1/√2
+	a0 := 1 + alpha
+	f := passFilter{a1: -2 * cosW0 / a0, a2: (1 - alpha) / a0}
+	if high {
+		f.b0 = (1 + cosW0) / 2 / a0
+		f.b1 = -(1 + cosW0) / a0
+	} else {
+		f.b0 = (1 - cosW0) / 2 / a0
+		f.b1 = (1 - cosW0) / a0
+	}
+	f.b2 = f.b0
+	return f
+}
+
+func (f *passFilter) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+func (f *passFilter) reset() {
+	f.x1, f.x2, f.y1, f.y2 = 0, 0, 0, 0
+}
@@ -0,0 +1,42 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+// karaokeRMS runs a second of tone through the karaoke filter and returns
+// the left channel's RMS over the last half, relative to the input's, in dB.
+func karaokeRMS(freq, left, right float64) float64 {
+	const sr = 44100
+	var on atomic.Bool
+	on.Store(true)
+	k := newKaraoke(&on, sr)
+
+	buf := make([][2]float64, sr)
+	for i := range buf {
+		v := math.Sin(2 * math.Pi * freq * float64(i) / sr)
+		buf[i] = [2]float64{left * v, right * v}
+	}
+	k.Process(buf)
+	var in, out float64
+	for i := sr / 2; i < sr; i++ {
+		v := left * math.Sin(2*math.Pi*freq*float64(i)/sr)
+		in += v * v
+		out += buf[i][0] * buf[i][0]
+	}
+	return 10 * math.Log10(out/in)
+}
+
+func TestKaraokeCancelsCentredVocals(t *testing.T) {
+	if got := karaokeRMS(1000, 0.5, 0.5); got > -15 {
+		t.Errorf("centred 1 kHz tone at %.1f dB, want it cancelled", got)
+	}
+	if got := karaokeRMS(1000, 0.5, 0); got < -7 {
+		t.Errorf("hard-panned 1 kHz tone at %.1f dB, want most of it kept", got)
+	}
+	if got := karaokeRMS(50, 0.5, 0.5); got < -3 {
+		t.Errorf("centred 50 Hz bass at %.1f dB, want it kept", got)
+	}
+}
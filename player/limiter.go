@@ -0,0 +1,84 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Limiter parameters. The ceiling sits just below full scale so inter-sample
+// peaks after the DAC's reconstruction filter stay clean; the knee spreads
+// the onset of gain reduction over 6 dB so quiet overshoots are barely touched.
+const (
+	limiterCeilingDB = -0.3
+	limiterKneeDB    = 6.0
+	limiterReleaseMs = 80.0
+)
+
+// limiter is a soft-knee peak limiter placed at the very end of the DSP chain.
+// Gain reduction attacks instantly (so no sample leaves above the ceiling)
+// and releases exponentially. It is bypassed when enabled is false.
+type limiter struct {
+	enabled *atomic.Bool
+	active  *atomic.Int64 // unix nanos of the last block that needed gain reduction
+
+	env     float64 // current linear gain, 1 = no reduction
+	release float64 // per-sample release coefficient
+	kneeLo  float64 // linear level where the knee starts
+}
+
+func newLimiter(enabled *atomic.Bool, active *atomic.Int64, sr float64) *limiter {
+	return &limiter{
+		enabled: enabled,
+		active:  active,
+		env:     1,
+		release: math.Exp(-1000 / (limiterReleaseMs * sr)),
+		kneeLo:  math.Pow(10, (limiterCeilingDB-limiterKneeDB/2)/20),
+	}
+}
+
+// limiterGainDB returns the static gain reduction (<= 0) for a peak level in
+// dBFS, using a quadratic soft knee centered on the ceiling.
+func limiterGainDB(levelDB float64) float64 {
+	over := levelDB - limiterCeilingDB
+	switch {
+	case over <= -limiterKneeDB/2:
+		return 0
+	case over >= limiterKneeDB/2:
+		return -over
+	default:
+		k := over + limiterKneeDB/2
+		return -k * k / (2 * limiterKneeDB)
+	}
+}
+
+func (l *limiter) Name() string { return EffectLimiter }
+
+func (l *limiter) Process(samples [][2]float64) {
+	if !l.enabled.Load() {
+		l.env = 1
+		return
+	}
+
+	reduced := false
+	for i := range samples {
+		peak := max(math.Abs(samples[i][0]), math.Abs(samples[i][1]))
+		target := 1.0
+		if peak > l.kneeLo {
+			target = math.Pow(10, limiterGainDB(20*math.Log10(peak))/20)
+		}
+		if target < l.env {
+			l.env = target
+		} else {
+			l.env = target + (l.env-target)*l.release
+		}
+		if l.env < 0.999 {
+			samples[i][0] *= l.env
+			samples[i][1] *= l.env
+			reduced = true
+		}
+	}
+	if reduced {
+		l.active.Store(time.Now().UnixNano())
+	}
+}
@@ -0,0 +1,73 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func testLimiter(sr float64) *limiter {
+	var on atomic.Bool
+	on.Store(true)
+	var hit atomic.Int64
+	return newLimiter(&on, &hit, sr)
+}
+
+func TestLimiterHoldsCeiling(t *testing.T) {
+	l := testLimiter(44100)
+	ceiling := math.Pow(10, limiterCeilingDB/20)
+	buf := make([][2]float64, 4410)
+	for _, amp := range []float64{0.9, 1, 1.5, 4} {
+		for i := range buf {
+			v := amp * math.Sin(2*math.Pi*440*float64(i)/44100)
+			buf[i] = [2]float64{v, -v}
+		}
+		l.Process(buf)
+		for i, s := range buf {
+			if math.Abs(s[0]) > ceiling+1e-12 || math.Abs(s[1]) > ceiling+1e-12 {
+				t.Fatalf("amp %.1f: sample %d = %v, above the %.4f ceiling", amp, i, s, ceiling)
+			}
+		}
+	}
+}
+
+func TestLimiterPassesQuietSignal(t *testing.T) {
+	l := testLimiter(44100)
+	buf := make([][2]float64, 4410)
+	want := make([][2]float64, len(buf))
+	amp := l.kneeLo * 0.99
+	for i := range buf {
+		v := amp * math.Sin(2*math.Pi*440*float64(i)/44100)
+		buf[i] = [2]float64{v, v / 2}
+	}
+	copy(want, buf)
+	l.Process(buf)
+	for i := range buf {
+		if buf[i] != want[i] {
+			t.Fatalf("sample %d = %v, want it unchanged at %v", i, buf[i], want[i])
+		}
+	}
+}
+
+func TestLimiterRelease(t *testing.T) {
+	const sr = 48000
+	l := testLimiter(sr)
+
+	// One peak 12 dB over full scale, then a steady level under the knee:
+	// the gain reduction should fall to 1/e of its start in limiterReleaseMs.
+	buf := [][2]float64{{4, 4}}
+	l.Process(buf)
+	start := 1 - buf[0][0]/4
+
+	n := int(limiterReleaseMs * sr / 1000)
+	quiet := make([][2]float64, n)
+	for i := range quiet {
+		quiet[i] = [2]float64{0.1, 0.1}
+	}
+	l.Process(quiet)
+	got := 1 - quiet[n-1][0]/0.1
+	want := start / math.E
+	if math.Abs(got-want) > 0.01*start {
+		t.Errorf("gain reduction after %.0f ms = %.4f, want about %.4f", limiterReleaseMs, got, want)
+	}
+}
@@ -0,0 +1,255 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// mp3HeadWindow is how many bytes from the first MPEG frame are kept for
+// header parsing. A Xing frame with a TOC fits in well under 1 KiB; VBRI
+// tables are a few hundred bytes more.
+const mp3HeadWindow = 4096
+
+// mp3VBR is the Xing/Info or VBRI header found in the first frame of many
+// MP3 files. It gives the exact frame count, and for VBR files a table of
+// contents that maps playback time to byte offset. Without it a stream's
+// duration and seek offsets are guesses that drift with the bitrate.
+type mp3VBR struct {
+	frames          int64 // audio frames, excluding the header frame
+	bytes           int64 // audio bytes from dataStart; 0 if not given
+	sampleRate      int
+	samplesPerFrame int
+	dataStart       int64 // file offset of the first frame (after ID3v2)
+	cbr             bool  // an "Info" tag, which LAME writes for constant bitrate files
+
+	toc []byte // Xing: 100 entries, each the byte position of a percent / 256
+
+	vbriTable          []int64 // VBRI: byte size of each run of framesPerEntry frames
+	vbriFramesPerEntry int64
+}
+
+// mp3SampleRates is indexed by MPEG version bits and sample rate index.
+var mp3SampleRates = map[byte][3]int{
+	3: {44100, 48000, 32000}, // MPEG-1
+	2: {22050, 24000, 16000}, // MPEG-2
+	0: {11025, 12000, 8000},  // MPEG-2.5
+}
+
+// parseMP3VBR reads the VBR header from frame, the bytes starting at the
+// first Layer III frame. It returns nil when there is no usable header.
+func parseMP3VBR(frame []byte, dataStart int64) *mp3VBR {
+	if len(frame) < 4 || frame[0] != 0xFF || frame[1]&0xE0 != 0xE0 {
+		return nil
+	}
+	version := frame[1] >> 3 & 3
+	layer := frame[1] >> 1 & 3
+	srIdx := frame[2] >> 2 & 3
+	rates, ok := mp3SampleRates[version]
+	if !ok || layer != 1 || srIdx == 3 {
+		return nil
+	}
+	mono := frame[3]>>6 == 3
+
+	v := &mp3VBR{sampleRate: rates[srIdx], samplesPerFrame: 1152, dataStart: dataStart}
+	sideInfo := 32
+	switch {
+	case version == 3 && mono:
+		sideInfo = 17
+	case version != 3:
+		v.samplesPerFrame = 576
+		sideInfo = 17
+		if mono {
+			sideInfo = 9
+		}
+	}
+
+	if x := frame[min(len(frame), 4+sideInfo):]; bytes.HasPrefix(x, []byte("Xing")) || bytes.HasPrefix(x, []byte("Info")) {
+		v.cbr = bytes.HasPrefix(x, []byte("Info"))
+		return v.parseXing(x[4:])
+	}
+	if x := frame[min(len(frame), 36):]; bytes.HasPrefix(x, []byte("VBRI")) {
+		return v.parseVBRI(x[4:])
+	}
+	return nil
+}
+
+// parseXing fills v from the fields after a "Xing" or "Info" tag.
+func (v *mp3VBR) parseXing(b []byte) *mp3VBR {
+	if len(b) < 4 {
+		return nil
+	}
+	flags := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	if flags&1 != 0 {
+		if len(b) < 4 {
+			return nil
+		}
+		v.frames = int64(binary.BigEndian.Uint32(b))
+		b = b[4:]
+	}
+	if flags&2 != 0 {
+		if len(b) < 4 {
+			return nil
+		}
+		v.bytes = int64(binary.BigEndian.Uint32(b))
+		b = b[4:]
+	}
+	if flags&4 != 0 && len(b) >= 100 {
+		v.toc = b[:100:100]
+	}
+	if v.frames == 0 {
+		return nil
+	}
+	return v
+}
+
+// parseVBRI fills v from the fields after a "VBRI" tag.
+func (v *mp3VBR) parseVBRI(b []byte) *mp3VBR {
+	// version, delay, quality, bytes, frames, entries, scale, entry size,
+	// frames per entry.
+	if len(b) < 22 {
+		return nil
+	}
+	v.bytes = int64(binary.BigEndian.Uint32(b[6:]))
+	v.frames = int64(binary.BigEndian.Uint32(b[10:]))
+	entries := int(binary.BigEndian.Uint16(b[14:]))
+	scale := int64(binary.BigEndian.Uint16(b[16:]))
+	size := int(binary.BigEndian.Uint16(b[18:]))
+	v.vbriFramesPerEntry = int64(binary.BigEndian.Uint16(b[20:]))
+	if v.frames == 0 {
+		return nil
+	}
+	b = b[22:]
+	if size < 1 || size > 4 || len(b) < entries*size || v.vbriFramesPerEntry == 0 {
+		return v // duration only
+	}
+	v.vbriTable = make([]int64, entries)
+	for i := range v.vbriTable {
+		var n int64
+		for _, c := range b[i*size : (i+1)*size] {
+			n = n<<8 | int64(c)
+		}
+		v.vbriTable[i] = n * scale
+	}
+	return v
+}
+
+// duration returns the exact playing time from the frame count, or 0 when
+// v is nil.
+func (v *mp3VBR) duration() time.Duration {
+	if v == nil || v.sampleRate == 0 {
+		return 0
+	}
+	return time.Duration(v.frames * int64(v.samplesPerFrame) * int64(time.Second) / int64(v.sampleRate))
+}
+
+// offsetAt returns the file byte offset at which playback time t starts,
+// using the table of contents when there is one. contentLength is the file
+// size, used when the header does not record the audio size. It returns -1
+// when v is nil so callers can fall back to a linear estimate.
+func (v *mp3VBR) offsetAt(t time.Duration, contentLength int64) int64 {
+	dur := v.duration()
+	if dur <= 0 {
+		return -1
+	}
+	total := v.bytes
+	if total <= 0 {
+		total = contentLength - v.dataStart
+	}
+	frac := min(max(float64(t)/float64(dur), 0), 1)
+
+	switch {
+	case len(v.toc) == 100:
+		pct := frac * 100
+		i := min(int(pct), 99)
+		a, b := float64(v.toc[i]), 256.0
+		if i < 99 {
+			b = float64(v.toc[i+1])
+		}
+		pos := a + (b-a)*(pct-float64(i))
+		return v.dataStart + int64(math.Round(pos/256*float64(total)))
+	case len(v.vbriTable) > 0:
+		frame := frac * float64(v.frames)
+		var off int64
+		for _, n := range v.vbriTable {
+			if frame < float64(v.vbriFramesPerEntry) {
+				return v.dataStart + off + int64(math.Round(frame/float64(v.vbriFramesPerEntry)*float64(n)))
+			}
+			frame -= float64(v.vbriFramesPerEntry)
+			off += n
+		}
+		return v.dataStart + off
+	}
+	return v.dataStart + int64(math.Round(frac*float64(total)))
+}
+
+// mp3HeadTap passes an MP3 stream through unchanged while keeping the first
+// mp3HeadWindow bytes after any ID3v2 tag, so the VBR header can be parsed
+// once the decoder has read the first frame. Album art in the tag is
+// skipped rather than stored.
+type mp3HeadTap struct {
+	io.ReadCloser
+	off   int64
+	id3   [10]byte
+	start int64 // offset of the first frame; -1 until the ID3 header is read
+	frame []byte
+}
+
+func newMP3HeadTap(rc io.ReadCloser) *mp3HeadTap {
+	return &mp3HeadTap{ReadCloser: rc, start: -1}
+}
+
+func (t *mp3HeadTap) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	t.record(p[:n])
+	return n, err
+}
+
+func (t *mp3HeadTap) record(b []byte) {
+	for len(b) > 0 {
+		if t.start < 0 {
+			c := copy(t.id3[t.off:], b)
+			t.off += int64(c)
+			b = b[c:]
+			if t.off < int64(len(t.id3)) {
+				return
+			}
+			t.start = 0
+			if bytes.HasPrefix(t.id3[:], []byte("ID3")) {
+				size := int64(t.id3[6])<<21 | int64(t.id3[7])<<14 | int64(t.id3[8])<<7 | int64(t.id3[9])
+				t.start = 10 + size
+				if t.id3[5]&0x10 != 0 {
+					t.start += 10 // footer
+				}
+			} else {
+				t.frame = append(t.frame, t.id3[:]...)
+			}
+			continue
+		}
+		if skip := t.start - t.off; skip > 0 {
+			s := min(skip, int64(len(b)))
+			t.off += s
+			b = b[s:]
+			continue
+		}
+		keep := min(len(b), mp3HeadWindow-len(t.frame))
+		t.frame = append(t.frame, b[:keep]...)
+		t.off += int64(len(b))
+		return
+	}
+}
+
+// vbr parses the header from the bytes seen so far. Leading junk before
+// the first frame sync is skipped, as the decoder does.
+func (t *mp3HeadTap) vbr() *mp3VBR {
+	f := t.frame
+	for i := 0; i+1 < len(f); i++ {
+		if f[i] == 0xFF && f[i+1]&0xE0 == 0xE0 {
+			return parseMP3VBR(f[i:], t.start+int64(i))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+)
+
+// xingFrame builds an MPEG-1 Layer III stereo 44.1 kHz frame carrying a
+// Xing header with the given frame count, byte count and TOC.
+func xingFrame(frames, size uint32, toc []byte) []byte {
+	f := make([]byte, 417)
+	copy(f, []byte{0xFF, 0xFB, 0x90, 0x00})
+	x := f[4+32:]
+	copy(x, "Xing")
+	flags := uint32(3)
+	if toc != nil {
+		flags |= 4
+	}
+	binary.BigEndian.PutUint32(x[4:], flags)
+	binary.BigEndian.PutUint32(x[8:], frames)
+	binary.BigEndian.PutUint32(x[12:], size)
+	copy(x[16:], toc)
+	return f
+}
+
+func TestParseMP3VBRXing(t *testing.T) {
+	// Front-loaded bitrate: the first half of the time holds 3/4 of the bytes.
+	toc := make([]byte, 100)
+	for i := range toc {
+		if i < 50 {
+			toc[i] = byte(i * 192 / 50)
+		} else {
+			toc[i] = byte(192 + (i-50)*64/50)
+		}
+	}
+	v := parseMP3VBR(xingFrame(10000, 1_000_000, toc), 100)
+	if v == nil {
+		t.Fatal("no header parsed")
+	}
+	// 10000 frames × 1152 samples / 44100 Hz.
+	if got, want := v.duration(), 261224489795*time.Nanosecond; got.Round(time.Millisecond) != want.Round(time.Millisecond) {
+		t.Errorf("duration = %v, want %v", got, want)
+	}
+	if got := v.offsetAt(0, 0); got != 100 {
+		t.Errorf("offsetAt(0) = %d, want 100", got)
+	}
+	if got, want := v.offsetAt(v.duration()/2, 0), int64(100+750_000); got != want {
+		t.Errorf("offsetAt(half) = %d, want %d", got, want)
+	}
+}
+
+func TestParseMP3VBRInfoWithoutTOC(t *testing.T) {
+	f := xingFrame(1000, 0, nil)
+	copy(f[36:], "Info")
+	v := parseMP3VBR(f, 0)
+	if v == nil {
+		t.Fatal("no header parsed")
+	}
+	// No TOC and no byte count: linear over the file after the header.
+	if got := v.offsetAt(v.duration()/4, 4000); got != 1000 {
+		t.Errorf("offsetAt(quarter) = %d, want 1000", got)
+	}
+}
+
+func TestParseMP3VBRVBRI(t *testing.T) {
+	f := make([]byte, 417)
+	copy(f, []byte{0xFF, 0xFB, 0x90, 0x00})
+	x := f[36:]
+	copy(x, "VBRI")
+	b := x[4:]
+	binary.BigEndian.PutUint32(b[6:], 3000) // bytes
+	binary.BigEndian.PutUint32(b[10:], 300) // frames
+	binary.BigEndian.PutUint16(b[14:], 3)   // entries
+	binary.BigEndian.PutUint16(b[16:], 1)   // scale
+	binary.BigEndian.PutUint16(b[18:], 2)   // entry size
+	binary.BigEndian.PutUint16(b[20:], 100) // frames per entry
+	for i, n := range []uint16{2000, 500, 500} {
+		binary.BigEndian.PutUint16(b[22+2*i:], n)
+	}
+	v := parseMP3VBR(f, 0)
+	if v == nil {
+		t.Fatal("no header parsed")
+	}
+	if got := v.offsetAt(v.duration()/3, 0); got != 2000 {
+		t.Errorf("offsetAt(third) = %d, want 2000", got)
+	}
+	if got := v.offsetAt(v.duration()/2, 0); got != 2250 {
+		t.Errorf("offsetAt(half) = %d, want 2250", got)
+	}
+}
+
+func TestParseMP3VBRNone(t *testing.T) {
+	f := make([]byte, 417)
+	copy(f, []byte{0xFF, 0xFB, 0x90, 0x00})
+	if v := parseMP3VBR(f, 0); v != nil {
+		t.Errorf("plain frame parsed as %+v", v)
+	}
+	var v *mp3VBR
+	if v.offsetAt(time.Second, 1000) != -1 || v.duration() != 0 {
+		t.Error("nil header should report unknown")
+	}
+}
+
+func TestMP3HeadTapSkipsID3(t *testing.T) {
+	tag := make([]byte, 10+300)
+	copy(tag, "ID3\x03\x00\x00")
+	tag[8], tag[9] = 2, 44 // syncsafe 300
+	frame := xingFrame(10000, 1_000_000, nil)
+	src := append(append(tag, 0, 0), frame...) // two bytes of padding junk
+
+	tap := newMP3HeadTap(io.NopCloser(bytes.NewReader(src)))
+	buf := make([]byte, 7) // odd reads straddle every boundary
+	for {
+		if _, err := tap.Read(buf); err != nil {
+			break
+		}
+	}
+	v := tap.vbr()
+	if v == nil {
+		t.Fatal("no header found after ID3 tag")
+	}
+	if v.dataStart != int64(len(tag)+2) || v.frames != 10000 {
+		t.Errorf("dataStart = %d, frames = %d; want %d, 10000", v.dataStart, v.frames, len(tag)+2)
+	}
+}
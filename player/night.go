@@ -0,0 +1,135 @@
+package player
+
+import (
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+// NightPreset selects how hard night mode evens out the level.
+type NightPreset int
+
+const (
+	NightGentle NightPreset = iota // 2:1, quiet parts lifted up to 6 dB
+	NightStrong                    // 4:1, quiet parts lifted up to 12 dB
+	numNightPresets
+)
+
+var nightParams = [numNightPresets]struct {
+	name  string
+	ratio float64 // compression ratio around the target level
+	boost float64 // most a quiet passage is lifted, in dB
+}{
+	{"gentle", 2, 6},
+	{"strong", 4, 12},
+}
+
+// String returns the preset's config name.
+func (n NightPreset) String() string {
+	if n < 0 || n >= numNightPresets {
+		return nightParams[NightGentle].name
+	}
+	return nightParams[n].name
+}
+
+// Next returns the following preset, wrapping around.
+func (n NightPreset) Next() NightPreset {
+	return (n + 1) % numNightPresets
+}
+
+// ParseNightPreset maps a config name to a preset. Unknown names fall back
+// to NightGentle.
+func ParseNightPreset(name string) NightPreset {
+	for i, p := range nightParams {
+		if strings.EqualFold(p.name, name) {
+			return NightPreset(i)
+		}
+	}
+	return NightGentle
+}
+
+// Night mode levels. Loudness is pulled towards the target from both sides;
+// below the floor, where fades and silence sit, the lift tapers off over
+// nightTaperDB so hiss between tracks is not brought up with the music.
+const (
+	nightTargetDB   = -20.0
+	nightFloorDB    = -45.0
+	nightTaperDB    = 15.0
+	nightDetectMs   = 10.0  // level detector attack; it releases over nightReleaseMs
+	nightAttackMs   = 5.0   // gain falls this fast for a sudden loud passage
+	nightReleaseMs  = 400.0 // and recovers, or lifts a quiet one, this slowly
+	nightMinLevelDB = -120.0
+)
+
+// nightMode is a compressor and automatic gain control for listening at
+// low volume: loud passages are turned down and quiet ones up, towards
+// one level. It runs before the volume stage, so the volume still sets how
+// loud the evened-out result plays. It is bypassed when enabled is false.
+type nightMode struct {
+	enabled *atomic.Bool
+	preset  *atomic.Int32 // NightPreset
+
+	env     float64 // mean-square level of the input
+	gain    float64 // current gain in dB
+	detAtk  float64 // per-sample smoothing coefficients
+	detRel  float64
+	gainAtk float64
+	gainRel float64
+}
+
+func newNightMode(enabled *atomic.Bool, preset *atomic.Int32, sr float64) *nightMode {
+	coef := func(ms float64) float64 { return math.Exp(-1000 / (ms * sr)) }
+	return &nightMode{
+		enabled: enabled,
+		preset:  preset,
+		detAtk:  coef(nightDetectMs),
+		detRel:  coef(nightReleaseMs),
+		gainAtk: coef(nightAttackMs),
+		gainRel: coef(nightReleaseMs),
+	}
+}
+
+// nightGainDB returns the gain night mode aims for at a level in dBFS.
+func nightGainDB(levelDB float64, p NightPreset) float64 {
+	params := nightParams[p]
+	g := (nightTargetDB - levelDB) * (1 - 1/params.ratio)
+	if g <= 0 {
+		return g
+	}
+	g = min(g, params.boost)
+	if levelDB < nightFloorDB {
+		g *= max(0, 1-(nightFloorDB-levelDB)/nightTaperDB)
+	}
+	return g
+}
+
+func (n *nightMode) Name() string { return EffectNight }
+
+func (n *nightMode) Process(samples [][2]float64) {
+	if !n.enabled.Load() {
+		n.env, n.gain = 0, 0
+		return
+	}
+	p := NightPreset(n.preset.Load())
+	if p < 0 || p >= numNightPresets {
+		p = NightGentle
+	}
+	for i := range samples {
+		ms := (samples[i][0]*samples[i][0] + samples[i][1]*samples[i][1]) / 2
+		if ms > n.env {
+			n.env = ms + (n.env-ms)*n.detAtk
+		} else {
+			n.env = ms + (n.env-ms)*n.detRel
+		}
+		level := max(nightMinLevelDB, 10*math.Log10(n.env+1e-12))
+		target := nightGainDB(level, p)
+		if target < n.gain {
+			n.gain = target + (n.gain-target)*n.gainAtk
+		} else {
+			n.gain = target + (n.gain-target)*n.gainRel
+		}
+		g := math.Pow(10, n.gain/20)
+		samples[i][0] *= g
+		samples[i][1] *= g
+	}
+}
@@ -0,0 +1,59 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+// nightLevel runs two seconds of a 1 kHz tone at amp through night mode
+// and returns its RMS level in dBFS over the last half second.
+func nightLevel(amp float64, preset NightPreset) float64 {
+	const sr = 44100
+	var on atomic.Bool
+	on.Store(true)
+	var p atomic.Int32
+	p.Store(int32(preset))
+	n := newNightMode(&on, &p, sr)
+
+	buf := make([][2]float64, 512)
+	var sum float64
+	var count int
+	for i := 0; i < 2*sr; i += len(buf) {
+		for j := range buf {
+			v := amp * math.Sin(2*math.Pi*1000*float64(i+j)/sr)
+			buf[j] = [2]float64{v, v}
+		}
+		n.Process(buf)
+		if i >= 3*sr/2 {
+			for _, s := range buf {
+				sum += s[0] * s[0]
+				count++
+			}
+		}
+	}
+	return 10 * math.Log10(sum/float64(count))
+}
+
+func TestNightModeEvensOutLevels(t *testing.T) {
+	loud := nightLevel(0.9, NightGentle)   // about -4 dBFS RMS
+	quiet := nightLevel(0.03, NightGentle) // about -33 dBFS RMS
+	if loud > -8 {
+		t.Errorf("loud tone at %.1f dBFS, want it turned down", loud)
+	}
+	if quiet < -29 {
+		t.Errorf("quiet tone at %.1f dBFS, want it lifted", quiet)
+	}
+	if strong := nightLevel(0.03, NightStrong); strong <= quiet {
+		t.Errorf("strong lifts the quiet tone to %.1f dBFS, no more than gentle's %.1f", strong, quiet)
+	}
+}
+
+func TestNightModeLeavesSilenceAlone(t *testing.T) {
+	if got := nightGainDB(-80, NightStrong); got != 0 {
+		t.Errorf("gain at -80 dBFS = %.1f dB, want 0", got)
+	}
+	if got := nightGainDB(-30, NightGentle); got != 5 {
+		t.Errorf("gain at -30 dBFS = %.1f dB, want 5", got)
+	}
+}
@@ -0,0 +1,74 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gopxl/beep/v2/speaker"
+)
+
+// outputStallTimeout is how long the audio callback may go silent while the
+// player is supposed to be producing sound before the device is considered
+// lost. It is well above any buffer size the config allows (500 ms).
+const outputStallTimeout = 3 * time.Second
+
+// ErrOutputLost is reported when the audio device stops pulling samples while
+// playback is active — typically because a USB DAC or Bluetooth headset was
+// unplugged or the sound server restarted.
+var ErrOutputLost = errors.New("audio output device lost")
+
+// OutputErr reports whether the output device appears to have gone away.
+// The speaker calls the pipeline every buffer period while unpaused, so a
+// long gap since the last callback returned means the driver has stopped.
+// A callback still running is a source waiting on a slow stream or a seek,
+// not a lost device, however long it takes.
+func (p *Player) OutputErr() error {
+	if !p.playing.Load() || p.paused.Load() {
+		return nil
+	}
+	p.mu.Lock()
+	tap := p.tap
+	p.mu.Unlock()
+	if tap == nil {
+		return nil
+	}
+	if tap.inStream.Load() {
+		return nil
+	}
+	last := tap.lastStream.Load()
+	if last == 0 || time.Since(time.Unix(0, last)) < outputStallTimeout {
+		return nil
+	}
+	return ErrOutputLost
+}
+
+// RecoverOutput asks the audio driver to resume after a device loss and
+// restarts the stall watchdog. It does not open a new device: the speaker
+// can only be initialized once per process, so which device plays is left
+// to the sound server. PipeWire, PulseAudio, CoreAudio and WASAPI move the
+// stream to the new default device on their own, so resuming is enough
+// there. If the driver itself has failed, the error is returned and cliamp
+// must be restarted to open a fresh output.
+func (p *Player) RecoverOutput() error {
+	if err := speaker.Resume(); err != nil {
+		slog.Error("audio device recovery failed", "err", err)
+		return fmt.Errorf("reopen audio output: %w", err)
+	}
+	slog.Info("audio device resumed")
+	p.touchOutput()
+	return nil
+}
+
+// touchOutput restarts the stall watchdog. Called whenever the pipeline goes
+// from silent (stopped/paused) to audible, since the speaker doesn't reach
+// the tap while the controller is paused.
+func (p *Player) touchOutput() {
+	p.mu.Lock()
+	tap := p.tap
+	p.mu.Unlock()
+	if tap != nil {
+		tap.lastStream.Store(time.Now().UnixNano())
+	}
+}
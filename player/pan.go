@@ -0,0 +1,28 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// panNode applies a stereo balance. Balance ranges from -1 (left only)
+// through 0 (centered, untouched) to +1 (right only); moving away from center
+// attenuates the opposite channel linearly while leaving the near one at unity.
+type panNode struct {
+	balance *atomic.Uint64 // stored as Float64bits, range [-1, +1]
+}
+
+func (p *panNode) Name() string { return EffectPan }
+
+func (p *panNode) Process(samples [][2]float64) {
+	b := math.Float64frombits(p.balance.Load())
+	if b == 0 {
+		return
+	}
+	left := min(1, 1-b)
+	right := min(1, 1+b)
+	for i := range samples {
+		samples[i][0] *= left
+		samples[i][1] *= right
+	}
+}
@@ -0,0 +1,33 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPanBalanceLaw(t *testing.T) {
+	tests := []struct {
+		balance     float64
+		left, right float64
+	}{
+		{0, 1, 1},
+		{-1, 1, 0},
+		{1, 0, 1},
+		{-0.5, 1, 0.5},
+		{0.25, 0.75, 1},
+	}
+	for _, tt := range tests {
+		var b atomic.Uint64
+		b.Store(math.Float64bits(tt.balance))
+		p := &panNode{balance: &b}
+		buf := [][2]float64{{1, 1}, {-0.5, 0.5}}
+		p.Process(buf)
+		if buf[0] != [2]float64{tt.left, tt.right} {
+			t.Errorf("balance %+.2f: gains = %v, want [%v %v]", tt.balance, buf[0], tt.left, tt.right)
+		}
+		if buf[1] != [2]float64{-0.5 * tt.left, 0.5 * tt.right} {
+			t.Errorf("balance %+.2f: second sample = %v, want the same gains applied", tt.balance, buf[1])
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package player
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// ProbeSampleRate returns the native sample rate of a local audio file, or 0
+// if it cannot be determined. Native formats are read from their headers;
+// FFmpeg formats are probed with ffprobe.
+func ProbeSampleRate(path string) int {
+	ext := strings.ToLower(filepath.Ext(path))
+	if needsFFmpeg(ext) {
+		return ffprobeSampleRate(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	decoder, format, err := decodeWithExt(f, ext, path, 0, 16)
+	if err != nil {
+		f.Close()
+		return 0
+	}
+	decoder.Close()
+	return int(format.SampleRate)
+}
+
+// ffprobeSampleRate asks ffprobe for the sample rate of the first audio
+// stream of a file or URL, or returns 0 if it cannot be determined.
+func ffprobeSampleRate(path string) int {
+	out, err := exec.Command("ffprobe",
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=sample_rate",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	).Output()
+	if err != nil {
+		return 0
+	}
+	sr, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0
+	}
+	return sr
+}
+
+// SetDSPBypass enables passthrough mode: the DSP chain (EQ, volume, pan,
+// width, crossfeed, reverb and limiter) is bypassed so decoded samples reach
+// the output without processing. It is not bit-perfect output: the speaker
+// is opened once, when the player is created, and always plays 16-bit PCM,
+// so tracks at a different native rate are still resampled and deeper
+// samples are truncated. Choose the rate with ProbeSampleRate before
+// calling New to match at least the first track.
+func (p *Player) SetDSPBypass(on bool) {
+	p.dsp.bypass.Store(on)
+}
+
+// DSPBypass reports whether passthrough mode is enabled.
+func (p *Player) DSPBypass() bool {
+	return p.dsp.bypass.Load()
+}
+
+// Resampling reports whether the current track's native sample rate differs
+// from the output rate, or is unknown because FFmpeg converted the track to
+// the output rate and its own rate could not be probed.
+func (p *Player) Resampling() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil {
+		return false
+	}
+	return p.current.sourceRate() != p.sr
+}
+
+// probeNativeRate records the source rate of a track FFmpeg decodes, whose
+// format only carries the output rate it was converted to. It runs ffprobe,
+// so openPipeline only calls it while the DSP is bypassed, the one time the
+// rate is shown.
+func (tp *trackPipeline) probeNativeRate(path string) {
+	if tp.ffmpegRate && tp.nativeRate == 0 {
+		tp.nativeRate = beep.SampleRate(ffprobeSampleRate(path))
+	}
+}
+
+// sourceRate returns the track's native sample rate, or 0 if FFmpeg
+// converted it and the rate was not probed.
+func (tp *trackPipeline) sourceRate() beep.SampleRate {
+	if tp.ffmpegRate {
+		return tp.nativeRate
+	}
+	return tp.format.SampleRate
+}
@@ -0,0 +1,34 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/gopxl/beep/v2"
+)
+
+func TestResamplingUsesSourceRate(t *testing.T) {
+	p := &Player{sr: 48000}
+	if p.Resampling() {
+		t.Fatal("resampling reported with no track")
+	}
+
+	p.current = &trackPipeline{format: beep.Format{SampleRate: 48000}}
+	if p.Resampling() {
+		t.Fatal("native 48 kHz track on a 48 kHz output reported as resampled")
+	}
+
+	// FFmpeg converts to the output rate, so the format says 48 kHz
+	// whatever the file is.
+	p.current = &trackPipeline{format: beep.Format{SampleRate: 48000}, ffmpegRate: true, nativeRate: 44100}
+	if !p.Resampling() {
+		t.Fatal("44.1 kHz track converted by FFmpeg not reported as resampled")
+	}
+	p.current.nativeRate = 48000
+	if p.Resampling() {
+		t.Fatal("48 kHz track decoded by FFmpeg on a 48 kHz output reported as resampled")
+	}
+	p.current.nativeRate = 0
+	if !p.Resampling() {
+		t.Fatal("FFmpeg track of unknown rate not reported as resampled")
+	}
+}
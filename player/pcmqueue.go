@@ -0,0 +1,55 @@
+package player
+
+import "encoding/binary"
+
+// pcmQueue hands the processed output from the audio thread to a network
+// sink as 16-bit stereo PCM blocks. The audio thread never blocks on it:
+// when the consumer falls behind, blocks are dropped.
+type pcmQueue struct {
+	ch   chan []byte // filled blocks, audio thread → consumer
+	free chan []byte // recycled blocks, consumer → audio thread
+}
+
+// pcmBlockFrames is the size of each preallocated block; larger callbacks
+// are split across several.
+const pcmBlockFrames = 4096
+
+// newPCMQueue allocates all of the queue's blocks up front, so the audio
+// thread never has to.
+func newPCMQueue(blocks int) pcmQueue {
+	q := pcmQueue{ch: make(chan []byte, blocks), free: make(chan []byte, blocks)}
+	for range blocks {
+		q.free <- make([]byte, pcmBlockFrames*4)
+	}
+	return q
+}
+
+// push converts samples to little-endian int16 and queues them in blocks
+// from the free pool. Called on the audio thread, so it neither allocates
+// nor blocks: when no block is free, the rest of the samples are dropped
+// and push reports false.
+func (q *pcmQueue) push(samples [][2]float64) bool {
+	for len(samples) > 0 {
+		var buf []byte
+		select {
+		case buf = <-q.free:
+		default:
+			return false
+		}
+		n := min(len(samples), pcmBlockFrames)
+		buf = buf[:n*4]
+		for i, s := range samples[:n] {
+			binary.LittleEndian.PutUint16(buf[i*4:], uint16(toInt16(s[0])))
+			binary.LittleEndian.PutUint16(buf[i*4+2:], uint16(toInt16(s[1])))
+		}
+		// Every block is either free or queued, so ch has room.
+		q.ch <- buf
+		samples = samples[n:]
+	}
+	return true
+}
+
+// recycle returns a consumed block for reuse.
+func (q *pcmQueue) recycle(buf []byte) {
+	q.free <- buf[:cap(buf)]
+}
@@ -1,6 +1,7 @@
 package player
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"sync/atomic"
@@ -32,6 +33,35 @@ type trackPipeline struct {
 	// Network byte counter — incremented by countingReader for HTTP streams.
 	// nil for local files.
 	bytesRead *atomic.Int64
+
+	// buffer holds a direct file URL in memory for seeking; nil otherwise.
+	buffer *httpBuffer
+
+	// vbr is the Xing/VBRI header of an MP3 stream, used for its exact
+	// duration and time-to-byte mapping on seek; nil when absent.
+	vbr *mp3VBR
+
+	// src is the path the pipeline was opened for by Play, Preload or
+	// Prepare, used to find an already-built pipeline for a track.
+	src string
+
+	// pos and length mirror decoder.Position() and decoder.Len() in sample
+	// frames, kept up to date by positionTracker.
+	pos    atomic.Int64
+	length atomic.Int64
+
+	// serial numbers the pipeline in its events; set by trackPosition.
+	serial uint64
+
+	// ffmpegRate is set when FFmpeg decodes the track straight to the output
+	// rate, so format.SampleRate is not the source's; nativeRate is then the
+	// source rate found by probeNativeRate, 0 if unknown.
+	ffmpegRate bool
+	nativeRate beep.SampleRate
+
+	// gain is the track's gain offset in dB, looked up by trackPosition
+	// and applied when the track starts playing.
+	gain atomic.Uint64
 }
 
 // countingReader wraps an io.ReadCloser and atomically counts bytes read.
@@ -62,7 +92,12 @@ func (tp *trackPipeline) close() {
 
 // setKnownDuration stores the metadata duration hint and, for navFFmpegStreamer
 // pipelines, converts it to sample frames so Len() and proportional seeking work.
+// An MP3 stream's own frame count wins over the metadata, which is often
+// rounded to the second or estimated from the bitrate.
 func (tp *trackPipeline) setKnownDuration(d time.Duration) {
+	if vd := tp.vbr.duration(); vd > 0 {
+		d = vd
+	}
 	tp.knownDuration = d
 	if d > 0 {
 		if ns, ok := tp.decoder.(*navFFmpegStreamer); ok && ns.total == 0 {
@@ -114,6 +149,11 @@ func (p *Player) buildPipelineAt(path string, byteOffset int64, timeOffset time.
 		}, nil
 	}
 
+	// "-" plays whatever is piped into standard input.
+	if isStdin(path) {
+		return p.buildStdinPipeline()
+	}
+
 	// For HTTP URLs, pass the ICY metadata callback; for local files, nil.
 	var onMeta func(string)
 	if isURL(path) {
@@ -154,9 +194,22 @@ func (p *Player) buildPipelineAt(path string, byteOffset int64, timeOffset time.
 			path:          path,
 			bytesRead:     &nb.bytesIn,
 			contentLength: contentLen,
+			ffmpegRate:    true,
 		}, nil
 	}
 
+	// Direct file URLs whose server honours Range requests are held in a
+	// sparse in-memory buffer so the native decoder can seek over the network.
+	if isURL(path) && byteOffset == 0 && bufferedExts[formatExt(path)] {
+		tp, err := p.buildBufferedPipeline(path)
+		if err == nil {
+			return tp, nil
+		}
+		if !errors.Is(err, errNoRanges) {
+			return nil, err
+		}
+	}
+
 	src, err := openSourceAt(path, byteOffset, onMeta)
 	if err != nil {
 		return nil, fmt.Errorf("open source: %w", err)
@@ -178,6 +231,14 @@ func (p *Player) buildPipelineAt(path string, byteOffset int64, timeOffset time.
 		}
 	}
 
+	// MP3 streams are not scanned by the decoder, so keep their first frame
+	// to read the VBR header from once decoding has started.
+	var head *mp3HeadTap
+	if isURL(path) && ext == ".mp3" && byteOffset == 0 {
+		head = newMP3HeadTap(rc)
+		rc = head
+	}
+
 	// For OGG HTTP streams, use the chained decoder so Icecast radio
 	// continues across song boundaries instead of stopping at EOS.
 	if isURL(path) && ext == ".ogg" {
@@ -200,9 +261,10 @@ func (p *Player) buildPipelineAt(path string, byteOffset int64, timeOffset time.
 			return nil, fmt.Errorf("decode: %w", err)
 		}
 		return &trackPipeline{
-			decoder: decoder,
-			stream:  decoder,
-			format:  format,
+			decoder:    decoder,
+			stream:     decoder,
+			format:     format,
+			ffmpegRate: true,
 		}, nil
 	}
 
@@ -216,11 +278,12 @@ func (p *Player) buildPipelineAt(path string, byteOffset int64, timeOffset time.
 			return nil, fmt.Errorf("decode: %w", err)
 		}
 		return &trackPipeline{
-			decoder:  decoder,
-			stream:   decoder, // outputs at target sample rate
-			format:   format,
-			seekable: true,
-			path:     path,
+			decoder:    decoder,
+			stream:     decoder, // outputs at target sample rate
+			format:     format,
+			seekable:   true,
+			path:       path,
+			ffmpegRate: true,
 		}, nil
 	}
 
@@ -240,10 +303,11 @@ func (p *Player) buildPipelineAt(path string, byteOffset int64, timeOffset time.
 		}
 		// pcmStreamer is fully buffered in memory — always seekable, no rc to manage.
 		return &trackPipeline{
-			decoder:  decoder,
-			stream:   decoder, // decodeFFmpeg outputs at target sample rate
-			format:   format,
-			seekable: true,
+			decoder:    decoder,
+			stream:     decoder, // decodeFFmpeg outputs at target sample rate
+			format:     format,
+			seekable:   true,
+			ffmpegRate: true,
 		}, nil
 	}
 
@@ -276,6 +340,10 @@ func (p *Player) buildPipelineAt(path string, byteOffset int64, timeOffset time.
 		path:         path,
 		streamOffset: timeOffset,
 		bytesRead:    byteCounter,
+		ffmpegRate:   isPCM, // decodeWithExt hands needsFFmpeg formats to FFmpeg
+	}
+	if head != nil {
+		tp.vbr = head.vbr()
 	}
 
 	// Mark HTTP streams with a known Content-Length as seek-by-reconnect capable.
@@ -307,3 +375,32 @@ func (p *Player) buildChainedOggPipeline(rc io.ReadCloser, onMeta func(string))
 		rc:       nil, // chainedOggStreamer owns the lifecycle
 	}, nil
 }
+
+// buildBufferedPipeline decodes a direct file URL through an httpBuffer.
+// It returns errNoRanges when the server cannot serve byte ranges.
+func (p *Player) buildBufferedPipeline(path string) (*trackPipeline, error) {
+	hb, err := newHTTPBuffer(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder, format, err := decodeWithExt(hb, formatExt(path), path, p.sr, p.bitDepth)
+	if err != nil {
+		hb.Close()
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	var s beep.Streamer = decoder
+	if format.SampleRate != p.sr {
+		s = beep.Resample(p.resampleQuality, format.SampleRate, p.sr, s)
+	}
+	// The native decoder owns hb and closes it with itself.
+	return &trackPipeline{
+		decoder:       decoder,
+		stream:        s,
+		format:        format,
+		seekable:      true,
+		path:          path,
+		bytesRead:     &hb.bytesIn,
+		contentLength: hb.total,
+		buffer:        hb,
+	}, nil
+}
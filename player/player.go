@@ -2,6 +2,7 @@ package player
 
 import (
 	"fmt"
+	"log/slog"
 	"math"
 	"sync"
 	"sync/atomic"
@@ -26,32 +27,72 @@ type StreamerFactory func(uri string) (beep.StreamSeekCloser, beep.Format, time.
 
 // Player is the audio engine managing the playback pipeline:
 //
-//	[Gapless] -> [10x Biquad EQ] -> [Volume] -> [Tap] -> [Ctrl] -> speaker
+//	[Gapless] -> [Declick] -> [DSP chain] -> [Fade] -> [Record] -> [Tap] -> [Ctrl] -> speaker
 //	     ↑
 //	     ├─ current: [Decode A] → [Resample A]
 //	     └─ next:    [Decode B] → [Resample B]  (preloaded)
+//
+// The DSP chain is an ordered list of Effects, by default
+// EQ → Karaoke → Night → Volume → Pan → Width → Crossfeed → Reverb → Limiter. It can be reordered or
+// extended at runtime with InsertEffect, RemoveEffect and SetEffectOrder.
 type Player struct {
 	mu              sync.Mutex
 	sr              beep.SampleRate
 	gapless         *gaplessStreamer
-	current         *trackPipeline // active track's resources
-	nextPipeline    *trackPipeline // preloaded track's resources
-	started         bool           // true after first speaker.Play()
+	declick         *declick // seek crossfade
+	dsp             *dspChain
+	fade            *fader           // pause/resume/stop envelope
+	rec             *recorder        // optional WAV tee (--record), cast and broadcast streams
+	current         *trackPipeline   // active track's resources
+	nextPipeline    *trackPipeline   // preloaded track's resources
+	ready           []*trackPipeline // prepared but unqueued pipelines (see Prepare)
+	started         bool             // true after first speaker.Play()
 	ctrl            *beep.Ctrl
 	volume          atomic.Uint64     // dB stored as Float64bits, range [-30, +6]
 	eqBands         [10]atomic.Uint64 // dB stored as math.Float64bits
+	eqFreqs         [10]atomic.Uint64 // center frequency in Hz, Float64bits
+	eqQs            [10]atomic.Uint64 // quality factor, Float64bits
+	eqPreamp        atomic.Uint64     // automatic makeup gain in dB (<= 0), Float64bits
+	autoPreamp      atomic.Bool       // derive eqPreamp from the EQ curve to prevent clipping
 	tap             *tap
 	playing         atomic.Bool
 	paused          atomic.Bool
 	mono            atomic.Bool
+	muted           atomic.Bool
+	trackGain       atomic.Uint64 // gain offset of the playing track in dB, Float64bits
+	gainLookup      func(path string) float64
+	balance         atomic.Uint64 // stereo balance stored as Float64bits, range [-1, +1]
+	widthOn         atomic.Bool   // mid/side stereo widening enabled
+	width           atomic.Uint64 // stereo width stored as Float64bits, range [0, 2]
+	crossfeedOn     atomic.Bool   // headphone crossfeed enabled
+	crossfeedPreset atomic.Int32  // CrossfeedPreset
+	reverbOn        atomic.Bool   // room reverb enabled
+	reverbPreset    atomic.Int32  // ReverbPreset
+	karaokeOn       atomic.Bool   // centre-channel vocal reduction
+	nightOn         atomic.Bool   // night mode compression before the volume stage
+	nightPreset     atomic.Int32  // NightPreset
+	limiterOn       atomic.Bool   // soft-knee limiter after the volume stage
+	limiterHit      atomic.Int64  // unix nanos when the limiter last reduced gain
+	clipHit         atomic.Int64  // unix nanos when the volume stage last output beyond ±1
+	skipSilence     atomic.Bool   // trim leading/trailing silence on local files
+	silenceDB       atomic.Uint64 // silence threshold in dBFS, Float64bits
+	silenceMin      atomic.Int64  // trailing silence run that ends a track, nanoseconds
 	resampleQuality int
 	bitDepth        int // 16 or 32
 
-	gaplessAdvance atomic.Bool // set when gapless transition fires
+	events         chan Event      // playback events for Events; never closed
+	emitMu         sync.Mutex      // serialises emit's make-room pass
 	seekGen        atomic.Int64    // generation counter for yt-dlp seeks; incremented to cancel stale seeks
 
 	streamTitle    atomic.Value    // stores string, set by ICY reader callback
 	customFactory  StreamerFactory // optional factory for custom URI schemes (e.g., spotify:)
+
+	castOut    atomic.Pointer[castOutput] // active Chromecast output, nil when local
+	castVolume atomic.Bool                // the cast device applies the volume, not the DSP chain
+	castErr    error                      // why the last cast ended on the device's side; guarded by mu
+
+	bcast    atomic.Pointer[broadcaster] // active Icecast broadcast, nil when off
+	bcastErr error                       // why the last broadcast stopped on its own; guarded by mu
 }
 
 // New creates a Player and initializes the speaker with the given quality settings.
@@ -62,13 +103,22 @@ func New(q Quality) (*Player, error) {
 	}
 	sr := beep.SampleRate(q.SampleRate)
 	if err := speaker.Init(sr, sr.N(time.Duration(q.BufferMs)*time.Millisecond)); err != nil {
+		slog.Error("audio device init failed", "rate", q.SampleRate, "buffer_ms", q.BufferMs, "err", err)
 		return nil, fmt.Errorf("speaker init: %w", err)
 	}
 	bitDepth := q.BitDepth
 	if bitDepth != 32 {
 		bitDepth = 16
 	}
-	p := &Player{sr: sr, resampleQuality: q.ResampleQuality, bitDepth: bitDepth}
+	slog.Info("audio device ready", "rate", q.SampleRate, "buffer_ms", q.BufferMs, "bit_depth", bitDepth)
+	p := &Player{sr: sr, resampleQuality: q.ResampleQuality, bitDepth: bitDepth, events: make(chan Event, eventBuffer)}
+	p.width.Store(math.Float64bits(1))
+	for i := range p.eqFreqs {
+		p.eqFreqs[i].Store(math.Float64bits(DefaultEQFreqs[i]))
+		p.eqQs[i].Store(math.Float64bits(DefaultEQQ))
+	}
+	p.silenceDB.Store(math.Float64bits(defaultSilenceThresholdDB))
+	p.silenceMin.Store(int64(defaultSilenceMin))
 	p.gapless = &gaplessStreamer{}
 	p.gapless.onSwap = func() {
 		// Called from audio thread (goroutine) when gapless transition occurs.
@@ -77,26 +127,50 @@ func New(q Quality) (*Player, error) {
 		old := p.current
 		p.current = p.nextPipeline
 		p.nextPipeline = nil
+		cur := p.current
 		p.mu.Unlock()
 		if old != nil {
 			old.close()
 		}
-		p.gaplessAdvance.Store(true)
+		if cur != nil {
+			p.emit(Event{Kind: EventGapless, Track: cur.serial})
+		}
 	}
+	p.gapless.onDrain = func(s beep.Streamer) { p.emitFor(EventDrained, s) }
+	p.declick = newDeclick(p.gapless, sr)
+	p.dsp = newDSPChain(p.declick, []Effect{
+		newEQNode(&p.eqBands, &p.eqFreqs, &p.eqQs, float64(sr)),
+		newKaraoke(&p.karaokeOn, float64(sr)),
+		newNightMode(&p.nightOn, &p.nightPreset, float64(sr)),
+		&volumeNode{vol: &p.volume, preamp: &p.eqPreamp, trim: &p.trackGain, mono: &p.mono, mute: &p.muted, bypass: &p.castVolume, clipped: &p.clipHit, cachedDB: math.NaN()},
+		&panNode{balance: &p.balance},
+		&widthNode{enabled: &p.widthOn, width: &p.width},
+		newCrossfeed(&p.crossfeedOn, &p.crossfeedPreset, float64(sr)),
+		newReverb(&p.reverbOn, &p.reverbPreset, float64(sr)),
+		newLimiter(&p.limiterOn, &p.limiterHit, float64(sr)),
+	})
+	p.fade = newFader(p.dsp, float64(sr))
+	p.rec = &recorder{s: p.fade}
 	return p, nil
 }
 
 // Play opens and starts playing an audio file. On the first call it builds
-// the long-lived EQ → volume → tap → ctrl chain and starts the speaker.
+// the long-lived DSP → tap → ctrl chain and starts the speaker.
 // Subsequent calls swap only the track source via the gapless streamer.
 // knownDuration is the metadata duration (use 0 if unknown); it is used as a
 // fallback when the decoder cannot determine the length (e.g. HTTP streams).
+// A pipeline already built for path by Preload or Prepare is used as is, so
+// skipping to an adjacent track does not wait for the file to be decoded.
 func (p *Player) Play(path string, knownDuration time.Duration) error {
-	tp, err := p.buildPipeline(path)
-	if err != nil {
-		return err
+	tp := p.takeReady(path)
+	if tp == nil {
+		var err error
+		if tp, err = p.openPipeline(path, knownDuration); err != nil {
+			slog.Error("open track failed", "path", path, "err", err)
+			return err
+		}
 	}
-	tp.setKnownDuration(knownDuration)
+	slog.Debug("playing", "path", path)
 	return p.playPipeline(tp)
 }
 
@@ -110,6 +184,7 @@ func (p *Player) PlayYTDL(pageURL string, knownDuration time.Duration) error {
 	}
 	tp, err := p.buildYTDLPipeline(pageURL, 0)
 	if err != nil {
+		slog.Error("yt-dlp pipeline failed", "url", pageURL, "err", err)
 		return err
 	}
 	if knownDuration == 0 {
@@ -133,7 +208,7 @@ func (p *Player) PlayYTDL(pageURL string, knownDuration time.Duration) error {
 }
 
 // playPipeline wires a ready-to-play trackPipeline into the speaker chain.
-// On the first call it builds the long-lived EQ → volume → tap → ctrl chain.
+// On the first call it builds the long-lived DSP → tap → ctrl chain.
 // Subsequent calls swap only the track source via the gapless streamer.
 func (p *Player) playPipeline(tp *trackPipeline) error {
 	// Collect old pipelines to close after releasing locks.
@@ -145,8 +220,11 @@ func (p *Player) playPipeline(tp *trackPipeline) error {
 		// must happen under the speaker lock because the audio thread reads it
 		// on every Stream() call.
 		speaker.Lock()
-		p.gapless.Replace(tp.stream)
+		p.gapless.Replace(p.trackPosition(tp))
 		p.ctrl.Paused = false
+		// A new track starts at full level even if the last one was
+		// faded out by Stop or a pause, unless a fade-in is armed.
+		p.fade.start()
 		speaker.Unlock()
 	}
 
@@ -158,17 +236,12 @@ func (p *Player) playPipeline(tp *trackPipeline) error {
 	p.nextPipeline = nil
 
 	if !p.started {
-		p.gapless.Replace(tp.stream)
+		p.gapless.Replace(p.trackPosition(tp))
+		p.fade.start()
 
 		// Build the long-lived pipeline once
-		var s beep.Streamer = p.gapless
-
-		for i := range 10 {
-			s = newBiquad(s, eqFreqs[i], 1.4, &p.eqBands[i], float64(p.sr))
-		}
-
-		s = &volumeStreamer{s: s, vol: &p.volume, mono: &p.mono, cachedDB: math.NaN()}
-		p.tap = newTap(s, 4096)
+		p.tap = newTap(p.rec, 4096)
+		p.tap.mute.Store(p.castOut.Load() != nil)
 		p.ctrl = &beep.Ctrl{Streamer: p.tap}
 		p.started = true
 		p.playing.Store(true)
@@ -183,6 +256,7 @@ func (p *Player) playPipeline(tp *trackPipeline) error {
 	p.playing.Store(true)
 	p.paused.Store(false)
 	p.mu.Unlock()
+	p.touchOutput()
 
 	// Close old resources after all locks are released
 	closePipelines(oldCurrent, oldNext)
@@ -192,11 +266,14 @@ func (p *Player) playPipeline(tp *trackPipeline) error {
 // Preload builds a pipeline for the next track and queues it for gapless transition.
 // knownDuration is the metadata duration (use 0 if unknown).
 func (p *Player) Preload(path string, knownDuration time.Duration) error {
-	tp, err := p.buildPipeline(path)
-	if err != nil {
-		return err
+	tp := p.takeReady(path)
+	if tp == nil {
+		var err error
+		if tp, err = p.openPipeline(path, knownDuration); err != nil {
+			slog.Warn("preload failed", "path", path, "err", err)
+			return err
+		}
 	}
-	tp.setKnownDuration(knownDuration)
 	return p.preloadPipeline(tp)
 }
 
@@ -215,7 +292,7 @@ func (p *Player) preloadPipeline(tp *trackPipeline) error {
 	// Lock speaker to atomically swap the gapless next stream, ensuring no
 	// in-flight transition reads from the old pipeline we're about to close.
 	speaker.Lock()
-	p.gapless.SetNext(tp.stream)
+	p.gapless.SetNext(p.trackPosition(tp))
 	speaker.Unlock()
 
 	p.mu.Lock()
@@ -247,28 +324,74 @@ func (p *Player) ClearPreload() {
 	}
 }
 
-// GaplessAdvanced returns true (once) when a gapless transition happened.
-func (p *Player) GaplessAdvanced() bool {
-	return p.gaplessAdvance.CompareAndSwap(true, false)
-}
-
-// TogglePause toggles between paused and playing states.
+// TogglePause toggles between paused and playing states. Pausing fades the
+// output out over the fade duration before the controller is paused;
+// resuming unpauses immediately and fades back in.
 func (p *Player) TogglePause() {
 	speaker.Lock()
-	if p.ctrl != nil {
-		p.ctrl.Paused = !p.ctrl.Paused
-		paused := p.ctrl.Paused
+	if p.ctrl == nil {
 		speaker.Unlock()
-		p.paused.Store(paused)
+		return
+	}
+	paused := !p.paused.Load()
+	if paused {
+		if p.fade.enabled() {
+			ctrl := p.ctrl
+			p.fade.fadeTo(0, func() { ctrl.Paused = true })
+		} else {
+			p.ctrl.Paused = true
+		}
 	} else {
-		speaker.Unlock()
+		p.ctrl.Paused = false
+		p.fade.fadeTo(1, nil)
+	}
+	speaker.Unlock()
+	if !paused {
+		p.touchOutput()
+	}
+	p.paused.Store(paused)
+	p.castSetPaused(paused)
+	kind := EventResumed
+	if paused {
+		kind = EventPaused
+	}
+	p.emit(Event{Kind: kind, Track: p.Serial(), Position: p.Position()})
+}
+
+// SetFadeDuration sets the ramp length used when pausing, resuming and
+// stopping. Zero disables fading.
+func (p *Player) SetFadeDuration(d time.Duration) {
+	p.fade.duration.Store(int64(max(d, 0)))
+}
+
+// FadeInNext makes the next track to start begin in silence and rise to
+// full level over d, as the alarm clock does. Pausing during the ramp falls
+// back to the normal fade.
+func (p *Player) FadeInNext(d time.Duration) {
+	p.fade.armed.Store(int64(max(d, 0)))
+}
+
+// fadeOutAndWait fades the output to silence and blocks until the audio
+// thread has finished the ramp (or a safety timeout expires). It is a no-op
+// when nothing is audible.
+func (p *Player) fadeOutAndWait() {
+	if !p.fade.enabled() || !p.playing.Load() || p.paused.Load() {
+		return
+	}
+	done := make(chan struct{})
+	p.fade.fadeTo(0, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Duration(p.fade.duration.Load()) + 150*time.Millisecond):
 	}
 }
 
 // Stop halts playback and releases resources. The speaker continues running
 // (outputting silence via the gapless streamer) so it can be restarted without
-// rebuilding the pipeline.
+// rebuilding the pipeline. Audible output is faded out first.
 func (p *Player) Stop() {
+	p.fadeOutAndWait()
+
 	// Lock speaker to ensure the goroutine finishes any in-progress Stream()
 	// call, then clear the source and pause. After unlock, the speaker will
 	// only see silence from the gapless streamer (paused ctrl).
@@ -302,7 +425,18 @@ func (p *Player) Stop() {
 // The speaker lock is acquired first (outer), then p.mu briefly to snapshot
 // the current pipeline, ensuring consistent lock ordering with the audio thread.
 // Clears the preloaded next pipeline to prevent a stale gapless transition.
+// Local and seek-by-reconnect jumps are crossfaded over a few milliseconds
+// (see declick) to avoid an audible click at the seek point.
 func (p *Player) Seek(d time.Duration) error {
+	err := p.seek(d)
+	if err != nil {
+		slog.Warn("seek failed", "offset", d, "err", err)
+	}
+	return err
+}
+
+func (p *Player) seek(d time.Duration) error {
+	defer p.castFlush()
 	speaker.Lock()
 	defer speaker.Unlock()
 	p.mu.Lock()
@@ -327,6 +461,10 @@ func (p *Player) Seek(d time.Duration) error {
 		// Use floating-point to avoid int64 overflow on large files.
 		ratio := float64(newPos) / float64(cur.knownDuration)
 		byteOffset := int64(ratio * float64(cur.contentLength))
+		// A VBR MP3's table of contents places the target far more exactly.
+		if off := cur.vbr.offsetAt(newPos, cur.contentLength); off >= 0 {
+			byteOffset = off
+		}
 
 		// Build a new pipeline starting at the computed byte offset.
 		// Speaker is already locked, so we can safely swap gapless.
@@ -340,8 +478,12 @@ func (p *Player) Seek(d time.Duration) error {
 		// original full-file contentLength and mark seekableStream explicitly.
 		tp.seekableStream = true
 		tp.contentLength = cur.contentLength
+		tp.vbr = cur.vbr
 
-		p.gapless.Replace(tp.stream)
+		if cur.knownDuration-curPos > primeMargin {
+			p.declick.prime(cur.stream)
+		}
+		p.gapless.Replace(p.trackPosition(tp))
 
 		// Clear any preloaded next pipeline — its transition point is now stale.
 		p.gapless.SetNext(nil)
@@ -377,19 +519,26 @@ func (p *Player) Seek(d time.Duration) error {
 	if newSample >= cur.decoder.Len() {
 		newSample = cur.decoder.Len() - 1
 	}
+	// Capture the pre-seek tail so the jump is crossfaded instead of clicking.
+	if cur.format.SampleRate.D(cur.decoder.Len()-curSample) > primeMargin {
+		p.declick.prime(cur.stream)
+	}
 	if err := cur.decoder.Seek(newSample); err != nil {
 		return err
 	}
+	cur.syncPosition()
 	// Invalidate the preloaded next pipeline — the gapless transition point
 	// has moved and the old preload may be stale. The speaker lock is already
 	// held, so we can safely clear the gapless next stream.
+	// The pipeline itself has not been read yet, so it is kept ready for
+	// the next Preload or a skip instead of being closed.
 	p.gapless.SetNext(nil)
 	p.mu.Lock()
 	old := p.nextPipeline
 	p.nextPipeline = nil
 	p.mu.Unlock()
 	if old != nil {
-		old.close()
+		p.stashReady(old)
 	}
 	return nil
 }
@@ -450,7 +599,7 @@ func (p *Player) SeekYTDL(d time.Duration) error {
 
 	// Now acquire speaker lock to swap streams.
 	speaker.Lock()
-	p.gapless.Replace(tp.stream)
+	p.gapless.Replace(p.trackPosition(tp))
 	p.gapless.SetNext(nil)
 	speaker.Unlock()
 
@@ -477,16 +626,16 @@ func (p *Player) IsYTDLSeek() bool {
 // For ranged HTTP streams (seek-by-reconnect), streamOffset is added to the
 // decoder's sample-based position so the reported time is absolute within
 // the track, not relative to the reconnect point.
+// It reads the position the audio goroutine last recorded, so rendering
+// never waits on (or holds up) the speaker lock.
 func (p *Player) Position() time.Duration {
-	speaker.Lock()
-	defer speaker.Unlock()
 	p.mu.Lock()
 	cur := p.current
 	p.mu.Unlock()
 	if cur == nil {
 		return 0
 	}
-	return cur.format.SampleRate.D(cur.decoder.Position()) + cur.streamOffset
+	return cur.position()
 }
 
 // Duration returns the total duration of the current track.
@@ -494,23 +643,25 @@ func (p *Player) Position() time.Duration {
 // For HTTP streams where the decoder reports Len()==0, the metadata hint
 // stored at pipeline build time (knownDuration) is returned instead.
 func (p *Player) Duration() time.Duration {
-	speaker.Lock()
-	defer speaker.Unlock()
 	p.mu.Lock()
 	cur := p.current
 	p.mu.Unlock()
 	if cur == nil {
 		return 0
 	}
-	if n := cur.decoder.Len(); n > 0 {
-		return cur.format.SampleRate.D(n)
+	if n := cur.length.Load(); n > 0 {
+		return cur.format.SampleRate.D(int(n))
 	}
 	return cur.knownDuration
 }
 
 // SetVolume sets the volume in dB, clamped to [-30, +6].
 func (p *Player) SetVolume(db float64) {
-	p.volume.Store(math.Float64bits(max(min(db, 6), -30)))
+	db = max(min(db, 6), -30)
+	p.volume.Store(math.Float64bits(db))
+	if !p.muted.Load() {
+		p.castSetVolume(db)
+	}
 }
 
 // Volume returns the current volume in dB.
@@ -518,6 +669,49 @@ func (p *Player) Volume() float64 {
 	return math.Float64frombits(p.volume.Load())
 }
 
+// SetMuted silences or restores the output. The volume is kept as it is,
+// so unmuting returns to the exact level.
+func (p *Player) SetMuted(muted bool) {
+	p.muted.Store(muted)
+	if muted {
+		p.castSetLevel(0)
+	} else {
+		p.castSetVolume(p.Volume())
+	}
+}
+
+// ToggleMute mutes or unmutes the output.
+func (p *Player) ToggleMute() {
+	p.SetMuted(!p.muted.Load())
+}
+
+// Muted reports whether the output is muted.
+func (p *Player) Muted() bool {
+	return p.muted.Load()
+}
+
+// SetTrackGainLookup sets how the gain offset of a track is found, by the
+// path it is played from. Set it before playback starts.
+func (p *Player) SetTrackGainLookup(f func(path string) float64) {
+	p.gainLookup = f
+}
+
+// SetTrackGain changes the gain offset of the playing track, in dB.
+func (p *Player) SetTrackGain(db float64) {
+	bits := math.Float64bits(db)
+	p.mu.Lock()
+	if p.current != nil {
+		p.current.gain.Store(bits)
+	}
+	p.mu.Unlock()
+	p.trackGain.Store(bits)
+}
+
+// TrackGain returns the gain offset of the playing track in dB.
+func (p *Player) TrackGain() float64 {
+	return math.Float64frombits(p.trackGain.Load())
+}
+
 // ToggleMono switches between stereo and mono (L+R downmix) output.
 func (p *Player) ToggleMono() {
 	p.mono.Store(!p.mono.Load())
@@ -528,12 +722,204 @@ func (p *Player) Mono() bool {
 	return p.mono.Load()
 }
 
+// SetBalance sets the stereo balance, clamped to [-1, +1], where -1 is hard
+// left, 0 is centered and +1 is hard right.
+func (p *Player) SetBalance(b float64) {
+	b = max(min(b, 1), -1)
+	// Snap tiny values left over from repeated ±0.1 steps back to center.
+	if math.Abs(b) < 1e-9 {
+		b = 0
+	}
+	p.balance.Store(math.Float64bits(b))
+}
+
+// Balance returns the current stereo balance in [-1, +1].
+func (p *Player) Balance() float64 {
+	return math.Float64frombits(p.balance.Load())
+}
+
+// SetStereoWidth sets the mid/side stereo width, clamped to [0, 2]
+// (0% mono, 100% unchanged, 200% doubled side signal).
+func (p *Player) SetStereoWidth(w float64) {
+	p.width.Store(math.Float64bits(max(min(w, 2), 0)))
+}
+
+// StereoWidth returns the configured stereo width in [0, 2].
+func (p *Player) StereoWidth() float64 {
+	return math.Float64frombits(p.width.Load())
+}
+
+// SetStereoWiden enables or disables the stereo width stage.
+func (p *Player) SetStereoWiden(on bool) {
+	p.widthOn.Store(on)
+}
+
+// StereoWiden reports whether the stereo width stage is enabled.
+func (p *Player) StereoWiden() bool {
+	return p.widthOn.Load()
+}
+
+// SetCrossfeed enables or disables headphone crossfeed.
+func (p *Player) SetCrossfeed(on bool) {
+	p.crossfeedOn.Store(on)
+}
+
+// Crossfeed reports whether headphone crossfeed is enabled.
+func (p *Player) Crossfeed() bool {
+	return p.crossfeedOn.Load()
+}
+
+// SetCrossfeedPreset selects the crossfeed cutoff/level preset.
+func (p *Player) SetCrossfeedPreset(c CrossfeedPreset) {
+	if c < 0 || c >= numCrossfeedPresets {
+		c = CrossfeedDefault
+	}
+	p.crossfeedPreset.Store(int32(c))
+}
+
+// CrossfeedPreset returns the active crossfeed preset.
+func (p *Player) CrossfeedPreset() CrossfeedPreset {
+	return CrossfeedPreset(p.crossfeedPreset.Load())
+}
+
+// SetReverb enables or disables the reverb effect.
+func (p *Player) SetReverb(on bool) {
+	p.reverbOn.Store(on)
+}
+
+// Reverb reports whether the reverb effect is enabled.
+func (p *Player) Reverb() bool {
+	return p.reverbOn.Load()
+}
+
+// SetReverbPreset selects the reverb room model.
+func (p *Player) SetReverbPreset(r ReverbPreset) {
+	if r < 0 || r >= numReverbPresets {
+		r = ReverbRoom
+	}
+	p.reverbPreset.Store(int32(r))
+}
+
+// ReverbPreset returns the active reverb room model.
+func (p *Player) ReverbPreset() ReverbPreset {
+	return ReverbPreset(p.reverbPreset.Load())
+}
+
+// SetKaraoke enables or disables vocal reduction, which cancels the
+// centre of the mix across the vocal range.
+func (p *Player) SetKaraoke(on bool) {
+	p.karaokeOn.Store(on)
+}
+
+// Karaoke reports whether vocal reduction is enabled.
+func (p *Player) Karaoke() bool {
+	return p.karaokeOn.Load()
+}
+
+// SetNightMode enables or disables night mode, which evens out loud and
+// quiet passages for listening at low volume.
+func (p *Player) SetNightMode(on bool) {
+	p.nightOn.Store(on)
+}
+
+// NightMode reports whether night mode is enabled.
+func (p *Player) NightMode() bool {
+	return p.nightOn.Load()
+}
+
+// SetNightPreset selects how strongly night mode evens out the level.
+func (p *Player) SetNightPreset(n NightPreset) {
+	if n < 0 || n >= numNightPresets {
+		n = NightGentle
+	}
+	p.nightPreset.Store(int32(n))
+}
+
+// NightPreset returns the active night mode strength.
+func (p *Player) NightPreset() NightPreset {
+	return NightPreset(p.nightPreset.Load())
+}
+
+// SetLimiter enables or disables the output limiter.
+func (p *Player) SetLimiter(on bool) {
+	p.limiterOn.Store(on)
+}
+
+// Limiter reports whether the output limiter is enabled.
+func (p *Player) Limiter() bool {
+	return p.limiterOn.Load()
+}
+
+// LimiterActive reports whether the limiter reduced gain within the last
+// second, i.e. the signal would otherwise have clipped.
+func (p *Player) LimiterActive() bool {
+	last := p.limiterHit.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < time.Second
+}
+
+// Clipping reports whether samples left the volume stage beyond full scale
+// within the last second, i.e. the EQ and volume settings distort unless
+// the limiter catches them.
+func (p *Player) Clipping() bool {
+	last := p.clipHit.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < time.Second
+}
+
 // SetEQBand sets a single EQ band's gain in dB, clamped to [-12, +12].
 func (p *Player) SetEQBand(band int, dB float64) {
 	if band < 0 || band >= 10 {
 		return
 	}
 	p.eqBands[band].Store(math.Float64bits(max(min(dB, 12), -12)))
+	p.updatePreamp()
+}
+
+// SetEQBandFreq sets a single EQ band's center frequency in Hz, clamped to
+// [MinEQFreq, MaxEQFreq].
+func (p *Player) SetEQBandFreq(band int, hz float64) {
+	if band < 0 || band >= 10 || math.IsNaN(hz) {
+		return
+	}
+	p.eqFreqs[band].Store(math.Float64bits(max(min(hz, MaxEQFreq), MinEQFreq)))
+	p.updatePreamp()
+}
+
+// SetEQBandQ sets a single EQ band's quality factor, clamped to [MinEQQ,
+// MaxEQQ]. A higher Q narrows the band around its center frequency.
+func (p *Player) SetEQBandQ(band int, q float64) {
+	if band < 0 || band >= 10 || math.IsNaN(q) {
+		return
+	}
+	p.eqQs[band].Store(math.Float64bits(max(min(q, MaxEQQ), MinEQQ)))
+	p.updatePreamp()
+}
+
+// SetEQAutoPreamp enables or disables automatic EQ makeup gain. When enabled,
+// the output is attenuated by the peak boost of the EQ curve so boosted bands
+// cannot push the signal into clipping.
+func (p *Player) SetEQAutoPreamp(on bool) {
+	p.autoPreamp.Store(on)
+	p.updatePreamp()
+}
+
+// EQAutoPreamp reports whether automatic EQ makeup gain is enabled.
+func (p *Player) EQAutoPreamp() bool {
+	return p.autoPreamp.Load()
+}
+
+// EQPreamp returns the makeup gain in dB currently applied to compensate
+// for EQ boost. It is 0 when auto preamp is off or the curve has no boost.
+func (p *Player) EQPreamp() float64 {
+	return math.Float64frombits(p.eqPreamp.Load())
+}
+
+// updatePreamp recomputes the automatic makeup gain from the current EQ bands.
+func (p *Player) updatePreamp() {
+	pre := 0.0
+	if p.autoPreamp.Load() {
+		pre = -eqPeakGain(p.EQBands(), p.EQFreqs(), p.EQQs(), float64(p.sr))
+	}
+	p.eqPreamp.Store(math.Float64bits(pre))
 }
 
 // EQBands returns a copy of all 10 EQ band gains.
@@ -545,6 +931,24 @@ func (p *Player) EQBands() [10]float64 {
 	return bands
 }
 
+// EQFreqs returns a copy of all 10 EQ band center frequencies in Hz.
+func (p *Player) EQFreqs() [10]float64 {
+	var freqs [10]float64
+	for i := range 10 {
+		freqs[i] = math.Float64frombits(p.eqFreqs[i].Load())
+	}
+	return freqs
+}
+
+// EQQs returns a copy of all 10 EQ band quality factors.
+func (p *Player) EQQs() [10]float64 {
+	var qs [10]float64
+	for i := range 10 {
+		qs[i] = math.Float64frombits(p.eqQs[i].Load())
+	}
+	return qs
+}
+
 // IsPlaying returns true if a track is loaded and playing (possibly paused).
 func (p *Player) IsPlaying() bool {
 	return p.playing.Load()
@@ -555,11 +959,6 @@ func (p *Player) IsPaused() bool {
 	return p.paused.Load()
 }
 
-// Drained returns true if the current track ended with no preloaded next track.
-func (p *Player) Drained() bool {
-	return p.gapless.Drained()
-}
-
 // HasPreload returns true if a next track is already queued for gapless transition.
 func (p *Player) HasPreload() bool {
 	p.mu.Lock()
@@ -615,6 +1014,18 @@ func (p *Player) SamplesInto(dst []float64) int {
 	return tap.SamplesInto(dst)
 }
 
+// PeakLevel returns the output's peak level in dBFS since the last call,
+// taken across both channels after all processing, or -Inf for silence.
+func (p *Player) PeakLevel() float64 {
+	p.mu.Lock()
+	tap := p.tap
+	p.mu.Unlock()
+	if tap == nil {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(tap.TakePeak())
+}
+
 // SampleRate returns the output sample rate in Hz.
 func (p *Player) SampleRate() int {
 	return int(p.sr)
@@ -636,6 +1047,20 @@ func (p *Player) StreamBytes() (downloaded, total int64) {
 	return downloaded, total
 }
 
+// StreamBuffered returns the parts of the current track that have been
+// downloaded, as [start, end) fractions of the file, for direct file URLs
+// played from the seekable network buffer. It returns nil for everything
+// else. Byte fractions track time closely but not exactly for VBR audio.
+func (p *Player) StreamBuffered() [][2]float64 {
+	p.mu.Lock()
+	cur := p.current
+	p.mu.Unlock()
+	if cur == nil || cur.buffer == nil {
+		return nil
+	}
+	return cur.buffer.Buffered()
+}
+
 // SetStreamerFactory registers a factory function for custom URI schemes.
 // When buildPipeline encounters a URI that isn't a local file or HTTP URL,
 // it calls this factory to create the decoder.
@@ -647,6 +1072,9 @@ func (p *Player) SetStreamerFactory(f StreamerFactory) {
 
 // Close fully stops the speaker and cleans up all resources.
 func (p *Player) Close() {
+	p.StopCast()
+	p.StopBroadcast()
 	p.Stop()
+	p.dropReady()
 	speaker.Clear()
 }
@@ -0,0 +1,69 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// positionTracker copies the decoder's position and length into its
+// pipeline after every read, so Position and Duration can be answered
+// without taking the speaker lock the audio callback holds. It runs on the
+// audio goroutine, which is the one reading the decoder, and reports the
+// track's start and each whole second played. When the track starts it
+// also switches the volume to the track's gain offset, so the change lands
+// exactly at the boundary.
+type positionTracker struct {
+	beep.Streamer
+	tp      *trackPipeline
+	emit    func(Event)
+	gain    *atomic.Uint64 // Player.trackGain
+	started bool
+	second  time.Duration // last whole second reported
+}
+
+func (pt *positionTracker) Stream(samples [][2]float64) (int, bool) {
+	n, ok := pt.Streamer.Stream(samples)
+	pt.tp.syncPosition()
+	pos := pt.tp.position()
+	if !pt.started {
+		pt.started = true
+		pt.gain.Store(pt.tp.gain.Load())
+		pt.second = pos.Truncate(time.Second)
+		pt.emit(Event{Kind: EventTrackStarted, Track: pt.tp.serial, Position: pos})
+	} else if s := pos.Truncate(time.Second); s != pt.second {
+		pt.second = s
+		pt.emit(Event{Kind: EventPosition, Track: pt.tp.serial, Position: pos})
+	}
+	return n, ok
+}
+
+// syncPosition records the decoder's position and length. Call it only
+// from the audio goroutine or with the speaker locked.
+func (tp *trackPipeline) syncPosition() {
+	tp.pos.Store(int64(tp.decoder.Position()))
+	tp.length.Store(int64(tp.decoder.Len()))
+}
+
+// position returns the playback position last recorded by syncPosition.
+func (tp *trackPipeline) position() time.Duration {
+	return tp.format.SampleRate.D(int(tp.pos.Load())) + tp.streamOffset
+}
+
+// trackPosition wraps the pipeline's stream in a positionTracker, once,
+// numbers the pipeline for its events, and returns the stream. Call it
+// before handing the stream to the speaker.
+func (p *Player) trackPosition(tp *trackPipeline) beep.Streamer {
+	if _, ok := tp.stream.(*positionTracker); !ok {
+		tp.syncPosition()
+		tp.serial = pipelineSerial.Add(1)
+		if p.gainLookup != nil {
+			file, _, _ := splitRange(tp.src)
+			tp.gain.Store(math.Float64bits(p.gainLookup(file)))
+		}
+		tp.stream = &positionTracker{Streamer: tp.stream, tp: tp, emit: p.emit, gain: &p.trackGain}
+	}
+	return tp.stream
+}
@@ -0,0 +1,85 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+type pcmCloser struct{ *pcm }
+
+func (pcmCloser) Close() error { return nil }
+
+func TestPositionWithoutSpeakerLock(t *testing.T) {
+	src := &pcm{data: make([][2]float64, 3000)}
+	tp := &trackPipeline{decoder: pcmCloser{src}, stream: src, format: beep.Format{SampleRate: 1000}}
+	p := &Player{current: tp, events: make(chan Event, eventBuffer)}
+
+	s := p.trackPosition(tp)
+	if p.trackPosition(tp) != s {
+		t.Fatal("trackPosition wrapped the stream twice")
+	}
+	if got := p.Duration(); got != 3*time.Second {
+		t.Fatalf("Duration = %v, want 3s", got)
+	}
+	s.Stream(make([][2]float64, 500))
+	if got := p.Position(); got != 500*time.Millisecond {
+		t.Fatalf("Position = %v after 500 frames, want 500ms", got)
+	}
+
+	tp.streamOffset = time.Minute
+	if got := p.Position(); got != time.Minute+500*time.Millisecond {
+		t.Fatalf("Position = %v with a stream offset, want 1m0.5s", got)
+	}
+}
+
+func TestTrackEvents(t *testing.T) {
+	p := &Player{events: make(chan Event, eventBuffer)}
+	g := &gaplessStreamer{onDrain: func(s beep.Streamer) { p.emitFor(EventDrained, s) }}
+	src := &pcm{data: make([][2]float64, 2500)}
+	tp := &trackPipeline{decoder: pcmCloser{src}, stream: src, format: beep.Format{SampleRate: 1000}}
+	g.Replace(p.trackPosition(tp))
+
+	buf := make([][2]float64, 600)
+	for range 5 {
+		g.Stream(buf)
+	}
+	var kinds []EventKind
+	for len(p.events) > 0 {
+		e := <-p.events
+		if e.Track != tp.serial {
+			t.Fatalf("event %+v is for track %d, want %d", e, e.Track, tp.serial)
+		}
+		kinds = append(kinds, e.Kind)
+	}
+	want := []EventKind{EventTrackStarted, EventPosition, EventPosition, EventDrained}
+	if len(kinds) != len(want) {
+		t.Fatalf("events = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("events = %v, want %v", kinds, want)
+		}
+	}
+}
+
+func TestTrackGainAppliedAtStart(t *testing.T) {
+	p := &Player{events: make(chan Event, eventBuffer)}
+	p.SetTrackGainLookup(func(path string) float64 {
+		if path == "quiet.mp3" {
+			return 4
+		}
+		return 0
+	})
+	src := &pcm{data: make([][2]float64, 100)}
+	tp := &trackPipeline{decoder: pcmCloser{src}, stream: src, format: beep.Format{SampleRate: 1000}, src: "quiet.mp3"}
+	s := p.trackPosition(tp)
+	if p.TrackGain() != 0 {
+		t.Fatal("gain applied before the track started")
+	}
+	s.Stream(make([][2]float64, 10))
+	if got := p.TrackGain(); got != 4 {
+		t.Fatalf("gain after start = %v, want 4", got)
+	}
+}
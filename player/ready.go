@@ -0,0 +1,104 @@
+package player
+
+import (
+	"time"
+
+	"github.com/gopxl/beep/v2/speaker"
+)
+
+// readySlots is how many idle pipelines Prepare keeps open. Two covers the
+// previous track plus one leftover from an invalidated preload.
+const readySlots = 2
+
+// openPipeline builds a pipeline for path with its duration hint and
+// silence trimming applied, ready to hand to playPipeline or preloadPipeline.
+// A path carrying a time range plays only that part of the file, untrimmed.
+func (p *Player) openPipeline(path string, knownDuration time.Duration) (*trackPipeline, error) {
+	file, start, end := splitRange(path)
+	tp, err := p.buildPipeline(file)
+	if err != nil {
+		return nil, err
+	}
+	tp.setKnownDuration(knownDuration)
+	if p.DSPBypass() {
+		tp.probeNativeRate(file)
+	}
+	if start > 0 || end > 0 {
+		clipRange(tp, start, end)
+	} else {
+		p.trimSilence(file, tp)
+	}
+	tp.src = path
+	return tp, nil
+}
+
+// Prepare opens and decodes a local file in advance without queueing it,
+// so that a later Play or Preload of the same path starts immediately.
+// Streams are not prepared: holding an idle connection open would stall
+// or time out. It is a no-op when path is already prepared or preloaded.
+func (p *Player) Prepare(path string, knownDuration time.Duration) error {
+	if isURL(path) || isCustomURI(path) || isStdin(path) {
+		return nil
+	}
+	p.mu.Lock()
+	have := p.nextPipeline != nil && p.nextPipeline.src == path
+	for _, tp := range p.ready {
+		have = have || tp.src == path
+	}
+	p.mu.Unlock()
+	if have {
+		return nil
+	}
+
+	tp, err := p.openPipeline(path, knownDuration)
+	if err != nil {
+		return err
+	}
+	p.stashReady(tp)
+	return nil
+}
+
+// stashReady keeps an unread pipeline for reuse, closing the oldest one
+// when all slots are taken.
+func (p *Player) stashReady(tp *trackPipeline) {
+	p.mu.Lock()
+	p.ready = append(p.ready, tp)
+	var evicted *trackPipeline
+	if len(p.ready) > readySlots {
+		evicted = p.ready[0]
+		p.ready = p.ready[1:]
+	}
+	p.mu.Unlock()
+	closePipelines(evicted)
+}
+
+// takeReady returns an unread pipeline for path and releases it to the
+// caller, or nil when none is available. The queued gapless preload is
+// taken first, unless the audio thread has already started playing it.
+func (p *Player) takeReady(path string) *trackPipeline {
+	speaker.Lock()
+	defer speaker.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if next := p.nextPipeline; next != nil && next.src == path && p.gapless.TakeNext(next.stream) {
+		p.nextPipeline = nil
+		return next
+	}
+	for i, tp := range p.ready {
+		if tp.src == path {
+			p.ready = append(p.ready[:i:i], p.ready[i+1:]...)
+			return tp
+		}
+	}
+	return nil
+}
+
+// dropReady closes every prepared pipeline.
+func (p *Player) dropReady() {
+	p.mu.Lock()
+	rs := p.ready
+	p.ready = nil
+	p.mu.Unlock()
+	closePipelines(rs...)
+}
@@ -0,0 +1,74 @@
+package player
+
+import "testing"
+
+// closeCounter is a decoder that records being closed.
+type closeCounter struct {
+	pcm
+	closed bool
+}
+
+func (c *closeCounter) Close() error { c.closed = true; return nil }
+
+func readyPipeline(src string) (*trackPipeline, *closeCounter) {
+	d := &closeCounter{}
+	return &trackPipeline{decoder: d, stream: d, src: src}, d
+}
+
+func TestTakeReady(t *testing.T) {
+	p := &Player{gapless: &gaplessStreamer{}}
+
+	next, _ := readyPipeline("b.flac")
+	p.nextPipeline = next
+	p.gapless.SetNext(next.stream)
+	prev, _ := readyPipeline("a.flac")
+	p.stashReady(prev)
+
+	if tp := p.takeReady("c.flac"); tp != nil {
+		t.Fatalf("takeReady(c.flac) = %v, want nil", tp)
+	}
+	if tp := p.takeReady("a.flac"); tp != prev {
+		t.Fatal("prepared pipeline not returned")
+	}
+	if len(p.ready) != 0 {
+		t.Errorf("ready = %d pipelines after take, want 0", len(p.ready))
+	}
+	if tp := p.takeReady("b.flac"); tp != next {
+		t.Fatal("preloaded pipeline not returned")
+	}
+	if p.nextPipeline != nil || p.gapless.next != nil {
+		t.Error("preload still queued after take")
+	}
+}
+
+func TestTakeReadyAfterTransition(t *testing.T) {
+	p := &Player{gapless: &gaplessStreamer{}}
+	next, _ := readyPipeline("b.flac")
+	p.nextPipeline = next
+	// The audio thread already swapped to the preload; it must stay put.
+	p.gapless.current = next.stream
+
+	if tp := p.takeReady("b.flac"); tp != nil {
+		t.Fatal("took a preload that is already playing")
+	}
+	if p.nextPipeline != next {
+		t.Error("nextPipeline cleared")
+	}
+}
+
+func TestStashReadyEvictsOldest(t *testing.T) {
+	p := &Player{gapless: &gaplessStreamer{}}
+	var decs []*closeCounter
+	for _, src := range []string{"a.flac", "b.flac", "c.flac"} {
+		tp, d := readyPipeline(src)
+		decs = append(decs, d)
+		p.stashReady(tp)
+	}
+	if !decs[0].closed || decs[1].closed || decs[2].closed {
+		t.Errorf("closed = %v %v %v, want only the oldest", decs[0].closed, decs[1].closed, decs[2].closed)
+	}
+	p.dropReady()
+	if !decs[1].closed || !decs[2].closed || len(p.ready) != 0 {
+		t.Error("dropReady left pipelines open")
+	}
+}
@@ -0,0 +1,191 @@
+package player
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync/atomic"
+
+	"github.com/gopxl/beep/v2"
+	"github.com/gopxl/beep/v2/speaker"
+)
+
+// recordQueue is the number of sample blocks buffered between the audio
+// thread and the WAV writer goroutine before blocks start being dropped.
+const recordQueue = 64
+
+// recorder tees the processed output into a WAV file. It sits between the
+// fade envelope and the tap, so recordings include EQ, volume and effects.
+// The audio thread only copies samples into a preallocated block and hands it
+// to the writer goroutine; it never touches the file.
+type recorder struct {
+	s     beep.Streamer
+	w     atomic.Pointer[wavWriter]
+	cast  atomic.Pointer[castOutput]  // also feeds a Chromecast stream
+	bcast atomic.Pointer[broadcaster] // and an Icecast broadcast
+}
+
+func (r *recorder) Stream(samples [][2]float64) (int, bool) {
+	n, ok := r.s.Stream(samples)
+	if w := r.w.Load(); w != nil && n > 0 {
+		w.push(samples[:n])
+	}
+	if c := r.cast.Load(); c != nil && n > 0 {
+		c.push(samples[:n])
+	}
+	if b := r.bcast.Load(); b != nil && n > 0 {
+		b.push(samples[:n])
+	}
+	return n, ok
+}
+
+func (r *recorder) Err() error { return r.s.Err() }
+
+// wavWriter streams 16-bit stereo PCM to a WAV file, patching the RIFF
+// sizes in the header on close.
+type wavWriter struct {
+	pcmQueue // blocks, audio thread ⇄ writer
+
+	f      *os.File
+	bw     *bufio.Writer
+	sr     int
+	done   chan struct{}
+	frames int64
+	err    error
+
+	dropped atomic.Int64 // blocks lost because the writer fell behind
+}
+
+const wavHeaderSize = 44
+
+func newWAVWriter(path string, sr int) (*wavWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &wavWriter{
+		f:        f,
+		bw:       bufio.NewWriterSize(f, 64*1024),
+		sr:       sr,
+		pcmQueue: newPCMQueue(recordQueue),
+		done:     make(chan struct{}),
+	}
+	h := wavHeader(sr, 0)
+	if _, err := w.bw.Write(h[:]); err != nil {
+		f.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// push queues a block for the writer, counting it as dropped when the
+// writer has fallen behind. Called on the audio thread.
+func (w *wavWriter) push(samples [][2]float64) {
+	if !w.pcmQueue.push(samples) {
+		w.dropped.Add(1)
+	}
+}
+
+func toInt16(v float64) int16 {
+	v = max(-1, min(1, v))
+	return int16(math.Round(v * math.MaxInt16))
+}
+
+func (w *wavWriter) run() {
+	defer close(w.done)
+	for buf := range w.ch {
+		if w.err == nil {
+			_, w.err = w.bw.Write(buf)
+			w.frames += int64(len(buf) / 4)
+		}
+		w.recycle(buf)
+	}
+}
+
+// wavHeader builds a canonical 44-byte header for 16-bit stereo PCM.
+func wavHeader(sr int, dataBytes uint32) [wavHeaderSize]byte {
+	var h [wavHeaderSize]byte
+	copy(h[0:], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:], 36+dataBytes)
+	copy(h[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(h[16:], 16)           // fmt chunk size
+	binary.LittleEndian.PutUint16(h[20:], 1)            // PCM
+	binary.LittleEndian.PutUint16(h[22:], 2)            // channels
+	binary.LittleEndian.PutUint32(h[24:], uint32(sr))   // sample rate
+	binary.LittleEndian.PutUint32(h[28:], uint32(sr*4)) // byte rate
+	binary.LittleEndian.PutUint16(h[32:], 4)            // block align
+	binary.LittleEndian.PutUint16(h[34:], 16)           // bits per sample
+	copy(h[36:], "data")
+	binary.LittleEndian.PutUint32(h[40:], dataBytes)
+	return h
+}
+
+// close drains the queue, finalizes the header and closes the file. The
+// caller must make sure push is no longer being called.
+func (w *wavWriter) close() error {
+	close(w.ch)
+	<-w.done
+	err := w.err
+	if ferr := w.bw.Flush(); err == nil {
+		err = ferr
+	}
+	size := min(w.frames*4, math.MaxUint32-36)
+	h := wavHeader(w.sr, uint32(size))
+	if _, herr := w.f.WriteAt(h[:], 0); err == nil {
+		err = herr
+	}
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// StartRecording begins writing the processed output to a 16-bit WAV file
+// at path. Any recording already in progress is finished first.
+func (p *Player) StartRecording(path string) error {
+	if err := p.StopRecording(); err != nil {
+		return err
+	}
+	w, err := newWAVWriter(path, int(p.sr))
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	p.rec.w.Store(w)
+	return nil
+}
+
+// StopRecording finishes the current recording, if any, and reports any
+// write error. It also reports an error if blocks were dropped because the
+// disk could not keep up.
+func (p *Player) StopRecording() error {
+	// Detach under the speaker lock so the audio thread is guaranteed to be
+	// done with the writer before it is closed.
+	w := p.detachRecorder()
+	if w == nil {
+		return nil
+	}
+	if err := w.close(); err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	if n := w.dropped.Load(); n > 0 {
+		return fmt.Errorf("record: %w (%d blocks)", errRecordDropped, n)
+	}
+	return nil
+}
+
+func (p *Player) detachRecorder() *wavWriter {
+	speaker.Lock()
+	defer speaker.Unlock()
+	return p.rec.w.Swap(nil)
+}
+
+var errRecordDropped = errors.New("output dropped while the disk was busy")
+
+// Recording reports whether the output is being written to a file.
+func (p *Player) Recording() bool {
+	return p.rec.w.Load() != nil
+}
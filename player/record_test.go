@@ -0,0 +1,64 @@
+package player
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWAVWriterFinalizesHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	w, err := newWAVWriter(path, 48000)
+	if err != nil {
+		t.Fatalf("newWAVWriter: %v", err)
+	}
+	w.push([][2]float64{{0, 0}, {1, -1}, {2, -2}})
+	if err := w.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if len(data) != wavHeaderSize+3*4 {
+		t.Fatalf("file size = %d, want %d", len(data), wavHeaderSize+3*4)
+	}
+	if got := binary.LittleEndian.Uint32(data[24:]); got != 48000 {
+		t.Fatalf("sample rate = %d, want 48000", got)
+	}
+	if got := binary.LittleEndian.Uint32(data[40:]); got != 12 {
+		t.Fatalf("data size = %d, want 12", got)
+	}
+	if got := binary.LittleEndian.Uint32(data[4:]); got != 36+12 {
+		t.Fatalf("RIFF size = %d, want 48", got)
+	}
+	// Out-of-range samples are clipped to full scale.
+	if l, r := int16(binary.LittleEndian.Uint16(data[52:])), int16(binary.LittleEndian.Uint16(data[54:])); l != 32767 || r != -32767 {
+		t.Fatalf("clipped frame = (%d, %d), want (32767, -32767)", l, r)
+	}
+}
+
+func TestWAVWriterSplitsLargeBlocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	w, err := newWAVWriter(path, 44100)
+	if err != nil {
+		t.Fatalf("newWAVWriter: %v", err)
+	}
+	frames := 3*pcmBlockFrames + 7
+	w.push(make([][2]float64, frames))
+	if err := w.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if n := w.dropped.Load(); n != 0 {
+		t.Fatalf("dropped = %d, want 0", n)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(wavHeaderSize + frames*4); info.Size() != want {
+		t.Fatalf("file size = %d, want %d", info.Size(), want)
+	}
+}
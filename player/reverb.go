@@ -0,0 +1,167 @@
+package player
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// ReverbPreset selects one of the built-in room models.
+type ReverbPreset int
+
+const (
+	ReverbRoom  ReverbPreset = iota // small room, short and dry
+	ReverbHall                      // large hall, long and dark
+	ReverbPlate                     // bright plate, dense early tail
+	numReverbPresets
+)
+
+var reverbParams = [numReverbPresets]struct {
+	name     string
+	feedback float64 // comb feedback: longer tail as it approaches 1
+	damp     float64 // high-frequency damping inside the combs (0–1)
+	wet      float64 // wet mix level
+}{
+	{"room", 0.70, 0.40, 0.25},
+	{"hall", 0.84, 0.30, 0.40},
+	{"plate", 0.78, 0.10, 0.33},
+}
+
+// String returns the preset's config name.
+func (r ReverbPreset) String() string {
+	if r < 0 || r >= numReverbPresets {
+		return reverbParams[ReverbRoom].name
+	}
+	return reverbParams[r].name
+}
+
+// Next returns the following preset, wrapping around.
+func (r ReverbPreset) Next() ReverbPreset {
+	return (r + 1) % numReverbPresets
+}
+
+// ParseReverbPreset maps a config name to a preset. Unknown names fall back
+// to ReverbRoom.
+func ParseReverbPreset(name string) ReverbPreset {
+	for i, p := range reverbParams {
+		if strings.EqualFold(p.name, name) {
+			return ReverbPreset(i)
+		}
+	}
+	return ReverbRoom
+}
+
+// Delay line lengths in samples at 44.1 kHz, from Jezar's Freeverb. The right
+// channel uses slightly longer lines to decorrelate the two tails.
+var (
+	reverbCombTuning    = [4]int{1116, 1188, 1277, 1356}
+	reverbAllpassTuning = [2]int{556, 441}
+)
+
+const (
+	reverbStereoSpread = 23
+	reverbInputGain    = 0.015 // Freeverb's fixed gain into the comb bank
+	reverbWetScale     = 3     // Freeverb's wet scaling
+)
+
+// comb is a feedback comb filter with a one-pole lowpass in the loop.
+type comb struct {
+	buf   []float64
+	pos   int
+	store float64
+}
+
+func (c *comb) process(x, feedback, damp float64) float64 {
+	y := c.buf[c.pos]
+	c.store = y*(1-damp) + c.store*damp
+	c.buf[c.pos] = x + c.store*feedback
+	c.pos = (c.pos + 1) % len(c.buf)
+	return y
+}
+
+// allpass is a Schroeder allpass diffuser with fixed 0.5 feedback.
+type allpass struct {
+	buf []float64
+	pos int
+}
+
+func (a *allpass) process(x float64) float64 {
+	b := a.buf[a.pos]
+	a.buf[a.pos] = x + b*0.5
+	a.pos = (a.pos + 1) % len(a.buf)
+	return b - x
+}
+
+// reverb is a Schroeder/Freeverb-style reverb: four parallel combs feeding
+// two series allpasses per channel. All delay lines are allocated up front,
+// so switching presets on the fly only changes coefficients.
+type reverb struct {
+	enabled *atomic.Bool
+	preset  *atomic.Int32
+
+	combs      [2][4]comb
+	allpasses  [2][2]allpass
+	wasEnabled bool
+}
+
+func newReverb(enabled *atomic.Bool, preset *atomic.Int32, sr float64) *reverb {
+	r := &reverb{enabled: enabled, preset: preset}
+	scale := sr / 44100
+	for ch := range 2 {
+		spread := ch * reverbStereoSpread
+		for i, n := range reverbCombTuning {
+			r.combs[ch][i].buf = make([]float64, max(1, int(float64(n+spread)*scale)))
+		}
+		for i, n := range reverbAllpassTuning {
+			r.allpasses[ch][i].buf = make([]float64, max(1, int(float64(n+spread)*scale)))
+		}
+	}
+	return r
+}
+
+func (r *reverb) Name() string { return EffectReverb }
+
+// reset silences the delay lines so a re-enabled reverb doesn't replay an
+// old tail.
+func (r *reverb) reset() {
+	for ch := range 2 {
+		for i := range r.combs[ch] {
+			clear(r.combs[ch][i].buf)
+			r.combs[ch][i].store = 0
+		}
+		for i := range r.allpasses[ch] {
+			clear(r.allpasses[ch][i].buf)
+		}
+	}
+}
+
+func (r *reverb) Process(samples [][2]float64) {
+	if !r.enabled.Load() {
+		r.wasEnabled = false
+		return
+	}
+	if !r.wasEnabled {
+		r.reset()
+		r.wasEnabled = true
+	}
+	p := ReverbPreset(r.preset.Load())
+	if p < 0 || p >= numReverbPresets {
+		p = ReverbRoom
+	}
+	params := reverbParams[p]
+	wet := params.wet * reverbWetScale
+	dry := 1 - params.wet/2
+
+	for i := range samples {
+		in := (samples[i][0] + samples[i][1]) * reverbInputGain
+		for ch := range 2 {
+			out := 0.0
+			for c := range r.combs[ch] {
+				out += r.combs[ch][c].process(in, params.feedback, params.damp)
+			}
+			for a := range r.allpasses[ch] {
+				out = r.allpasses[ch][a].process(out)
+			}
+			samples[i][ch] = samples[i][ch]*dry + out*wet
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReverbTailDecays(t *testing.T) {
+	const sr = 44100
+	for p := range numReverbPresets {
+		var on atomic.Bool
+		on.Store(true)
+		var preset atomic.Int32
+		preset.Store(int32(p))
+		r := newReverb(&on, &preset, sr)
+
+		// An impulse, then eight seconds of silence in 512-sample blocks.
+		buf := make([][2]float64, 512)
+		buf[0] = [2]float64{1, 1}
+		var first, last float64
+		for i := 0; i < 8*sr; i += len(buf) {
+			r.Process(buf)
+			var energy float64
+			for _, s := range buf {
+				if math.IsNaN(s[0]) || math.IsInf(s[0], 0) || math.IsNaN(s[1]) || math.IsInf(s[1], 0) {
+					t.Fatalf("%s: sample at %d is %v", ReverbPreset(p), i, s)
+				}
+				energy += s[0]*s[0] + s[1]*s[1]
+			}
+			switch {
+			case i < sr/2:
+				first += energy
+			case i >= 15*sr/2:
+				last += energy
+			}
+			clear(buf)
+		}
+		if last > first*1e-6 {
+			t.Errorf("%s: tail energy %.3g in the last half second, want it well under the first's %.3g", ReverbPreset(p), last, first)
+		}
+	}
+}
+
+func TestReverbBypassedWhenDisabled(t *testing.T) {
+	var on atomic.Bool
+	var preset atomic.Int32
+	preset.Store(int32(ReverbHall))
+	r := newReverb(&on, &preset, 44100)
+
+	buf := [][2]float64{{0.5, -0.5}, {0.25, 0.1}, {-1, 1}}
+	want := append([][2]float64(nil), buf...)
+	r.Process(buf)
+	for i := range buf {
+		if buf[i] != want[i] {
+			t.Errorf("sample %d = %v, want it unchanged at %v", i, buf[i], want[i])
+		}
+	}
+}
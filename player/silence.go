@@ -0,0 +1,156 @@
+package player
+
+import (
+	"math"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// Defaults for silence trimming.
+const (
+	defaultSilenceThresholdDB = -60.0
+	defaultSilenceMin         = 1500 * time.Millisecond
+
+	// maxLeadingSkip bounds how much leading silence is dropped, so a
+	// track that is silent throughout (or very quiet) still plays.
+	maxLeadingSkip = 10 * time.Second
+	// trailingWindow is how close to the end a silent run must be before it
+	// ends the track. Quiet passages earlier in the track are never cut.
+	trailingWindow = 30 * time.Second
+)
+
+// silenceTrim wraps a local track's stream and skips trailing silence: near
+// the end of the track, a run of silence longer than minRun ends the track
+// early, so the gapless streamer moves straight to the next one. Leading
+// silence is skipped before playback starts, by skipLeadingSilence.
+//
+// Position and length are read from the raw decoder, which runs at the
+// source rate; the stream itself may be resampled to the output rate.
+type silenceTrim struct {
+	s         beep.Streamer
+	dec       beep.StreamSeeker
+	srcRate   beep.SampleRate
+	threshold float64 // linear amplitude
+	minRun    int     // output frames of silence that end the track
+
+	run   int
+	ended bool
+	endAt int // decoder position when the track was ended
+}
+
+func newSilenceTrim(s beep.Streamer, dec beep.StreamSeeker, srcRate, outRate beep.SampleRate, thresholdDB float64, minDur time.Duration) *silenceTrim {
+	return &silenceTrim{
+		s:         s,
+		dec:       dec,
+		srcRate:   srcRate,
+		threshold: math.Pow(10, thresholdDB/20),
+		minRun:    max(1, outRate.N(minDur)),
+	}
+}
+
+func (t *silenceTrim) Stream(samples [][2]float64) (int, bool) {
+	if t.ended && t.dec.Position() == t.endAt {
+		return 0, false
+	}
+	if t.ended {
+		// Seeked since, for instance right after priming the declick ran
+		// into the silence that ended the track: play on from there.
+		t.ended, t.run = false, 0
+	}
+	n, ok := t.s.Stream(samples)
+	for i := range n {
+		if !t.silent(samples[i]) {
+			t.run = 0
+			continue
+		}
+		t.run++
+		if t.run >= t.minRun && t.nearEnd() {
+			t.ended, t.endAt = true, t.dec.Position()
+			return i + 1, true
+		}
+	}
+	return n, ok
+}
+
+// skipLeadingSilence moves a decoder that is at the start of its track past
+// up to maxLeadingSkip of silence, leaving it on the first audible frame.
+// It decodes ahead, so it runs while the pipeline is opened rather than on
+// the audio thread. A track that ends inside the silence is left at the
+// start.
+func skipLeadingSilence(dec beep.StreamSeeker, sr beep.SampleRate, threshold float64) {
+	if dec.Position() != 0 {
+		return
+	}
+	buf := make([][2]float64, 4096)
+	limit := sr.N(maxLeadingSkip)
+	pos := 0
+	for pos < limit {
+		n, ok := dec.Stream(buf[:min(len(buf), limit-pos)])
+		for i, s := range buf[:n] {
+			if !silentFrame(s, threshold) {
+				dec.Seek(pos + i)
+				return
+			}
+		}
+		pos += n
+		if !ok || n == 0 {
+			dec.Seek(0)
+			return
+		}
+	}
+	dec.Seek(pos)
+}
+
+func silentFrame(s [2]float64, threshold float64) bool {
+	return math.Abs(s[0]) < threshold && math.Abs(s[1]) < threshold
+}
+
+func (t *silenceTrim) silent(s [2]float64) bool { return silentFrame(s, t.threshold) }
+
+// nearEnd reports whether the decoder is within trailingWindow of the end.
+// Tracks of unknown length are never cut.
+func (t *silenceTrim) nearEnd() bool {
+	total := t.dec.Len()
+	if total <= 0 {
+		return false
+	}
+	return total-t.dec.Position() <= t.srcRate.N(trailingWindow)
+}
+
+func (t *silenceTrim) Err() error { return t.s.Err() }
+
+// SetSkipSilence enables trimming of leading and trailing silence on local
+// files. It applies to tracks opened after the call.
+func (p *Player) SetSkipSilence(on bool) {
+	p.skipSilence.Store(on)
+}
+
+// SkipSilence reports whether silence trimming is enabled.
+func (p *Player) SkipSilence() bool {
+	return p.skipSilence.Load()
+}
+
+// SetSilenceThreshold sets the level in dBFS below which audio counts as
+// silence, clamped to [-90, -20].
+func (p *Player) SetSilenceThreshold(dB float64) {
+	p.silenceDB.Store(math.Float64bits(max(-90, min(-20, dB))))
+}
+
+// SetSilenceMinDuration sets how long trailing silence must last before
+// the track is ended early.
+func (p *Player) SetSilenceMinDuration(d time.Duration) {
+	p.silenceMin.Store(int64(max(0, d)))
+}
+
+// trimSilence skips a freshly built local pipeline's leading silence and
+// wraps it with silenceTrim when silence skipping is enabled.
+func (p *Player) trimSilence(path string, tp *trackPipeline) {
+	if !p.skipSilence.Load() || isURL(path) || isCustomURI(path) || !tp.seekable {
+		return
+	}
+	db := math.Float64frombits(p.silenceDB.Load())
+	skipLeadingSilence(tp.decoder, tp.format.SampleRate, math.Pow(10, db/20))
+	tp.stream = newSilenceTrim(tp.stream, tp.decoder, tp.format.SampleRate, p.sr,
+		db, time.Duration(p.silenceMin.Load()))
+}
@@ -0,0 +1,128 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// pcm is an in-memory StreamSeeker over a fixed sample slice.
+type pcm struct {
+	data [][2]float64
+	pos  int
+}
+
+func (s *pcm) Stream(samples [][2]float64) (int, bool) {
+	if s.pos >= len(s.data) {
+		return 0, false
+	}
+	n := copy(samples, s.data[s.pos:])
+	s.pos += n
+	return n, true
+}
+
+func (s *pcm) Err() error       { return nil }
+func (s *pcm) Len() int         { return len(s.data) }
+func (s *pcm) Position() int    { return s.pos }
+func (s *pcm) Seek(p int) error { s.pos = p; return nil }
+
+// drain reads s to the end in blocks and returns what it produced.
+func drain(s beep.Streamer, block int) [][2]float64 {
+	var out [][2]float64
+	buf := make([][2]float64, block)
+	for {
+		n, ok := s.Stream(buf)
+		out = append(out, buf[:n]...)
+		if !ok || n < block {
+			return out
+		}
+	}
+}
+
+func TestSilenceTrim(t *testing.T) {
+	const sr = beep.SampleRate(1000)
+	var data [][2]float64
+	data = append(data, make([][2]float64, 300)...) // 0.3 s leading silence
+	for range 500 {
+		data = append(data, [2]float64{0.5, -0.5})
+	}
+	data = append(data, make([][2]float64, 2000)...) // 2 s trailing silence
+
+	src := &pcm{data: data}
+	skipLeadingSilence(src, sr, 0.001)
+	if src.pos != 300 {
+		t.Fatalf("decoder at %d after skipping leading silence, want 300", src.pos)
+	}
+	trim := newSilenceTrim(src, src, sr, sr, -60, 500*time.Millisecond)
+	out := drain(trim, 128)
+
+	if out[0][0] != 0.5 {
+		t.Fatalf("first sample = %v, want audio (leading silence kept)", out[0])
+	}
+	if want := 500 + 500; len(out) != want {
+		t.Fatalf("got %d samples, want %d (audio + minimum trailing run)", len(out), want)
+	}
+}
+
+func TestSilenceTrimResumesAfterSeek(t *testing.T) {
+	const sr = beep.SampleRate(1000)
+	data := make([][2]float64, 3000)
+	for i := range 1000 {
+		data[i] = [2]float64{0.5, 0.5}
+	}
+
+	src := &pcm{data: data}
+	trim := newSilenceTrim(src, src, sr, sr, -60, 500*time.Millisecond)
+	drain(trim, 128)
+	if n, ok := trim.Stream(make([][2]float64, 10)); n != 0 || ok {
+		t.Fatalf("Stream after the trailing silence = (%d, %v), want the track ended", n, ok)
+	}
+	src.Seek(500)
+	if out := drain(trim, 128); len(out) != 500+500 {
+		t.Fatalf("got %d samples after seeking back, want %d", len(out), 500+500)
+	}
+}
+
+func TestSilenceTrimKeepsQuietPassages(t *testing.T) {
+	const sr = beep.SampleRate(1000)
+	var data [][2]float64
+	for range 100 {
+		data = append(data, [2]float64{0.5, 0.5})
+	}
+	data = append(data, make([][2]float64, 2000)...) // long gap mid-track
+	for range 40000 {
+		data = append(data, [2]float64{0.5, 0.5})
+	}
+
+	src := &pcm{data: data}
+	out := drain(newSilenceTrim(src, src, sr, sr, -60, 500*time.Millisecond), 256)
+	if len(out) != len(data) {
+		t.Fatalf("got %d samples, want %d (gap far from the end must not cut)", len(out), len(data))
+	}
+}
+
+func TestSkipLeadingSilenceBounds(t *testing.T) {
+	const sr = beep.SampleRate(1000)
+
+	// Longer than maxLeadingSkip: only that much is skipped.
+	long := &pcm{data: append(make([][2]float64, sr.N(maxLeadingSkip)+500), [2]float64{0.5, 0.5})}
+	skipLeadingSilence(long, sr, 0.001)
+	if want := sr.N(maxLeadingSkip); long.pos != want {
+		t.Errorf("decoder at %d, want %d", long.pos, want)
+	}
+
+	// Silent throughout: it plays from the start.
+	silent := &pcm{data: make([][2]float64, 2000)}
+	skipLeadingSilence(silent, sr, 0.001)
+	if silent.pos != 0 {
+		t.Errorf("silent track's decoder at %d, want 0", silent.pos)
+	}
+
+	// Opened part-way through (a resume): left alone.
+	resumed := &pcm{data: make([][2]float64, 2000), pos: 100}
+	skipLeadingSilence(resumed, sr, 0.001)
+	if resumed.pos != 100 {
+		t.Errorf("resumed decoder at %d, want 100", resumed.pos)
+	}
+}
@@ -0,0 +1,163 @@
+package player
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+	"github.com/gopxl/beep/v2/flac"
+	"github.com/gopxl/beep/v2/mp3"
+	"github.com/gopxl/beep/v2/vorbis"
+	"github.com/gopxl/beep/v2/wav"
+)
+
+// stdinBufSize is the read-ahead buffer in front of stdin. A pipe only
+// holds 64 KiB, so this absorbs bursts from a producer like ffmpeg that
+// writes faster than real time and smooths over short stalls.
+const stdinBufSize = 1 << 20
+
+// stdinSniffSize is how many leading bytes are peeked to detect the format.
+const stdinSniffSize = 64
+
+// stdin is the reader "-" plays from. Replaced in tests.
+var stdin io.Reader = os.Stdin
+
+// isStdin reports whether path names standard input ("-").
+func isStdin(path string) bool {
+	return path == "-"
+}
+
+// sniffFormat guesses the container of a stream from its first bytes and
+// returns the matching extension. Formats the native decoders cannot handle
+// (Opus in Ogg, float WAV, MP4, unknown data) return "" and go to ffmpeg.
+func sniffFormat(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")):
+		return ".mp3"
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return ".flac"
+	case bytes.HasPrefix(head, []byte("OggS")):
+		if bytes.Contains(head, []byte("\x01vorbis")) {
+			return ".ogg"
+		}
+		return ""
+	case len(head) >= 22 && bytes.HasPrefix(head, []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WAVE")):
+		// Only integer PCM (format tag 1) decodes natively; the fmt chunk
+		// is assumed to come first, as every common encoder writes it.
+		if bytes.Equal(head[12:16], []byte("fmt ")) && binary.LittleEndian.Uint16(head[20:22]) == 1 {
+			return ".wav"
+		}
+		return ""
+	case len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0 && head[1]&0x06 != 0:
+		// MPEG audio frame sync with a non-reserved layer. ADTS AAC uses
+		// the same sync word with layer 00 and falls through to ffmpeg.
+		return ".mp3"
+	}
+	return ""
+}
+
+// buildStdinPipeline decodes audio piped into standard input. The input is
+// read once front to back, so the pipeline is never seekable and has no
+// known duration; the UI shows it like a live stream.
+func (p *Player) buildStdinPipeline() (*trackPipeline, error) {
+	br := bufio.NewReaderSize(stdin, stdinBufSize)
+	// Peek blocks until the producer has written something, and returns
+	// what it has with io.EOF for inputs shorter than the sniff window.
+	head, err := br.Peek(stdinSniffSize)
+	if len(head) == 0 {
+		if err == nil || err == io.EOF {
+			err = fmt.Errorf("no audio on stdin")
+		}
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+
+	ext := sniffFormat(head)
+	if ext == "" {
+		decoder, format, err := decodeFFmpegReader(br, p.sr, p.bitDepth)
+		if err != nil {
+			return nil, fmt.Errorf("decode stdin: %w", err)
+		}
+		return &trackPipeline{
+			decoder:    decoder,
+			stream:     decoder, // outputs at target sample rate
+			format:     format,
+			path:       "-",
+			ffmpegRate: true,
+		}, nil
+	}
+
+	rc := io.NopCloser(br)
+	var (
+		decoder beep.StreamSeekCloser
+		format  beep.Format
+	)
+	switch ext {
+	case ".flac":
+		decoder, format, err = flac.Decode(rc)
+	case ".ogg":
+		decoder, format, err = vorbis.Decode(rc)
+	case ".wav":
+		decoder, format, err = wav.Decode(rc)
+	default:
+		decoder, format, err = mp3.Decode(rc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode stdin: %w", err)
+	}
+
+	var s beep.Streamer = decoder
+	if format.SampleRate != p.sr {
+		s = beep.Resample(p.resampleQuality, format.SampleRate, p.sr, s)
+	}
+	return &trackPipeline{
+		decoder: decoder,
+		stream:  s,
+		format:  format,
+		path:    "-",
+	}, nil
+}
+
+// decodeFFmpegReader starts ffmpeg reading encoded audio from r and streams
+// PCM from its stdout. Like decodeFFmpegStream it never seeks.
+func decodeFFmpegReader(r io.Reader, sr beep.SampleRate, bitDepth int) (*ffmpegPipeStreamer, beep.Format, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, beep.Format{}, fmt.Errorf("ffmpeg is required to play this format from stdin — install it with your package manager")
+	}
+
+	pcmFmt, codec, precision := ffmpegPCMArgs(bitDepth)
+	cmd := exec.Command("ffmpeg",
+		"-i", "pipe:0",
+		"-f", pcmFmt,
+		"-acodec", codec,
+		"-ar", strconv.Itoa(int(sr)),
+		"-ac", "2",
+		"-loglevel", "error",
+		"pipe:1",
+	)
+	cmd.Stdin = r
+	// The copy goroutine may sit in a read on stdin after ffmpeg is
+	// killed; don't let Close wait on a producer that never writes again.
+	cmd.WaitDelay = time.Second
+
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, beep.Format{}, fmt.Errorf("ffmpeg stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, beep.Format{}, fmt.Errorf("ffmpeg start: %w", err)
+	}
+
+	format := beep.Format{
+		SampleRate:  sr,
+		NumChannels: 2,
+		Precision:   precision,
+	}
+	return &ffmpegPipeStreamer{ffmpegPipe: ffmpegPipe{cmd: cmd, reader: bufio.NewReaderSize(pipe, pipeBufSize), pipe: pipe, f32: bitDepth == 32}}, format, nil
+}
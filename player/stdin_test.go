@@ -0,0 +1,27 @@
+package player
+
+import "testing"
+
+func TestSniffFormat(t *testing.T) {
+	pcmWAV := []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x02\x00")
+	floatWAV := []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x03\x00\x02\x00")
+	for _, tc := range []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"id3", []byte("ID3\x04\x00"), ".mp3"},
+		{"mpeg sync", []byte{0xFF, 0xFB, 0x90, 0x64}, ".mp3"},
+		{"adts aac", []byte{0xFF, 0xF1, 0x50, 0x80}, ""},
+		{"flac", []byte("fLaC\x00\x00\x00\x22"), ".flac"},
+		{"ogg vorbis", []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00\x01vorbis"), ".ogg"},
+		{"ogg opus", []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00OpusHead"), ""},
+		{"pcm wav", pcmWAV, ".wav"},
+		{"float wav", floatWAV, ""},
+		{"unknown", []byte("\x00\x00\x00\x20ftypM4A "), ""},
+	} {
+		if got := sniffFormat(tc.head); got != tc.want {
+			t.Errorf("%s: sniffFormat = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
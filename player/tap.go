@@ -3,14 +3,16 @@
 package player
 
 import (
+	"math"
 	"sync/atomic"
+	"time"
 
 	"github.com/gopxl/beep/v2"
 )
 
 // tap is a streamer wrapper that copies samples into a ring buffer
 // for real-time FFT visualization. It sits in the audio pipeline
-// between the volume control and the speaker controller.
+// between the DSP chain and the speaker controller.
 //
 // The write position is updated atomically, allowing the audio thread
 // (sole writer) and the UI thread (infrequent reader at 50ms intervals)
@@ -21,6 +23,11 @@ type tap struct {
 	buf  []float64
 	pos  atomic.Int64
 	size int
+
+	peak       atomic.Uint64 // highest |sample| of either channel since TakePeak, Float64bits
+	lastStream atomic.Int64  // unix nanos the last Stream() call returned (device watchdog)
+	inStream   atomic.Bool   // a Stream() call is running; its source may be blocked on the network
+	mute       atomic.Bool   // silence the speaker while the output is cast elsewhere
 }
 
 // newTap wraps a streamer with a ring buffer of the given size.
@@ -34,13 +41,26 @@ func newTap(s beep.Streamer, bufSize int) *tap {
 
 // Stream passes audio through while capturing a mono mix into the ring buffer.
 func (t *tap) Stream(samples [][2]float64) (int, bool) {
+	t.inStream.Store(true)
 	n, ok := t.s.Stream(samples)
+	t.lastStream.Store(time.Now().UnixNano())
+	t.inStream.Store(false)
 	p := int(t.pos.Load())
+	var peak float64
 	for i := range n {
 		t.buf[p] = (samples[i][0] + samples[i][1]) / 2
 		p = (p + 1) % t.size
+		peak = max(peak, math.Abs(samples[i][0]), math.Abs(samples[i][1]))
 	}
 	t.pos.Store(int64(p))
+	// A TakePeak racing this store only carries the old peak into the
+	// next reading.
+	if peak > math.Float64frombits(t.peak.Load()) {
+		t.peak.Store(math.Float64bits(peak))
+	}
+	if t.mute.Load() {
+		clear(samples[:n])
+	}
 	return n, ok
 }
 
@@ -49,6 +69,12 @@ func (t *tap) Err() error {
 	return t.s.Err()
 }
 
+// TakePeak returns the highest sample level of either channel, linear,
+// since the last call, and starts over.
+func (t *tap) TakePeak() float64 {
+	return math.Float64frombits(t.peak.Swap(0))
+}
+
 // SamplesInto copies the last len(dst) samples into dst, avoiding allocation.
 // Returns the number of samples written.
 func (t *tap) SamplesInto(dst []float64) int {
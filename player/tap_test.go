@@ -0,0 +1,72 @@
+package player
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+func BenchmarkTap(b *testing.B) {
+	t := newTap(beep.Silence(-1), 8192)
+	block := make([][2]float64, 512)
+	dst := make([]float64, 2048)
+	b.ReportAllocs()
+	for b.Loop() {
+		t.Stream(block)
+		t.SamplesInto(dst)
+	}
+}
+
+func TestTapPeak(t *testing.T) {
+	src := &pcm{data: [][2]float64{{0.1, -0.5}, {0.25, 0.2}}}
+	tp := newTap(src, 16)
+	tp.Stream(make([][2]float64, 2))
+	if got := tp.TakePeak(); got != 0.5 {
+		t.Fatalf("peak = %v, want 0.5 (the right channel)", got)
+	}
+	if got := tp.TakePeak(); got != 0 {
+		t.Fatalf("peak after taking it = %v, want 0", got)
+	}
+}
+
+// blockingSource stands in for a stream whose read stalls inside the
+// speaker callback.
+type blockingSource struct{ release chan struct{} }
+
+func (b blockingSource) Stream(samples [][2]float64) (int, bool) {
+	<-b.release
+	return len(samples), true
+}
+
+func (b blockingSource) Err() error { return nil }
+
+func TestOutputErrIgnoresStalledSource(t *testing.T) {
+	src := blockingSource{release: make(chan struct{})}
+	p := &Player{tap: newTap(src, 16)}
+	p.playing.Store(true)
+	// The last callback returned long ago, but one is running now.
+	p.tap.lastStream.Store(time.Now().Add(-time.Minute).UnixNano())
+	done := make(chan struct{})
+	go func() {
+		p.tap.Stream(make([][2]float64, 4))
+		close(done)
+	}()
+	for !p.tap.inStream.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	if err := p.OutputErr(); err != nil {
+		t.Fatalf("OutputErr while the source is blocked = %v, want nil", err)
+	}
+	close(src.release)
+	<-done
+	if err := p.OutputErr(); err != nil {
+		t.Fatalf("OutputErr right after a callback = %v, want nil", err)
+	}
+
+	p.tap.lastStream.Store(time.Now().Add(-time.Minute).UnixNano())
+	if err := p.OutputErr(); !errors.Is(err, ErrOutputLost) {
+		t.Fatalf("OutputErr after the driver stopped calling = %v, want ErrOutputLost", err)
+	}
+}
@@ -0,0 +1,216 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// TechInfo describes how an audio file is encoded.
+type TechInfo struct {
+	Codec      string // "MP3", "FLAC", "Opus", …; "" when unknown
+	Bitrate    int    // kbit/s, averaged over the file
+	VBR        bool   // the bitrate varies (MP3 with a Xing or VBRI header)
+	SampleRate int    // Hz, the file's native rate before any resampling
+	Channels   int
+	BitDepth   int   // bits per sample of lossless formats; 0 otherwise
+	Size       int64 // file size in bytes
+}
+
+// Known reports whether anything was found out about the file.
+func (t TechInfo) Known() bool {
+	return t.Codec != "" || t.SampleRate > 0
+}
+
+// Short renders the codec, depth, rate and bitrate compactly, as in
+// "FLAC 24/96 kHz 2304 kbps" or "MP3 VBR 245 kbps".
+func (t TechInfo) Short() string {
+	var parts []string
+	if t.Codec != "" {
+		parts = append(parts, t.Codec)
+	}
+	if t.VBR {
+		parts = append(parts, "VBR")
+	}
+	if t.SampleRate > 0 {
+		rate := kHz(t.SampleRate)
+		if t.BitDepth > 0 {
+			rate = strconv.Itoa(t.BitDepth) + "/" + rate
+		}
+		parts = append(parts, rate)
+	}
+	if t.Bitrate > 0 {
+		parts = append(parts, strconv.Itoa(t.Bitrate)+" kbps")
+	}
+	return strings.Join(parts, " ")
+}
+
+// kHz renders a sample rate as "44.1 kHz" or "48 kHz".
+func kHz(hz int) string {
+	return strconv.FormatFloat(float64(hz)/1000, 'f', -1, 64) + " kHz"
+}
+
+// nativeCodecs names the formats decoded without ffmpeg.
+var nativeCodecs = map[string]string{
+	".mp3":  "MP3",
+	".flac": "FLAC",
+	".wav":  "WAV",
+	".ogg":  "Vorbis",
+}
+
+// ProbeTechInfo reads the encoding details of a local audio file. Natively
+// decoded formats are read by their decoder, the others with ffprobe.
+// Fields that cannot be determined are left zero; URLs return nothing.
+// It may read the whole file, so call it off the UI goroutine.
+func ProbeTechInfo(path string) TechInfo {
+	if isURL(path) || isCustomURI(path) {
+		return TechInfo{}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return TechInfo{}
+	}
+	ext := formatExt(path)
+	codec, ok := nativeCodecs[ext]
+	if !ok {
+		t := probeFFmpegInfo(path)
+		t.Size = fi.Size()
+		return t
+	}
+
+	t := TechInfo{Codec: codec, Size: fi.Size()}
+	f, err := os.Open(path)
+	if err != nil {
+		return t
+	}
+	decoder, format, err := decodeWithExt(f, ext, path, 0, 16)
+	if err != nil {
+		f.Close()
+		// Formats the native decoder refuses (e.g. float WAV) play
+		// through ffmpeg, so ffprobe can describe them.
+		ft := probeFFmpegInfo(path)
+		ft.Size = fi.Size()
+		return ft
+	}
+	defer decoder.Close()
+	t.SampleRate = int(format.SampleRate)
+	t.Channels = format.NumChannels
+	if ext == ".flac" || ext == ".wav" {
+		t.BitDepth = format.Precision * 8
+	}
+
+	audioBytes := fi.Size()
+	dur := format.SampleRate.D(decoder.Len())
+	if ext == ".mp3" {
+		if v := readMP3VBR(path); v != nil {
+			t.VBR = !v.cbr
+			if d := v.duration(); d > 0 {
+				dur = d
+			}
+			audioBytes -= v.dataStart
+			if v.bytes > 0 {
+				audioBytes = v.bytes
+			}
+		}
+	}
+	if dur > 0 {
+		t.Bitrate = int(float64(audioBytes) * 8 / dur.Seconds() / 1000)
+	}
+	return t
+}
+
+// readMP3VBR returns the Xing/VBRI header of a local MP3 file, or nil.
+func readMP3VBR(path string) *mp3VBR {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	head := newMP3HeadTap(f)
+	defer head.Close()
+	buf := make([]byte, 32*1024)
+	for len(head.frame) < mp3HeadWindow {
+		if _, err := head.Read(buf); err != nil {
+			break
+		}
+	}
+	return head.vbr()
+}
+
+// probeFFmpegInfo describes path with ffprobe.
+func probeFFmpegInfo(path string) TechInfo {
+	out, err := exec.Command("ffprobe",
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=codec_name,sample_rate,channels,bits_per_raw_sample,bit_rate:format=bit_rate",
+		"-of", "json",
+		path,
+	).Output()
+	if err != nil {
+		return TechInfo{}
+	}
+	return parseFFprobeInfo(out)
+}
+
+// ffprobeCodecs spells out codec names ffprobe reports in lower case.
+var ffprobeCodecs = map[string]string{
+	"opus":   "Opus",
+	"vorbis": "Vorbis",
+	"wmav2":  "WMA",
+	"wmapro": "WMA Pro",
+}
+
+func parseFFprobeInfo(data []byte) TechInfo {
+	var probe struct {
+		Streams []struct {
+			CodecName  string `json:"codec_name"`
+			SampleRate string `json:"sample_rate"`
+			Channels   int    `json:"channels"`
+			BitsPerRaw string `json:"bits_per_raw_sample"`
+			BitRate    string `json:"bit_rate"`
+		} `json:"streams"`
+		Format struct {
+			BitRate string `json:"bit_rate"`
+		} `json:"format"`
+	}
+	if json.Unmarshal(data, &probe) != nil || len(probe.Streams) == 0 {
+		return TechInfo{}
+	}
+	s := probe.Streams[0]
+	t := TechInfo{Channels: s.Channels}
+	t.Codec = ffprobeCodecs[s.CodecName]
+	switch {
+	case t.Codec != "":
+	case strings.HasPrefix(s.CodecName, "pcm_"):
+		t.Codec = "PCM"
+	default:
+		t.Codec = strings.ToUpper(s.CodecName)
+	}
+	t.SampleRate, _ = strconv.Atoi(s.SampleRate)
+	if s.CodecName == "alac" || s.CodecName == "flac" || strings.HasPrefix(s.CodecName, "pcm_") {
+		t.BitDepth, _ = strconv.Atoi(s.BitsPerRaw)
+	}
+	// The stream bitrate is missing for some containers (Opus in Ogg or
+	// WebM); the container's overall rate is close enough.
+	br, err := strconv.Atoi(s.BitRate)
+	if err != nil {
+		br, _ = strconv.Atoi(probe.Format.BitRate)
+	}
+	t.Bitrate = br / 1000
+	return t
+}
+
+// Channels names a channel count: "mono", "stereo", or "6 channels".
+func Channels(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return "mono"
+	case 2:
+		return "stereo"
+	}
+	return fmt.Sprintf("%d channels", n)
+}
@@ -0,0 +1,48 @@
+package player
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopxl/beep/v2"
+	"github.com/gopxl/beep/v2/generators"
+	"github.com/gopxl/beep/v2/wav"
+)
+
+func TestProbeTechInfoWAV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tone.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	format := beep.Format{SampleRate: 44100, NumChannels: 2, Precision: 2}
+	if err := wav.Encode(f, beep.Take(44100, generators.Silence(-1)), format); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	got := ProbeTechInfo(path)
+	if got.Codec != "WAV" || got.SampleRate != 44100 || got.Channels != 2 || got.BitDepth != 16 {
+		t.Fatalf("ProbeTechInfo = %+v", got)
+	}
+	if want := "WAV 16/44.1 kHz 1411 kbps"; got.Short() != want {
+		t.Errorf("Short() = %q, want %q", got.Short(), want)
+	}
+}
+
+func TestParseFFprobeInfo(t *testing.T) {
+	got := parseFFprobeInfo([]byte(`{"streams":[{"codec_name":"opus","sample_rate":"48000","channels":2}],"format":{"bit_rate":"131072"}}`))
+	want := TechInfo{Codec: "Opus", SampleRate: 48000, Channels: 2, Bitrate: 131}
+	if got != want {
+		t.Fatalf("parseFFprobeInfo = %+v, want %+v", got, want)
+	}
+	if got.Short() != "Opus 48 kHz 131 kbps" {
+		t.Errorf("Short() = %q", got.Short())
+	}
+
+	alac := parseFFprobeInfo([]byte(`{"streams":[{"codec_name":"alac","sample_rate":"96000","channels":2,"bits_per_raw_sample":"24","bit_rate":"2304000"}]}`))
+	if alac.Short() != "ALAC 24/96 kHz 2304 kbps" {
+		t.Errorf("ALAC Short() = %q", alac.Short())
+	}
+}
@@ -0,0 +1,75 @@
+package player
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+// splitRange separates the "#t=start,end" annotation that Track.PlayPath
+// appends to a ranged local file, with both ends in seconds and either one
+// optional. Paths without a range, or whose annotation does not parse, are
+// returned unchanged with a zero range.
+func splitRange(path string) (string, time.Duration, time.Duration) {
+	i := strings.LastIndex(path, "#t=")
+	if i <= 0 || isURL(path) || isCustomURI(path) {
+		return path, 0, 0
+	}
+	from, to, _ := strings.Cut(path[i+3:], ",")
+	start, ok1 := rangeSeconds(from)
+	end, ok2 := rangeSeconds(to)
+	if !ok1 || !ok2 || (end > 0 && end <= start) {
+		return path, 0, 0
+	}
+	return path[:i], start, end
+}
+
+func rangeSeconds(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 || v > 1e6 {
+		return 0, false
+	}
+	return time.Duration(v * float64(time.Second)), true
+}
+
+// rangeEnd ends a stream once its decoder reaches end, in source frames,
+// so the gapless streamer moves on to the next track there.
+type rangeEnd struct {
+	beep.Streamer
+	dec beep.StreamSeeker
+	end int
+}
+
+func (r *rangeEnd) Stream(samples [][2]float64) (int, bool) {
+	left := r.end - r.dec.Position()
+	if left <= 0 {
+		return 0, false
+	}
+	// At the source rate, stop exactly on the end frame; a resampled
+	// stream reads ahead, so it stops within one buffer of it.
+	if len(samples) > left {
+		samples = samples[:left]
+	}
+	return r.Streamer.Stream(samples)
+}
+
+// clipRange limits a local pipeline to [start, end): it seeks the decoder
+// to start and ends the stream at end. Streams cannot be clipped and play
+// whole.
+func clipRange(tp *trackPipeline, start, end time.Duration) {
+	if !tp.seekable {
+		return
+	}
+	rate := tp.format.SampleRate
+	if n := rate.N(start); n > 0 && n < tp.decoder.Len() {
+		tp.decoder.Seek(n)
+	}
+	if end > 0 {
+		tp.stream = &rangeEnd{Streamer: tp.stream, dec: tp.decoder, end: rate.N(end)}
+	}
+}
@@ -0,0 +1,30 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopxl/beep/v2"
+)
+
+func TestClipRange(t *testing.T) {
+	file, start, end := splitRange("/music/talk.flac#t=1.5,2")
+	if file != "/music/talk.flac" || start != 1500*time.Millisecond || end != 2*time.Second {
+		t.Fatalf("splitRange = %q %v %v", file, start, end)
+	}
+	if file, _, _ := splitRange("/music/b#t=x.flac"); file != "/music/b#t=x.flac" {
+		t.Fatalf("unparsable annotation split off: %q", file)
+	}
+
+	src := &pcm{data: make([][2]float64, 3000)}
+	for i := range src.data {
+		src.data[i][0] = float64(i)
+	}
+	tp := &trackPipeline{decoder: pcmCloser{src}, stream: src, seekable: true,
+		format: beep.Format{SampleRate: 1000, NumChannels: 2}}
+	clipRange(tp, start, end)
+	out := drain(tp.stream, 128)
+	if len(out) != 500 || out[0][0] != 1500 || out[len(out)-1][0] != 1999 {
+		t.Fatalf("played %d frames from %v to %v, want 500 from 1500 to 1999", len(out), out[0][0], out[len(out)-1][0])
+	}
+}
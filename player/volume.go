@@ -3,35 +3,51 @@ package player
 import (
 	"math"
 	"sync/atomic"
-
-	"github.com/gopxl/beep/v2"
+	"time"
 )
 
-// volumeStreamer applies dB gain and optional mono downmix to an audio stream.
+// volumeNode applies dB gain (volume plus EQ makeup gain) and optional
+// mono downmix to an audio stream, and notes when the result clips.
 // Volume and mono are read via atomic operations, eliminating mutex contention
 // with the UI thread on the audio hot path.
-type volumeStreamer struct {
-	s          beep.Streamer
+type volumeNode struct {
 	vol        *atomic.Uint64 // dB stored as Float64bits
+	preamp     *atomic.Uint64 // EQ makeup gain in dB, added to vol; may be nil
+	trim       *atomic.Uint64 // the playing track's gain offset in dB, added to vol; may be nil
 	mono       *atomic.Bool
-	cachedDB   float64 // last dB value used to compute cachedGain; starts NaN to force first compute
-	cachedGain float64 // precomputed linear gain = 10^(dB/20)
+	mute       *atomic.Bool  // silence the output without touching vol; may be nil
+	bypass     *atomic.Bool  // skip vol (but not preamp) while a cast device sets the volume; may be nil
+	clipped    *atomic.Int64 // unix nanos of the last block that left this stage beyond ±1; may be nil
+	cachedDB   float64       // last dB value used to compute cachedGain; starts NaN to force first compute
+	cachedGain float64       // precomputed linear gain = 10^(dB/20)
 }
 
-func (v *volumeStreamer) Stream(samples [][2]float64) (int, bool) {
-	n, ok := v.s.Stream(samples)
-	if n == 0 {
-		return 0, ok
+func (v *volumeNode) Name() string { return EffectVolume }
+
+func (v *volumeNode) Process(samples [][2]float64) {
+	if v.mute != nil && v.mute.Load() {
+		clear(samples)
+		return
 	}
 	db := math.Float64frombits(v.vol.Load())
+	if v.bypass != nil && v.bypass.Load() {
+		db = 0
+	}
+	if v.preamp != nil {
+		db += math.Float64frombits(v.preamp.Load())
+	}
+	if v.trim != nil {
+		db += math.Float64frombits(v.trim.Load())
+	}
 	mono := v.mono.Load()
-	// Recompute gain only when volume changes (rare) instead of every Stream() call.
+	// Recompute gain only when volume changes (rare) instead of every block.
 	if db != v.cachedDB {
 		v.cachedGain = math.Pow(10, db/20)
 		v.cachedDB = db
 	}
 	gain := v.cachedGain
-	for i := range n {
+	var peak float64
+	for i := range samples {
 		samples[i][0] *= gain
 		samples[i][1] *= gain
 		if mono {
@@ -39,8 +55,9 @@ func (v *volumeStreamer) Stream(samples [][2]float64) (int, bool) {
 			samples[i][0] = mid
 			samples[i][1] = mid
 		}
+		peak = max(peak, math.Abs(samples[i][0]), math.Abs(samples[i][1]))
+	}
+	if peak > 1 && v.clipped != nil {
+		v.clipped.Store(time.Now().UnixNano())
 	}
-	return n, ok
 }
-
-func (v *volumeStreamer) Err() error { return v.s.Err() }
@@ -0,0 +1,40 @@
+package player
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMuteKeepsVolume(t *testing.T) {
+	p := &Player{}
+	p.SetVolume(-12)
+	v := &volumeNode{vol: &p.volume, mono: &p.mono, mute: &p.muted, cachedDB: math.NaN()}
+	block := func() float64 {
+		buf := [][2]float64{{1, 1}}
+		v.Process(buf)
+		return buf[0][0]
+	}
+
+	p.ToggleMute()
+	if got := block(); got != 0 || !p.Muted() {
+		t.Fatalf("muted output = %v, want 0", got)
+	}
+	p.ToggleMute()
+	if got, want := block(), math.Pow(10, -12.0/20); math.Abs(got-want) > 1e-12 || p.Volume() != -12 {
+		t.Fatalf("unmuted output = %v at %v dB, want %v at -12 dB", got, p.Volume(), want)
+	}
+}
+
+func TestVolumeStageNotesClipping(t *testing.T) {
+	p := &Player{}
+	v := &volumeNode{vol: &p.volume, mono: &p.mono, clipped: &p.clipHit, cachedDB: math.NaN()}
+	v.Process([][2]float64{{0.9, -0.9}})
+	if p.Clipping() {
+		t.Fatal("clipping reported below full scale")
+	}
+	p.SetVolume(3)
+	v.Process([][2]float64{{0.9, -0.9}})
+	if !p.Clipping() {
+		t.Fatal("+3 dB on a 0.9 peak should clip")
+	}
+}
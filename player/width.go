@@ -0,0 +1,32 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// widthNode scales the side (L−R) component of a stereo signal while
+// keeping the mid (L+R) component intact. A width of 1 leaves the signal
+// untouched, 0 collapses it to mono and 2 doubles the stereo spread.
+type widthNode struct {
+	enabled *atomic.Bool
+	width   *atomic.Uint64 // stored as Float64bits, range [0, 2]
+}
+
+func (w *widthNode) Name() string { return EffectWidth }
+
+func (w *widthNode) Process(samples [][2]float64) {
+	if !w.enabled.Load() {
+		return
+	}
+	width := math.Float64frombits(w.width.Load())
+	if width == 1 {
+		return
+	}
+	for i := range samples {
+		mid := (samples[i][0] + samples[i][1]) / 2
+		side := (samples[i][0] - samples[i][1]) / 2 * width
+		samples[i][0] = mid + side
+		samples[i][1] = mid - side
+	}
+}
@@ -0,0 +1,42 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func testWidth(width float64) *widthNode {
+	var on atomic.Bool
+	on.Store(true)
+	var w atomic.Uint64
+	w.Store(math.Float64bits(width))
+	return &widthNode{enabled: &on, width: &w}
+}
+
+func TestWidthLeavesMonoAlone(t *testing.T) {
+	for _, width := range []float64{0, 0.5, 1.5, 2} {
+		buf := [][2]float64{{0.5, 0.5}, {-0.25, -0.25}, {1, 1}}
+		testWidth(width).Process(buf)
+		for i, want := range []float64{0.5, -0.25, 1} {
+			if buf[i] != [2]float64{want, want} {
+				t.Errorf("width %.1f: sample %d = %v, want [%v %v]", width, i, buf[i], want, want)
+			}
+		}
+	}
+}
+
+func TestWidthScalesSide(t *testing.T) {
+	for _, width := range []float64{0, 0.5, 1, 1.5, 2} {
+		buf := [][2]float64{{0.6, 0.2}}
+		testWidth(width).Process(buf)
+		mid := (buf[0][0] + buf[0][1]) / 2
+		side := (buf[0][0] - buf[0][1]) / 2
+		if math.Abs(mid-0.4) > 1e-12 {
+			t.Errorf("width %.1f: mid = %v, want 0.4", width, mid)
+		}
+		if want := 0.2 * width; math.Abs(side-want) > 1e-12 {
+			t.Errorf("width %.1f: side = %v, want %v", width, side, want)
+		}
+	}
+}
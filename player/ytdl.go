@@ -364,5 +364,6 @@ func (p *Player) buildYTDLPipeline(pageURL string, startSec int) (*trackPipeline
 		path:         pageURL,
 		ytdlSeek:     true,
 		streamOffset: time.Duration(startSec) * time.Second,
+		ffmpegRate:   true,
 	}, nil
 }
@@ -0,0 +1,129 @@
+package playlist
+
+import (
+	"cmp"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// AlbumKey identifies the album t belongs to. Local files are keyed by
+// folder as well, so two albums sharing a title stay apart. Tracks without
+// an album tag return "".
+func AlbumKey(t Track) string {
+	if t.Album == "" {
+		return ""
+	}
+	key := strings.ToLower(t.Album)
+	if !t.Stream && !IsURL(t.Path) {
+		key += "\x00" + filepath.Dir(t.Path)
+	}
+	return key
+}
+
+// GroupAlbums reorders the tracks so that each album's tracks sit
+// together in disc and track-number order. An album takes the place of its
+// first track and tracks without an album tag keep theirs. The playing
+// track and the queue move with their tracks. It reports whether the
+// order changed.
+func (p *Playlist) GroupAlbums() bool {
+	type item struct{ idx, rank int }
+	items := make([]item, len(p.tracks))
+	first := make(map[string]int)
+	for i, t := range p.tracks {
+		rank := i
+		if key := AlbumKey(t); key != "" {
+			if r, ok := first[key]; ok {
+				rank = r
+			} else {
+				first[key] = i
+			}
+		}
+		items[i] = item{i, rank}
+	}
+	slices.SortStableFunc(items, func(a, b item) int {
+		ta, tb := p.tracks[a.idx], p.tracks[b.idx]
+		return cmp.Or(
+			cmp.Compare(a.rank, b.rank),
+			cmp.Compare(ta.DiscNumber, tb.DiscNumber),
+			cmp.Compare(ta.TrackNumber, tb.TrackNumber),
+		)
+	})
+	perm := make([]int, len(items))
+	moved := false
+	for i, it := range items {
+		perm[i] = it.idx
+		moved = moved || it.idx != i
+	}
+	if moved {
+		p.permute(perm)
+	}
+	return moved
+}
+
+// shuffleAlbums builds a playback order that visits the albums in random
+// order and each album's tracks in disc and track-number order. Tracks
+// without an album tag shuffle as albums of one. The current album comes
+// first, with the position left on the current track.
+func (p *Playlist) shuffleAlbums() {
+	cur := p.order[p.pos]
+	var albums [][]int
+	at := make(map[string]int)
+	for i, t := range p.tracks {
+		key := AlbumKey(t)
+		if a, ok := at[key]; ok && key != "" {
+			albums[a] = append(albums[a], i)
+			continue
+		}
+		at[key] = len(albums)
+		albums = append(albums, []int{i})
+	}
+	curAlbum := 0
+	for a, tracks := range albums {
+		slices.SortStableFunc(tracks, func(i, j int) int {
+			ti, tj := p.tracks[i], p.tracks[j]
+			return cmp.Or(cmp.Compare(ti.DiscNumber, tj.DiscNumber), cmp.Compare(ti.TrackNumber, tj.TrackNumber))
+		})
+		if slices.Contains(tracks, cur) {
+			curAlbum = a
+		}
+	}
+	albums[0], albums[curAlbum] = albums[curAlbum], albums[0]
+	rest := albums[1:]
+	rand.Shuffle(len(rest), func(i, j int) { rest[i], rest[j] = rest[j], rest[i] })
+
+	p.order = slices.Concat(albums...)
+	p.pos = slices.Index(p.order, cur)
+}
+
+// permute puts the track at index perm[i] at index i, updating order,
+// queue and position references like Move does.
+func (p *Playlist) permute(perm []int) {
+	newIdx := make([]int, len(perm))
+	tracks := make([]Track, len(perm))
+	for i, old := range perm {
+		tracks[i] = p.tracks[old]
+		newIdx[old] = i
+	}
+	p.tracks = tracks
+	p.version++
+
+	for i, idx := range p.order {
+		p.order[i] = newIdx[idx]
+	}
+	for i, idx := range p.queue {
+		p.queue[i] = newIdx[idx]
+	}
+	p.queueVer++
+	if p.queuedIdx >= 0 {
+		p.queuedIdx = newIdx[p.queuedIdx]
+	}
+	if !p.shuffle && len(p.order) > 0 {
+		cur := p.order[p.pos]
+		for i := range p.order {
+			p.order[i] = i
+		}
+		p.pos = cur
+	}
+}
@@ -0,0 +1,221 @@
+package playlist
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Chapter is a named position inside a track, such as an audiobook chapter.
+type Chapter struct {
+	Title string
+	Start time.Duration
+}
+
+// maxID3Size bounds how much of an ID3v2 tag is read looking for chapters.
+// Tags are mostly cover art; 16 MiB leaves room for several large images.
+const maxID3Size = 16 << 20
+
+// ReadChapters returns the chapter markers embedded in a local file: ID3v2
+// CHAP frames for MP3, and Nero chpl atoms or QuickTime chapter tracks for
+// MP4 audio (M4B/M4A). Chapters are sorted by start time; untitled ones are
+// numbered. Returns nil for other formats, remote paths, or files without
+// chapters.
+func ReadChapters(path string) []Chapter {
+	if IsURL(path) {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var chs []Chapter
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mp3":
+		chs = readID3Chapters(f)
+	case ".m4b", ".m4a", ".mp4", ".aac":
+		chs = readMP4Chapters(f)
+	}
+	return tidyChapters(chs)
+}
+
+// ChapterAt returns the index of the chapter playing at pos, or -1 when
+// pos is before the first chapter or there are none.
+func ChapterAt(chs []Chapter, pos time.Duration) int {
+	idx := -1
+	for i, c := range chs {
+		if c.Start > pos {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
+
+// tidyChapters sorts chapters by start, drops duplicates at the same
+// position, and names untitled ones "Chapter N".
+func tidyChapters(chs []Chapter) []Chapter {
+	if len(chs) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(chs, func(a, b Chapter) int { return cmp.Compare(a.Start, b.Start) })
+	out := chs[:0]
+	for _, c := range chs {
+		if n := len(out); n > 0 && out[n-1].Start == c.Start {
+			continue
+		}
+		out = append(out, c)
+	}
+	for i := range out {
+		out[i].Title = sanitizeTag(strings.TrimSpace(out[i].Title))
+		if out[i].Title == "" {
+			out[i].Title = fmt.Sprintf("Chapter %d", i+1)
+		}
+	}
+	return out
+}
+
+// readID3Chapters parses the CHAP frames of an ID3v2.3 or v2.4 tag at the
+// start of r.
+func readID3Chapters(r io.Reader) []Chapter {
+	var hdr [10]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil || string(hdr[:3]) != "ID3" {
+		return nil
+	}
+	major, flags := hdr[3], hdr[5]
+	if major != 3 && major != 4 {
+		return nil
+	}
+	size := syncsafe(hdr[6:10])
+	if size > maxID3Size {
+		return nil
+	}
+	tag := make([]byte, size)
+	if _, err := io.ReadFull(r, tag); err != nil {
+		return nil
+	}
+	if flags&0x80 != 0 {
+		tag = bytes.ReplaceAll(tag, []byte{0xFF, 0x00}, []byte{0xFF})
+	}
+	if flags&0x40 != 0 && len(tag) >= 4 {
+		// Extended header: v2.4 counts its own size, v2.3 does not.
+		ext := int(binary.BigEndian.Uint32(tag))
+		if major == 4 {
+			ext = syncsafe(tag[:4])
+		} else {
+			ext += 4
+		}
+		if ext > len(tag) {
+			return nil
+		}
+		tag = tag[ext:]
+	}
+
+	var chs []Chapter
+	for _, fr := range id3Frames(tag, major) {
+		if fr.id != "CHAP" {
+			continue
+		}
+		body := fr.body
+		// Element ID, then start/end time in ms and start/end byte offsets.
+		elem := bytes.IndexByte(body, 0)
+		if elem < 0 || len(body) < elem+1+16 {
+			continue
+		}
+		start := binary.BigEndian.Uint32(body[elem+1:])
+		c := Chapter{Start: time.Duration(start) * time.Millisecond}
+		for _, sub := range id3Frames(body[elem+17:], major) {
+			if sub.id == "TIT2" {
+				c.Title = id3Text(sub.body)
+				break
+			}
+		}
+		chs = append(chs, c)
+	}
+	return chs
+}
+
+// id3Frame is one frame of an ID3v2 tag.
+type id3Frame struct {
+	id   string
+	body []byte
+}
+
+// id3Frames splits b into frames, stopping at padding or a malformed size.
+func id3Frames(b []byte, major byte) []id3Frame {
+	var frames []id3Frame
+	for len(b) >= 10 && b[0] != 0 {
+		n := int(binary.BigEndian.Uint32(b[4:8]))
+		if major == 4 {
+			n = syncsafe(b[4:8])
+		}
+		if n < 0 || n > len(b)-10 {
+			break
+		}
+		frames = append(frames, id3Frame{id: string(b[:4]), body: b[10 : 10+n]})
+		b = b[10+n:]
+	}
+	return frames
+}
+
+// id3Text decodes a text frame body: an encoding byte, then the text.
+func id3Text(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	enc, b := b[0], b[1:]
+	switch enc {
+	case 1, 2: // UTF-16 with BOM, UTF-16BE
+		return decodeUTF16(b, enc == 2)
+	case 3: // UTF-8
+		s, _, _ := strings.Cut(string(b), "\x00")
+		return s
+	}
+	// ISO-8859-1: every byte is its own code point.
+	s, _, _ := bytes.Cut(b, []byte{0})
+	r := make([]rune, len(s))
+	for i, c := range s {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
+
+// decodeUTF16 decodes NUL-terminated UTF-16 text, honouring a leading BOM.
+func decodeUTF16(b []byte, bigEndian bool) string {
+	order := binary.ByteOrder(binary.LittleEndian)
+	if bigEndian {
+		order = binary.BigEndian
+	}
+	if len(b) >= 2 {
+		switch {
+		case b[0] == 0xFE && b[1] == 0xFF:
+			order, b = binary.BigEndian, b[2:]
+		case b[0] == 0xFF && b[1] == 0xFE:
+			order, b = binary.LittleEndian, b[2:]
+		}
+	}
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		c := order.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u))
+}
+
+// syncsafe decodes a 4-byte ID3 syncsafe integer (7 bits per byte).
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
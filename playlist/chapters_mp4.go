@@ -0,0 +1,319 @@
+package playlist
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// maxMP4Chapters caps how many chapter samples are read from a chapter
+// track, so a corrupt table cannot make the reader walk millions of entries.
+const maxMP4Chapters = 10000
+
+// mp4Atom is a box in an MP4 file: its type and where its body lies.
+type mp4Atom struct {
+	typ       string
+	off, size int64 // body offset and length, excluding the header
+}
+
+// mp4Atoms lists the boxes laid out back to back in [start, end).
+func mp4Atoms(r io.ReadSeeker, start, end int64) []mp4Atom {
+	var atoms []mp4Atom
+	for pos := start; pos+8 <= end; {
+		var hdr [16]byte
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			break
+		}
+		if _, err := io.ReadFull(r, hdr[:8]); err != nil {
+			break
+		}
+		size, hlen := int64(binary.BigEndian.Uint32(hdr[:4])), int64(8)
+		switch size {
+		case 0: // extends to the end of the enclosing box
+			size = end - pos
+		case 1: // 64-bit size follows the type
+			if _, err := io.ReadFull(r, hdr[8:16]); err != nil {
+				return atoms
+			}
+			size, hlen = int64(binary.BigEndian.Uint64(hdr[8:16])), 16
+		}
+		if size < hlen || pos+size > end {
+			break
+		}
+		atoms = append(atoms, mp4Atom{typ: string(hdr[4:8]), off: pos + hlen, size: size - hlen})
+		pos += size
+	}
+	return atoms
+}
+
+// child returns the first box of the given type inside a, following a path
+// of nested types.
+func (a mp4Atom) child(r io.ReadSeeker, path ...string) (mp4Atom, bool) {
+	for _, typ := range path {
+		found := false
+		for _, c := range mp4Atoms(r, a.off, a.off+a.size) {
+			if c.typ == typ {
+				a, found = c, true
+				break
+			}
+		}
+		if !found {
+			return mp4Atom{}, false
+		}
+	}
+	return a, true
+}
+
+// read returns the body of a, which must be small (a table or a header).
+func (a mp4Atom) read(r io.ReadSeeker) []byte {
+	if a.size > 16<<20 {
+		return nil
+	}
+	b := make([]byte, a.size)
+	if _, err := r.Seek(a.off, io.SeekStart); err != nil {
+		return nil
+	}
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil
+	}
+	return b
+}
+
+// readMP4Chapters reads Nero chapters (moov/udta/chpl), falling back to a
+// QuickTime chapter track (a text track referenced by tref/chap), which is
+// what Apple's tools write.
+func readMP4Chapters(r io.ReadSeeker) []Chapter {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil
+	}
+	var moov mp4Atom
+	found := false
+	for _, a := range mp4Atoms(r, 0, end) {
+		if a.typ == "moov" {
+			moov, found = a, true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+	if chpl, ok := moov.child(r, "udta", "chpl"); ok {
+		if chs := parseChpl(chpl.read(r)); len(chs) > 0 {
+			return chs
+		}
+	}
+	return readChapterTrack(r, moov)
+}
+
+// parseChpl decodes a Nero chapter list: version and flags, a reserved word
+// in version 1, a count, then per chapter a start time in 100ns units and a
+// length-prefixed title.
+func parseChpl(b []byte) []Chapter {
+	if len(b) < 5 {
+		return nil
+	}
+	p := 4
+	if b[0] == 1 {
+		p += 4
+	}
+	if p >= len(b) {
+		return nil
+	}
+	n := int(b[p])
+	p++
+	var chs []Chapter
+	for range n {
+		if p+9 > len(b) {
+			break
+		}
+		start := binary.BigEndian.Uint64(b[p:])
+		l := int(b[p+8])
+		p += 9
+		if p+l > len(b) {
+			break
+		}
+		chs = append(chs, Chapter{Title: string(b[p : p+l]), Start: time.Duration(start) * 100})
+		p += l
+	}
+	return chs
+}
+
+// readChapterTrack finds the track named by a tref/chap reference and reads
+// each of its text samples as one chapter.
+func readChapterTrack(r io.ReadSeeker, moov mp4Atom) []Chapter {
+	traks := make(map[uint32]mp4Atom)
+	var chapID uint32
+	for _, a := range mp4Atoms(r, moov.off, moov.off+moov.size) {
+		if a.typ != "trak" {
+			continue
+		}
+		if tkhd, ok := a.child(r, "tkhd"); ok {
+			b := tkhd.read(r)
+			idOff := 12 // version/flags, creation and modification times
+			if len(b) > 0 && b[0] == 1 {
+				idOff = 20
+			}
+			if len(b) >= idOff+4 {
+				traks[binary.BigEndian.Uint32(b[idOff:])] = a
+			}
+		}
+		if chap, ok := a.child(r, "tref", "chap"); ok && chapID == 0 {
+			if b := chap.read(r); len(b) >= 4 {
+				chapID = binary.BigEndian.Uint32(b)
+			}
+		}
+	}
+	trak, ok := traks[chapID]
+	if chapID == 0 || !ok {
+		return nil
+	}
+
+	mdhd, ok := trak.child(r, "mdia", "mdhd")
+	if !ok {
+		return nil
+	}
+	timescale := mdhdTimescale(mdhd.read(r))
+	stbl, ok := trak.child(r, "mdia", "minf", "stbl")
+	if timescale == 0 || !ok {
+		return nil
+	}
+	table := func(typ string) []byte {
+		if a, ok := stbl.child(r, typ); ok {
+			return a.read(r)
+		}
+		return nil
+	}
+	offsets := sampleOffsets(table("stsc"), table("stsz"), chunkOffsets(table("stco"), table("co64")))
+	starts := sampleStarts(table("stts"), len(offsets))
+
+	var chs []Chapter
+	for i, off := range offsets {
+		if i >= len(starts) {
+			break
+		}
+		var l [2]byte
+		if _, err := r.Seek(off, io.SeekStart); err != nil {
+			break
+		}
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			break
+		}
+		text := make([]byte, binary.BigEndian.Uint16(l[:]))
+		if _, err := io.ReadFull(r, text); err != nil {
+			break
+		}
+		title := string(text)
+		if len(text) >= 2 && (text[0] == 0xFE && text[1] == 0xFF || text[0] == 0xFF && text[1] == 0xFE) {
+			title = decodeUTF16(text, true)
+		}
+		chs = append(chs, Chapter{
+			Title: title,
+			Start: time.Duration(starts[i] * uint64(time.Second) / uint64(timescale)),
+		})
+	}
+	return chs
+}
+
+// mdhdTimescale reads the time units per second from a media header.
+func mdhdTimescale(b []byte) uint32 {
+	off := 12 // version/flags, creation and modification times
+	if len(b) > 0 && b[0] == 1 {
+		off = 20
+	}
+	if len(b) < off+4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b[off:])
+}
+
+// chunkOffsets decodes the file offset of every chunk from stco or co64.
+func chunkOffsets(stco, co64 []byte) []int64 {
+	if len(stco) >= 8 {
+		n := min(int(binary.BigEndian.Uint32(stco[4:])), (len(stco)-8)/4)
+		out := make([]int64, n)
+		for i := range out {
+			out[i] = int64(binary.BigEndian.Uint32(stco[8+4*i:]))
+		}
+		return out
+	}
+	if len(co64) >= 8 {
+		n := min(int(binary.BigEndian.Uint32(co64[4:])), (len(co64)-8)/8)
+		out := make([]int64, n)
+		for i := range out {
+			out[i] = int64(binary.BigEndian.Uint64(co64[8+8*i:]))
+		}
+		return out
+	}
+	return nil
+}
+
+// sampleOffsets works out where each sample starts from the sample-to-chunk
+// map, the sample sizes and the chunk offsets.
+func sampleOffsets(stsc, stsz []byte, chunks []int64) []int64 {
+	if len(stsc) < 8 || len(stsz) < 12 {
+		return nil
+	}
+	fixed := binary.BigEndian.Uint32(stsz[4:])
+	count := min(int(binary.BigEndian.Uint32(stsz[8:])), maxMP4Chapters)
+	size := func(i int) int64 {
+		if fixed != 0 {
+			return int64(fixed)
+		}
+		if 12+4*i+4 > len(stsz) {
+			return -1
+		}
+		return int64(binary.BigEndian.Uint32(stsz[12+4*i:]))
+	}
+
+	entries := min(int(binary.BigEndian.Uint32(stsc[4:])), (len(stsc)-8)/12)
+	var out []int64
+	sample := 0
+	for e := range entries {
+		first := int(binary.BigEndian.Uint32(stsc[8+12*e:]))
+		perChunk := int(binary.BigEndian.Uint32(stsc[8+12*e+4:]))
+		last := len(chunks) + 1
+		if e+1 < entries {
+			last = int(binary.BigEndian.Uint32(stsc[8+12*(e+1):]))
+		}
+		for c := first; c < last && c >= 1 && c <= len(chunks); c++ {
+			off := chunks[c-1]
+			for range perChunk {
+				if sample >= count {
+					return out
+				}
+				sz := size(sample)
+				if sz < 0 {
+					return out
+				}
+				out = append(out, off)
+				off += sz
+				sample++
+			}
+		}
+	}
+	return out
+}
+
+// sampleStarts returns the start of each of the first n samples, in media
+// time units, from the time-to-sample table.
+func sampleStarts(stts []byte, n int) []uint64 {
+	if len(stts) < 8 {
+		return nil
+	}
+	entries := min(int(binary.BigEndian.Uint32(stts[4:])), (len(stts)-8)/8)
+	out := make([]uint64, 0, n)
+	var t uint64
+	for e := range entries {
+		count := binary.BigEndian.Uint32(stts[8+8*e:])
+		delta := uint64(binary.BigEndian.Uint32(stts[8+8*e+4:]))
+		for range count {
+			if len(out) >= n {
+				return out
+			}
+			out = append(out, t)
+			t += delta
+		}
+	}
+	return out
+}
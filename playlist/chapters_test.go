@@ -0,0 +1,139 @@
+package playlist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func id3v23Frame(id string, body []byte) []byte {
+	b := make([]byte, 10, 10+len(body))
+	copy(b, id)
+	binary.BigEndian.PutUint32(b[4:], uint32(len(body)))
+	return append(b, body...)
+}
+
+func chapFrame(elem string, startMs uint32, title []byte) []byte {
+	body := append([]byte(elem), 0)
+	var times [16]byte
+	binary.BigEndian.PutUint32(times[:], startMs)
+	body = append(body, times[:]...)
+	if title != nil {
+		body = append(body, id3v23Frame("TIT2", title)...)
+	}
+	return id3v23Frame("CHAP", body)
+}
+
+func TestReadID3Chapters(t *testing.T) {
+	var frames []byte
+	frames = append(frames, id3v23Frame("TIT2", []byte("\x03Book"))...)
+	// Out of order, one UTF-16 title and one untitled.
+	frames = append(frames, chapFrame("ch2", 90_000, []byte("\x01\xFF\xFEO\x00n\x00e\x00\x00\x00"))...)
+	frames = append(frames, chapFrame("ch1", 0, []byte("\x03Intro"))...)
+	frames = append(frames, chapFrame("ch3", 3_600_000, nil)...)
+	frames = append(frames, make([]byte, 32)...) // padding
+
+	hdr := []byte("ID3\x03\x00\x00\x00\x00\x00\x00")
+	n := len(frames)
+	hdr[6], hdr[7], hdr[8], hdr[9] = byte(n>>21)&0x7F, byte(n>>14)&0x7F, byte(n>>7)&0x7F, byte(n)&0x7F
+
+	path := filepath.Join(t.TempDir(), "book.mp3")
+	if err := os.WriteFile(path, append(hdr, frames...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got := ReadChapters(path)
+	want := []Chapter{
+		{"Intro", 0},
+		{"One", 90 * time.Second},
+		{"Chapter 3", time.Hour},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ReadChapters = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chapter %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func box(typ string, body ...[]byte) []byte {
+	b := bytes.Join(body, nil)
+	out := make([]byte, 8, 8+len(b))
+	binary.BigEndian.PutUint32(out, uint32(8+len(b)))
+	copy(out[4:], typ)
+	return append(out, b...)
+}
+
+func u32(v ...uint32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.BigEndian.PutUint32(b[4*i:], x)
+	}
+	return b
+}
+
+func TestReadMP4ChaptersNero(t *testing.T) {
+	chpl := []byte{1, 0, 0, 0, 0, 0, 0, 0, 2}
+	for _, c := range []struct {
+		start uint64
+		title string
+	}{{0, "Opening"}, {uint64(5 * time.Minute / 100), "Part Two"}} {
+		var ts [8]byte
+		binary.BigEndian.PutUint64(ts[:], c.start)
+		chpl = append(append(append(chpl, ts[:]...), byte(len(c.title))), c.title...)
+	}
+	file := append(box("ftyp", []byte("M4B ")), box("moov", box("udta", box("chpl", chpl)))...)
+
+	got := readMP4Chapters(bytes.NewReader(file))
+	if len(got) != 2 || got[1] != (Chapter{"Part Two", 5 * time.Minute}) {
+		t.Errorf("chapters = %+v", got)
+	}
+}
+
+func TestReadMP4ChapterTrack(t *testing.T) {
+	// Two text samples stored together in one chunk in mdat.
+	samples := []byte("\x00\x05First\x00\x06Second")
+	ftyp := box("ftyp", []byte("M4A "))
+	mdatOff := uint32(len(ftyp) + 8)
+	mdat := box("mdat", samples)
+
+	audio := box("trak",
+		box("tkhd", u32(0, 0, 0, 1)),
+		box("tref", box("chap", u32(2))),
+	)
+	text := box("trak",
+		box("tkhd", u32(0, 0, 0, 2)),
+		box("mdia",
+			box("mdhd", u32(0, 0, 0, 1000, 0)),
+			box("minf", box("stbl",
+				box("stts", u32(0, 1, 2, 61_500)),
+				box("stsc", u32(0, 1, 1, 2, 1)),
+				box("stsz", u32(0, 0, 2, 7, 8)),
+				box("stco", u32(0, 1, mdatOff)),
+			)),
+		),
+	)
+	file := bytes.Join([][]byte{ftyp, mdat, box("moov", audio, text)}, nil)
+
+	got := readMP4Chapters(bytes.NewReader(file))
+	want := []Chapter{{"First", 0}, {"Second", 61500 * time.Millisecond}}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("chapters = %+v, want %+v", got, want)
+	}
+}
+
+func TestChapterAt(t *testing.T) {
+	chs := []Chapter{{"A", time.Second}, {"B", time.Minute}}
+	for _, tc := range []struct {
+		pos  time.Duration
+		want int
+	}{{0, -1}, {time.Second, 0}, {59 * time.Second, 0}, {time.Hour, 1}} {
+		if got := ChapterAt(chs, tc.pos); got != tc.want {
+			t.Errorf("ChapterAt(%v) = %d, want %d", tc.pos, got, tc.want)
+		}
+	}
+}
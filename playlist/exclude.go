@@ -0,0 +1,38 @@
+package playlist
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Excludes is a list of glob patterns naming files and folders to leave out
+// when scanning folders for audio. A pattern ending in "/" matches folders
+// only ("podcasts/"); a pattern containing "/" matches the path below the
+// scanned folder ("Live/*.flac"); any other pattern matches the name of a
+// file or folder ("*.live.*").
+type Excludes []string
+
+// Match reports whether the file or folder at rel, a path relative to the
+// scanned folder, is excluded. Everything under an excluded folder is
+// excluded with it, so a scan should not descend into one.
+func (e Excludes) Match(rel string, dir bool) bool {
+	rel = filepath.ToSlash(rel)
+	name := rel[strings.LastIndexByte(rel, '/')+1:]
+	for _, p := range e {
+		p = filepath.ToSlash(p)
+		if folder, ok := strings.CutSuffix(p, "/"); ok {
+			if !dir {
+				continue
+			}
+			p = folder
+		}
+		target := name
+		if strings.Contains(p, "/") {
+			target = rel
+		}
+		if ok, _ := filepath.Match(p, target); ok {
+			return true
+		}
+	}
+	return false
+}
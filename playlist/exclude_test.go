@@ -0,0 +1,26 @@
+package playlist
+
+import "testing"
+
+func TestExcludesMatch(t *testing.T) {
+	e := Excludes{"*.live.*", "podcasts/", "Demos/*.wav"}
+	tests := []struct {
+		rel  string
+		dir  bool
+		want bool
+	}{
+		{"Artist/Album/01 Song.live.flac", false, true},
+		{"Artist/Album/01 Song.flac", false, false},
+		{"podcasts", true, true},
+		{"Talk/podcasts", true, true},
+		{"podcasts", false, false},
+		{"Demos/take1.wav", false, true},
+		{"Demos/take1.flac", false, false},
+		{"Band/Demos/take1.wav", false, false},
+	}
+	for _, tt := range tests {
+		if got := e.Match(tt.rel, tt.dir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.rel, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package playlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// PathStyle is how WriteM3U writes the paths of local files.
+type PathStyle int
+
+const (
+	PathsAbsolute PathStyle = iota // "/home/me/Music/a.flac"
+	PathsRelative                  // "../Music/a.flac", from the playlist's folder
+	PathsURI                       // "file:///home/me/Music/a.flac"
+)
+
+// ParsePathStyle parses "absolute", "relative" or "uri".
+func ParsePathStyle(s string) (PathStyle, error) {
+	switch strings.ToLower(s) {
+	case "absolute":
+		return PathsAbsolute, nil
+	case "relative":
+		return PathsRelative, nil
+	case "uri":
+		return PathsURI, nil
+	}
+	return 0, fmt.Errorf("invalid path style %q (want absolute, relative or uri)", s)
+}
+
+// M3UOptions controls how WriteM3U writes paths, so that a playlist can be
+// read on another device or by another player.
+type M3UOptions struct {
+	Paths PathStyle
+	// Base is the folder the playlist is written to, which PathsRelative
+	// paths start from. A path that cannot be made relative to it, such as
+	// one on another Windows drive, is written absolute.
+	Base string
+	// Separator is the path separator to write, '/' or '\\'; zero keeps
+	// this system's. URIs always use '/'.
+	Separator rune
+}
+
+// WriteM3U writes tracks as an extended M3U playlist. Streams are written
+// as their URLs; a track that plays part of its file keeps its range.
+func WriteM3U(w io.Writer, tracks []Track, opt M3UOptions) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	for _, t := range tracks {
+		secs := t.DurationSecs
+		if secs <= 0 {
+			secs = -1
+		}
+		title := t.Title
+		if t.Artist != "" && title != "" {
+			title = t.Artist + " - " + title
+		}
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", secs, title)
+		if t.Stream || IsURL(t.Path) {
+			fmt.Fprintln(bw, t.Path)
+			continue
+		}
+		fmt.Fprintln(bw, m3uPath(t.Path, opt)+strings.TrimPrefix(t.PlayPath(), t.Path))
+	}
+	return bw.Flush()
+}
+
+// m3uPath writes the local path p in the style opt asks for.
+func m3uPath(p string, opt M3UOptions) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+	switch opt.Paths {
+	case PathsURI:
+		return FileURI(p)
+	case PathsRelative:
+		if rel, err := filepath.Rel(opt.Base, p); err == nil {
+			p = rel
+		}
+	}
+	switch opt.Separator {
+	case '/':
+		p = strings.ReplaceAll(p, `\`, "/")
+	case '\\':
+		p = strings.ReplaceAll(p, "/", `\`)
+	}
+	return p
+}
+
+// FileURI returns the file:// URL of the absolute path p:
+// "file:///home/me/a%20b.flac", or "file:///C:/Users/me/a.flac" for a
+// Windows path.
+func FileURI(p string) string {
+	p = filepath.ToSlash(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // a drive letter: "C:/Users/..."
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// PathFromFileURI turns a file:// URL back into a local path, keeping a
+// range fragment such as "#t=60". It returns "" for anything else.
+func PathFromFileURI(s string) string {
+	if !strings.HasPrefix(strings.ToLower(s), "file:") {
+		return ""
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Host != "" && u.Host != "localhost") {
+		return ""
+	}
+	p := u.Path
+	// A Windows drive letter follows the root slash: "/C:/Users/...".
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	p = filepath.FromSlash(p)
+	if u.Fragment != "" {
+		p += "#" + u.Fragment
+	}
+	return p
+}
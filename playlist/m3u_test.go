@@ -0,0 +1,61 @@
+package playlist
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteM3U(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("paths below are Unix paths")
+	}
+	tracks := []Track{
+		{Path: "/home/me/Music/Artist/a b.flac", Title: "A", Artist: "Artist", DurationSecs: 200},
+		{Path: "/home/me/Talks/t.mp3", Title: "T", Start: time.Minute},
+		{Path: "http://radio.example/stream", Title: "Radio", Stream: true},
+	}
+	tests := []struct {
+		opt  M3UOptions
+		want []string
+	}{
+		{M3UOptions{}, []string{"/home/me/Music/Artist/a b.flac", "/home/me/Talks/t.mp3#t=60"}},
+		{M3UOptions{Paths: PathsRelative, Base: "/home/me/Music"}, []string{"Artist/a b.flac", "../Talks/t.mp3#t=60"}},
+		{M3UOptions{Paths: PathsRelative, Base: "/home/me/Music", Separator: '\\'}, []string{`Artist\a b.flac`, `..\Talks\t.mp3#t=60`}},
+		{M3UOptions{Paths: PathsURI}, []string{"file:///home/me/Music/Artist/a%20b.flac", "file:///home/me/Talks/t.mp3#t=60"}},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := WriteM3U(&b, tracks, tt.opt); err != nil {
+			t.Fatal(err)
+		}
+		want := "#EXTM3U\n" +
+			"#EXTINF:200,Artist - A\n" + tt.want[0] + "\n" +
+			"#EXTINF:-1,T\n" + tt.want[1] + "\n" +
+			"#EXTINF:-1,Radio\nhttp://radio.example/stream\n"
+		if got := b.String(); got != want {
+			t.Errorf("WriteM3U(%+v) =\n%s\nwant\n%s", tt.opt, got, want)
+		}
+	}
+}
+
+func TestPathFromFileURI(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"file:///home/me/a%20b.flac", filepath.FromSlash("/home/me/a b.flac")},
+		{"file://localhost/home/me/a.flac#t=60", filepath.FromSlash("/home/me/a.flac") + "#t=60"},
+		{"file:///C:/Users/me/a.mp3", filepath.FromSlash("C:/Users/me/a.mp3")},
+		{"file://server/share/a.mp3", ""},
+		{"/home/me/a.flac", ""},
+		{"http://example.com/a.mp3", ""},
+	}
+	for _, tt := range tests {
+		if got := PathFromFileURI(tt.in); got != tt.want {
+			t.Errorf("PathFromFileURI(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := PathFromFileURI(FileURI("/music/a#1.flac")); got != filepath.FromSlash("/music/a#1.flac") {
+		t.Errorf("round trip = %q", got)
+	}
+}
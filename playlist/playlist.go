@@ -2,11 +2,14 @@
 package playlist
 
 import (
+	"cmp"
+	"math"
 	"math/rand"
 	"net/url"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 )
 
 // RepeatMode controls playlist repeat behavior.
@@ -38,10 +41,17 @@ type Track struct {
 	Genre        string
 	Year         int
 	TrackNumber  int
+	DiscNumber   int
 	Stream       bool   // true for HTTP/HTTPS URLs
 	Realtime     bool   // true for real-time/live streams (e.g. radio)
 	DurationSecs int    // known duration in seconds (0 = unknown)
 	NavidromeID  string // Subsonic song ID; empty for non-Navidrome tracks
+	AutoDJ       bool   // appended by Auto-DJ rather than by the user
+
+	// Start and End limit playback to part of a local file; zero means
+	// from the beginning and to the end.
+	Start time.Duration
+	End   time.Duration
 }
 
 // IsURL reports whether path is an HTTP or HTTPS URL, or a yt-dlp search protocol string.
@@ -51,6 +61,12 @@ func IsURL(path string) bool {
 		strings.HasPrefix(path, "scsearch:") || strings.HasPrefix(path, "scsearch1:")
 }
 
+// IsStdin reports whether path is "-", the argument that plays audio piped
+// into standard input.
+func IsStdin(path string) bool {
+	return path == "-"
+}
+
 // IsM3U reports whether the path points to an M3U playlist file (URL or local).
 func IsM3U(path string) bool {
 	if IsURL(path) {
@@ -151,6 +167,9 @@ func IsYTDL(path string) bool {
 	switch host {
 	case "soundcloud.com",
 		"bandcamp.com",
+		"mixcloud.com",
+		"audiomack.com",
+		"vimeo.com",
 		"music.163.com",
 		"bilibili.com",
 		"b23.tv":
@@ -253,14 +272,20 @@ type Playlist struct {
 	order     []int // indices into tracks, shuffled or sequential
 	pos       int   // current position in order
 	shuffle   bool
+	byAlbum   bool // shuffle whole albums rather than single tracks
+	weight    func(Track) float64
 	repeat    RepeatMode
+	repeats   int   // with RepeatOne, plays of the track left before moving on; 0 = endless
 	queue     []int // track indices queued to play next
 	queuedIdx int   // track index currently playing from queue, -1 if none
+	version   uint64
+	queueVer  uint64
+	passes    int // whole passes left after this one when SetLoops limits play; -1 follows repeat
 }
 
 // New creates an empty Playlist.
 func New() *Playlist {
-	return &Playlist{queuedIdx: -1}
+	return &Playlist{queuedIdx: -1, passes: -1}
 }
 
 // Replace clears the playlist and loads the given tracks, resetting
@@ -274,6 +299,8 @@ func (p *Playlist) Replace(tracks []Track) {
 	p.pos = 0
 	p.queue = nil
 	p.queuedIdx = -1
+	p.version++
+	p.queueVer++
 	if p.shuffle && len(tracks) > 0 {
 		p.doShuffle()
 	}
@@ -286,11 +313,17 @@ func (p *Playlist) Add(tracks ...Track) {
 	for i := start; i < len(p.tracks); i++ {
 		p.order = append(p.order, i)
 	}
+	p.version++
 }
 
 // Len returns the number of tracks.
 func (p *Playlist) Len() int { return len(p.tracks) }
 
+// Version returns a counter that changes whenever tracks are added,
+// replaced, moved or edited, so observers can tell the list changed
+// without comparing it.
+func (p *Playlist) Version() uint64 { return p.version }
+
 // Current returns the currently selected track and its index.
 func (p *Playlist) Current() (Track, int) {
 	if len(p.tracks) == 0 {
@@ -324,18 +357,28 @@ func (p *Playlist) Next() (Track, bool) {
 	if len(p.queue) > 0 {
 		idx := p.queue[0]
 		p.queue = p.queue[1:]
+		p.queueVer++
 		p.queuedIdx = idx
 		return p.tracks[idx], true
 	}
 	p.queuedIdx = -1
 	if p.repeat == RepeatOne {
+		if p.repeats > 0 {
+			p.repeats--
+			if p.repeats == 0 {
+				p.repeat = RepeatOff
+			}
+		}
 		return p.tracks[p.order[p.pos]], true
 	}
 	if p.pos+1 < len(p.order) {
 		p.pos++
 		return p.tracks[p.order[p.pos]], true
 	}
-	if p.repeat == RepeatAll {
+	if p.wraps() {
+		if p.passes > 0 {
+			p.passes--
+		}
 		p.pos = 0
 		if p.shuffle {
 			p.doShuffle()
@@ -365,7 +408,7 @@ func (p *Playlist) PeekNext() (Track, bool) {
 	if p.pos+1 < len(p.order) {
 		return p.tracks[p.order[p.pos+1]], true
 	}
-	if p.repeat == RepeatAll && !p.shuffle {
+	if p.wraps() && !p.shuffle {
 		return p.tracks[p.order[0]], true
 	}
 	// RepeatAll+shuffle: can't predict after re-shuffle; RepeatOff at end
@@ -390,6 +433,20 @@ func (p *Playlist) Prev() (Track, bool) {
 	return p.tracks[p.order[p.pos]], false
 }
 
+// PeekPrev returns the track Prev would move to without changing position.
+func (p *Playlist) PeekPrev() (Track, bool) {
+	if len(p.tracks) == 0 {
+		return Track{}, false
+	}
+	if p.pos > 0 {
+		return p.tracks[p.order[p.pos-1]], true
+	}
+	if p.repeat == RepeatAll {
+		return p.tracks[p.order[len(p.order)-1]], true
+	}
+	return Track{}, false
+}
+
 // SetIndex sets the current position to the given track index.
 func (p *Playlist) SetIndex(i int) {
 	p.queuedIdx = -1
@@ -405,6 +462,7 @@ func (p *Playlist) SetIndex(i int) {
 func (p *Playlist) Queue(trackIdx int) {
 	if trackIdx >= 0 && trackIdx < len(p.tracks) {
 		p.queue = append(p.queue, trackIdx)
+		p.queueVer++
 	}
 }
 
@@ -413,6 +471,7 @@ func (p *Playlist) Dequeue(trackIdx int) bool {
 	for i, idx := range p.queue {
 		if idx == trackIdx {
 			p.queue = slices.Delete(p.queue, i, i+1)
+			p.queueVer++
 			return true
 		}
 	}
@@ -433,6 +492,58 @@ func (p *Playlist) QueuePosition(trackIdx int) int {
 // QueueLen returns the number of tracks in the queue.
 func (p *Playlist) QueueLen() int { return len(p.queue) }
 
+// Remaining returns how long the tracks still to play after the current one
+// last, by their known durations: the queue, repeats of the current track,
+// the rest of the order and any further passes through the playlist. ok is
+// false when playback doesn't end on its own or a track ahead has no known
+// duration.
+func (p *Playlist) Remaining() (d time.Duration, ok bool) {
+	if len(p.tracks) == 0 {
+		return 0, false
+	}
+	secs := 0
+	add := func(idx ...int) bool {
+		for _, i := range idx {
+			if p.tracks[i].DurationSecs <= 0 {
+				return false
+			}
+			secs += p.tracks[i].DurationSecs
+		}
+		return true
+	}
+	if !add(p.queue...) {
+		return 0, false
+	}
+	if p.repeat == RepeatOne {
+		if p.repeats == 0 {
+			return 0, false
+		}
+		for range p.repeats {
+			if !add(p.order[p.pos]) {
+				return 0, false
+			}
+		}
+	}
+	if !add(p.order[p.pos+1:]...) {
+		return 0, false
+	}
+	switch {
+	case p.passes > 0:
+		before := secs
+		if !add(p.order...) {
+			return 0, false
+		}
+		secs = before + (secs-before)*p.passes
+	case p.passes < 0 && p.repeat == RepeatAll:
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// QueueVersion returns a counter that changes whenever the play-next queue
+// does, like Version does for the tracks.
+func (p *Playlist) QueueVersion() uint64 { return p.queueVer }
+
 // QueueTracks returns copies of the tracks in queue order.
 func (p *Playlist) QueueTracks() []Track {
 	out := make([]Track, len(p.queue))
@@ -443,12 +554,16 @@ func (p *Playlist) QueueTracks() []Track {
 }
 
 // ClearQueue removes all entries from the play-next queue.
-func (p *Playlist) ClearQueue() { p.queue = nil }
+func (p *Playlist) ClearQueue() {
+	p.queue = nil
+	p.queueVer++
+}
 
 // RemoveQueueAt removes the entry at the given 0-based queue position.
 func (p *Playlist) RemoveQueueAt(pos int) {
 	if pos >= 0 && pos < len(p.queue) {
 		p.queue = slices.Delete(p.queue, pos, pos+1)
+		p.queueVer++
 	}
 }
 
@@ -458,6 +573,7 @@ func (p *Playlist) MoveQueue(from, to int) bool {
 		return false
 	}
 	p.queue[from], p.queue[to] = p.queue[to], p.queue[from]
+	p.queueVer++
 	return true
 }
 
@@ -471,6 +587,7 @@ func (p *Playlist) Move(from, to int) bool {
 
 	// Swap in the tracks array (visual order).
 	p.tracks[from], p.tracks[to] = p.tracks[to], p.tracks[from]
+	p.version++
 
 	// Update order: swap all references so they point at the moved tracks.
 	for i, idx := range p.order {
@@ -482,6 +599,7 @@ func (p *Playlist) Move(from, to int) bool {
 	}
 
 	// Queue also references track indices.
+	p.queueVer++
 	for i, idx := range p.queue {
 		if idx == from {
 			p.queue[i] = to
@@ -512,6 +630,7 @@ func (p *Playlist) Move(from, to int) bool {
 func (p *Playlist) SetTrack(i int, t Track) {
 	if i >= 0 && i < len(p.tracks) {
 		p.tracks[i] = t
+		p.version++
 	}
 }
 
@@ -521,7 +640,23 @@ func (p *Playlist) Tracks() []Track { return p.tracks }
 // ToggleShuffle enables or disables shuffle mode.
 // Uses Fisher-Yates shuffle, preserving the current track at position 0.
 func (p *Playlist) ToggleShuffle() {
-	p.shuffle = !p.shuffle
+	p.setShuffle(!p.shuffle, false)
+}
+
+// CycleShuffle cycles through Off -> Tracks -> Albums.
+func (p *Playlist) CycleShuffle() {
+	switch {
+	case !p.shuffle:
+		p.setShuffle(true, false)
+	case !p.byAlbum:
+		p.setShuffle(true, true)
+	default:
+		p.setShuffle(false, false)
+	}
+}
+
+func (p *Playlist) setShuffle(on, byAlbum bool) {
+	p.shuffle, p.byAlbum = on, on && byAlbum
 	if len(p.tracks) == 0 {
 		return
 	}
@@ -538,6 +673,10 @@ func (p *Playlist) ToggleShuffle() {
 }
 
 func (p *Playlist) doShuffle() {
+	if p.byAlbum {
+		p.shuffleAlbums()
+		return
+	}
 	cur := p.order[p.pos]
 	others := make([]int, 0, len(p.tracks)-1)
 	for i := range len(p.tracks) {
@@ -545,9 +684,13 @@ func (p *Playlist) doShuffle() {
 			others = append(others, i)
 		}
 	}
-	for i := len(others) - 1; i > 0; i-- {
-		j := rand.Intn(i + 1)
-		others[i], others[j] = others[j], others[i]
+	if p.weight != nil {
+		p.weightedShuffle(others)
+	} else {
+		for i := len(others) - 1; i > 0; i-- {
+			j := rand.Intn(i + 1)
+			others[i], others[j] = others[j], others[i]
+		}
 	}
 	p.order = make([]int, 0, len(p.tracks))
 	p.order = append(p.order, cur)
@@ -555,13 +698,72 @@ func (p *Playlist) doShuffle() {
 	p.pos = 0
 }
 
+// weightedShuffle orders idx at random, with tracks of greater weight
+// tending to come earlier: a weighted sample without replacement, drawn by
+// giving each track the key -ln(u)/w and sorting by it (Efraimidis and
+// Spirakis).
+func (p *Playlist) weightedShuffle(idx []int) {
+	keys := make(map[int]float64, len(idx))
+	for _, i := range idx {
+		w := max(p.weight(p.tracks[i]), 1e-3)
+		keys[i] = -math.Log(1-rand.Float64()) / w
+	}
+	slices.SortFunc(idx, func(a, b int) int { return cmp.Compare(keys[a], keys[b]) })
+}
+
+// SetShuffleWeight makes track shuffle favour tracks by w: a track of
+// weight 2 tends to come up before one of weight 1. Nil shuffles uniformly.
+// It applies from the next shuffle; see Reshuffle.
+func (p *Playlist) SetShuffleWeight(w func(Track) float64) {
+	p.weight = w
+}
+
+// Reshuffle draws a new track shuffle order after the current track, for
+// when the shuffle weights have changed. It does nothing unless tracks are
+// shuffled.
+func (p *Playlist) Reshuffle() {
+	if p.shuffle && !p.byAlbum && len(p.tracks) > 0 {
+		p.doShuffle()
+	}
+}
+
 // CycleRepeat cycles through Off -> All -> One.
 func (p *Playlist) CycleRepeat() {
 	p.repeat = (p.repeat + 1) % 3
+	p.repeats = 0
+}
+
+// SetRepeatCount repeats the current track n more times and then moves
+// on, switching repeat off. n <= 0 repeats it endlessly.
+func (p *Playlist) SetRepeatCount(n int) {
+	p.repeat = RepeatOne
+	p.repeats = max(0, n)
 }
 
-// Shuffled returns whether shuffle is enabled.
+// RepeatsLeft returns how many more times the current track repeats, or 0
+// when it repeats endlessly or repeat one is off.
+func (p *Playlist) RepeatsLeft() int { return p.repeats }
+
+// SetLoops makes the playlist play through n times and then end, whatever
+// the repeat mode; n <= 0 removes the limit. The pass under way counts as
+// the first.
+func (p *Playlist) SetLoops(n int) {
+	p.passes = max(-1, n-1)
+}
+
+// wraps reports whether Next goes round from the last track to the first.
+func (p *Playlist) wraps() bool {
+	if p.passes >= 0 {
+		return p.passes > 0
+	}
+	return p.repeat == RepeatAll
+}
+
+// Shuffled returns whether shuffle is enabled, by track or by album.
 func (p *Playlist) Shuffled() bool { return p.shuffle }
 
+// ShuffledByAlbum returns whether albums rather than tracks are shuffled.
+func (p *Playlist) ShuffledByAlbum() bool { return p.byAlbum }
+
 // Repeat returns the current repeat mode.
 func (p *Playlist) Repeat() RepeatMode { return p.repeat }
@@ -1,6 +1,9 @@
 package playlist
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 // helper builds a playlist with n tracks named "A", "B", "C", ...
 func makePlaylist(n int, shuffle bool) *Playlist {
@@ -249,3 +252,223 @@ func TestMoveQueueBoundary(t *testing.T) {
 		t.Error("MoveQueue(0, 0) should return false")
 	}
 }
+
+func TestVersionChangesWithTracks(t *testing.T) {
+	p := makePlaylist(3, false)
+	v := p.Version()
+
+	p.Next()
+	p.Queue(2)
+	if p.Version() != v {
+		t.Error("Version changed on navigation or queueing")
+	}
+	steps := []struct {
+		name   string
+		mutate func()
+	}{
+		{"Add", func() { p.Add(Track{Title: "D"}) }},
+		{"Move", func() { p.Move(0, 1) }},
+		{"SetTrack", func() { p.SetTrack(0, Track{Title: "Z"}) }},
+		{"Replace", func() { p.Replace(nil) }},
+	}
+	for _, s := range steps {
+		s.mutate()
+		if p.Version() == v {
+			t.Errorf("%s did not change Version", s.name)
+		}
+		v = p.Version()
+	}
+}
+
+func TestIsYTDL(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", true},
+		{"https://soundcloud.com/artist/track", true},
+		{"https://artist.bandcamp.com/album/name", true},
+		{"https://www.mixcloud.com/someone/a-mix/", true},
+		{"https://audiomack.com/artist/song/title", true},
+		{"https://vimeo.com/123456", true},
+		{"scsearch1:lofi", true},
+		{"https://example.com/song.mp3", false},
+		{"/music/song.mp3", false},
+	}
+	for _, tt := range tests {
+		if got := IsYTDL(tt.path); got != tt.want {
+			t.Errorf("IsYTDL(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPeekPrev(t *testing.T) {
+	p := makePlaylist(3, false) // A B C
+	p.SetIndex(1)
+	if tr, ok := p.PeekPrev(); !ok || tr.Title != "A" {
+		t.Errorf("PeekPrev() = %q, %v; want A, true", tr.Title, ok)
+	}
+	if _, idx := p.Current(); idx != 1 {
+		t.Errorf("PeekPrev moved the position to %d", idx)
+	}
+
+	p.SetIndex(0)
+	if _, ok := p.PeekPrev(); ok {
+		t.Error("PeekPrev at the start with repeat off returned a track")
+	}
+	p.repeat = RepeatAll
+	if tr, ok := p.PeekPrev(); !ok || tr.Title != "C" {
+		t.Errorf("PeekPrev() with repeat all = %q, %v; want C, true", tr.Title, ok)
+	}
+}
+
+func TestGroupAlbums(t *testing.T) {
+	p := New()
+	p.Replace([]Track{
+		{Path: "/m/x/2.mp3", Title: "X2", Album: "X", TrackNumber: 2},
+		{Path: "/m/single.mp3", Title: "Single"},
+		{Path: "/m/y/1.mp3", Title: "Y1", Album: "Y", TrackNumber: 1},
+		{Path: "/m/x/d2.mp3", Title: "X1-2", Album: "X", DiscNumber: 2, TrackNumber: 1},
+		{Path: "/m/x/1.mp3", Title: "X1", Album: "X", TrackNumber: 1},
+		{Path: "/other/x.mp3", Title: "OtherX", Album: "X"},
+	})
+	p.SetIndex(4) // X1
+	p.Queue(2)    // Y1
+
+	if !p.GroupAlbums() {
+		t.Fatal("GroupAlbums reported no change")
+	}
+	want := []string{"X1", "X2", "X1-2", "Single", "Y1", "OtherX"}
+	if got := titles(p); !sliceEq(got, want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	if cur, _ := p.Current(); cur.Title != "X1" {
+		t.Errorf("current = %q, want X1", cur.Title)
+	}
+	if got := p.QueuePosition(4); got != 1 {
+		t.Errorf("Y1 queue position = %d, want 1", got)
+	}
+	if next, _ := p.PeekNext(); next.Title != "Y1" {
+		t.Errorf("next = %q, want the queued Y1", next.Title)
+	}
+	if p.GroupAlbums() {
+		t.Error("second GroupAlbums reported a change")
+	}
+}
+
+func TestSetLoopsEndsAfterPasses(t *testing.T) {
+	p := makePlaylist(2, false) // A B
+	p.repeat = RepeatAll
+	p.SetLoops(2)
+	var played []string
+	for {
+		played = append(played, p.tracks[p.order[p.pos]].Title)
+		if _, ok := p.Next(); !ok {
+			break
+		}
+		if len(played) > 10 {
+			t.Fatal("playlist never ended")
+		}
+	}
+	if want := []string{"A", "B", "A", "B"}; !sliceEq(played, want) {
+		t.Fatalf("played %v, want %v", played, want)
+	}
+	if _, ok := p.PeekNext(); ok {
+		t.Fatal("PeekNext past the last pass should report nothing")
+	}
+}
+
+func TestCycleShuffleKeepsAlbumsTogether(t *testing.T) {
+	p := New()
+	for _, album := range []string{"X", "Y", "Z"} {
+		for n := 3; n >= 1; n-- {
+			p.Add(Track{Path: "/m/" + album + "/" + string(rune('0'+n)), Album: album, TrackNumber: n})
+		}
+	}
+	p.CycleShuffle()
+	p.CycleShuffle()
+	if !p.Shuffled() || !p.ShuffledByAlbum() {
+		t.Fatal("two cycles should shuffle by album")
+	}
+	for i := 0; i < len(p.order); i += 3 {
+		a := p.tracks[p.order[i]].Album
+		for j := range 3 {
+			if tr := p.tracks[p.order[i+j]]; tr.Album != a || tr.TrackNumber != j+1 {
+				t.Fatalf("order slot %d holds %s/%d, want %s/%d", i+j, tr.Album, tr.TrackNumber, a, j+1)
+			}
+		}
+	}
+	if cur, _ := p.Current(); cur.Path != "/m/X/3" {
+		t.Fatalf("current track moved to %s", cur.Path)
+	}
+	p.CycleShuffle()
+	if p.Shuffled() || p.ShuffledByAlbum() {
+		t.Fatal("third cycle should turn shuffle off")
+	}
+}
+
+func TestWeightedShuffleFavoursHeavyTracks(t *testing.T) {
+	p := makePlaylist(10, false)
+	p.SetShuffleWeight(func(tr Track) float64 {
+		if tr.Title == "J" {
+			return 1e6
+		}
+		return 1
+	})
+	p.ToggleShuffle()
+	if got := p.tracks[p.order[1]].Title; got != "J" {
+		t.Fatalf("track after the current one is %s, want the heavily weighted J", got)
+	}
+	if p.tracks[p.order[0]].Title != "A" || len(p.order) != 10 {
+		t.Fatalf("order %v lost the current track or a track", p.order)
+	}
+}
+
+func TestRepeatCountMovesOn(t *testing.T) {
+	p := makePlaylist(3, false) // A B C
+	p.SetRepeatCount(2)
+	var played []string
+	for range 4 {
+		tr, _ := p.Next()
+		played = append(played, tr.Title)
+	}
+	if want := []string{"A", "A", "B", "C"}; !sliceEq(played, want) {
+		t.Fatalf("played %v, want %v", played, want)
+	}
+	if p.Repeat() != RepeatOff || p.RepeatsLeft() != 0 {
+		t.Fatalf("repeat = %v with %d left, want off", p.Repeat(), p.RepeatsLeft())
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	p := New()
+	for i := range 4 {
+		p.Add(Track{Title: string(rune('A' + i)), DurationSecs: 60 * (i + 1)}) // 1-4 minutes
+	}
+	p.Next() // B playing
+	if d, ok := p.Remaining(); !ok || d != 7*time.Minute {
+		t.Fatalf("Remaining = %v, %v; want 7m (C, D)", d, ok)
+	}
+	p.Queue(0)
+	if d, _ := p.Remaining(); d != 8*time.Minute {
+		t.Fatalf("with A queued Remaining = %v, want 8m", d)
+	}
+	p.SetLoops(2)
+	if d, _ := p.Remaining(); d != 18*time.Minute {
+		t.Fatalf("with a second pass Remaining = %v, want 18m", d)
+	}
+	p.SetLoops(0)
+	p.repeat = RepeatAll
+	if _, ok := p.Remaining(); ok {
+		t.Fatal("Remaining should be unknown with repeat all")
+	}
+	p.SetRepeatCount(2)
+	if d, _ := p.Remaining(); d != 12*time.Minute {
+		t.Fatalf("repeating B twice Remaining = %v, want 12m", d)
+	}
+	p.repeat = RepeatOff
+	p.Add(Track{Title: "Live", Stream: true})
+	if _, ok := p.Remaining(); ok {
+		t.Fatal("Remaining should be unknown with a live stream ahead")
+	}
+}
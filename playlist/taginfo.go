@@ -0,0 +1,161 @@
+package playlist
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dhowden/tag"
+)
+
+// TagField is one embedded tag, named for display.
+type TagField struct {
+	Name  string
+	Value string
+}
+
+// maxExtraTags caps the miscellaneous tags ReadTagFields returns, so a file
+// stuffed with tagger bookkeeping does not flood the info view.
+const maxExtraTags = 10
+
+// replayGainTags names the ReplayGain tags, in display order. Every tag
+// format stores them under these names, in any case: as Vorbis comments,
+// ID3v2 TXXX descriptions or MP4 freeform atoms.
+var replayGainTags = []TagField{
+	{"replaygain_track_gain", "ReplayGain track"},
+	{"replaygain_track_peak", "ReplayGain track peak"},
+	{"replaygain_album_gain", "ReplayGain album"},
+	{"replaygain_album_peak", "ReplayGain album peak"},
+}
+
+// knownTags are raw tag names that ReadTagFields either reports through the
+// common accessors or skips (pictures, lyrics, text Track already holds).
+var knownTags = map[string]bool{
+	// ID3v2.3/2.4 and ID3v2.2
+	"TIT2": true, "TPE1": true, "TALB": true, "TCON": true, "TYER": true, "TDRC": true,
+	"TRCK": true, "TPE2": true, "TCOM": true, "TPOS": true, "COMM": true, "APIC": true, "USLT": true,
+	"TT2": true, "TP1": true, "TAL": true, "TCO": true, "TYE": true, "TRK": true,
+	"TP2": true, "TCM": true, "TPA": true, "COM": true, "PIC": true, "ULT": true,
+	// Vorbis comments
+	"title": true, "artist": true, "album": true, "genre": true, "date": true, "year": true,
+	"tracknumber": true, "totaltracks": true, "tracktotal": true, "albumartist": true,
+	"album artist": true, "composer": true, "discnumber": true, "totaldiscs": true,
+	"disctotal": true, "comment": true, "description": true, "lyrics": true,
+	"unsyncedlyrics": true, "metadata_block_picture": true,
+	// MP4 atoms
+	"\xa9nam": true, "\xa9ART": true, "\xa9alb": true, "\xa9gen": true, "\xa9day": true,
+	"trkn": true, "aART": true, "\xa9wrt": true, "disk": true, "\xa9cmt": true,
+	"covr": true, "\xa9lyr": true,
+}
+
+// id3Names spells out the ID3v2 frames worth showing by a readable name.
+var id3Names = map[string]string{
+	"TSSE": "Encoder settings",
+	"TENC": "Encoded by",
+	"TCOP": "Copyright",
+	"TPUB": "Publisher",
+	"TBPM": "BPM",
+	"TKEY": "Key",
+	"TSRC": "ISRC",
+	"TLAN": "Language",
+	"TCMP": "Compilation",
+	"TSOA": "Album sort",
+	"TSOP": "Artist sort",
+}
+
+// ReadTagFields returns the embedded tags of a local audio file beyond
+// what Track holds: album artist, composer, disc, comment, ReplayGain
+// values and other text tags. Pictures and lyrics are skipped. It returns
+// nil for URLs and files without tags.
+func ReadTagFields(path string) []TagField {
+	if IsURL(path) {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	m, err := tag.ReadFrom(f)
+	if err != nil || m == nil {
+		return nil
+	}
+
+	var fields []TagField
+	add := func(name, value string) {
+		if value = sanitizeTag(strings.TrimSpace(value)); value != "" {
+			fields = append(fields, TagField{name, value})
+		}
+	}
+	add("Album artist", m.AlbumArtist())
+	add("Composer", m.Composer())
+	if _, total := m.Track(); total > 0 {
+		add("Tracks", fmt.Sprint(total))
+	}
+	if disc, discs := m.Disc(); disc > 0 {
+		d := fmt.Sprint(disc)
+		if discs > 0 {
+			d += fmt.Sprintf(" of %d", discs)
+		}
+		add("Disc", d)
+	}
+	add("Comment", firstLine(m.Comment()))
+	add("Tag format", string(m.Format()))
+
+	// Collect the remaining text tags by their lower-case name, with
+	// TXXX frames named by their description.
+	raw := make(map[string]TagField)
+	for k, v := range m.Raw() {
+		name := dedupName(k)
+		var value string
+		switch v := v.(type) {
+		case string:
+			value = v
+		case *tag.Comm:
+			if name != "TXXX" && name != "TXX" {
+				continue
+			}
+			name, value = v.Description, v.Text
+		default:
+			continue
+		}
+		if knownTags[name] || value == "" || strings.Contains(value, "\n") || len(value) > 200 {
+			continue
+		}
+		label := name
+		if n, ok := id3Names[name]; ok {
+			label = n
+		}
+		raw[strings.ToLower(name)] = TagField{label, value}
+	}
+	for _, rg := range replayGainTags {
+		if t, ok := raw[rg.Name]; ok {
+			add(rg.Value, t.Value)
+			delete(raw, rg.Name)
+		}
+	}
+	extra := make([]TagField, 0, len(raw))
+	for _, t := range raw {
+		extra = append(extra, t)
+	}
+	slices.SortFunc(extra, func(a, b TagField) int { return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) })
+	for _, t := range extra[:min(len(extra), maxExtraTags)] {
+		add(t.Name, t.Value)
+	}
+	return fields
+}
+
+// dedupName strips the "_0", "_1", … suffix the tag reader adds to
+// repeated ID3v2 frames.
+func dedupName(k string) string {
+	if i := strings.LastIndexByte(k, '_'); i > 0 && i < len(k)-1 && strings.Trim(k[i+1:], "0123456789") == "" {
+		return k[:i]
+	}
+	return k
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
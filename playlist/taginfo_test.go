@@ -0,0 +1,43 @@
+package playlist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadTagFields(t *testing.T) {
+	var frames []byte
+	frames = append(frames, id3v23Frame("TIT2", []byte("\x03Song"))...)
+	frames = append(frames, id3v23Frame("TPE2", []byte("\x03Various"))...)
+	frames = append(frames, id3v23Frame("TRCK", []byte("\x033/12"))...)
+	frames = append(frames, id3v23Frame("TXXX", []byte("\x03REPLAYGAIN_ALBUM_GAIN\x00-7.10 dB"))...)
+	frames = append(frames, id3v23Frame("TXXX", []byte("\x03replaygain_track_gain\x00-6.48 dB"))...)
+	frames = append(frames, id3v23Frame("TBPM", []byte("\x03128"))...)
+
+	hdr := []byte("ID3\x03\x00\x00\x00\x00\x00\x00")
+	n := len(frames)
+	hdr[6], hdr[7], hdr[8], hdr[9] = byte(n>>21)&0x7F, byte(n>>14)&0x7F, byte(n>>7)&0x7F, byte(n)&0x7F
+
+	path := filepath.Join(t.TempDir(), "song.mp3")
+	if err := os.WriteFile(path, append(hdr, frames...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got := ReadTagFields(path)
+	want := []TagField{
+		{"Album artist", "Various"},
+		{"Tracks", "12"},
+		{"Tag format", "ID3v2.3"},
+		{"ReplayGain track", "-6.48 dB"},
+		{"ReplayGain album", "-7.10 dB"},
+		{"BPM", "128"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ReadTagFields = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
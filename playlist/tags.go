@@ -33,6 +33,7 @@ func readTags(path string) Track {
 	}
 	trackNum, _ := m.Track()
 	t.TrackNumber = trackNum
+	t.DiscNumber, _ = m.Disc()
 	return t
 }
 
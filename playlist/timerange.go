@@ -0,0 +1,82 @@
+package playlist
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rangeFragment introduces a time range annotation on a path, in the style
+// of Media Fragments: "talk.flac#t=1:00,2:30" plays from one minute to two
+// and a half; "#t=1:00" plays from one minute to the end and "#t=,2:30"
+// from the start to two and a half minutes.
+const rangeFragment = "#t="
+
+// Ranged reports whether the track plays only part of its file.
+func (t Track) Ranged() bool {
+	return t.Start > 0 || t.End > 0
+}
+
+// PlayPath returns the path to hand to the player: Path itself, or Path
+// with the track's range appended in seconds when it has one.
+func (t Track) PlayPath() string {
+	if !t.Ranged() || IsURL(t.Path) || IsStdin(t.Path) {
+		return t.Path
+	}
+	s := t.Path + rangeFragment
+	if t.Start > 0 {
+		s += strconv.FormatFloat(t.Start.Seconds(), 'f', -1, 64)
+	}
+	if t.End > 0 {
+		s += "," + strconv.FormatFloat(t.End.Seconds(), 'f', -1, 64)
+	}
+	return s
+}
+
+// SplitRange separates a trailing range annotation from a local path. It
+// returns the path unchanged, with a zero range, when there is none, when
+// the annotation does not parse, or when a file by the full name exists.
+func SplitRange(path string) (string, time.Duration, time.Duration) {
+	i := strings.LastIndex(path, rangeFragment)
+	if i <= 0 || IsURL(path) {
+		return path, 0, 0
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path, 0, 0
+	}
+	from, to, _ := strings.Cut(path[i+len(rangeFragment):], ",")
+	var start, end time.Duration
+	var err error
+	if from != "" {
+		if start, err = ParseClock(from); err != nil {
+			return path, 0, 0
+		}
+	}
+	if to != "" {
+		if end, err = ParseClock(to); err != nil || end <= start {
+			return path, 0, 0
+		}
+	}
+	return path[:i], start, end
+}
+
+// ParseClock parses a position in a track: seconds ("90", "90.5"), or
+// minutes and seconds ("1:30"), optionally with hours ("1:02:03").
+func ParseClock(s string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q (use ss, mm:ss or hh:mm:ss)", s)
+	}
+	var secs float64
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || strings.Trim(p, "0123456789.") != "" || (i > 0 && v >= 60) ||
+			(i < len(parts)-1 && strings.Contains(p, ".")) {
+			return 0, fmt.Errorf("invalid time %q (use ss, mm:ss or hh:mm:ss)", s)
+		}
+		secs = secs*60 + v
+	}
+	return time.Duration(secs * float64(time.Second)), nil
+}
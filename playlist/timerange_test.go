@@ -0,0 +1,31 @@
+package playlist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitRange(t *testing.T) {
+	for _, c := range []struct {
+		in         string
+		path       string
+		start, end time.Duration
+	}{
+		{"talk.flac#t=1:00,2:30", "talk.flac", time.Minute, 150 * time.Second},
+		{"talk.flac#t=45", "talk.flac", 45 * time.Second, 0},
+		{"talk.flac#t=,1:02:03.5", "talk.flac", 0, time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{"talk.flac#t=2:30,1:00", "talk.flac#t=2:30,1:00", 0, 0},
+		{"talk.flac#t=1:75", "talk.flac#t=1:75", 0, 0},
+		{"http://host/a.mp3#t=10", "http://host/a.mp3#t=10", 0, 0},
+	} {
+		path, start, end := SplitRange(c.in)
+		if path != c.path || start != c.start || end != c.end {
+			t.Errorf("SplitRange(%q) = %q %v %v, want %q %v %v", c.in, path, start, end, c.path, c.start, c.end)
+		}
+	}
+
+	tr := Track{Path: "talk.flac", Start: 90 * time.Second, End: 150500 * time.Millisecond}
+	if got := tr.PlayPath(); got != "talk.flac#t=90,150.5" {
+		t.Errorf("PlayPath = %q", got)
+	}
+}
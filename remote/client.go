@@ -0,0 +1,185 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotRunning is returned by Call when no instance is listening.
+var ErrNotRunning = errors.New("cliamp is not running")
+
+// dialTimeout bounds how long a command waits for the running instance.
+const dialTimeout = 2 * time.Second
+
+// commands is the set of subcommands forwarded to a running instance.
+var commands = map[string]bool{
+	"play": true, "pause": true, "toggle": true, "next": true, "prev": true,
+	"stop": true, "quit": true, "add": true, "open": true, "status": true,
+}
+
+// IsCommand reports whether name is a remote subcommand.
+func IsCommand(name string) bool {
+	return commands[name]
+}
+
+// Call sends req to the running instance and returns its answer.
+func Call(req Request) (Response, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return Response{}, err
+	}
+	c, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return Response{}, ErrNotRunning
+	}
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(requestTimeout))
+
+	if err := json.NewEncoder(c).Encode(req); err != nil {
+		return Response{}, err
+	}
+	var resp Response
+	if err := json.NewDecoder(bufio.NewReader(c)).Decode(&resp); err != nil {
+		return Response{}, err
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// Watch calls fn with the running instance's status now and again each
+// time it changes, until fn or the connection fails.
+func Watch(fn func(Status) error) error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	c, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return ErrNotRunning
+	}
+	defer c.Close()
+
+	if err := json.NewEncoder(c).Encode(Request{Cmd: "watch"}); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bufio.NewReader(c))
+	for {
+		var resp Response
+		if err := dec.Decode(&resp); err != nil {
+			return err
+		}
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+		if resp.Status != nil {
+			if err := fn(*resp.Status); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RunCommand executes `cliamp <cmd> [args]` against the running instance
+// and prints any result to w. `status --json` prints the status as one
+// line of JSON, in the form the HTTP /status endpoint serves.
+func RunCommand(args []string, w io.Writer) error {
+	req := Request{Cmd: args[0], Args: args[1:]}
+	asJSON := false
+	switch req.Cmd {
+	case "add", "open":
+		req.Args = absPaths(req.Args)
+	case "status":
+		for _, a := range req.Args {
+			if a != "--json" {
+				return fmt.Errorf("status: unknown option %q (use --json)", a)
+			}
+			asJSON = true
+		}
+		req.Args = nil
+	}
+	resp, err := Call(req)
+	if err != nil {
+		return err
+	}
+	if resp.Status != nil {
+		if asJSON {
+			return json.NewEncoder(w).Encode(resp.Status)
+		}
+		fmt.Fprint(w, FormatStatus(*resp.Status))
+	}
+	return nil
+}
+
+// Forward hands paths to the running instance, appending them to its
+// playlist or, with replace, swapping the playlist for them and playing.
+// It returns ErrNotRunning when there is no instance to take them.
+func Forward(paths []string, replace bool) error {
+	cmd := "add"
+	if replace {
+		cmd = "open"
+	}
+	_, err := Call(Request{Cmd: cmd, Args: absPaths(paths)})
+	return err
+}
+
+// absPaths makes local paths absolute, since the player resolves them
+// from its own working directory. URLs are left alone.
+func absPaths(paths []string) []string {
+	out := make([]string, len(paths))
+	for i, a := range paths {
+		out[i] = a
+		if !strings.Contains(a, "://") {
+			if abs, err := filepath.Abs(a); err == nil {
+				out[i] = abs
+			}
+		}
+	}
+	return out
+}
+
+// FormatStatus renders st for humans, e.g.
+//
+//	[playing] Band - Song
+//	1:23 / 4:01  track 3/12  vol -4.0 dB
+func FormatStatus(st Status) string {
+	var b strings.Builder
+	name := st.Track.Title
+	if name == "" {
+		name = filepath.Base(st.Track.Path)
+	}
+	if st.Track.Artist != "" {
+		name = st.Track.Artist + " - " + name
+	}
+	if st.Index < 0 {
+		name = "(empty playlist)"
+	}
+	fmt.Fprintf(&b, "[%s] %s\n", st.State, name)
+
+	fmt.Fprintf(&b, "%s", clock(st.Position))
+	if st.Duration > 0 {
+		fmt.Fprintf(&b, " / %s", clock(st.Duration))
+	}
+	if st.Index >= 0 {
+		fmt.Fprintf(&b, "  track %d/%d", st.Index+1, st.Length)
+	}
+	fmt.Fprintf(&b, "  vol %+.1f dB\n", st.Volume)
+	return b.String()
+}
+
+// clock formats seconds as m:ss, or h:mm:ss past an hour.
+func clock(secs float64) string {
+	s := int(secs)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
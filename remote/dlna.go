@@ -0,0 +1,705 @@
+package remote
+
+import (
+	"crypto/sha1"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cliamp/mpris"
+	"cliamp/playlist"
+)
+
+// UPnP service and device types of a MediaRenderer.
+const (
+	upnpRenderer  = "urn:schemas-upnp-org:device:MediaRenderer:1"
+	upnpTransport = "urn:schemas-upnp-org:service:AVTransport:1"
+	upnpRendering = "urn:schemas-upnp-org:service:RenderingControl:1"
+	upnpConnMgr   = "urn:schemas-upnp-org:service:ConnectionManager:1"
+)
+
+// sinkProtocols lists what the renderer accepts, in GetProtocolInfo form.
+var sinkProtocols = []string{
+	"http-get:*:audio/mpeg:*", "http-get:*:audio/mp3:*", "http-get:*:audio/flac:*",
+	"http-get:*:audio/x-flac:*", "http-get:*:audio/ogg:*", "http-get:*:audio/opus:*",
+	"http-get:*:audio/wav:*", "http-get:*:audio/x-wav:*", "http-get:*:audio/L16:*",
+	"http-get:*:audio/mp4:*", "http-get:*:audio/aac:*", "http-get:*:audio/x-m4a:*",
+	"http-get:*:audio/x-ms-wma:*", "http-get:*:application/ogg:*",
+}
+
+// DLNARenderer exposes the player as a UPnP AV MediaRenderer, so control
+// points such as BubbleUPnP or a phone's "play to" menu can cast audio to
+// it. A cast track is appended to the playlist and played. Control points
+// poll for state; no events are sent.
+type DLNARenderer struct {
+	ln   net.Listener
+	srv  *http.Server
+	ssdp *ssdpServer
+	hub  *Hub
+	send func(interface{})
+	name string
+	uuid string
+
+	mu          sync.Mutex
+	pending     *playlist.Track // set by SetAVTransportURI, started by Play
+	pendingMeta string
+}
+
+// ListenDLNA serves the renderer on addr (e.g. ":49494") and announces it
+// on the local network under the given friendly name ("" picks
+// "cliamp on <host>"). Commands are forwarded to the UI through send.
+func ListenDLNA(addr, name string, hub *Hub, send func(interface{})) (*DLNARenderer, error) {
+	if name == "" {
+		host, _ := os.Hostname()
+		name = "cliamp on " + host
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	r := &DLNARenderer{ln: ln, hub: hub, send: send, name: name, uuid: rendererUUID(name)}
+	r.srv = &http.Server{Handler: r.handler(), ReadHeaderTimeout: 10 * time.Second}
+	go r.srv.Serve(ln)
+
+	r.ssdp, err = startSSDP(r.uuid, ln.Addr().(*net.TCPAddr).Port)
+	if err != nil {
+		r.srv.Close()
+		return nil, fmt.Errorf("ssdp: %w", err)
+	}
+	return r, nil
+}
+
+// Addr returns the address the renderer's HTTP server is listening on.
+func (r *DLNARenderer) Addr() string { return r.ln.Addr().String() }
+
+// Close says goodbye on the network and stops the server.
+func (r *DLNARenderer) Close() error {
+	if r.ssdp != nil {
+		r.ssdp.Close()
+	}
+	return r.srv.Close()
+}
+
+// rendererUUID derives a stable device UUID from the host and friendly
+// name, so control points recognise the renderer across restarts.
+func rendererUUID(name string) string {
+	host, _ := os.Hostname()
+	h := sha1.Sum([]byte("cliamp-renderer\x00" + host + "\x00" + name))
+	h[6] = h[6]&0x0f | 0x50 // version 5 (name-based, SHA-1)
+	h[8] = h[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+func (r *DLNARenderer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/description.xml", func(w http.ResponseWriter, req *http.Request) {
+		writeXML(w, r.description())
+	})
+	for _, svc := range []struct {
+		name    string
+		urn     string
+		scpd    string
+		actions func(action string, args map[string]string) ([][2]string, error)
+	}{
+		{"AVTransport", upnpTransport, transportSCPD, r.transport},
+		{"RenderingControl", upnpRendering, renderingSCPD, r.rendering},
+		{"ConnectionManager", upnpConnMgr, connMgrSCPD, r.connMgr},
+	} {
+		svc := svc
+		mux.HandleFunc("/"+svc.name+".xml", func(w http.ResponseWriter, req *http.Request) {
+			writeXML(w, svc.scpd)
+		})
+		mux.HandleFunc("/control/"+svc.name, func(w http.ResponseWriter, req *http.Request) {
+			serveSOAP(w, req, svc.urn, svc.actions)
+		})
+		mux.HandleFunc("/event/"+svc.name, serveSubscribe)
+	}
+	return mux
+}
+
+func writeXML(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	io.WriteString(w, body)
+}
+
+// serveSubscribe accepts GENA subscriptions so control points that insist
+// on them carry on; they fall back to polling when no events arrive.
+func serveSubscribe(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "SUBSCRIBE":
+		sid := req.Header.Get("SID")
+		if sid == "" {
+			sid = "uuid:" + rendererUUID(req.RemoteAddr+time.Now().String())
+		}
+		w.Header().Set("SID", sid)
+		w.Header().Set("TIMEOUT", "Second-1800")
+	case "UNSUBSCRIBE":
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// upnpError is a SOAP fault with a UPnP error code.
+type upnpError struct {
+	code int
+	desc string
+}
+
+func (e *upnpError) Error() string { return e.desc }
+
+var (
+	errInvalidAction = &upnpError{401, "Invalid Action"}
+	errInvalidArgs   = &upnpError{402, "Invalid Args"}
+	errNoTransition  = &upnpError{701, "Transition not available"}
+	errSeekMode      = &upnpError{710, "Seek mode not supported"}
+	errIllegalTarget = &upnpError{711, "Illegal seek target"}
+)
+
+// serveSOAP decodes a control request, runs the action and writes the
+// response envelope with the output arguments in order.
+func serveSOAP(w http.ResponseWriter, req *http.Request, urn string, run func(string, map[string]string) ([][2]string, error)) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	action, args, err := parseSOAP(io.LimitReader(req.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	out, err := run(action, args)
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	w.Header().Set("EXT", "")
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	if err != nil {
+		ue, ok := err.(*upnpError)
+		if !ok {
+			ue = &upnpError{501, err.Error()}
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(&b, `<s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail>`+
+			`<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>%d</errorCode><errorDescription>%s</errorDescription></UPnPError>`+
+			`</detail></s:Fault>`, ue.code, escapeXML(ue.desc))
+	} else {
+		fmt.Fprintf(&b, `<u:%sResponse xmlns:u="%s">`, action, urn)
+		for _, kv := range out {
+			fmt.Fprintf(&b, "<%s>%s</%s>", kv[0], escapeXML(kv[1]), kv[0])
+		}
+		fmt.Fprintf(&b, "</u:%sResponse>", action)
+	}
+	b.WriteString("</s:Body></s:Envelope>")
+	io.WriteString(w, b.String())
+}
+
+// parseSOAP returns the action element's name and its child arguments.
+func parseSOAP(r io.Reader) (string, map[string]string, error) {
+	dec := xml.NewDecoder(r)
+	inBody := false
+	action := ""
+	args := make(map[string]string)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case !inBody:
+			inBody = start.Name.Local == "Body"
+		case action == "":
+			action = start.Name.Local
+		default:
+			var v string
+			if err := dec.DecodeElement(&v, &start); err != nil {
+				return "", nil, err
+			}
+			args[start.Name.Local] = v
+		}
+	}
+	if action == "" {
+		return "", nil, errors.New("no SOAP action")
+	}
+	return action, args, nil
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// transport implements the AVTransport service.
+func (r *DLNARenderer) transport(action string, args map[string]string) ([][2]string, error) {
+	st := r.hub.Status()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch action {
+	case "SetAVTransportURI":
+		uri := args["CurrentURI"]
+		if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+			return nil, errInvalidArgs
+		}
+		t := trackFromDIDL(args["CurrentURIMetaData"])
+		t.Path = uri
+		t.Stream = true
+		r.pending = &t
+		r.pendingMeta = args["CurrentURIMetaData"]
+		return nil, nil
+	case "Play":
+		if r.pending != nil {
+			r.send(PlayTrackMsg{Track: *r.pending})
+			r.pending = nil
+			return nil, nil
+		}
+		if st.Index < 0 {
+			return nil, errNoTransition
+		}
+		if st.State != "playing" {
+			r.send(mpris.PlayPauseMsg{})
+		}
+		return nil, nil
+	case "Pause":
+		if st.State == "playing" {
+			r.send(mpris.PlayPauseMsg{})
+		}
+		return nil, nil
+	case "Stop":
+		r.pending = nil
+		r.send(mpris.StopMsg{})
+		return nil, nil
+	case "Next":
+		r.send(mpris.NextMsg{})
+		return nil, nil
+	case "Previous":
+		r.send(mpris.PrevMsg{})
+		return nil, nil
+	case "Seek":
+		if unit := args["Unit"]; unit != "REL_TIME" && unit != "ABS_TIME" {
+			return nil, errSeekMode
+		}
+		secs, ok := parseUPnPTime(args["Target"])
+		if !ok {
+			return nil, errIllegalTarget
+		}
+		r.send(mpris.SetPositionMsg{Position: int64(secs * 1e6)})
+		return nil, nil
+	case "GetTransportInfo":
+		return [][2]string{
+			{"CurrentTransportState", r.transportState(st)},
+			{"CurrentTransportStatus", "OK"},
+			{"CurrentSpeed", "1"},
+		}, nil
+	case "GetPositionInfo":
+		uri, meta, dur := r.current(st)
+		pos := st.Position
+		if r.pending != nil {
+			pos = 0
+		}
+		return [][2]string{
+			{"Track", boolDigit(uri != "")},
+			{"TrackDuration", formatUPnPTime(dur)},
+			{"TrackMetaData", meta},
+			{"TrackURI", uri},
+			{"RelTime", formatUPnPTime(pos)},
+			{"AbsTime", formatUPnPTime(pos)},
+			{"RelCount", "2147483647"},
+			{"AbsCount", "2147483647"},
+		}, nil
+	case "GetMediaInfo":
+		uri, meta, dur := r.current(st)
+		return [][2]string{
+			{"NrTracks", boolDigit(uri != "")},
+			{"MediaDuration", formatUPnPTime(dur)},
+			{"CurrentURI", uri},
+			{"CurrentURIMetaData", meta},
+			{"NextURI", ""},
+			{"NextURIMetaData", ""},
+			{"PlayMedium", "NETWORK"},
+			{"RecordMedium", "NOT_IMPLEMENTED"},
+			{"WriteStatus", "NOT_IMPLEMENTED"},
+		}, nil
+	case "GetDeviceCapabilities":
+		return [][2]string{
+			{"PlayMedia", "NETWORK"},
+			{"RecMedia", "NOT_IMPLEMENTED"},
+			{"RecQualityModes", "NOT_IMPLEMENTED"},
+		}, nil
+	case "GetTransportSettings":
+		return [][2]string{{"PlayMode", "NORMAL"}, {"RecQualityMode", "NOT_IMPLEMENTED"}}, nil
+	case "GetCurrentTransportActions":
+		return [][2]string{{"Actions", "Play,Pause,Stop,Seek,Next,Previous"}}, nil
+	}
+	return nil, errInvalidAction
+}
+
+// transportState maps the player state to a UPnP TransportState. A track
+// set but not yet played reads as STOPPED, as the spec expects.
+func (r *DLNARenderer) transportState(st Status) string {
+	switch {
+	case r.pending != nil:
+		return "STOPPED"
+	case st.Index < 0:
+		return "NO_MEDIA_PRESENT"
+	case st.State == "playing":
+		return "PLAYING"
+	case st.State == "paused":
+		return "PAUSED_PLAYBACK"
+	}
+	return "STOPPED"
+}
+
+// current returns the URI, DIDL-Lite metadata and duration of the track
+// the renderer is on: the pending cast, or whatever cliamp is playing.
+func (r *DLNARenderer) current(st Status) (uri, meta string, dur float64) {
+	if r.pending != nil {
+		return r.pending.Path, r.pendingMeta, float64(r.pending.DurationSecs)
+	}
+	if st.Index < 0 {
+		return "", "", 0
+	}
+	dur = st.Duration
+	if dur == 0 {
+		dur = float64(st.Track.Duration)
+	}
+	return st.Track.Path, didl(st.Track), dur
+}
+
+// rendering implements the RenderingControl service. Volume uses the same
+// 0–100 scale as the MPD server.
+func (r *DLNARenderer) rendering(action string, args map[string]string) ([][2]string, error) {
+	switch action {
+	case "GetVolume":
+		return [][2]string{{"CurrentVolume", strconv.Itoa(mpdVolume(r.hub.Status().Volume))}}, nil
+	case "SetVolume":
+		v, err := strconv.Atoi(args["DesiredVolume"])
+		if err != nil || v < 0 || v > 100 {
+			return nil, errInvalidArgs
+		}
+		r.send(mpris.SetVolumeMsg{Volume: float64(v) / 100})
+		return nil, nil
+	case "GetMute":
+		return [][2]string{{"CurrentMute", "0"}}, nil
+	case "ListPresets":
+		return [][2]string{{"CurrentPresetNameList", "FactoryDefaults"}}, nil
+	}
+	return nil, errInvalidAction
+}
+
+// connMgr implements the ConnectionManager service with a single,
+// permanent connection.
+func (r *DLNARenderer) connMgr(action string, _ map[string]string) ([][2]string, error) {
+	switch action {
+	case "GetProtocolInfo":
+		return [][2]string{{"Source", ""}, {"Sink", strings.Join(sinkProtocols, ",")}}, nil
+	case "GetCurrentConnectionIDs":
+		return [][2]string{{"ConnectionIDs", "0"}}, nil
+	case "GetCurrentConnectionInfo":
+		return [][2]string{
+			{"RcsID", "0"},
+			{"AVTransportID", "0"},
+			{"ProtocolInfo", ""},
+			{"PeerConnectionManager", ""},
+			{"PeerConnectionID", "-1"},
+			{"Direction", "Input"},
+			{"Status", "OK"},
+		}, nil
+	}
+	return nil, errInvalidAction
+}
+
+func boolDigit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// formatUPnPTime renders seconds as H:MM:SS.
+func formatUPnPTime(secs float64) string {
+	s := int(secs)
+	return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+// parseUPnPTime parses H+:MM:SS with optional fractional seconds.
+func parseUPnPTime(s string) (float64, bool) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	h, err1 := strconv.Atoi(parts[0])
+	m, err2 := strconv.Atoi(parts[1])
+	sec, err3 := strconv.ParseFloat(parts[2], 64)
+	if err1 != nil || err2 != nil || err3 != nil || h < 0 || m < 0 || m > 59 || sec < 0 || sec >= 60 {
+		return 0, false
+	}
+	return float64(h*3600+m*60) + sec, true
+}
+
+// trackFromDIDL reads title, artist, album and duration from DIDL-Lite
+// metadata. Missing or malformed metadata yields an empty track.
+func trackFromDIDL(meta string) playlist.Track {
+	var doc struct {
+		Items []struct {
+			Title   string `xml:"http://purl.org/dc/elements/1.1/ title"`
+			Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			Artist  string `xml:"urn:schemas-upnp-org:metadata-1-0/upnp/ artist"`
+			Album   string `xml:"urn:schemas-upnp-org:metadata-1-0/upnp/ album"`
+			Genre   string `xml:"urn:schemas-upnp-org:metadata-1-0/upnp/ genre"`
+			Res     []struct {
+				Duration string `xml:"duration,attr"`
+			} `xml:"res"`
+		} `xml:"item"`
+	}
+	var t playlist.Track
+	if meta == "" || xml.Unmarshal([]byte(meta), &doc) != nil || len(doc.Items) == 0 {
+		return t
+	}
+	item := doc.Items[0]
+	t.Title = strings.TrimSpace(item.Title)
+	t.Artist = strings.TrimSpace(item.Artist)
+	if t.Artist == "" {
+		t.Artist = strings.TrimSpace(item.Creator)
+	}
+	t.Album = strings.TrimSpace(item.Album)
+	t.Genre = strings.TrimSpace(item.Genre)
+	for _, res := range item.Res {
+		if secs, ok := parseUPnPTime(res.Duration); ok {
+			t.DurationSecs = int(secs)
+			break
+		}
+	}
+	return t
+}
+
+// didl describes a track as DIDL-Lite for GetPositionInfo and GetMediaInfo.
+func didl(t Track) string {
+	title := t.Title
+	if title == "" {
+		title = t.Path
+	}
+	var b strings.Builder
+	b.WriteString(`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">`)
+	b.WriteString(`<item id="0" parentID="-1" restricted="1">`)
+	fmt.Fprintf(&b, "<dc:title>%s</dc:title>", escapeXML(title))
+	if t.Artist != "" {
+		fmt.Fprintf(&b, "<dc:creator>%s</dc:creator><upnp:artist>%s</upnp:artist>", escapeXML(t.Artist), escapeXML(t.Artist))
+	}
+	if t.Album != "" {
+		fmt.Fprintf(&b, "<upnp:album>%s</upnp:album>", escapeXML(t.Album))
+	}
+	b.WriteString("<upnp:class>object.item.audioItem.musicTrack</upnp:class>")
+	fmt.Fprintf(&b, "<res>%s</res></item></DIDL-Lite>", escapeXML(t.Path))
+	return b.String()
+}
+
+func (r *DLNARenderer) description() string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0" xmlns:dlna="urn:schemas-dlna-org:device-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <device>
+    <deviceType>` + upnpRenderer + `</deviceType>
+    <friendlyName>` + escapeXML(r.name) + `</friendlyName>
+    <manufacturer>cliamp</manufacturer>
+    <manufacturerURL>https://github.com/bjarneo/cliamp</manufacturerURL>
+    <modelName>cliamp</modelName>
+    <modelDescription>Terminal music player</modelDescription>
+    <UDN>uuid:` + r.uuid + `</UDN>
+    <dlna:X_DLNADOC>DMR-1.50</dlna:X_DLNADOC>
+    <serviceList>
+      <service>
+        <serviceType>` + upnpTransport + `</serviceType>
+        <serviceId>urn:upnp-org:serviceId:AVTransport</serviceId>
+        <SCPDURL>/AVTransport.xml</SCPDURL>
+        <controlURL>/control/AVTransport</controlURL>
+        <eventSubURL>/event/AVTransport</eventSubURL>
+      </service>
+      <service>
+        <serviceType>` + upnpRendering + `</serviceType>
+        <serviceId>urn:upnp-org:serviceId:RenderingControl</serviceId>
+        <SCPDURL>/RenderingControl.xml</SCPDURL>
+        <controlURL>/control/RenderingControl</controlURL>
+        <eventSubURL>/event/RenderingControl</eventSubURL>
+      </service>
+      <service>
+        <serviceType>` + upnpConnMgr + `</serviceType>
+        <serviceId>urn:upnp-org:serviceId:ConnectionManager</serviceId>
+        <SCPDURL>/ConnectionManager.xml</SCPDURL>
+        <controlURL>/control/ConnectionManager</controlURL>
+        <eventSubURL>/event/ConnectionManager</eventSubURL>
+      </service>
+    </serviceList>
+  </device>
+</root>
+`
+}
+
+// scpdArg is an action argument and the state variable that types it.
+type scpdArg struct{ name, variable string }
+
+type scpdAction struct {
+	name    string
+	in, out []scpdArg
+}
+
+// scpdVar is a state variable; allowed lists its permitted values.
+type scpdVar struct {
+	name, dataType string
+	allowed        []string
+}
+
+// buildSCPD renders a service description.
+func buildSCPD(actions []scpdAction, vars []scpdVar) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n" +
+		`<scpd xmlns="urn:schemas-upnp-org:service-1-0"><specVersion><major>1</major><minor>0</minor></specVersion><actionList>`)
+	for _, a := range actions {
+		fmt.Fprintf(&b, "<action><name>%s</name><argumentList>", a.name)
+		for _, arg := range a.in {
+			fmt.Fprintf(&b, "<argument><name>%s</name><direction>in</direction><relatedStateVariable>%s</relatedStateVariable></argument>", arg.name, arg.variable)
+		}
+		for _, arg := range a.out {
+			fmt.Fprintf(&b, "<argument><name>%s</name><direction>out</direction><relatedStateVariable>%s</relatedStateVariable></argument>", arg.name, arg.variable)
+		}
+		b.WriteString("</argumentList></action>")
+	}
+	b.WriteString("</actionList><serviceStateTable>")
+	for _, v := range vars {
+		events := "no"
+		if v.name == "LastChange" {
+			events = "yes"
+		}
+		fmt.Fprintf(&b, `<stateVariable sendEvents="%s"><name>%s</name><dataType>%s</dataType>`, events, v.name, v.dataType)
+		if len(v.allowed) > 0 {
+			b.WriteString("<allowedValueList>")
+			for _, a := range v.allowed {
+				fmt.Fprintf(&b, "<allowedValue>%s</allowedValue>", a)
+			}
+			b.WriteString("</allowedValueList>")
+		}
+		b.WriteString("</stateVariable>")
+	}
+	b.WriteString("</serviceStateTable></scpd>\n")
+	return b.String()
+}
+
+var instanceArg = scpdArg{"InstanceID", "A_ARG_TYPE_InstanceID"}
+
+var transportSCPD = buildSCPD([]scpdAction{
+	{name: "SetAVTransportURI", in: []scpdArg{instanceArg, {"CurrentURI", "AVTransportURI"}, {"CurrentURIMetaData", "AVTransportURIMetaData"}}},
+	{name: "GetMediaInfo", in: []scpdArg{instanceArg}, out: []scpdArg{
+		{"NrTracks", "NumberOfTracks"}, {"MediaDuration", "CurrentMediaDuration"},
+		{"CurrentURI", "AVTransportURI"}, {"CurrentURIMetaData", "AVTransportURIMetaData"},
+		{"NextURI", "NextAVTransportURI"}, {"NextURIMetaData", "NextAVTransportURIMetaData"},
+		{"PlayMedium", "PlaybackStorageMedium"}, {"RecordMedium", "RecordStorageMedium"},
+		{"WriteStatus", "RecordMediumWriteStatus"},
+	}},
+	{name: "GetTransportInfo", in: []scpdArg{instanceArg}, out: []scpdArg{
+		{"CurrentTransportState", "TransportState"}, {"CurrentTransportStatus", "TransportStatus"},
+		{"CurrentSpeed", "TransportPlaySpeed"},
+	}},
+	{name: "GetPositionInfo", in: []scpdArg{instanceArg}, out: []scpdArg{
+		{"Track", "CurrentTrack"}, {"TrackDuration", "CurrentTrackDuration"},
+		{"TrackMetaData", "CurrentTrackMetaData"}, {"TrackURI", "CurrentTrackURI"},
+		{"RelTime", "RelativeTimePosition"}, {"AbsTime", "AbsoluteTimePosition"},
+		{"RelCount", "RelativeCounterPosition"}, {"AbsCount", "AbsoluteCounterPosition"},
+	}},
+	{name: "GetDeviceCapabilities", in: []scpdArg{instanceArg}, out: []scpdArg{
+		{"PlayMedia", "PossiblePlaybackStorageMedia"}, {"RecMedia", "PossibleRecordStorageMedia"},
+		{"RecQualityModes", "PossibleRecordQualityModes"},
+	}},
+	{name: "GetTransportSettings", in: []scpdArg{instanceArg}, out: []scpdArg{
+		{"PlayMode", "CurrentPlayMode"}, {"RecQualityMode", "CurrentRecordQualityMode"},
+	}},
+	{name: "GetCurrentTransportActions", in: []scpdArg{instanceArg}, out: []scpdArg{{"Actions", "CurrentTransportActions"}}},
+	{name: "Play", in: []scpdArg{instanceArg, {"Speed", "TransportPlaySpeed"}}},
+	{name: "Pause", in: []scpdArg{instanceArg}},
+	{name: "Stop", in: []scpdArg{instanceArg}},
+	{name: "Seek", in: []scpdArg{instanceArg, {"Unit", "A_ARG_TYPE_SeekMode"}, {"Target", "A_ARG_TYPE_SeekTarget"}}},
+	{name: "Next", in: []scpdArg{instanceArg}},
+	{name: "Previous", in: []scpdArg{instanceArg}},
+}, []scpdVar{
+	{"TransportState", "string", []string{"STOPPED", "PLAYING", "PAUSED_PLAYBACK", "NO_MEDIA_PRESENT"}},
+	{"TransportStatus", "string", []string{"OK", "ERROR_OCCURRED"}},
+	{"TransportPlaySpeed", "string", []string{"1"}},
+	{"NumberOfTracks", "ui4", nil},
+	{"CurrentMediaDuration", "string", nil},
+	{"AVTransportURI", "string", nil},
+	{"AVTransportURIMetaData", "string", nil},
+	{"NextAVTransportURI", "string", nil},
+	{"NextAVTransportURIMetaData", "string", nil},
+	{"PlaybackStorageMedium", "string", []string{"NETWORK"}},
+	{"RecordStorageMedium", "string", []string{"NOT_IMPLEMENTED"}},
+	{"RecordMediumWriteStatus", "string", []string{"NOT_IMPLEMENTED"}},
+	{"PossiblePlaybackStorageMedia", "string", nil},
+	{"PossibleRecordStorageMedia", "string", nil},
+	{"PossibleRecordQualityModes", "string", nil},
+	{"CurrentPlayMode", "string", []string{"NORMAL"}},
+	{"CurrentRecordQualityMode", "string", []string{"NOT_IMPLEMENTED"}},
+	{"CurrentTransportActions", "string", nil},
+	{"CurrentTrack", "ui4", nil},
+	{"CurrentTrackDuration", "string", nil},
+	{"CurrentTrackMetaData", "string", nil},
+	{"CurrentTrackURI", "string", nil},
+	{"RelativeTimePosition", "string", nil},
+	{"AbsoluteTimePosition", "string", nil},
+	{"RelativeCounterPosition", "i4", nil},
+	{"AbsoluteCounterPosition", "i4", nil},
+	{"LastChange", "string", nil},
+	{"A_ARG_TYPE_InstanceID", "ui4", nil},
+	{"A_ARG_TYPE_SeekMode", "string", []string{"REL_TIME", "ABS_TIME"}},
+	{"A_ARG_TYPE_SeekTarget", "string", nil},
+})
+
+var renderingSCPD = buildSCPD([]scpdAction{
+	{name: "ListPresets", in: []scpdArg{instanceArg}, out: []scpdArg{{"CurrentPresetNameList", "PresetNameList"}}},
+	{name: "GetVolume", in: []scpdArg{instanceArg, {"Channel", "A_ARG_TYPE_Channel"}}, out: []scpdArg{{"CurrentVolume", "Volume"}}},
+	{name: "SetVolume", in: []scpdArg{instanceArg, {"Channel", "A_ARG_TYPE_Channel"}, {"DesiredVolume", "Volume"}}},
+	{name: "GetMute", in: []scpdArg{instanceArg, {"Channel", "A_ARG_TYPE_Channel"}}, out: []scpdArg{{"CurrentMute", "Mute"}}},
+}, []scpdVar{
+	{"PresetNameList", "string", nil},
+	{"Volume", "ui2", nil},
+	{"Mute", "boolean", nil},
+	{"LastChange", "string", nil},
+	{"A_ARG_TYPE_InstanceID", "ui4", nil},
+	{"A_ARG_TYPE_Channel", "string", []string{"Master"}},
+})
+
+var connMgrSCPD = buildSCPD([]scpdAction{
+	{name: "GetProtocolInfo", out: []scpdArg{{"Source", "SourceProtocolInfo"}, {"Sink", "SinkProtocolInfo"}}},
+	{name: "GetCurrentConnectionIDs", out: []scpdArg{{"ConnectionIDs", "CurrentConnectionIDs"}}},
+	{name: "GetCurrentConnectionInfo", in: []scpdArg{{"ConnectionID", "A_ARG_TYPE_ConnectionID"}}, out: []scpdArg{
+		{"RcsID", "A_ARG_TYPE_RcsID"}, {"AVTransportID", "A_ARG_TYPE_AVTransportID"},
+		{"ProtocolInfo", "A_ARG_TYPE_ProtocolInfo"}, {"PeerConnectionManager", "A_ARG_TYPE_ConnectionManager"},
+		{"PeerConnectionID", "A_ARG_TYPE_ConnectionID"}, {"Direction", "A_ARG_TYPE_Direction"},
+		{"Status", "A_ARG_TYPE_ConnectionStatus"},
+	}},
+}, []scpdVar{
+	{"SourceProtocolInfo", "string", nil},
+	{"SinkProtocolInfo", "string", nil},
+	{"CurrentConnectionIDs", "string", nil},
+	{"A_ARG_TYPE_ConnectionStatus", "string", []string{"OK", "ContentFormatMismatch", "InsufficientBandwidth", "UnreliableChannel", "Unknown"}},
+	{"A_ARG_TYPE_ConnectionManager", "string", nil},
+	{"A_ARG_TYPE_Direction", "string", []string{"Input", "Output"}},
+	{"A_ARG_TYPE_ProtocolInfo", "string", nil},
+	{"A_ARG_TYPE_ConnectionID", "i4", nil},
+	{"A_ARG_TYPE_AVTransportID", "i4", nil},
+	{"A_ARG_TYPE_RcsID", "i4", nil},
+})
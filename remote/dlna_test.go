@@ -0,0 +1,186 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cliamp/mpris"
+	"cliamp/playlist"
+)
+
+// testRenderer serves a renderer's HTTP side for hub without SSDP.
+func testRenderer(t *testing.T, hub *Hub) (*httptest.Server, chan interface{}) {
+	t.Helper()
+	sent := make(chan interface{}, 8)
+	r := &DLNARenderer{hub: hub, send: func(msg interface{}) { sent <- msg }, name: "Test", uuid: rendererUUID("Test")}
+	srv := httptest.NewServer(r.handler())
+	t.Cleanup(srv.Close)
+	return srv, sent
+}
+
+// soap calls action on the service and returns the status and body.
+func soap(t *testing.T, srv *httptest.Server, service, action string, args ...string) (int, string) {
+	t.Helper()
+	var b strings.Builder
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&b, "<%s>%s</%s>", args[i], escapeXML(args[i+1]), args[i])
+	}
+	urn := "urn:schemas-upnp-org:service:" + service + ":1"
+	body := `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+		`<u:` + action + ` xmlns:u="` + urn + `"><InstanceID>0</InstanceID>` + b.String() + `</u:` + action + `>` +
+		`</s:Body></s:Envelope>`
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/control/"+service, strings.NewReader(body))
+	req.Header.Set("SOAPACTION", `"`+urn+"#"+action+`"`)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestDLNA_Description(t *testing.T) {
+	srv, _ := testRenderer(t, testHub())
+	resp, err := http.Get(srv.URL + "/description.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{upnpRenderer, "<friendlyName>Test</friendlyName>", "/control/AVTransport", upnpRendering} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("description missing %q", want)
+		}
+	}
+}
+
+func TestDLNA_CastPlaysTrack(t *testing.T) {
+	srv, sent := testRenderer(t, testHub())
+	meta := `<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">` +
+		`<item id="1"><dc:title>Cast Song</dc:title><upnp:artist>Phone Band</upnp:artist><upnp:album>LP</upnp:album>` +
+		`<res duration="0:03:05.000" protocolInfo="http-get:*:audio/mpeg:*">http://phone:8080/song.mp3</res></item></DIDL-Lite>`
+
+	if code, body := soap(t, srv, "AVTransport", "SetAVTransportURI",
+		"CurrentURI", "http://phone:8080/song.mp3", "CurrentURIMetaData", meta); code != http.StatusOK {
+		t.Fatalf("SetAVTransportURI = %d %s", code, body)
+	}
+	if _, body := soap(t, srv, "AVTransport", "GetTransportInfo"); !strings.Contains(body, "<CurrentTransportState>STOPPED<") {
+		t.Errorf("state after SetAVTransportURI: %s", body)
+	}
+	soap(t, srv, "AVTransport", "Play", "Speed", "1")
+
+	want := PlayTrackMsg{Track: playlist.Track{
+		Path: "http://phone:8080/song.mp3", Title: "Cast Song", Artist: "Phone Band",
+		Album: "LP", DurationSecs: 185, Stream: true,
+	}}
+	if got := <-sent; !reflect.DeepEqual(got, want) {
+		t.Errorf("Play sent %#v, want %#v", got, want)
+	}
+}
+
+func TestDLNA_TransportControls(t *testing.T) {
+	srv, sent := testRenderer(t, testHub())
+
+	// Already playing: Play is a no-op and Pause toggles.
+	soap(t, srv, "AVTransport", "Play", "Speed", "1")
+	soap(t, srv, "AVTransport", "Pause")
+	soap(t, srv, "AVTransport", "Seek", "Unit", "REL_TIME", "Target", "0:01:30")
+	soap(t, srv, "AVTransport", "Next")
+	soap(t, srv, "RenderingControl", "SetVolume", "Channel", "Master", "DesiredVolume", "40")
+
+	for _, want := range []interface{}{
+		mpris.PlayPauseMsg{},
+		mpris.SetPositionMsg{Position: 90_000_000},
+		mpris.NextMsg{},
+		mpris.SetVolumeMsg{Volume: 0.4},
+	} {
+		if got := <-sent; !reflect.DeepEqual(got, want) {
+			t.Errorf("sent %#v, want %#v", got, want)
+		}
+	}
+}
+
+func TestDLNA_Queries(t *testing.T) {
+	srv, _ := testRenderer(t, testHub())
+
+	_, body := soap(t, srv, "AVTransport", "GetPositionInfo")
+	for _, want := range []string{"<TrackDuration>0:03:00<", "<RelTime>0:00:12<", "<TrackURI>/music/b.flac<", "Second"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("GetPositionInfo missing %q:\n%s", want, body)
+		}
+	}
+	if _, body := soap(t, srv, "AVTransport", "GetTransportInfo"); !strings.Contains(body, "<CurrentTransportState>PLAYING<") {
+		t.Errorf("GetTransportInfo: %s", body)
+	}
+	if _, body := soap(t, srv, "RenderingControl", "GetVolume", "Channel", "Master"); !strings.Contains(body, "<CurrentVolume>50<") {
+		t.Errorf("GetVolume: %s", body)
+	}
+	if _, body := soap(t, srv, "ConnectionManager", "GetProtocolInfo"); !strings.Contains(body, "audio/flac") {
+		t.Errorf("GetProtocolInfo: %s", body)
+	}
+}
+
+func TestDLNA_Faults(t *testing.T) {
+	srv, _ := testRenderer(t, testHub())
+	for _, tc := range []struct {
+		service, action string
+		args            []string
+		code            string
+	}{
+		{"AVTransport", "Record", nil, "401"},
+		{"AVTransport", "SetAVTransportURI", []string{"CurrentURI", "file:///etc/passwd"}, "402"},
+		{"AVTransport", "Seek", []string{"Unit", "TRACK_NR", "Target", "2"}, "710"},
+		{"RenderingControl", "SetVolume", []string{"DesiredVolume", "150"}, "402"},
+	} {
+		status, body := soap(t, srv, tc.service, tc.action, tc.args...)
+		if status != http.StatusInternalServerError || !strings.Contains(body, "<errorCode>"+tc.code+"<") {
+			t.Errorf("%s = %d %s, want fault %s", tc.action, status, body, tc.code)
+		}
+	}
+}
+
+func TestUPnPTime(t *testing.T) {
+	for in, want := range map[string]float64{"0:00:00": 0, "0:03:05.500": 185.5, "12:00:01": 43201} {
+		if got, ok := parseUPnPTime(in); !ok || got != want {
+			t.Errorf("parseUPnPTime(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "3:05", "0:61:00", "NOT_IMPLEMENTED"} {
+		if _, ok := parseUPnPTime(in); ok {
+			t.Errorf("parseUPnPTime(%q) accepted", in)
+		}
+	}
+	if got := formatUPnPTime(3725.9); got != "1:02:05" {
+		t.Errorf("formatUPnPTime = %q", got)
+	}
+}
+
+func TestSSDPMatches(t *testing.T) {
+	const uuid = "1234"
+	if got := ssdpMatches("ssdp:all", uuid); len(got) != 6 {
+		t.Errorf("ssdp:all matched %v", got)
+	}
+	if got := ssdpMatches(upnpRenderer, uuid); !reflect.DeepEqual(got, []string{upnpRenderer}) {
+		t.Errorf("renderer search matched %v", got)
+	}
+	if got := ssdpMatches("urn:schemas-upnp-org:device:MediaServer:1", uuid); got != nil {
+		t.Errorf("media server search matched %v", got)
+	}
+
+	resp := string(ssdpResponse(uuid, "upnp:rootdevice", "http://10.0.0.2:49494/description.xml"))
+	for _, want := range []string{"HTTP/1.1 200 OK\r\n", "LOCATION: http://10.0.0.2:49494/description.xml\r\n",
+		"ST: upnp:rootdevice\r\n", "USN: uuid:1234::upnp:rootdevice\r\n"} {
+		if !strings.Contains(resp, want) {
+			t.Errorf("response missing %q:\n%s", want, resp)
+		}
+	}
+	if got := ssdpUSN(uuid, "uuid:1234"); got != "uuid:1234" {
+		t.Errorf("device USN = %q", got)
+	}
+}
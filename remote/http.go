@@ -0,0 +1,96 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// writeTimeout bounds how long a single event may take to reach a client
+// before the connection is dropped.
+const writeTimeout = 5 * time.Second
+
+// HTTPServer serves the player state over HTTP.
+//
+//	GET /status  current Status as JSON
+//	GET /events  WebSocket stream of Events; add ?spectrum=1 for spectrum frames
+type HTTPServer struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// ListenHTTP starts serving hub on addr (e.g. "127.0.0.1:8754").
+func ListenHTTP(addr string, hub *Hub) (*HTTPServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &HTTPServer{
+		srv: &http.Server{Handler: Handler(hub), ReadHeaderTimeout: 10 * time.Second},
+		ln:  ln,
+	}
+	go s.srv.Serve(ln)
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *HTTPServer) Addr() string { return s.ln.Addr().String() }
+
+// Close stops the server and drops all event subscribers.
+func (s *HTTPServer) Close() error {
+	return s.srv.Close()
+}
+
+// Handler returns the HTTP handler for hub.
+func Handler(hub *Hub) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(hub.Status())
+	})
+	mux.HandleFunc("GET /events", func(w http.ResponseWriter, r *http.Request) {
+		serveEvents(w, r, hub)
+	})
+	return mux
+}
+
+// serveEvents upgrades the request to a WebSocket and forwards hub events
+// until the client goes away. Pages served from localhost may connect, so
+// a local dashboard works without a proxy.
+func serveEvents(w http.ResponseWriter, r *http.Request, hub *Hub) {
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		OriginPatterns: []string{"localhost:*", "127.0.0.1:*", "[::1]:*"},
+	})
+	if err != nil {
+		return
+	}
+	defer c.CloseNow()
+
+	sub := hub.Subscribe(r.URL.Query().Get("spectrum") == "1")
+	defer sub.Close()
+
+	// The stream is one-way; CloseRead discards client messages and cancels
+	// ctx when the client disconnects.
+	ctx := c.CloseRead(r.Context())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			wctx, cancel := context.WithTimeout(ctx, writeTimeout)
+			err := wsjson.Write(wctx, c, ev)
+			cancel()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package remote exposes the running player to external programs: a
+// snapshot of the current state and a stream of events as it changes.
+package remote
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Track describes a playlist entry.
+type Track struct {
+	Path        string `json:"path"`
+	Title       string `json:"title"`
+	Artist      string `json:"artist,omitempty"`
+	Album       string `json:"album,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+	TrackNumber int    `json:"track_number,omitempty"`
+	Duration    int    `json:"duration,omitempty"` // seconds, from tags
+	Stream      bool   `json:"stream,omitempty"`
+}
+
+// Status is a snapshot of the player.
+type Status struct {
+	State    string  `json:"state"` // "playing", "paused" or "stopped"
+	Track    Track   `json:"track"`
+	Index    int     `json:"index"`    // playlist position, -1 when empty
+	Length   int     `json:"length"`   // number of playlist entries
+	Position float64 `json:"position"` // seconds
+	Duration float64 `json:"duration"` // seconds, 0 when unknown
+	Volume   float64 `json:"volume"`   // dB, range [-30, +6]
+	Seekable bool    `json:"seekable"`
+	Shuffle  bool    `json:"shuffle"`
+	Repeat   string  `json:"repeat"` // "off", "all" or "one"
+
+	// PlaylistVersion changes whenever playlist entries change.
+	PlaylistVersion uint64 `json:"playlist_version"`
+}
+
+// Event types pushed to subscribers.
+const (
+	EventStatus   = "status"   // Data: Status, sent once on subscribe
+	EventTrack    = "track"    // Data: Track
+	EventState    = "state"    // Data: string
+	EventPosition = "position" // Data: Position
+	EventVolume   = "volume"   // Data: float64 (dB)
+	EventOptions  = "options"  // Data: Options
+	EventPlaylist = "playlist" // Data: uint64, the new PlaylistVersion
+	EventSpectrum = "spectrum" // Data: []float64, normalized band levels 0–1
+)
+
+// Event is one message on the event stream.
+type Event struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// Position is the payload of a position event.
+type Position struct {
+	Position float64 `json:"position"`
+	Duration float64 `json:"duration"`
+}
+
+// Options is the payload of an options event.
+type Options struct {
+	Shuffle bool   `json:"shuffle"`
+	Repeat  string `json:"repeat"`
+}
+
+// subscriberQueue is how many events a subscriber may fall behind before
+// new ones are dropped for it.
+const subscriberQueue = 64
+
+// Hub holds the latest Status and fans events out to subscribers. The UI
+// publishes from its tick loop; servers read and subscribe from their own
+// goroutines. Publish never blocks: a subscriber that cannot keep up loses
+// events rather than stalling playback.
+type Hub struct {
+	mu       sync.Mutex
+	status   Status
+	playlist []Track
+	subs     map[*Subscription]struct{}
+	spectrum atomic.Int32 // subscribers that asked for spectrum frames
+}
+
+// NewHub returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{
+		status: Status{State: "stopped", Index: -1},
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives events from a Hub until Close is called.
+type Subscription struct {
+	C        <-chan Event
+	ch       chan Event
+	hub      *Hub
+	spectrum bool
+	once     sync.Once
+}
+
+// Subscribe registers a new subscriber. The current Status is queued as
+// its first event. Spectrum frames are only delivered when spectrum is
+// true, since they arrive many times per second.
+func (h *Hub) Subscribe(spectrum bool) *Subscription {
+	ch := make(chan Event, subscriberQueue)
+	s := &Subscription{C: ch, ch: ch, hub: h, spectrum: spectrum}
+	h.mu.Lock()
+	ch <- Event{Type: EventStatus, Data: h.status}
+	h.subs[s] = struct{}{}
+	h.mu.Unlock()
+	if spectrum {
+		h.spectrum.Add(1)
+	}
+	return s
+}
+
+// Close unregisters the subscription and closes its channel.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		h := s.hub
+		h.mu.Lock()
+		delete(h.subs, s)
+		close(s.ch)
+		h.mu.Unlock()
+		if s.spectrum {
+			h.spectrum.Add(-1)
+		}
+	})
+}
+
+// Publish delivers ev to every subscriber that has room for it.
+func (h *Hub) Publish(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs {
+		if ev.Type == EventSpectrum && !s.spectrum {
+			continue
+		}
+		select {
+		case s.ch <- ev:
+		default:
+		}
+	}
+}
+
+// WantsSpectrum reports whether any subscriber asked for spectrum frames,
+// so the publisher can skip the FFT otherwise.
+func (h *Hub) WantsSpectrum() bool {
+	return h.spectrum.Load() > 0
+}
+
+// SetStatus replaces the current snapshot.
+func (h *Hub) SetStatus(st Status) {
+	h.mu.Lock()
+	h.status = st
+	h.mu.Unlock()
+}
+
+// Status returns the current snapshot.
+func (h *Hub) Status() Status {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// SetPlaylist replaces the playlist entries. The UI calls it only when
+// the playlist version changes.
+func (h *Hub) SetPlaylist(tracks []Track) {
+	h.mu.Lock()
+	h.playlist = tracks
+	h.mu.Unlock()
+}
+
+// Playlist returns the playlist entries. The slice must not be modified.
+func (h *Hub) Playlist() []Track {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.playlist
+}
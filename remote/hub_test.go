@@ -0,0 +1,135 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestSubscribe_StartsWithStatus(t *testing.T) {
+	h := NewHub()
+	h.SetStatus(Status{State: "playing", Index: 2})
+	sub := h.Subscribe(false)
+	defer sub.Close()
+
+	ev := <-sub.C
+	st, ok := ev.Data.(Status)
+	if ev.Type != EventStatus || !ok || st.State != "playing" || st.Index != 2 {
+		t.Fatalf("first event = %+v, want current status", ev)
+	}
+}
+
+func TestPublish_SpectrumOnlyToOptedIn(t *testing.T) {
+	h := NewHub()
+	plain := h.Subscribe(false)
+	spec := h.Subscribe(true)
+	<-plain.C
+	<-spec.C
+
+	if !h.WantsSpectrum() {
+		t.Fatal("WantsSpectrum() = false with a spectrum subscriber")
+	}
+	h.Publish(Event{Type: EventSpectrum, Data: []float64{0.5}})
+	h.Publish(Event{Type: EventVolume, Data: -3.0})
+
+	if ev := <-plain.C; ev.Type != EventVolume {
+		t.Errorf("plain subscriber got %q, want volume", ev.Type)
+	}
+	if ev := <-spec.C; ev.Type != EventSpectrum {
+		t.Errorf("spectrum subscriber got %q, want spectrum", ev.Type)
+	}
+
+	spec.Close()
+	if h.WantsSpectrum() {
+		t.Error("WantsSpectrum() = true after the spectrum subscriber closed")
+	}
+	plain.Close()
+	plain.Close() // idempotent
+}
+
+func TestPublish_DropsWhenSubscriberIsFull(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(false)
+	defer sub.Close()
+
+	done := make(chan struct{})
+	go func() {
+		for range subscriberQueue * 2 {
+			h.Publish(Event{Type: EventState, Data: "paused"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a slow subscriber")
+	}
+	if n := len(sub.C); n != subscriberQueue {
+		t.Errorf("queued = %d, want %d", n, subscriberQueue)
+	}
+}
+
+func TestHandler_Status(t *testing.T) {
+	h := NewHub()
+	h.SetStatus(Status{State: "paused", Track: Track{Title: "Song"}, Volume: -6})
+	srv := httptest.NewServer(Handler(h))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got Status
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.State != "paused" || got.Track.Title != "Song" || got.Volume != -6 {
+		t.Errorf("status = %+v", got)
+	}
+}
+
+func TestHandler_EventStream(t *testing.T) {
+	h := NewHub()
+	srv := httptest.NewServer(Handler(h))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.CloseNow()
+
+	read := func() map[string]any {
+		t.Helper()
+		_, data, err := c.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ev map[string]any
+		if err := json.Unmarshal(data, &ev); err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+	if ev := read(); ev["type"] != EventStatus {
+		t.Fatalf("first event type = %v, want status", ev["type"])
+	}
+
+	// Subscribe queues the status event, so once it has arrived the
+	// server-side subscription is registered.
+	h.Publish(Event{Type: EventTrack, Data: Track{Title: "Next"}})
+	ev := read()
+	data, _ := ev["data"].(map[string]any)
+	if ev["type"] != EventTrack || data["title"] != "Next" {
+		t.Errorf("event = %v, want track Next", ev)
+	}
+}
@@ -0,0 +1,590 @@
+package remote
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cliamp/mpris"
+	"cliamp/playlist"
+)
+
+// mpdVersion is the protocol version announced to clients. Clients use it
+// to decide which commands they may send.
+const mpdVersion = "0.23.0"
+
+// MPD error codes (ACK [code@index]).
+const (
+	ackArg      = 2
+	ackUnknown  = 5
+	ackNoExist  = 50
+	ackNotAvail = 4 // reported as "permission" by most clients
+)
+
+type ackError struct {
+	code int
+	msg  string
+}
+
+func (e *ackError) Error() string { return e.msg }
+
+func ackf(code int, format string, args ...any) error {
+	return &ackError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// MPDServer speaks a subset of the Music Player Daemon protocol, enough for
+// mpc, ncmpcpp and mobile clients such as MALP to show the playlist and
+// drive playback. There is no music database: browsing commands return
+// empty results, and add takes paths or URLs as cliamp would on the
+// command line. Song IDs are playlist positions.
+type MPDServer struct {
+	ln      net.Listener
+	hub     *Hub
+	send    func(interface{})
+	started time.Time
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// ListenMPD starts an MPD server on addr (e.g. "127.0.0.1:6600"). Commands
+// that change playback are forwarded to the UI through send.
+func ListenMPD(addr string, hub *Hub, send func(interface{})) (*MPDServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &MPDServer{
+		ln:      ln,
+		hub:     hub,
+		send:    send,
+		started: time.Now(),
+		conns:   make(map[net.Conn]struct{}),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *MPDServer) Addr() string { return s.ln.Addr().String() }
+
+// Close stops the server and disconnects all clients.
+func (s *MPDServer) Close() error {
+	err := s.ln.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+func (s *MPDServer) serve() {
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+		go func() {
+			s.handle(c)
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// handle runs one client session. Lines are read in their own goroutine so
+// that idle can wait for player events and for "noidle" at the same time.
+func (s *MPDServer) handle(c net.Conn) {
+	defer c.Close()
+	done := make(chan struct{})
+	defer close(done)
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(c)
+		for sc.Scan() {
+			select {
+			case lines <- sc.Text():
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	w := bufio.NewWriter(c)
+	fmt.Fprintf(w, "OK MPD %s\n", mpdVersion)
+	w.Flush()
+
+	var list []string
+	inList, listOK := false, false
+	for line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "command_list_begin" || line == "command_list_ok_begin":
+			inList, listOK, list = true, line == "command_list_ok_begin", nil
+			continue
+		case inList && line == "command_list_end":
+			inList = false
+			s.runList(w, list, listOK)
+		case inList:
+			list = append(list, line)
+			continue
+		case line == "close":
+			return
+		case line == "idle" || strings.HasPrefix(line, "idle "):
+			if !s.idle(w, strings.Fields(line)[1:], lines) {
+				return
+			}
+		default:
+			s.runList(w, []string{line}, false)
+		}
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
+
+// runList executes commands in order and stops at the first failure, which
+// is reported with its index in the list.
+func (s *MPDServer) runList(w io.Writer, list []string, listOK bool) {
+	for i, line := range list {
+		args, err := splitArgs(line)
+		name := ""
+		if err == nil && len(args) > 0 {
+			name = args[0]
+			err = s.exec(w, name, args[1:])
+		} else if err == nil {
+			err = ackf(ackUnknown, "No command given")
+		}
+		if err != nil {
+			code := ackArg
+			var ack *ackError
+			if errors.As(err, &ack) {
+				code = ack.code
+			}
+			fmt.Fprintf(w, "ACK [%d@%d] {%s} %s\n", code, i, name, err)
+			return
+		}
+		if listOK {
+			io.WriteString(w, "list_OK\n")
+		}
+	}
+	io.WriteString(w, "OK\n")
+}
+
+// idle waits until one of the requested subsystems changes, or the client
+// sends noidle. It reports false when the connection should be closed.
+func (s *MPDServer) idle(w *bufio.Writer, want []string, lines <-chan string) bool {
+	sub := s.hub.Subscribe(false)
+	defer sub.Close()
+	wanted := func(sys string) bool {
+		if len(want) == 0 {
+			return true
+		}
+		for _, x := range want {
+			if x == sys {
+				return true
+			}
+		}
+		return false
+	}
+
+	changed := map[string]bool{}
+	reply := func() bool {
+		names := make([]string, 0, len(changed))
+		for sys := range changed {
+			names = append(names, sys)
+		}
+		sort.Strings(names)
+		for _, sys := range names {
+			fmt.Fprintf(w, "changed: %s\n", sys)
+		}
+		io.WriteString(w, "OK\n")
+		return w.Flush() == nil
+	}
+	for {
+		select {
+		case ev, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			if sys := subsystem(ev.Type); sys != "" && wanted(sys) {
+				changed[sys] = true
+				// Collect whatever else is already queued so one reply
+				// covers a burst of related changes.
+				for more := true; more; {
+					select {
+					case ev := <-sub.C:
+						if sys := subsystem(ev.Type); sys != "" && wanted(sys) {
+							changed[sys] = true
+						}
+					default:
+						more = false
+					}
+				}
+				return reply()
+			}
+		case line, ok := <-lines:
+			if !ok || strings.TrimSpace(line) != "noidle" {
+				// Anything but noidle during idle is a protocol error.
+				return false
+			}
+			return reply()
+		}
+	}
+}
+
+// subsystem maps a hub event to the MPD idle subsystem it affects.
+func subsystem(eventType string) string {
+	switch eventType {
+	case EventTrack, EventState:
+		return "player"
+	case EventVolume:
+		return "mixer"
+	case EventOptions:
+		return "options"
+	case EventPlaylist:
+		return "playlist"
+	}
+	return ""
+}
+
+// mpdCommands lists the commands exec understands, for the "commands"
+// command.
+var mpdCommands = []string{
+	"add", "addid", "binarylimit", "clear", "close", "commands", "consume",
+	"currentsong", "decoders", "find", "getvol", "idle", "list", "listall",
+	"listallinfo", "listplaylists", "lsinfo", "next", "noidle", "notcommands",
+	"outputs", "password", "pause", "ping", "play", "playid", "playlistid",
+	"playlistinfo", "plchanges", "plchangesposid", "previous", "random",
+	"repeat", "replay_gain_status", "search", "seek", "seekcur", "seekid",
+	"setvol", "single", "stats", "status", "stop", "tagtypes", "urlhandlers",
+	"volume",
+}
+
+// exec runs one command, writing its response lines (but not the final OK).
+func (s *MPDServer) exec(w io.Writer, name string, args []string) error {
+	st := s.hub.Status()
+	switch name {
+	case "ping", "password", "binarylimit":
+	case "tagtypes":
+		// "tagtypes enable/disable/clear/all" are accepted and ignored.
+		if len(args) == 0 {
+			for _, t := range []string{"Artist", "Album", "Title", "Track", "Genre"} {
+				fmt.Fprintf(w, "tagtype: %s\n", t)
+			}
+		}
+	case "status":
+		writeStatus(w, st)
+	case "currentsong":
+		if st.Index >= 0 {
+			writeSong(w, st.Track, st.Index)
+		}
+	case "playlistinfo", "playlistid":
+		tracks := s.hub.Playlist()
+		start, end := 0, len(tracks)
+		if len(args) > 0 {
+			var err error
+			if start, end, err = parseRange(args[0], len(tracks)); err != nil {
+				return err
+			}
+			if start >= len(tracks) {
+				return ackf(ackArg, "Bad song index")
+			}
+		}
+		for i := start; i < end; i++ {
+			writeSong(w, tracks[i], i)
+		}
+	case "plchanges", "plchangesposid":
+		if len(args) == 0 {
+			return ackf(ackArg, "wrong number of arguments for %q", name)
+		}
+		// Versions are not tracked per song: any change resends the list.
+		if v, err := strconv.ParseUint(args[0], 10, 64); err == nil && v == st.PlaylistVersion {
+			return nil
+		}
+		for i, t := range s.hub.Playlist() {
+			if name == "plchanges" {
+				writeSong(w, t, i)
+			} else {
+				fmt.Fprintf(w, "cpos: %d\nId: %d\n", i, i)
+			}
+		}
+	case "play", "playid":
+		if len(args) == 0 {
+			if st.State != "playing" {
+				s.send(mpris.PlayPauseMsg{})
+			}
+			return nil
+		}
+		i, err := s.songIndex(args[0], st)
+		if err != nil {
+			return err
+		}
+		s.send(PlayIndexMsg{Index: i})
+	case "pause":
+		switch {
+		case len(args) == 0:
+			s.send(mpris.PlayPauseMsg{})
+		case args[0] == "1" && st.State == "playing", args[0] == "0" && st.State == "paused":
+			s.send(mpris.PlayPauseMsg{})
+		}
+	case "stop":
+		s.send(mpris.StopMsg{})
+	case "next":
+		s.send(mpris.NextMsg{})
+	case "previous":
+		s.send(mpris.PrevMsg{})
+	case "seek", "seekid":
+		if len(args) != 2 {
+			return ackf(ackArg, "wrong number of arguments for %q", name)
+		}
+		i, err := s.songIndex(args[0], st)
+		if err != nil {
+			return err
+		}
+		if i != st.Index {
+			return ackf(ackNotAvail, "only the current song can be seeked")
+		}
+		return s.seek(args[1], false)
+	case "seekcur":
+		if len(args) != 1 {
+			return ackf(ackArg, "wrong number of arguments for %q", name)
+		}
+		return s.seek(args[0], true)
+	case "setvol", "volume":
+		if len(args) != 1 {
+			return ackf(ackArg, "wrong number of arguments for %q", name)
+		}
+		v, err := strconv.Atoi(args[0])
+		if err != nil {
+			return ackf(ackArg, "Integer expected: %s", args[0])
+		}
+		if name == "volume" {
+			v += mpdVolume(st.Volume)
+		}
+		s.send(mpris.SetVolumeMsg{Volume: float64(max(0, min(100, v))) / 100})
+	case "getvol":
+		fmt.Fprintf(w, "volume: %d\n", mpdVolume(st.Volume))
+	case "random":
+		on, err := parseBool(args)
+		if err != nil {
+			return err
+		}
+		s.send(SetShuffleMsg{On: on})
+	case "repeat", "single":
+		on, err := parseBool(args)
+		if err != nil {
+			return err
+		}
+		repeat, single := st.Repeat != "off", st.Repeat == "one"
+		if name == "repeat" {
+			repeat = on
+		} else {
+			single = on
+		}
+		// cliamp has one repeat setting; MPD's single maps to repeat one.
+		mode := playlist.RepeatOff
+		switch {
+		case single:
+			mode = playlist.RepeatOne
+		case repeat:
+			mode = playlist.RepeatAll
+		}
+		s.send(SetRepeatMsg{Mode: mode})
+	case "consume":
+		if on, err := parseBool(args); err != nil || on {
+			return ackf(ackNotAvail, "consume mode is not supported")
+		}
+	case "add", "addid":
+		if len(args) == 0 {
+			return ackf(ackArg, "wrong number of arguments for %q", name)
+		}
+		s.send(AddMsg{Paths: args[:1]})
+		if name == "addid" {
+			// Tracks are resolved asynchronously and appended, so the new
+			// entry lands at the current end of the playlist.
+			fmt.Fprintf(w, "Id: %d\n", st.Length)
+		}
+	case "clear":
+		s.send(ClearMsg{})
+	case "stats":
+		fmt.Fprintf(w, "uptime: %d\nplaytime: 0\nartists: 0\nalbums: 0\nsongs: 0\ndb_playtime: 0\n",
+			int(time.Since(s.started).Seconds()))
+	case "outputs":
+		io.WriteString(w, "outputid: 0\noutputname: cliamp\nplugin: cliamp\noutputenabled: 1\n")
+	case "commands":
+		for _, c := range mpdCommands {
+			fmt.Fprintf(w, "command: %s\n", c)
+		}
+	case "urlhandlers":
+		io.WriteString(w, "handler: http://\nhandler: https://\n")
+	case "replay_gain_status":
+		io.WriteString(w, "replay_gain_mode: off\n")
+	case "notcommands", "decoders", "lsinfo", "listall", "listallinfo",
+		"listplaylists", "list", "find", "search":
+		// No music database: nothing to list.
+	default:
+		return ackf(ackUnknown, "unknown command %q", name)
+	}
+	return nil
+}
+
+// songIndex parses a song position (or ID, which is the same here).
+func (s *MPDServer) songIndex(arg string, st Status) (int, error) {
+	i, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, ackf(ackArg, "Integer expected: %s", arg)
+	}
+	if i < 0 || i >= st.Length {
+		return 0, ackf(ackNoExist, "No such song")
+	}
+	return i, nil
+}
+
+// seek jumps to an absolute time in seconds, or moves by a signed amount
+// when relative is set and the value starts with + or -.
+func (s *MPDServer) seek(arg string, relative bool) error {
+	secs, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return ackf(ackArg, "Number expected: %s", arg)
+	}
+	us := int64(secs * 1e6)
+	if relative && (arg[0] == '+' || arg[0] == '-') {
+		s.send(mpris.SeekMsg{Offset: us})
+		return nil
+	}
+	s.send(mpris.SetPositionMsg{Position: us})
+	return nil
+}
+
+func writeStatus(w io.Writer, st Status) {
+	state := "stop"
+	switch st.State {
+	case "playing":
+		state = "play"
+	case "paused":
+		state = "pause"
+	}
+	fmt.Fprintf(w, "volume: %d\n", mpdVolume(st.Volume))
+	fmt.Fprintf(w, "repeat: %s\nrandom: %s\nsingle: %s\nconsume: 0\n",
+		mpdBool(st.Repeat != "off"), mpdBool(st.Shuffle), mpdBool(st.Repeat == "one"))
+	fmt.Fprintf(w, "playlist: %d\nplaylistlength: %d\nstate: %s\n", st.PlaylistVersion, st.Length, state)
+	if st.Index >= 0 {
+		fmt.Fprintf(w, "song: %d\nsongid: %d\n", st.Index, st.Index)
+	}
+	if state != "stop" {
+		fmt.Fprintf(w, "time: %d:%d\nelapsed: %.3f\n", int(st.Position), int(st.Duration), st.Position)
+		if st.Duration > 0 {
+			fmt.Fprintf(w, "duration: %.3f\n", st.Duration)
+		}
+	}
+}
+
+func writeSong(w io.Writer, t Track, pos int) {
+	fmt.Fprintf(w, "file: %s\n", t.Path)
+	for _, tag := range [][2]string{
+		{"Title", t.Title}, {"Artist", t.Artist}, {"Album", t.Album}, {"Genre", t.Genre},
+	} {
+		if tag[1] != "" {
+			fmt.Fprintf(w, "%s: %s\n", tag[0], tag[1])
+		}
+	}
+	if t.TrackNumber > 0 {
+		fmt.Fprintf(w, "Track: %d\n", t.TrackNumber)
+	}
+	if t.Duration > 0 {
+		fmt.Fprintf(w, "Time: %d\nduration: %d.000\n", t.Duration, t.Duration)
+	}
+	fmt.Fprintf(w, "Pos: %d\nId: %d\n", pos, pos)
+}
+
+// mpdVolume converts a dB volume to MPD's 0–100 scale using the same
+// curve as MPRIS, so 0 dB shows as 50.
+func mpdVolume(dB float64) int {
+	if dB <= -30 {
+		return 0
+	}
+	return max(0, min(100, int(math.Round(100*math.Pow(10, (dB-6)/20)))))
+}
+
+func mpdBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func parseBool(args []string) (bool, error) {
+	if len(args) != 1 || (args[0] != "0" && args[0] != "1") {
+		return false, ackf(ackArg, "Boolean (0/1) expected")
+	}
+	return args[0] == "1", nil
+}
+
+// parseRange parses "N" or "START:END" (END optional) into a half-open
+// range clamped to n.
+func parseRange(arg string, n int) (start, end int, err error) {
+	lo, hi, isRange := strings.Cut(arg, ":")
+	start, err = strconv.Atoi(lo)
+	if err != nil || start < 0 {
+		return 0, 0, ackf(ackArg, "Integer or range expected: %s", arg)
+	}
+	end = start + 1
+	if isRange {
+		end = n
+		if hi != "" {
+			if end, err = strconv.Atoi(hi); err != nil || end < start {
+				return 0, 0, ackf(ackArg, "Integer or range expected: %s", arg)
+			}
+		}
+	}
+	return start, min(end, n), nil
+}
+
+// splitArgs splits a command line into words. Double-quoted words may
+// contain spaces, and backslash escapes the next character inside quotes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == ' ' || line[i] == '\t':
+			i++
+		case line[i] == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				}
+				b.WriteByte(line[i])
+			}
+			if i >= len(line) {
+				return nil, ackf(ackArg, "Missing closing '\"'")
+			}
+			i++
+			args = append(args, b.String())
+		default:
+			j := i
+			for j < len(line) && line[j] != ' ' && line[j] != '\t' {
+				j++
+			}
+			args = append(args, line[i:j])
+			i = j
+		}
+	}
+	return args, nil
+}
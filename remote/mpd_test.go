@@ -0,0 +1,216 @@
+package remote
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cliamp/mpris"
+	"cliamp/playlist"
+)
+
+type mpdClient struct {
+	t *testing.T
+	c net.Conn
+	r *bufio.Reader
+}
+
+// dialMPD starts a server for hub and connects a client past the greeting.
+func dialMPD(t *testing.T, hub *Hub) (*mpdClient, chan interface{}) {
+	t.Helper()
+	sent := make(chan interface{}, 8)
+	srv, err := ListenMPD("127.0.0.1:0", hub, func(msg interface{}) { sent <- msg })
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	c, err := net.Dial("tcp", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetDeadline(time.Now().Add(5 * time.Second))
+	mc := &mpdClient{t: t, c: c, r: bufio.NewReader(c)}
+	if greeting := mc.line(); !strings.HasPrefix(greeting, "OK MPD ") {
+		t.Fatalf("greeting = %q", greeting)
+	}
+	return mc, sent
+}
+
+func (m *mpdClient) line() string {
+	m.t.Helper()
+	l, err := m.r.ReadString('\n')
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	return strings.TrimSuffix(l, "\n")
+}
+
+// cmd sends lines and returns the response up to and including OK or ACK.
+func (m *mpdClient) cmd(lines ...string) []string {
+	m.t.Helper()
+	fmt.Fprint(m.c, strings.Join(lines, "\n")+"\n")
+	var out []string
+	for {
+		l := m.line()
+		out = append(out, l)
+		if l == "OK" || strings.HasPrefix(l, "ACK ") {
+			return out
+		}
+	}
+}
+
+func testHub() *Hub {
+	h := NewHub()
+	h.SetPlaylist([]Track{
+		{Path: "/music/a.flac", Title: "First", Artist: "Band", Duration: 200},
+		{Path: "/music/b.flac", Title: "Second", Artist: "Band", TrackNumber: 2},
+	})
+	h.SetStatus(Status{
+		State: "playing", Index: 1, Length: 2, Position: 12.5, Duration: 180,
+		Track:  Track{Path: "/music/b.flac", Title: "Second", Artist: "Band", TrackNumber: 2},
+		Repeat: "off", PlaylistVersion: 7,
+	})
+	return h
+}
+
+func TestMPD_Status(t *testing.T) {
+	c, _ := dialMPD(t, testHub())
+	got := strings.Join(c.cmd("status"), "\n")
+	for _, want := range []string{
+		"volume: 50", "repeat: 0", "random: 0", "playlist: 7", "playlistlength: 2",
+		"state: play", "song: 1", "songid: 1", "time: 12:180", "elapsed: 12.500", "duration: 180.000",
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("status missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestMPD_PlaylistInfo(t *testing.T) {
+	c, _ := dialMPD(t, testHub())
+
+	got := c.cmd("playlistinfo")
+	want := []string{
+		"file: /music/a.flac", "Title: First", "Artist: Band", "Time: 200", "duration: 200.000", "Pos: 0", "Id: 0",
+		"file: /music/b.flac", "Title: Second", "Artist: Band", "Track: 2", "Pos: 1", "Id: 1",
+		"OK",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("playlistinfo =\n%q\nwant\n%q", got, want)
+	}
+	if got := c.cmd("playlistinfo 1"); got[0] != "file: /music/b.flac" {
+		t.Errorf("playlistinfo 1 starts with %q", got[0])
+	}
+	if got := c.cmd("currentsong"); got[0] != "file: /music/b.flac" {
+		t.Errorf("currentsong starts with %q", got[0])
+	}
+	if got := c.cmd("plchanges 7"); !reflect.DeepEqual(got, []string{"OK"}) {
+		t.Errorf("plchanges at current version = %q, want only OK", got)
+	}
+}
+
+func TestMPD_ForwardsCommands(t *testing.T) {
+	c, sent := dialMPD(t, testHub())
+
+	tests := []struct {
+		cmd  string
+		want interface{}
+	}{
+		{"next", mpris.NextMsg{}},
+		{"previous", mpris.PrevMsg{}},
+		{"pause 1", mpris.PlayPauseMsg{}},
+		{"play 0", PlayIndexMsg{Index: 0}},
+		{"seekcur +10", mpris.SeekMsg{Offset: 10e6}},
+		{"seekcur 30", mpris.SetPositionMsg{Position: 30e6}},
+		{"setvol 100", mpris.SetVolumeMsg{Volume: 1}},
+		{"random 1", SetShuffleMsg{On: true}},
+		{"single 1", SetRepeatMsg{Mode: playlist.RepeatOne}},
+		{`add "/music/with space.mp3"`, AddMsg{Paths: []string{"/music/with space.mp3"}}},
+		{"clear", ClearMsg{}},
+	}
+	for _, tt := range tests {
+		if got := c.cmd(tt.cmd); got[len(got)-1] != "OK" {
+			t.Fatalf("%s: response %q", tt.cmd, got)
+		}
+		select {
+		case msg := <-sent:
+			if !reflect.DeepEqual(msg, tt.want) {
+				t.Errorf("%s sent %#v, want %#v", tt.cmd, msg, tt.want)
+			}
+		default:
+			t.Errorf("%s sent nothing", tt.cmd)
+		}
+	}
+
+	// Already playing: a bare play must not toggle to paused.
+	c.cmd("play")
+	if len(sent) != 0 {
+		t.Errorf("play while playing sent %#v", <-sent)
+	}
+}
+
+func TestMPD_Errors(t *testing.T) {
+	c, _ := dialMPD(t, testHub())
+
+	if got := c.cmd("frobnicate"); !strings.HasPrefix(got[0], "ACK [5@0] {frobnicate}") {
+		t.Errorf("unknown command = %q", got)
+	}
+	if got := c.cmd("play 9"); !strings.HasPrefix(got[0], "ACK [50@0] {play}") {
+		t.Errorf("play out of range = %q", got)
+	}
+	got := c.cmd("command_list_ok_begin", "ping", "seek 0 10", "ping", "command_list_end")
+	if !reflect.DeepEqual(got[:1], []string{"list_OK"}) || !strings.HasPrefix(got[1], "ACK [4@1] {seek}") {
+		t.Errorf("command list = %q", got)
+	}
+}
+
+func TestMPD_Idle(t *testing.T) {
+	h := testHub()
+	c, _ := dialMPD(t, h)
+
+	// The subscription is registered once the server reads the command;
+	// keep publishing until the reply arrives.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			h.Publish(Event{Type: EventTrack})
+			h.Publish(Event{Type: EventVolume, Data: -3.0})
+			select {
+			case <-stop:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+	if got := c.cmd("idle mixer"); !reflect.DeepEqual(got, []string{"changed: mixer", "OK"}) {
+		t.Errorf("idle mixer = %q", got)
+	}
+
+	if got := c.cmd("idle stored_playlist", "noidle"); !reflect.DeepEqual(got, []string{"OK"}) {
+		t.Errorf("noidle = %q, want OK", got)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	got, err := splitArgs(`add "a \"quoted\" name"  plain`)
+	want := []string{"add", `a "quoted" name`, "plain"}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("splitArgs = %q, %v; want %q", got, err, want)
+	}
+	if _, err := splitArgs(`add "open`); err == nil {
+		t.Error("unterminated quote: expected error")
+	}
+}
+
+func TestMPDVolume(t *testing.T) {
+	for dB, want := range map[float64]int{-30: 0, 0: 50, 6: 100, -6: 25} {
+		if got := mpdVolume(dB); got != want {
+			t.Errorf("mpdVolume(%v) = %d, want %d", dB, got, want)
+		}
+	}
+}
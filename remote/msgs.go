@@ -0,0 +1,15 @@
+package remote
+
+import "cliamp/playlist"
+
+// Messages the servers send to the UI for actions MPRIS has no message
+// for. Transport controls reuse the mpris message types.
+type (
+	AddMsg        struct{ Paths []string }           // append files, folders, playlists or URLs
+	OpenMsg       struct{ Paths []string }           // replace the playlist with Paths and play
+	PlayIndexMsg  struct{ Index int }                // play the playlist entry at Index
+	PlayTrackMsg  struct{ Track playlist.Track }     // append Track and play it
+	ClearMsg      struct{}                           // stop and empty the playlist
+	SetShuffleMsg struct{ On bool }                  // turn shuffle on or off
+	SetRepeatMsg  struct{ Mode playlist.RepeatMode } // select a repeat mode
+)
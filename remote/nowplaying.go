@@ -0,0 +1,135 @@
+package remote
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNowPlayingFormat is the text written when no format is configured.
+const DefaultNowPlayingFormat = "{artist} - {title}"
+
+// nowPlayingInterval is how often the file is refreshed. Once a second is
+// enough for a position readout and costs nothing while the text is
+// unchanged, since the file is only rewritten when it differs.
+var nowPlayingInterval = time.Second
+
+// NowPlayingWriter keeps a file up to date with the hub's status, for OBS
+// text sources, polybar, tmux and other status bars that read a file.
+type NowPlayingWriter struct {
+	path   string
+	format string // "" writes JSON
+	hub    *Hub
+	last   string
+	stop   chan struct{}
+	done   sync.WaitGroup
+}
+
+// WriteNowPlaying starts refreshing path from hub. A path ending in .json
+// gets the full Status as JSON; any other file gets format with
+// placeholders such as {artist} and {position} filled in, or nothing while
+// stopped. A leading "~/" is expanded.
+func WriteNowPlaying(path, format string, hub *Hub) *NowPlayingWriter {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	w := &NowPlayingWriter{path: path, hub: hub, stop: make(chan struct{})}
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		w.format = format
+		if w.format == "" {
+			w.format = DefaultNowPlayingFormat
+		}
+	}
+	w.refresh()
+	w.done.Add(1)
+	go w.loop()
+	return w
+}
+
+// Close stops refreshing and leaves the file describing a stopped player.
+func (w *NowPlayingWriter) Close() {
+	close(w.stop)
+	w.done.Wait()
+	w.write(Status{State: "stopped", Index: -1})
+}
+
+func (w *NowPlayingWriter) loop() {
+	defer w.done.Done()
+	t := time.NewTicker(nowPlayingInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-t.C:
+			w.refresh()
+		}
+	}
+}
+
+func (w *NowPlayingWriter) refresh() {
+	w.write(w.hub.Status())
+}
+
+// write renders st and replaces the file when the text changed. The new
+// text goes to a temporary file that is renamed over the old one, so a
+// reader never sees a half-written file.
+func (w *NowPlayingWriter) write(st Status) {
+	text := w.render(st)
+	if text == w.last {
+		return
+	}
+	tmp := w.path + ".tmp"
+	_ = os.MkdirAll(filepath.Dir(w.path), 0o755)
+	if err := os.WriteFile(tmp, []byte(text), 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		os.Remove(tmp)
+		return
+	}
+	w.last = text
+}
+
+func (w *NowPlayingWriter) render(st Status) string {
+	if w.format == "" {
+		data, _ := json.Marshal(st)
+		return string(data) + "\n"
+	}
+	if st.State == "stopped" || st.Index < 0 {
+		return ""
+	}
+	return FormatTemplate(w.format, st) + "\n"
+}
+
+// FormatTemplate fills the placeholders of format from st: {artist},
+// {title}, {album}, {state}, {position}, {duration}, {index} and {length},
+// and \n for a line break.
+func FormatTemplate(format string, st Status) string {
+	title := st.Track.Title
+	if title == "" {
+		title = filepath.Base(st.Track.Path)
+	}
+	if st.Track.Artist == "" {
+		// A track without an artist would otherwise read " - Title".
+		format = strings.ReplaceAll(format, "{artist} - ", "")
+	}
+	r := strings.NewReplacer(
+		"{artist}", st.Track.Artist,
+		"{title}", title,
+		"{album}", st.Track.Album,
+		"{state}", st.State,
+		"{position}", clock(st.Position),
+		"{duration}", clock(st.Duration),
+		"{index}", strconv.Itoa(st.Index+1),
+		"{length}", strconv.Itoa(st.Length),
+		`\n`, "\n",
+	)
+	return r.Replace(format)
+}
@@ -0,0 +1,58 @@
+package remote
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNowPlayingText(t *testing.T) {
+	hub := NewHub()
+	hub.SetStatus(Status{
+		State:    "playing",
+		Track:    Track{Path: "/m/a.flac", Title: "Song", Artist: "Band"},
+		Index:    1,
+		Length:   3,
+		Position: 65,
+		Duration: 200,
+	})
+	path := filepath.Join(t.TempDir(), "np.txt")
+	w := WriteNowPlaying(path, "{artist} - {title} [{position}/{duration}] {index}/{length}", hub)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "Band - Song [1:05/3:20] 2/3\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+
+	w.Close()
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("file after Close = %q, want empty", data)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestNowPlayingJSON(t *testing.T) {
+	hub := NewHub()
+	hub.SetStatus(Status{State: "paused", Track: Track{Path: "/m/a.flac", Title: "Song"}, Length: 1})
+	path := filepath.Join(t.TempDir(), "np.json")
+	w := WriteNowPlaying(path, "ignored", hub)
+	defer w.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st Status
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatalf("file is not JSON: %v", err)
+	}
+	if st.State != "paused" || st.Track.Title != "Song" {
+		t.Errorf("status = %+v", st)
+	}
+}
@@ -0,0 +1,184 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cliamp/internal/appdir"
+	"cliamp/mpris"
+)
+
+// ErrRunning is returned by ListenSocket when another instance already
+// owns the control socket.
+var ErrRunning = errors.New("another cliamp instance is running")
+
+// Request is one command sent over the control socket.
+type Request struct {
+	Cmd  string   `json:"cmd"`
+	Args []string `json:"args,omitempty"`
+}
+
+// Response answers a Request.
+type Response struct {
+	OK     bool    `json:"ok"`
+	Error  string  `json:"error,omitempty"`
+	Status *Status `json:"status,omitempty"`
+}
+
+// requestTimeout bounds how long a connection may sit idle between
+// requests.
+const requestTimeout = 30 * time.Second
+
+// SocketPath returns where the control socket lives: $XDG_RUNTIME_DIR when
+// set, otherwise the cliamp config directory.
+func SocketPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "cliamp.sock"), nil
+	}
+	dir, err := appdir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cliamp.sock"), nil
+}
+
+// SocketServer accepts control commands on a Unix socket.
+type SocketServer struct {
+	ln   net.Listener
+	hub  *Hub
+	send func(interface{})
+}
+
+// ListenSocket starts accepting commands on path. Commands that change
+// playback are forwarded to the UI through send; status is answered from
+// hub. A socket file left behind by a crashed instance is replaced, but a
+// live one yields ErrRunning.
+func ListenSocket(path string, hub *Hub, send func(interface{})) (*SocketServer, error) {
+	if c, err := net.Dial("unix", path); err == nil {
+		c.Close()
+		return nil, ErrRunning
+	}
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// Only the owner may drive the player.
+	os.Chmod(path, 0o600)
+	s := &SocketServer{ln: ln, hub: hub, send: send}
+	go s.serve()
+	return s, nil
+}
+
+// Close stops accepting commands and removes the socket file.
+func (s *SocketServer) Close() error {
+	return s.ln.Close()
+}
+
+func (s *SocketServer) serve() {
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(c)
+	}
+}
+
+// handle answers newline-delimited JSON requests until the client hangs up.
+func (s *SocketServer) handle(c net.Conn) {
+	defer c.Close()
+	sc := bufio.NewScanner(c)
+	sc.Buffer(make([]byte, 0, 4096), 1<<20)
+	enc := json.NewEncoder(c)
+	for {
+		c.SetReadDeadline(time.Now().Add(requestTimeout))
+		if !sc.Scan() {
+			return
+		}
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			resp.Error = "malformed request"
+		} else if req.Cmd == "watch" {
+			s.watch(c, enc)
+			return
+		} else {
+			resp = s.exec(req)
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// watch answers a watch request: the status now and again after every
+// event, each as a Response line, until the client hangs up.
+func (s *SocketServer) watch(c net.Conn, enc *json.Encoder) {
+	sub := s.hub.Subscribe(false)
+	defer sub.Close()
+	c.SetReadDeadline(time.Time{})
+	// The client sends nothing more, so a read returning means it went away.
+	go func() {
+		io.Copy(io.Discard, c)
+		sub.Close()
+	}()
+	for range sub.C {
+		st := s.hub.Status()
+		if err := enc.Encode(Response{OK: true, Status: &st}); err != nil {
+			return
+		}
+	}
+}
+
+// exec runs one command. Play and pause are derived from the toggle so
+// that repeating them is harmless.
+func (s *SocketServer) exec(req Request) Response {
+	state := s.hub.Status().State
+	switch req.Cmd {
+	case "play":
+		if state != "playing" {
+			s.send(mpris.PlayPauseMsg{})
+		}
+	case "pause":
+		if state == "playing" {
+			s.send(mpris.PlayPauseMsg{})
+		}
+	case "toggle":
+		s.send(mpris.PlayPauseMsg{})
+	case "next":
+		s.send(mpris.NextMsg{})
+	case "prev":
+		s.send(mpris.PrevMsg{})
+	case "stop":
+		s.send(mpris.StopMsg{})
+	case "quit":
+		s.send(mpris.QuitMsg{})
+	case "add":
+		if len(req.Args) == 0 {
+			return Response{Error: "add: no paths given"}
+		}
+		s.send(AddMsg{Paths: req.Args})
+	case "open":
+		if len(req.Args) == 0 {
+			return Response{Error: "open: no paths given"}
+		}
+		s.send(OpenMsg{Paths: req.Args})
+	case "status":
+		st := s.hub.Status()
+		return Response{OK: true, Status: &st}
+	default:
+		return Response{Error: fmt.Sprintf("unknown command %q", req.Cmd)}
+	}
+	return Response{OK: true}
+}
@@ -0,0 +1,127 @@
+package remote
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cliamp/mpris"
+)
+
+// startSocket serves a hub on a socket in a temp runtime dir and returns
+// the messages forwarded to the UI.
+func startSocket(t *testing.T, hub *Hub) chan interface{} {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	sent := make(chan interface{}, 8)
+	srv, err := ListenSocket(filepath.Join(dir, "cliamp.sock"), hub, func(msg interface{}) { sent <- msg })
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return sent
+}
+
+func TestSocket_ForwardsCommands(t *testing.T) {
+	hub := NewHub()
+	hub.SetStatus(Status{State: "playing"})
+	sent := startSocket(t, hub)
+
+	tests := []struct {
+		req  Request
+		want interface{}
+	}{
+		{Request{Cmd: "next"}, mpris.NextMsg{}},
+		{Request{Cmd: "pause"}, mpris.PlayPauseMsg{}},
+		{Request{Cmd: "add", Args: []string{"/music/a.mp3"}}, AddMsg{Paths: []string{"/music/a.mp3"}}},
+		{Request{Cmd: "open", Args: []string{"/music/b.mp3"}}, OpenMsg{Paths: []string{"/music/b.mp3"}}},
+	}
+	for _, tt := range tests {
+		if _, err := Call(tt.req); err != nil {
+			t.Fatalf("Call(%v) error: %v", tt.req, err)
+		}
+		if got := <-sent; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Call(%v) sent %#v, want %#v", tt.req, got, tt.want)
+		}
+	}
+}
+
+func TestSocket_PlayWhilePlayingIsNoop(t *testing.T) {
+	hub := NewHub()
+	hub.SetStatus(Status{State: "playing"})
+	sent := startSocket(t, hub)
+
+	if _, err := Call(Request{Cmd: "play"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-sent:
+		t.Errorf("play while playing sent %#v", msg)
+	default:
+	}
+}
+
+func TestSocket_Status(t *testing.T) {
+	hub := NewHub()
+	hub.SetStatus(Status{State: "paused", Track: Track{Title: "Song", Artist: "Band"}, Index: 2, Length: 12, Position: 83, Duration: 241, Volume: -4})
+	startSocket(t, hub)
+
+	var out strings.Builder
+	if err := RunCommand([]string{"status"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "[paused] Band - Song\n1:23 / 4:01  track 3/12  vol -4.0 dB\n"
+	if out.String() != want {
+		t.Errorf("status output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestSocket_Errors(t *testing.T) {
+	startSocket(t, NewHub())
+
+	if _, err := Call(Request{Cmd: "dance"}); err == nil {
+		t.Error("unknown command: expected error")
+	}
+	if _, err := Call(Request{Cmd: "add"}); err == nil {
+		t.Error("add without paths: expected error")
+	}
+}
+
+func TestSocket_SecondInstance(t *testing.T) {
+	startSocket(t, NewHub())
+	path, _ := SocketPath()
+	if _, err := ListenSocket(path, NewHub(), func(interface{}) {}); err != ErrRunning {
+		t.Errorf("ListenSocket on a live socket = %v, want ErrRunning", err)
+	}
+}
+
+func TestCall_NotRunning(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	if _, err := Call(Request{Cmd: "status"}); err != ErrNotRunning {
+		t.Errorf("Call() = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestSocket_StatusJSON(t *testing.T) {
+	hub := NewHub()
+	hub.SetStatus(Status{State: "playing", Track: Track{Title: "Song"}, Index: 2, Length: 12, Position: 83, Duration: 241, Volume: -4, Shuffle: true, Repeat: "all"})
+	startSocket(t, hub)
+
+	var out strings.Builder
+	if err := RunCommand([]string{"status", "--json"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var got Status
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatalf("status --json output %q: %v", out.String(), err)
+	}
+	if got.State != "playing" || got.Track.Title != "Song" || got.Index != 2 || got.Position != 83 || !got.Shuffle || got.Repeat != "all" {
+		t.Errorf("status --json = %+v", got)
+	}
+	if err := RunCommand([]string{"status", "--yaml"}, &out); err == nil {
+		t.Error("status --yaml: expected error")
+	}
+}
@@ -0,0 +1,209 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ssdpAddr   = "239.255.255.250:1900"
+	ssdpMaxAge = 1800             // seconds an announcement stays valid
+	ssdpResend = 15 * time.Minute // re-announce well before max-age runs out
+)
+
+// ssdpServer answers discovery searches for the renderer and announces
+// it on every multicast-capable IPv4 interface.
+type ssdpServer struct {
+	uuid string
+	port int // HTTP port of the description document
+	conn *net.UDPConn
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func startSSDP(uuid string, port int) (*ssdpServer, error) {
+	group, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return nil, err
+	}
+	s := &ssdpServer{uuid: uuid, port: port, conn: conn, done: make(chan struct{})}
+	s.wg.Add(2)
+	go s.serve()
+	go s.announce()
+	return s, nil
+}
+
+// Close announces byebye and stops answering searches.
+func (s *ssdpServer) Close() {
+	close(s.done)
+	s.conn.Close()
+	s.wg.Wait()
+	s.notify("ssdp:byebye")
+}
+
+// ssdpTargets lists the notification types the renderer advertises.
+func ssdpTargets(uuid string) []string {
+	return []string{
+		"upnp:rootdevice", "uuid:" + uuid, upnpRenderer,
+		upnpTransport, upnpRendering, upnpConnMgr,
+	}
+}
+
+// ssdpUSN is the unique service name for a notification type.
+func ssdpUSN(uuid, nt string) string {
+	if nt == "uuid:"+uuid {
+		return nt
+	}
+	return "uuid:" + uuid + "::" + nt
+}
+
+// ssdpMatches returns the targets a search for st should be answered with.
+func ssdpMatches(st, uuid string) []string {
+	targets := ssdpTargets(uuid)
+	if st == "ssdp:all" {
+		return targets
+	}
+	for _, t := range targets {
+		if t == st {
+			return []string{t}
+		}
+	}
+	return nil
+}
+
+// ssdpResponse is the unicast reply to an M-SEARCH.
+func ssdpResponse(uuid, st, location string) []byte {
+	return []byte("HTTP/1.1 200 OK\r\n" +
+		fmt.Sprintf("CACHE-CONTROL: max-age=%d\r\n", ssdpMaxAge) +
+		"EXT:\r\n" +
+		"LOCATION: " + location + "\r\n" +
+		"SERVER: cliamp UPnP/1.0 DLNADOC/1.50\r\n" +
+		"ST: " + st + "\r\n" +
+		"USN: " + ssdpUSN(uuid, st) + "\r\n" +
+		"\r\n")
+}
+
+func (s *ssdpServer) serve() {
+	defer s.wg.Done()
+	buf := make([]byte, 2048)
+	for {
+		n, from, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-s.done:
+				return
+			default:
+				continue
+			}
+		}
+		req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(buf[:n])))
+		if err != nil || req.Method != "M-SEARCH" || req.Header.Get("MAN") != `"ssdp:discover"` {
+			continue
+		}
+		matches := ssdpMatches(req.Header.Get("ST"), s.uuid)
+		if len(matches) == 0 {
+			continue
+		}
+		ip := localIPFor(from)
+		if ip == nil {
+			continue
+		}
+		loc := s.location(ip)
+		for _, st := range matches {
+			s.conn.WriteToUDP(ssdpResponse(s.uuid, st, loc), from)
+		}
+	}
+}
+
+func (s *ssdpServer) announce() {
+	defer s.wg.Done()
+	s.notify("ssdp:alive")
+	t := time.NewTicker(ssdpResend)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+			s.notify("ssdp:alive")
+		}
+	}
+}
+
+// notify multicasts an alive or byebye message for every advertised type
+// from each interface, so the LOCATION is reachable on that network.
+func (s *ssdpServer) notify(nts string) {
+	group, _ := net.ResolveUDPAddr("udp4", ssdpAddr)
+	for _, ip := range multicastIPs() {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: ip})
+		if err != nil {
+			continue
+		}
+		for _, nt := range ssdpTargets(s.uuid) {
+			msg := "NOTIFY * HTTP/1.1\r\n" +
+				"HOST: " + ssdpAddr + "\r\n" +
+				"NT: " + nt + "\r\n" +
+				"NTS: " + nts + "\r\n" +
+				"USN: " + ssdpUSN(s.uuid, nt) + "\r\n"
+			if nts == "ssdp:alive" {
+				msg += fmt.Sprintf("CACHE-CONTROL: max-age=%d\r\n", ssdpMaxAge) +
+					"LOCATION: " + s.location(ip) + "\r\n" +
+					"SERVER: cliamp UPnP/1.0 DLNADOC/1.50\r\n"
+			}
+			conn.WriteToUDP([]byte(msg+"\r\n"), group)
+		}
+		conn.Close()
+	}
+}
+
+func (s *ssdpServer) location(ip net.IP) string {
+	return fmt.Sprintf("http://%s/description.xml", net.JoinHostPort(ip.String(), strconv.Itoa(s.port)))
+}
+
+// localIPFor returns the local address the system would use to reach peer.
+func localIPFor(peer *net.UDPAddr) net.IP {
+	conn, err := net.DialUDP("udp4", nil, peer)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP
+}
+
+// multicastIPs returns the IPv4 address of every up, non-loopback
+// interface that supports multicast.
+func multicastIPs() []net.IP {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, ifc := range ifaces {
+		if ifc.Flags&net.FlagUp == 0 || ifc.Flags&net.FlagMulticast == 0 || ifc.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := ifc.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			if ipn, ok := a.(*net.IPNet); ok {
+				if ip4 := ipn.IP.To4(); ip4 != nil && !strings.HasPrefix(ip4.String(), "169.254.") {
+					ips = append(ips, ip4)
+				}
+			}
+		}
+	}
+	return ips
+}
@@ -0,0 +1,141 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+const statuslineUsage = "usage: cliamp statusline [--format text|waybar|json] [--template <text>] [--once]"
+
+// statuslineRetry is how long statusline waits to look for a player again
+// after none was running or it quit.
+var statuslineRetry = 2 * time.Second
+
+// statusline writes lines for a status bar module, skipping repeats.
+type statusline struct {
+	w        io.Writer
+	format   string // "text", "waybar" or "json"
+	template string
+	last     string
+}
+
+// writeError marks a failed write to the bar, which ends statusline.
+type writeError struct{ error }
+
+// RunStatusline implements `cliamp statusline`: a line describing the
+// running instance each time it changes, for Waybar, polybar, i3blocks and
+// other bars that read a program's output. The text format fills
+// --template like now_playing_format, "waybar" writes the JSON Waybar's
+// custom modules take, and "json" the full status. While no instance is
+// running it writes an empty status and keeps looking for one; --once
+// writes the current status and exits.
+func RunStatusline(args []string, w io.Writer) error {
+	s := &statusline{w: w, format: "text", template: DefaultNowPlayingFormat}
+	once := false
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--format" || a == "--template" {
+			if i+1 >= len(args) {
+				return errors.New(statuslineUsage)
+			}
+			i++
+		}
+		switch a {
+		case "--format":
+			switch args[i] {
+			case "text", "waybar", "json":
+				s.format = args[i]
+			default:
+				return fmt.Errorf("statusline: unknown format %q (want text, waybar or json)", args[i])
+			}
+		case "--template":
+			s.template = args[i]
+		case "--once":
+			once = true
+		default:
+			return errors.New(statuslineUsage)
+		}
+	}
+
+	if once {
+		resp, err := Call(Request{Cmd: "status"})
+		if errors.Is(err, ErrNotRunning) {
+			return s.emit(Status{State: "stopped", Index: -1})
+		}
+		if err != nil {
+			return err
+		}
+		return s.emit(*resp.Status)
+	}
+	for {
+		err := Watch(s.emit)
+		var werr writeError
+		if errors.As(err, &werr) {
+			return werr.error
+		}
+		if err != nil && strings.HasPrefix(err.Error(), "unknown command") {
+			return errors.New("statusline: the running cliamp is too old to watch; restart it")
+		}
+		// Not running, or it quit: look again shortly.
+		if err := s.emit(Status{State: "stopped", Index: -1}); err != nil {
+			return err
+		}
+		time.Sleep(statuslineRetry)
+	}
+}
+
+// emit writes the line for st unless it is the same as the last one.
+func (s *statusline) emit(st Status) error {
+	line := s.render(st)
+	if line == s.last {
+		return nil
+	}
+	s.last = line
+	if _, err := fmt.Fprintln(s.w, line); err != nil {
+		return writeError{err}
+	}
+	return nil
+}
+
+func (s *statusline) render(st Status) string {
+	idle := st.State == "stopped" || st.Index < 0
+	switch s.format {
+	case "json":
+		data, _ := json.Marshal(st)
+		return string(data)
+	case "waybar":
+		// Waybar hides a module whose text is empty and styles it by
+		// class; text and tooltip are Pango markup, so tags are escaped.
+		out := struct {
+			Text       string `json:"text"`
+			Tooltip    string `json:"tooltip,omitempty"`
+			Class      string `json:"class"`
+			Alt        string `json:"alt"`
+			Percentage int    `json:"percentage"`
+		}{Class: st.State, Alt: st.State}
+		if !idle {
+			out.Text = html.EscapeString(oneLine(FormatTemplate(s.template, st)))
+			out.Tooltip = html.EscapeString(strings.TrimSpace(FormatStatus(st)))
+			if st.Duration > 0 {
+				out.Percentage = int(min(100, st.Position/st.Duration*100))
+			}
+		}
+		data, _ := json.Marshal(out)
+		return string(data)
+	default:
+		if idle {
+			return ""
+		}
+		return oneLine(FormatTemplate(s.template, st))
+	}
+}
+
+// oneLine keeps a bar's line to one line, whatever the template and tags.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
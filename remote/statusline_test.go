@@ -0,0 +1,67 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// lineWriter collects lines and fails once it has n, to end statusline.
+type lineWriter struct {
+	lines []string
+	n     int
+	got   chan struct{}
+}
+
+var errEnough = errors.New("enough")
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.lines = append(w.lines, strings.TrimSuffix(string(p), "\n"))
+	w.got <- struct{}{}
+	if len(w.lines) >= w.n {
+		return 0, errEnough
+	}
+	return len(p), nil
+}
+
+func TestStatuslineFollowsEvents(t *testing.T) {
+	hub := NewHub()
+	hub.SetStatus(Status{State: "playing", Track: Track{Title: "One", Artist: "Band"}, Index: 0, Length: 2})
+	startSocket(t, hub)
+
+	w := &lineWriter{n: 2, got: make(chan struct{}, 2)}
+	done := make(chan error)
+	go func() { done <- RunStatusline([]string{"--template", "{state}: {artist} - {title}"}, w) }()
+
+	<-w.got
+	hub.SetStatus(Status{State: "paused", Track: Track{Title: "Two & Three"}, Index: 1, Length: 2})
+	hub.Publish(Event{Type: EventState, Data: "paused"})
+	if err := <-done; !errors.Is(err, errEnough) {
+		t.Fatalf("RunStatusline = %v, want the write error", err)
+	}
+	want := []string{"playing: Band - One", "paused: Two & Three"}
+	if strings.Join(w.lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", w.lines, want)
+	}
+}
+
+func TestStatuslineWaybar(t *testing.T) {
+	s := &statusline{format: "waybar", template: DefaultNowPlayingFormat}
+	var got struct {
+		Text, Class string
+		Percentage  int
+	}
+	line := s.render(Status{State: "playing", Track: Track{Title: "Rock & Roll", Artist: "Led Zeppelin"}, Index: 0, Length: 1, Position: 60, Duration: 240})
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("waybar output %s: %v", line, err)
+	}
+	if got.Text != "Led Zeppelin - Rock &amp; Roll" || got.Class != "playing" || got.Percentage != 25 {
+		t.Errorf("waybar output = %+v", got)
+	}
+
+	line = s.render(Status{State: "stopped", Index: -1})
+	if err := json.Unmarshal([]byte(line), &got); err != nil || got.Text != "" {
+		t.Errorf("stopped waybar output %s should have empty text", line)
+	}
+}
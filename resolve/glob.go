@@ -0,0 +1,159 @@
+package resolve
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cliamp/player"
+	"cliamp/playlist"
+)
+
+// expandHome replaces a leading "~/" with the user's home directory, for
+// quoted arguments the shell left alone.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// exclude holds the patterns of files and folders that directory scans
+// and glob expansion leave out.
+var exclude playlist.Excludes
+
+// SetExclude sets the patterns of files and folders to leave out when
+// scanning directories and expanding globs. Paths named directly are
+// never excluded.
+func SetExclude(patterns []string) {
+	exclude = patterns
+}
+
+// excluded reports whether p, found while walking root, is excluded.
+func excluded(root, p string, dir bool) bool {
+	if len(exclude) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		rel = filepath.Base(p)
+	}
+	return exclude.Match(rel, dir)
+}
+
+// expandGlob returns the paths matching pattern, in lexical order. Besides
+// the wildcards of filepath.Match, a "**" path element matches any number
+// of directories, so "Music/**/*.flac" finds FLAC files at any depth. A
+// directory that matches stands for the audio files under it. Matches
+// that are neither audio files, nor directories, nor playlists when the
+// pattern asks for playlists are dropped, as are excluded ones.
+func expandGlob(pattern string) ([]string, error) {
+	var matches []string
+	if !strings.Contains(pattern, "**") {
+		var err error
+		if matches, err = filepath.Glob(pattern); err != nil {
+			return nil, err
+		}
+	} else {
+		matches = globRecursive(pattern)
+	}
+	wantLists := playlist.IsLocalM3U(pattern) || playlist.IsLocalPLS(pattern)
+	kept := matches[:0]
+	for _, m := range matches {
+		if fi, err := os.Stat(m); err == nil && exclude.Match(filepath.Base(m), fi.IsDir()) {
+			continue
+		}
+		switch {
+		case playlist.IsLocalM3U(m) || playlist.IsLocalPLS(m):
+			if !wantLists {
+				continue
+			}
+		case isAudioFile(m):
+		default:
+			if fi, err := os.Stat(m); err != nil || !fi.IsDir() {
+				continue
+			}
+		}
+		kept = append(kept, m)
+	}
+	return kept, nil
+}
+
+// globRecursive walks the directory before pattern's first wildcard and
+// gathers what matches the rest of it. A matching directory is not
+// entered: collectAudioFiles takes everything under it.
+func globRecursive(pattern string) []string {
+	sep := string(filepath.Separator)
+	elems := strings.Split(filepath.Clean(pattern), sep)
+	fixed := 0
+	for fixed < len(elems) && !hasMeta(elems[fixed]) {
+		fixed++
+	}
+	root := strings.Join(elems[:fixed], sep)
+	switch {
+	case root == "" && filepath.IsAbs(pattern):
+		root = sep
+	case root == "":
+		root = "."
+	}
+	rest := elems[fixed:]
+
+	var out []string
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		var parts []string
+		if rel != "." {
+			parts = strings.Split(rel, sep)
+		}
+		if p != root && excluded(root, p, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !matchElems(rest, parts) {
+			return nil
+		}
+		out = append(out, p)
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return out
+}
+
+// matchElems reports whether the path elements parts match the pattern
+// elements pat, where "**" stands for any number of elements.
+func matchElems(pat, parts []string) bool {
+	if len(pat) == 0 {
+		return len(parts) == 0
+	}
+	if pat[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchElems(pat[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	ok, err := filepath.Match(pat[0], parts[0])
+	return ok && err == nil && matchElems(pat[1:], parts[1:])
+}
+
+func hasMeta(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+func isAudioFile(path string) bool {
+	return player.SupportedExts[strings.ToLower(filepath.Ext(path))]
+}
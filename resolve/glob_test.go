@@ -0,0 +1,37 @@
+package resolve
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestExpandGlobRecursive(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.mp3", "x/b.mp3", "x/y/c.mp3", "x/y/cover.jpg", "x/list.m3u", "z/d.flac"} {
+		p := filepath.Join(dir, f)
+		os.MkdirAll(filepath.Dir(p), 0o755)
+		os.WriteFile(p, nil, 0o644)
+	}
+	rel := func(paths []string) []string {
+		for i, p := range paths {
+			paths[i], _ = filepath.Rel(dir, p)
+			paths[i] = filepath.ToSlash(paths[i])
+		}
+		return paths
+	}
+
+	got, err := expandGlob(filepath.Join(dir, "**", "*.mp3"))
+	if want := []string{"a.mp3", "x/b.mp3", "x/y/c.mp3"}; err != nil || !slices.Equal(rel(got), want) {
+		t.Fatalf("**/*.mp3 = %v, %v; want %v", got, err, want)
+	}
+	got, _ = expandGlob(filepath.Join(dir, "x", "**"))
+	if want := []string{"x"}; !slices.Equal(rel(got), want) {
+		t.Fatalf("x/** = %v, want the directory itself", got)
+	}
+	got, _ = expandGlob(filepath.Join(dir, "*", "*"))
+	if want := []string{"x/b.mp3", "x/y", "z/d.flac"}; !slices.Equal(rel(got), want) {
+		t.Fatalf("*/* = %v, want %v without the playlist", got, want)
+	}
+}
@@ -70,8 +70,14 @@ func parseM3U(r io.Reader, baseDir string) ([]m3uEntry, error) {
 			continue
 		}
 
-		// This is a path/URL line.
+		// This is a path/URL line. Playlists written elsewhere may hold
+		// file:// URLs, or relative paths with the other system's separator.
 		path := line
+		if p := playlist.PathFromFileURI(path); p != "" {
+			path = p
+		} else if filepath.Separator == '/' && !playlist.IsURL(path) && !filepath.IsAbs(path) {
+			path = strings.ReplaceAll(path, `\`, "/")
+		}
 		if baseDir != "" && !playlist.IsURL(path) && !filepath.IsAbs(path) {
 			path = filepath.Join(baseDir, path)
 		}
@@ -98,15 +104,18 @@ func m3uEntryToTrack(e m3uEntry) playlist.Track {
 	realtime := isURL && e.Duration < 0
 
 	if e.Title != "" {
+		path, start, end := playlist.SplitRange(e.Path)
 		return playlist.Track{
-			Path:         e.Path,
+			Path:         path,
 			Title:        e.Title,
 			Stream:       isURL,
 			Realtime:     realtime,
 			DurationSecs: duration,
+			Start:        start,
+			End:          end,
 		}
 	}
-	t := playlist.TrackFromPath(e.Path)
+	t := trackFromPath(e.Path)
 	t.Realtime = realtime
 	t.DurationSecs = duration
 	return t
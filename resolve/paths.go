@@ -0,0 +1,37 @@
+package resolve
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// ReadPaths reads a list of paths, one per entry separated by sep: '\n'
+// for the output of find or ls, 0 for find -print0. Blank entries are
+// skipped, and with '\n' a trailing "\r" is dropped. The paths are taken
+// literally, not as globs.
+func ReadPaths(r io.Reader, sep byte) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, scannerInitBufSize), scannerMaxLineSize)
+	sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, sep); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	var paths []string
+	for sc.Scan() {
+		p := sc.Text()
+		if sep == '\n' {
+			p = strings.TrimSuffix(p, "\r")
+		}
+		if strings.TrimSpace(p) != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, sc.Err()
+}
@@ -0,0 +1,18 @@
+package resolve
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadPaths(t *testing.T) {
+	got, err := ReadPaths(strings.NewReader("/m/a.flac\r\n\n/m/b [Live].mp3\n/m/c.ogg"), '\n')
+	if want := []string{"/m/a.flac", "/m/b [Live].mp3", "/m/c.ogg"}; err != nil || !slices.Equal(got, want) {
+		t.Fatalf("newline list = %q, %v; want %q", got, err, want)
+	}
+	got, err = ReadPaths(strings.NewReader("/m/odd\nname.mp3\x00/m/d.wav\x00"), 0)
+	if want := []string{"/m/odd\nname.mp3", "/m/d.wav"}; err != nil || !slices.Equal(got, want) {
+		t.Fatalf("NUL list = %q, %v; want %q", got, err, want)
+	}
+}
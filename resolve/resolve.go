@@ -6,7 +6,6 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io/fs"
 	"mime"
@@ -21,6 +20,7 @@ import (
 	"sync"
 	"time"
 
+	"cliamp/external/podcast"
 	"cliamp/player"
 	"cliamp/playlist"
 
@@ -57,6 +57,10 @@ func Args(args []string) (Result, error) {
 	var files []string
 
 	for _, arg := range args {
+		if playlist.IsStdin(arg) {
+			r.Tracks = append(r.Tracks, playlist.Track{Path: arg, Title: "stdin", Stream: true})
+			continue
+		}
 		if playlist.IsURL(arg) {
 			if playlist.IsFeed(arg) || playlist.IsM3U(arg) || playlist.IsPLS(arg) || playlist.IsYouTubeURL(arg) || playlist.IsYTDL(arg) || playlist.IsXiaoyuzhouEpisode(arg) || sniffFeedURL(arg) {
 				r.Pending = append(r.Pending, arg)
@@ -65,9 +69,26 @@ func Args(args []string) (Result, error) {
 			}
 			continue
 		}
-		matches, err := filepath.Glob(arg)
-		if err != nil || len(matches) == 0 {
-			matches = []string{arg}
+		arg = expandHome(arg)
+		if file, start, end := playlist.SplitRange(arg); start > 0 || end > 0 {
+			if _, err := os.Stat(file); err != nil {
+				return r, fmt.Errorf("scanning %s: %w", arg, err)
+			}
+			files = append(files, arg)
+			continue
+		}
+		// An existing path is taken as it is, even when its name holds
+		// glob characters such as "[Live]".
+		matches := []string{arg}
+		if _, err := os.Lstat(arg); err != nil && hasMeta(arg) {
+			m, err := expandGlob(arg)
+			if err != nil {
+				return r, fmt.Errorf("bad pattern %s: %w", arg, err)
+			}
+			if len(m) == 0 {
+				return r, fmt.Errorf("no audio files match %s", arg)
+			}
+			matches = m
 		}
 		for _, path := range matches {
 			if playlist.IsLocalM3U(path) {
@@ -188,7 +209,8 @@ func sniffFeedURL(rawURL string) bool {
 }
 
 // collectAudioFiles returns audio file paths for the given argument.
-// If path is a directory, it walks it recursively collecting supported files.
+// If path is a directory, it walks it recursively collecting supported files,
+// leaving out what the exclude patterns match.
 // If path is a file with a supported extension, it returns it directly.
 func collectAudioFiles(path string) ([]string, error) {
 	info, err := os.Stat(path)
@@ -208,6 +230,12 @@ func collectAudioFiles(path string) ([]string, error) {
 		if err != nil {
 			return err
 		}
+		if p != path && excluded(path, p, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 		if !d.IsDir() && player.SupportedExts[strings.ToLower(filepath.Ext(p))] {
 			files = append(files, p)
 		}
@@ -239,7 +267,7 @@ func scanTracks(files []string) []playlist.Track {
 		go func() {
 			defer wg.Done()
 			for i := range ch {
-				tracks[i] = playlist.TrackFromPath(files[i])
+				tracks[i] = trackFromPath(files[i])
 			}
 		}()
 	}
@@ -247,49 +275,22 @@ func scanTracks(files []string) []playlist.Track {
 	return tracks
 }
 
+// trackFromPath reads a local track, limited to the time range annotated
+// on its path, if any.
+func trackFromPath(path string) playlist.Track {
+	file, start, end := playlist.SplitRange(path)
+	t := playlist.TrackFromPath(file)
+	t.Start, t.End = start, end
+	return t
+}
+
 // resolveFeed fetches a podcast RSS feed and returns tracks with metadata.
 func resolveFeed(feedURL string) ([]playlist.Track, error) {
-	resp, err := httpClient.Get(feedURL)
+	feed, err := podcast.FetchFeed(httpClient, feedURL)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status %s", resp.Status)
-	}
-
-	var rss struct {
-		Channel struct {
-			Title string `xml:"title"`
-			Items []struct {
-				Title     string `xml:"title"`
-				Duration  string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
-				Enclosure struct {
-					URL  string `xml:"url,attr"`
-					Type string `xml:"type,attr"`
-				} `xml:"enclosure"`
-			} `xml:"item"`
-		} `xml:"channel"`
-	}
-	if err := xml.NewDecoder(resp.Body).Decode(&rss); err != nil {
-		return nil, fmt.Errorf("parsing feed: %w", err)
-	}
-
-	var tracks []playlist.Track
-	for _, item := range rss.Channel.Items {
-		if item.Enclosure.URL == "" {
-			continue
-		}
-		tracks = append(tracks, playlist.Track{
-			Path:         item.Enclosure.URL,
-			Title:        item.Title,
-			Artist:       rss.Channel.Title,
-			Stream:       true,
-			DurationSecs: parseItunesDuration(item.Duration),
-		})
-	}
-	return tracks, nil
+	return feed.Tracks(), nil
 }
 
 // resolveM3U fetches an M3U playlist URL and returns tracks with EXTINF metadata.
@@ -339,6 +340,13 @@ type ytdlFlatEntry struct {
 	PlaylistUploader   string  `json:"playlist_uploader"`
 	WebpageURLBasename string  `json:"webpage_url_basename"`
 	Duration           float64 `json:"duration"`
+
+	// Music metadata, filled by extractors that know it (Bandcamp,
+	// YouTube Music, SoundCloud). Preferred over the page title and
+	// uploader, which are often "Artist - Title" or a label's channel.
+	Track  string `json:"track"`
+	Artist string `json:"artist"`
+	Album  string `json:"album"`
 }
 
 // ytdlFullEntry holds JSON fields from yt-dlp --print-json output (download mode).
@@ -481,35 +489,50 @@ func resolveYTDLRange(pageURL string, start, end int) ([]playlist.Track, error)
 		if err := json.Unmarshal([]byte(line), &e); err != nil {
 			continue
 		}
-		trackURL := e.WebpageURL
-		if trackURL == "" {
-			trackURL = e.URL
-		}
-		if trackURL == "" {
-			continue
-		}
-		title := e.Title
-		if title == "" {
-			title = humanizeBasename(e.WebpageURLBasename)
-		}
-		if title == "" {
-			title = trackURL
-		}
-		artist := e.Uploader
-		if artist == "" {
-			artist = e.PlaylistUploader
+		if t, ok := e.track(); ok {
+			tracks = append(tracks, t)
 		}
-		tracks = append(tracks, playlist.Track{
-			Path:         trackURL,
-			Title:        title,
-			Artist:       artist,
-			Stream:       true,
-			DurationSecs: int(e.Duration),
-		})
 	}
 	return tracks, scanner.Err()
 }
 
+// track converts a yt-dlp entry to a playlist track. It reports false for
+// entries without a URL.
+func (e ytdlFlatEntry) track() (playlist.Track, bool) {
+	trackURL := e.WebpageURL
+	if trackURL == "" {
+		trackURL = e.URL
+	}
+	if trackURL == "" {
+		return playlist.Track{}, false
+	}
+	title := e.Track
+	if title == "" {
+		title = e.Title
+	}
+	if title == "" {
+		title = humanizeBasename(e.WebpageURLBasename)
+	}
+	if title == "" {
+		title = trackURL
+	}
+	artist := e.Artist
+	if artist == "" {
+		artist = e.Uploader
+	}
+	if artist == "" {
+		artist = e.PlaylistUploader
+	}
+	return playlist.Track{
+		Path:         trackURL,
+		Title:        title,
+		Artist:       artist,
+		Album:        e.Album,
+		Stream:       true,
+		DurationSecs: int(e.Duration),
+	}, true
+}
+
 // DownloadYTDL downloads a single track via yt-dlp to the given directory
 // and returns the output file path. Uses yt-dlp's default naming template.
 func DownloadYTDL(pageURL, saveDir string) (string, error) {
@@ -545,53 +568,6 @@ func DownloadYTDL(pageURL, saveDir string) (string, error) {
 	return e.Filename, nil
 }
 
-// parseItunesDuration parses an <itunes:duration> value into seconds.
-// Accepts "HH:MM:SS", "MM:SS", or a plain seconds string (integer or float).
-// Returns 0 for any invalid or negative input.
-func parseItunesDuration(s string) int {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return 0
-	}
-	// parseSec handles fractional seconds (e.g. "61.5" → 61).
-	parseSec := func(s string) (int, error) {
-		f, err := strconv.ParseFloat(s, 64)
-		return int(f), err
-	}
-
-	parts := strings.Split(s, ":")
-	var result int
-	switch len(parts) {
-	case 1:
-		n, err := parseSec(parts[0])
-		if err != nil {
-			return 0
-		}
-		result = n
-	case 2:
-		m, err1 := strconv.Atoi(parts[0])
-		sec, err2 := parseSec(parts[1])
-		if err1 != nil || err2 != nil {
-			return 0
-		}
-		result = m*60 + sec
-	case 3:
-		h, err1 := strconv.Atoi(parts[0])
-		m, err2 := strconv.Atoi(parts[1])
-		sec, err3 := parseSec(parts[2])
-		if err1 != nil || err2 != nil || err3 != nil {
-			return 0
-		}
-		result = h*3600 + m*60 + sec
-	default:
-		return 0
-	}
-	if result < 0 {
-		return 0
-	}
-	return result
-}
-
 // humanizeBasename converts a URL basename like "clr-podcast-467" into "clr podcast 467".
 func humanizeBasename(s string) string {
 	return strings.ReplaceAll(s, "-", " ")
@@ -112,46 +112,6 @@ func TestParseXiaoyuzhouOgAudioTakesPrecedence(t *testing.T) {
 	}
 }
 
-func TestParseItunesDuration(t *testing.T) {
-	tests := []struct {
-		input string
-		want  int
-	}{
-		// Plain seconds
-		{"3600", 3600},
-		{"90", 90},
-		{"0", 0},
-		// Fractional seconds
-		{"3661.5", 3661},
-		{"90.9", 90},
-		// MM:SS
-		{"1:30", 90},
-		{"87:05", 5225},
-		// HH:MM:SS
-		{"1:27:05", 5225},
-		{"0:01:30", 90},
-		// Whitespace
-		{" 3600 ", 3600},
-		// Empty
-		{"", 0},
-		// Invalid — return 0
-		{"abc", 0},
-		{"12:xx", 0},
-		{"1:2:xx", 0},
-		// Negative — clamp to 0
-		{"-1", 0},
-		{"0:-10", 0},
-	}
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got := parseItunesDuration(tt.input)
-			if got != tt.want {
-				t.Errorf("parseItunesDuration(%q) = %d, want %d", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
 type rewriteHostTransport struct {
 	target *url.URL
 	rt     http.RoundTripper
@@ -164,3 +124,30 @@ func (t rewriteHostTransport) RoundTrip(req *http.Request) (*http.Response, erro
 	clone.Host = t.target.Host
 	return t.rt.RoundTrip(clone)
 }
+
+func TestYTDLEntryTrack_PrefersMusicMetadata(t *testing.T) {
+	e := ytdlFlatEntry{
+		WebpageURL: "https://artist.bandcamp.com/track/song",
+		Title:      "Artist - Song (Official)",
+		Uploader:   "Some Label",
+		Track:      "Song",
+		Artist:     "Artist",
+		Album:      "Record",
+		Duration:   201.4,
+	}
+	got, ok := e.track()
+	if !ok {
+		t.Fatal("track() = false for entry with URL")
+	}
+	if got.Title != "Song" || got.Artist != "Artist" || got.Album != "Record" || got.DurationSecs != 201 || !got.Stream {
+		t.Errorf("track() = %+v", got)
+	}
+
+	plain := ytdlFlatEntry{URL: "https://soundcloud.com/a/b", Title: "B", PlaylistUploader: "A"}
+	if got, _ := plain.track(); got.Title != "B" || got.Artist != "A" {
+		t.Errorf("fallback track() = %+v, want title B by A", got)
+	}
+	if _, ok := (ytdlFlatEntry{Title: "no url"}).track(); ok {
+		t.Error("track() = true for entry without URL")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+
+	"cliamp/internal/replaygain"
+	"cliamp/internal/tagwrite"
+	"cliamp/playlist"
+	"cliamp/resolve"
+)
+
+const scanGainUsage = "usage: cliamp scan-gain [--no-album] [--dry-run] <file|folder>..."
+
+// runScanGain implements `cliamp scan-gain`: it measures the loudness of
+// local files and writes their ReplayGain track tags and, for tracks with
+// an album tag, album tags measured over the album's tracks in the same
+// folder. --dry-run prints the gains without writing.
+func runScanGain(args []string, w io.Writer) error {
+	var paths []string
+	album, dryRun := true, false
+	for _, a := range args {
+		switch a {
+		case "--no-album":
+			album = false
+		case "--dry-run":
+			dryRun = true
+		default:
+			if len(a) > 1 && a[0] == '-' {
+				return errors.New(scanGainUsage)
+			}
+			paths = append(paths, a)
+		}
+	}
+	if len(paths) == 0 {
+		return errors.New(scanGainUsage)
+	}
+	resolved, err := resolve.Args(paths)
+	if err != nil {
+		return err
+	}
+	var tracks []playlist.Track
+	seen := make(map[string]bool)
+	for _, t := range resolved.Tracks {
+		if t.Stream || t.Ranged() || seen[t.Path] {
+			continue
+		}
+		seen[t.Path] = true
+		if !dryRun && !tagwrite.Writable(t.Path) {
+			fmt.Fprintf(w, "skipped  %s: cannot tag this format\n", t.Path)
+			continue
+		}
+		tracks = append(tracks, t)
+	}
+	if len(tracks) == 0 {
+		return errors.New("scan-gain: no local audio files to scan")
+	}
+
+	// Decoding is the slow part; measure several files at once.
+	results := make([]replaygain.Result, len(tracks))
+	errs := make([]error, len(tracks))
+	ch := make(chan int)
+	var wg sync.WaitGroup
+	for range min(len(tracks), runtime.NumCPU()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				results[i], errs[i] = replaygain.Analyze(tracks[i].Path)
+			}
+		}()
+	}
+	for i := range tracks {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	albums := make(map[string][]replaygain.Result)
+	for i, t := range tracks {
+		if key := playlist.AlbumKey(t); album && key != "" && errs[i] == nil {
+			albums[key] = append(albums[key], results[i])
+		}
+	}
+	albumResults := make(map[string]replaygain.Result, len(albums))
+	for key, rs := range albums {
+		albumResults[key] = replaygain.Album(rs)
+	}
+
+	failed := 0
+	for i, t := range tracks {
+		err := errs[i]
+		if err == nil && math.IsInf(results[i].Loudness(), -1) {
+			err = errors.New("silent or too short to measure")
+		}
+		if err != nil {
+			fmt.Fprintf(w, "failed   %s: %v\n", t.Path, err)
+			failed++
+			continue
+		}
+		tags := replaygain.Tags{TrackGain: results[i].Gain(), TrackPeak: results[i].Peak, AlbumGain: math.NaN()}
+		line := fmt.Sprintf("%+6.2f dB", tags.TrackGain)
+		if a, ok := albumResults[playlist.AlbumKey(t)]; ok && !math.IsInf(a.Loudness(), -1) {
+			tags.AlbumGain, tags.AlbumPeak = a.Gain(), a.Peak
+			line += fmt.Sprintf("  album %+6.2f dB", tags.AlbumGain)
+		}
+		if !dryRun {
+			if err := replaygain.WriteTags(t.Path, tags); err != nil {
+				fmt.Fprintf(w, "failed   %s: %v\n", t.Path, err)
+				failed++
+				continue
+			}
+		}
+		fmt.Fprintf(w, "%s  %s\n", line, t.Path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("scan-gain: %d of %d files failed", failed, len(tracks))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/mpris"
+)
+
+// relaySignals turns SIGINT, SIGTERM and SIGHUP into the same orderly quit
+// as pressing q: playback fades out, positions and the listen are saved,
+// the terminal is restored and run persists the resume state. A second
+// signal while that is under way ends the program at once. Signals in
+// controls are sent to the model as their message instead, for window
+// manager key bindings. The returned func stops relaying.
+func relaySignals(prog *tea.Program, controls map[os.Signal]interface{}) func() {
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range controls {
+		signal.Notify(ch, sig)
+	}
+	done := make(chan struct{})
+	go func() {
+		quitting := false
+		for {
+			select {
+			case sig := <-ch:
+				if msg, ok := controls[sig]; ok {
+					slog.Debug("control signal", "signal", sig)
+					prog.Send(msg)
+					continue
+				}
+				if quitting {
+					prog.Kill()
+					return
+				}
+				quitting = true
+				slog.Info("quitting on signal", "signal", sig)
+				prog.Send(mpris.QuitMsg{})
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// signalAction returns the message for a signal_usr1 or signal_usr2
+// action, or false for "off".
+func signalAction(action string) (interface{}, bool) {
+	switch action {
+	case "toggle":
+		return mpris.PlayPauseMsg{}, true
+	case "next":
+		return mpris.NextMsg{}, true
+	case "prev":
+		return mpris.PrevMsg{}, true
+	case "stop":
+		return mpris.StopMsg{}, true
+	}
+	return nil, false
+}
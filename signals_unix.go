@@ -0,0 +1,24 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+
+	"cliamp/config"
+)
+
+// controlSignals maps SIGUSR1 and SIGUSR2 to the playback actions the
+// [remote] section gives them, so `pkill -USR1 cliamp` toggles pause
+// without the control socket.
+func controlSignals(rc config.RemoteConfig) map[os.Signal]interface{} {
+	controls := make(map[os.Signal]interface{})
+	if msg, ok := signalAction(rc.SignalUSR1); ok {
+		controls[syscall.SIGUSR1] = msg
+	}
+	if msg, ok := signalAction(rc.SignalUSR2); ok {
+		controls[syscall.SIGUSR2] = msg
+	}
+	return controls
+}
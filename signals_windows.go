@@ -0,0 +1,14 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"cliamp/config"
+)
+
+// controlSignals returns nothing: Windows has no SIGUSR1 or SIGUSR2.
+func controlSignals(config.RemoteConfig) map[os.Signal]interface{} {
+	return nil
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SetAccessible turns on the mode for terminal screen readers: plain ASCII
+// without box drawing or bars, titles that stay put, no visualizer or level
+// meter, and a time line that only changes with the track. Changes of track,
+// play state and status are announced as plain lines printed above the
+// view, which only shows in the terminal's scrollback when cliamp runs
+// without the alternate screen.
+func (m *Model) SetAccessible(v bool) {
+	m.a11y.on = v
+	if !v {
+		return
+	}
+	m.ascii = true
+	m.titleScroll = titleStatic
+	m.vis.Mode = VisNone
+	m.level.on = false
+}
+
+// announce returns a command printing what changed since the last call:
+// the track now playing, a pause or stop, a new status message or error.
+func (m *Model) announce() tea.Cmd {
+	if !m.a11y.on {
+		return nil
+	}
+	var lines []string
+
+	// Loading and buffering come and go between tracks; wait for them.
+	if !m.buffering {
+		track, idx := m.playlist.Current()
+		name := ""
+		if idx >= 0 {
+			name = track.DisplayName()
+			if m.streamTitle != "" && track.Stream {
+				name = m.streamTitle
+			}
+			if track.Album != "" && !track.Stream {
+				name += ", " + track.Album
+			}
+		}
+		state := "stopped"
+		switch {
+		case m.player.IsPlaying() && m.player.IsPaused():
+			state = "paused"
+		case m.player.IsPlaying():
+			state = "playing"
+		}
+		prev := m.a11y
+		if name != prev.track || state != prev.state {
+			switch {
+			case state == "playing" && (name != prev.track || prev.state != "paused"):
+				lines = append(lines, "Playing: "+name)
+			case state == "playing":
+				lines = append(lines, "Resumed")
+			case state == "paused" && prev.state == "playing":
+				lines = append(lines, "Paused at "+formatTimeStr(m.cachedPos, m.cachedDur, track.Stream && m.cachedDur <= 0, timeElapsed))
+			case state == "stopped" && prev.state != "stopped" && prev.state != "":
+				lines = append(lines, "Stopped")
+			}
+			m.a11y.track, m.a11y.state = name, state
+		}
+	}
+
+	if m.status.text != m.a11y.status {
+		m.a11y.status = m.status.text
+		if m.status.text != "" {
+			lines = append(lines, m.status.text)
+		}
+	}
+	errText := ""
+	if m.err != nil {
+		errText = m.err.Error()
+	}
+	if errText != m.a11y.err {
+		m.a11y.err = errText
+		if errText != "" {
+			lines = append(lines, "Error: "+errText)
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+	return tea.Println(plainText(strings.Join(lines, "\n")))
+}
+
+// plainText readies a frame for a screen reader: glyphs become ASCII, lines
+// made only of drawing (separators, bars) are left out along with runs of
+// two or more of the same symbol in other lines, and the indentation that
+// centres the frame is dropped. Colours are kept.
+func plainText(s string) string {
+	var out []string
+	for _, line := range strings.Split(toASCII(s), "\n") {
+		line = strings.TrimSpace(collapseRuns(line))
+		if !strings.ContainsFunc(stripEscapes(line), isWordRune) {
+			// Blank or only drawing: one blank line between sections.
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+func isWordRune(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+// escEnd returns the end of the colour escape sequence at s[i].
+func escEnd(s string, i int) int {
+	j := i + 2 // past ESC [
+	for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+		j++
+	}
+	return min(j+1, len(s))
+}
+
+// collapseRuns removes runs of two or more of the same symbol, such as
+// the dashes in "--- Queue ---", leaving escape sequences alone.
+func collapseRuns(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			end := escEnd(line, i)
+			b.WriteString(line[i:end])
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		j := i + size
+		for strings.HasPrefix(line[j:], string(r)) {
+			j += size
+		}
+		if j-i < 2*size || r == ' ' || isWordRune(r) {
+			b.WriteString(line[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// stripEscapes returns line without its colour escape sequences.
+func stripEscapes(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			i = escEnd(line, i)
+			continue
+		}
+		b.WriteByte(line[i])
+		i++
+	}
+	return b.String()
+}
+
+// accessibleTime is the time line's text in accessible mode: the length
+// alone, so the line is not read out again every second.
+func accessibleTime(dur time.Duration, live bool) string {
+	if live {
+		return "Live"
+	}
+	return "Length " + strings.TrimPrefix(formatTimeStr(0, dur, false, timeElapsed), "00:00 / ")
+}
@@ -0,0 +1,27 @@
+package ui
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	for in, want := range map[string]string{
+		"\n\n      ♫ Song · Album\n      ━━━━●────\n\n\n  ▶ 1. Song": "# Song - Album\n\n> 1. Song",
+		"── Queue (3) ──":             "Queue (3)",
+		"\x1b[1m─── Title ───\x1b[0m": "\x1b[1m Title \x1b[0m",
+		"Volume: -3.0 dB ... next":    "Volume: -3.0 dB  next",
+		"a  b":                        "a  b",
+		"\x1b[2m──────\x1b[0m\nLength 04:01\n": "Length 04:01",
+	} {
+		if got := plainText(in); got != want {
+			t.Errorf("plainText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAccessibleTime(t *testing.T) {
+	if got := accessibleTime(241e9, false); got != "Length 04:01" {
+		t.Errorf("accessibleTime = %q", got)
+	}
+	if got := accessibleTime(0, true); got != "Live" {
+		t.Errorf("accessibleTime(live) = %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// nextAlarm returns the next time after now that the 24-hour clock time
+// "HH:MM" comes round, today or tomorrow.
+func nextAlarm(clock string, now time.Time) (time.Time, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("use a 24-hour time like 07:30")
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+// SetAlarm makes cliamp stay idle until the clock time "HH:MM" and then
+// start the playlist, fading in. It overrides --auto-play.
+func (m *Model) SetAlarm(clock string) error {
+	at, err := nextAlarm(clock, time.Now())
+	if err != nil {
+		return err
+	}
+	m.alarm.at = at
+	return nil
+}
+
+// SetAlarmFade sets how long the alarm takes to fade in from silence.
+func (m *Model) SetAlarmFade(d time.Duration) {
+	m.alarm.fade = max(d, 0)
+}
+
+// checkAlarm starts playback once the alarm time has passed. A track that
+// is already playing is left alone; a paused one starts over, fading in.
+func (m *Model) checkAlarm(now time.Time) tea.Cmd {
+	if m.alarm.at.IsZero() || now.Before(m.alarm.at) {
+		return nil
+	}
+	m.alarm.at = time.Time{}
+	if _, idx := m.playlist.Current(); idx < 0 {
+		m.status.text = "Alarm: the playlist is empty"
+		m.status.ttl = statusTTLLong
+		return nil
+	}
+	if m.player.IsPlaying() {
+		if !m.player.IsPaused() {
+			return nil
+		}
+		m.player.Stop()
+	}
+	m.player.FadeInNext(m.alarm.fade)
+	m.status.text = "Alarm: fading in"
+	m.status.ttl = statusTTLLong
+	cmd := m.playCurrentTrack()
+	m.notifyMPRIS()
+	return cmd
+}
+
+// openAlarm opens the alarm time prompt.
+func (m *Model) openAlarm() {
+	m.alarm.editing = true
+	m.alarm.input = ""
+}
+
+// handleAlarmKey processes key presses while the alarm time prompt is open.
+// Entering nothing turns the alarm off.
+func (m *Model) handleAlarmKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.alarm.editing = false
+		return m.quit()
+	case tea.KeyEscape:
+		m.alarm.editing = false
+	case tea.KeyEnter:
+		if strings.TrimSpace(m.alarm.input) == "" {
+			m.alarm.editing = false
+			if !m.alarm.at.IsZero() {
+				m.alarm.at = time.Time{}
+				m.status.text = "Alarm off"
+				m.status.ttl = statusTTLMedium
+			}
+			return nil
+		}
+		if err := m.SetAlarm(m.alarm.input); err != nil {
+			m.status.text = err.Error()
+			m.status.ttl = statusTTLShort
+			m.alarm.input = ""
+			return nil
+		}
+		m.alarm.editing = false
+		in := time.Until(m.alarm.at).Round(time.Minute)
+		m.status.text = fmt.Sprintf("Alarm set for %s (in %s)", m.alarm.at.Format("15:04"), strings.TrimSuffix(in.String(), "0s"))
+		m.status.ttl = statusTTLMedium
+	case tea.KeyBackspace:
+		m.alarm.input = removeLastRune(m.alarm.input)
+	case tea.KeyRunes:
+		m.alarm.input += string(msg.Runes)
+	}
+	return nil
+}
+
+func (m Model) renderAlarmOverlay() string {
+	current := "No alarm set"
+	if !m.alarm.at.IsZero() {
+		current = "Alarm set for " + m.alarm.at.Format("15:04")
+	}
+	input := dimStyle.Faint(true).Render("  07:30")
+	if m.alarm.input != "" {
+		input = playlistSelectedStyle.Render("  " + m.alarm.input + "_")
+	}
+	lines := []string{
+		titleStyle.Render("A L A R M"),
+		"",
+		dimStyle.Render("  " + current),
+		"",
+		input,
+		dimStyle.Render("  24-hour time; empty turns the alarm off"),
+		"",
+		helpKey("Enter", "Set ") + helpKey("Esc", "Cancel"),
+	}
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
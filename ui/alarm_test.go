@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextAlarm(t *testing.T) {
+	now := time.Date(2024, 5, 1, 22, 15, 0, 0, time.Local)
+	for _, tc := range []struct {
+		clock string
+		want  time.Time
+	}{
+		{"23:00", time.Date(2024, 5, 1, 23, 0, 0, 0, time.Local)},
+		{"07:30", time.Date(2024, 5, 2, 7, 30, 0, 0, time.Local)},
+		{"22:15", time.Date(2024, 5, 2, 22, 15, 0, 0, time.Local)},
+	} {
+		got, err := nextAlarm(tc.clock, now)
+		if err != nil {
+			t.Fatalf("nextAlarm(%q): %v", tc.clock, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("nextAlarm(%q) = %v, want %v", tc.clock, got, tc.want)
+		}
+	}
+	if _, err := nextAlarm("7:30am", now); err == nil {
+		t.Fatal("nextAlarm(7:30am) succeeded, want error")
+	}
+}
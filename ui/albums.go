@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cliamp/playlist"
+)
+
+// plRow is one line of the playlist view: a track, or the header of the
+// album that starts at track.
+type plRow struct {
+	track  int
+	header bool
+}
+
+// SetGroupAlbums sets whether the playlist is grouped under album headers.
+func (m *Model) SetGroupAlbums(v bool) {
+	m.albums.on = v
+	m.albums.n = -1
+	if v {
+		m.regroupAlbums()
+	}
+}
+
+// toggleGroupAlbums switches album grouping on or off.
+func (m *Model) toggleGroupAlbums() {
+	m.SetGroupAlbums(!m.albums.on)
+	if m.albums.on {
+		m.status.text = "Grouped by album"
+	} else {
+		m.status.text = "Album grouping off"
+	}
+	m.status.ttl = statusTTLDefault
+	m.adjustScroll()
+}
+
+// regroupAlbums sorts newly loaded tracks into their albums. It runs only
+// when the number of tracks changes, so tracks moved by hand stay put.
+func (m *Model) regroupAlbums() {
+	if !m.albums.on || m.playlist.Len() == m.albums.n {
+		return
+	}
+	m.albums.n = m.playlist.Len()
+	var path string
+	if tracks := m.playlist.Tracks(); m.plCursor >= 0 && m.plCursor < len(tracks) {
+		path = tracks[m.plCursor].Path
+	}
+	if !m.playlist.GroupAlbums() {
+		return
+	}
+	for i, t := range m.playlist.Tracks() {
+		if t.Path == path {
+			m.plCursor = i
+			break
+		}
+	}
+	m.adjustScroll()
+}
+
+// toggleAlbumFold collapses the album under the cursor to its header, or
+// expands it again.
+func (m *Model) toggleAlbumFold() {
+	tracks := m.playlist.Tracks()
+	if !m.albums.on || m.plCursor < 0 || m.plCursor >= len(tracks) {
+		return
+	}
+	key := playlist.AlbumKey(tracks[m.plCursor])
+	if key == "" {
+		return
+	}
+	if m.albums.collapsed == nil {
+		m.albums.collapsed = make(map[string]bool)
+	}
+	if m.albums.collapsed[key] {
+		delete(m.albums.collapsed, key)
+	} else {
+		m.albums.collapsed[key] = true
+		// Park the cursor on the album's first track, which the header
+		// stands for.
+		for m.plCursor > 0 && playlist.AlbumKey(tracks[m.plCursor-1]) == key {
+			m.plCursor--
+		}
+	}
+	m.albums.folds++
+	m.adjustScroll()
+}
+
+// plRows lays out the playlist view. Without grouping every track is one
+// row; with it, each album gets a header and collapsed albums show only
+// that header.
+func (m Model) plRows() []plRow {
+	tracks := m.playlist.Tracks()
+	rows := make([]plRow, 0, len(tracks))
+	prev := ""
+	for i, t := range tracks {
+		if !m.albums.on {
+			rows = append(rows, plRow{track: i})
+			continue
+		}
+		key := playlist.AlbumKey(t)
+		if key != "" && key != prev {
+			rows = append(rows, plRow{track: i, header: true})
+		}
+		prev = key
+		if key == "" || !m.albums.collapsed[key] {
+			rows = append(rows, plRow{track: i})
+		}
+	}
+	return rows
+}
+
+// collapsedHeader reports whether r is the header of a collapsed album,
+// which the cursor can select in place of the hidden tracks.
+func (m Model) collapsedHeader(r plRow) bool {
+	return r.header && m.albums.collapsed[playlist.AlbumKey(m.playlist.Tracks()[r.track])]
+}
+
+// selectable reports whether the cursor can rest on r.
+func (m Model) selectable(r plRow) bool {
+	return !r.header || m.collapsedHeader(r)
+}
+
+// cursorRow returns the row showing plCursor: its track, or the header of
+// its album when that is collapsed.
+func (m Model) cursorRow(rows []plRow) int {
+	header := 0
+	for i, r := range rows {
+		if r.track > m.plCursor {
+			break
+		}
+		if r.header {
+			header = i
+		} else if r.track == m.plCursor {
+			return i
+		}
+	}
+	return header
+}
+
+// albumHeader renders the header line of the album starting at track
+// start: its title, artist (or "Various Artists") and year, plus the
+// track count while collapsed.
+func albumHeader(tracks []playlist.Track, start int, collapsed bool) string {
+	first := tracks[start]
+	key := playlist.AlbumKey(first)
+	n := 0
+	artist := first.Artist
+	for _, t := range tracks[start:] {
+		if playlist.AlbumKey(t) != key {
+			break
+		}
+		if t.Artist != artist {
+			artist = "Various Artists"
+		}
+		n++
+	}
+	parts := []string{first.Album}
+	if artist != "" {
+		parts = append(parts, artist)
+	}
+	if first.Year > 0 {
+		parts = append(parts, strconv.Itoa(first.Year))
+	}
+	marker := "▾ "
+	if collapsed {
+		marker = "▸ "
+		parts = append(parts, fmt.Sprintf("%d tracks", n))
+	}
+	return marker + strings.Join(parts, " · ")
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"testing"
+
+	"cliamp/playlist"
+)
+
+func TestAlbumGroupingRows(t *testing.T) {
+	pl := playlist.New()
+	pl.Replace([]playlist.Track{
+		{Path: "/m/a/2.mp3", Album: "A", TrackNumber: 2},
+		{Path: "/m/b/1.mp3", Album: "B", TrackNumber: 1},
+		{Path: "/m/a/1.mp3", Album: "A", TrackNumber: 1},
+		{Path: "/m/loose.mp3"},
+	})
+	m := Model{playlist: pl, plVisible: 10, focus: focusPlaylist}
+	m.SetGroupAlbums(true)
+
+	// A header, A1, A2, B header, B1, loose.
+	rows := m.plRows()
+	if len(rows) != 6 || !rows[0].header || !rows[3].header || rows[5].header {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if got := pl.Tracks()[1].Path; got != "/m/a/2.mp3" {
+		t.Fatalf("track 1 = %s, want A's second track", got)
+	}
+
+	// Moving down skips the expanded album's header.
+	m.plCursor = 1
+	m.movePlCursor(1)
+	if m.plCursor != 2 {
+		t.Fatalf("cursor = %d, want 2 (B1)", m.plCursor)
+	}
+
+	// Folding A parks the cursor on its header, in place of A's tracks.
+	m.plCursor = 1
+	m.toggleAlbumFold()
+	rows = m.plRows()
+	if len(rows) != 4 || m.plCursor != 0 || m.cursorRow(rows) != 0 {
+		t.Fatalf("folded: rows %+v cursor %d", rows, m.plCursor)
+	}
+	m.movePlCursor(1)
+	if m.plCursor != 2 {
+		t.Fatalf("cursor = %d after folding, want 2 (B1)", m.plCursor)
+	}
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"math/bits"
+	"os"
+	"strings"
+)
+
+// asciiGlyphs maps the glyphs the UI draws to plain ASCII one column wide,
+// so layouts line up the same with or without them.
+var asciiGlyphs = map[rune]rune{
+	'↑': '^', '↓': 'v', '←': '<', '→': '>', '⇢': '>',
+	'▸': '>', '▶': '>', '▾': 'v', '⏯': '>', '⏸': '"',
+	'─': '-', '━': '=', '│': '|', '┃': '|', '┼': '+', '╬': '+',
+	'╱': '/', '╲': '\\', '╳': 'X', '▞': '/', '▚': '\\',
+	'█': '#', '▓': '#', '▒': '+', '░': '.', '▌': '|', '▐': '|', '▀': '"',
+	'▁': '_', '▂': '_', '▃': '=', '▄': '=', '▅': '#', '▆': '#', '▇': '#',
+	'●': '*', '◌': 'o', '■': '#', '✓': '+', '✦': '*', '♫': '#', '♪': '#',
+	'⟳': '@', '⇆': '=', '∞': '~', '±': '+', '—': '-', '–': '-', '…': '~', '·': '-', '×': 'x',
+}
+
+// asciiRune returns the ASCII stand-in for a UI glyph. Braille dots, used
+// by most visualizers, become a character of similar density. Other
+// non-ASCII text, such as track titles, is left for the terminal.
+func asciiRune(r rune) rune {
+	if r < 0x80 {
+		return r
+	}
+	if a, ok := asciiGlyphs[r]; ok {
+		return a
+	}
+	switch {
+	case r >= 0x2800 && r <= 0x28FF: // braille patterns
+		switch n := bits.OnesCount(uint(r - 0x2800)); {
+		case n == 0:
+			return ' '
+		case n <= 2:
+			return '.'
+		case n <= 5:
+			return ':'
+		default:
+			return '#'
+		}
+	case r >= 0x2500 && r <= 0x257F: // box drawing
+		return '+'
+	case r >= 0x2580 && r <= 0x259F: // block elements
+		return '#'
+	case r >= 0xFF66 && r <= 0xFF9D: // half-width katakana (Matrix)
+		return 'a' + (r-0xFF66)%26
+	}
+	return r
+}
+
+func toASCII(s string) string {
+	return strings.Map(asciiRune, s)
+}
+
+// SetASCII sets whether the UI is drawn with plain ASCII only.
+func (m *Model) SetASCII(v bool) { m.ascii = v }
+
+// DetectASCII guesses whether the terminal lacks the glyphs the UI uses:
+// the Linux console and other bare terminal types, or a locale that is
+// not UTF-8.
+func DetectASCII() bool {
+	switch os.Getenv("TERM") {
+	case "linux", "dumb", "vt100", "vt102", "vt220", "ansi":
+		return true
+	}
+	for _, v := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if loc := os.Getenv(v); loc != "" {
+			loc = strings.ToLower(loc)
+			return !strings.Contains(loc, "utf-8") && !strings.Contains(loc, "utf8")
+		}
+	}
+	return false
+}
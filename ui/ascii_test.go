@@ -0,0 +1,38 @@
+package ui
+
+import "testing"
+
+func TestToASCII(t *testing.T) {
+	for in, want := range map[string]string{
+		"▶ 1. Song · Album":     "> 1. Song - Album",
+		"━━━━●────":             "====*----",
+		"⣿⠁⠀":                   "#. ",
+		"Björk ─ Jóga":          "Björk - Jóga",
+		"\x1b[1m♫ Title\x1b[0m": "\x1b[1m# Title\x1b[0m",
+	} {
+		if got := toASCII(in); got != want {
+			t.Errorf("toASCII(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDetectASCII(t *testing.T) {
+	for _, tc := range []struct {
+		term, lang string
+		want       bool
+	}{
+		{"xterm-256color", "en_US.UTF-8", false},
+		{"linux", "en_US.UTF-8", true},
+		{"xterm", "C", true},
+		{"xterm", "de_DE.utf8", false},
+		{"xterm", "", false},
+	} {
+		t.Setenv("TERM", tc.term)
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tc.lang)
+		if got := DetectASCII(); got != tc.want {
+			t.Errorf("TERM=%s LANG=%s: DetectASCII = %v, want %v", tc.term, tc.lang, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"cliamp/internal/audiobook"
+	"cliamp/playlist"
+)
+
+// bookSaveEvery is how often, in seconds of playback, the position in an
+// audiobook is written while it plays, so a crash loses little.
+const bookSaveEvery = 30
+
+// SetAudiobooks attaches the audiobook library. Tracks it covers resume
+// where they were left, a little earlier, and never play shuffled.
+func (m *Model) SetAudiobooks(l *audiobook.Library) {
+	m.audiobooks = l
+}
+
+// isBook reports whether track is played in audiobook mode.
+func (m Model) isBook(track playlist.Track) bool {
+	return !track.Stream && m.audiobooks.Is(track.Path)
+}
+
+// saveBookPosition remembers how far playback got in the current
+// audiobook. Called whenever playback leaves a track before its end.
+func (m *Model) saveBookPosition() {
+	track, idx := m.playlist.Current()
+	if idx < 0 || !m.player.IsPlaying() || !m.isBook(track) {
+		return
+	}
+	m.audiobooks.SetPosition(track.Path, int(m.player.Position().Seconds()))
+}
+
+// tickBookPosition saves the audiobook position every bookSaveEvery
+// seconds of playback.
+func (m *Model) tickBookPosition() {
+	if !m.player.IsPlaying() || m.player.IsPaused() {
+		return
+	}
+	secs := int(m.cachedPos.Seconds())
+	if secs/bookSaveEvery == m.bookSavedAt/bookSaveEvery {
+		return
+	}
+	m.bookSavedAt = secs
+	if track, idx := m.playlist.Current(); idx >= 0 && m.isBook(track) {
+		m.audiobooks.SetPosition(track.Path, secs)
+	}
+}
+
+// bookFinished forgets the position of an audiobook that played to the end.
+func (m *Model) bookFinished(track playlist.Track) {
+	if m.isBook(track) {
+		m.audiobooks.SetPosition(track.Path, 0)
+	}
+}
+
+// bookStarted is called whenever a track starts. For an audiobook left
+// part-way through it arms the one-shot resume seek, audiobook.Rewind
+// before the saved point, and it turns shuffle off so the book's files play
+// in order. Shuffle comes back once a track that is not a book starts.
+func (m *Model) bookStarted(track playlist.Track) {
+	if !m.isBook(track) {
+		if m.bookUnshuffled {
+			m.bookUnshuffled = false
+			if !m.playlist.Shuffled() {
+				m.playlist.ToggleShuffle()
+			}
+		}
+		return
+	}
+	m.bookSavedAt = 0
+	if m.playlist.Shuffled() {
+		m.playlist.ToggleShuffle()
+		m.bookUnshuffled = true
+		m.status.text = "Audiobook: shuffle off"
+		m.status.ttl = statusTTLMedium
+	}
+	if secs := m.audiobooks.Position(track.Path) - int(audiobook.Rewind.Seconds()); secs > 0 {
+		m.resume.path = track.Path
+		m.resume.secs = secs
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/autodj"
+	"cliamp/playlist"
+)
+
+// autoDJMsg carries the track Auto-DJ picked; ok is false when the library
+// has nothing left that is not already in the playlist.
+type autoDJMsg struct {
+	track playlist.Track
+	ok    bool
+}
+
+func autoDJCmd(dj *autodj.DJ, last playlist.Track, exclude map[string]bool) tea.Cmd {
+	return func() tea.Msg {
+		t, ok := dj.Pick(last, exclude)
+		return autoDJMsg{track: t, ok: ok}
+	}
+}
+
+// SetAutoDJ attaches the Auto-DJ and sets whether it starts switched on.
+func (m *Model) SetAutoDJ(dj *autodj.DJ, on bool) {
+	m.autoDJ.dj = dj
+	m.autoDJ.on = on && dj != nil
+}
+
+// toggleAutoDJ switches Auto-DJ on or off.
+func (m *Model) toggleAutoDJ() tea.Cmd {
+	if m.autoDJ.dj == nil {
+		return nil
+	}
+	m.autoDJ.on = !m.autoDJ.on
+	m.autoDJ.play = false
+	if m.autoDJ.on {
+		m.status.text = "Auto-DJ on (" + m.autoDJ.dj.Mode.String() + ")"
+	} else {
+		m.status.text = "Auto-DJ off"
+	}
+	m.status.ttl = statusTTLDefault
+	return nil
+}
+
+// autoDJFill asks Auto-DJ for another track when the playlist is about to
+// run out: nothing follows the current track and repeat is off.
+func (m *Model) autoDJFill() tea.Cmd {
+	if !m.autoDJ.on || m.autoDJ.pending || m.playlist.Len() == 0 || m.playlist.Repeat() != playlist.RepeatOff {
+		return nil
+	}
+	if _, ok := m.playlist.PeekNext(); ok {
+		return nil
+	}
+	last, _ := m.playlist.Current()
+	exclude := make(map[string]bool, m.playlist.Len())
+	for _, t := range m.playlist.Tracks() {
+		exclude[t.Path] = true
+	}
+	m.autoDJ.pending = true
+	return autoDJCmd(m.autoDJ.dj, last, exclude)
+}
+
+// autoDJContinue is called when playback reaches the end of the playlist.
+// With Auto-DJ on, it plays the picked track as soon as it arrives and
+// reports true; otherwise the playlist has really ended.
+func (m *Model) autoDJContinue() (tea.Cmd, bool) {
+	if !m.autoDJ.on {
+		return nil, false
+	}
+	m.autoDJ.play = true
+	return m.autoDJFill(), true
+}
+
+// autoDJPicked appends the picked track, marked as auto-added, and starts
+// it if playback was waiting for it.
+func (m *Model) autoDJPicked(msg autoDJMsg) tea.Cmd {
+	m.autoDJ.pending = false
+	play := m.autoDJ.play
+	m.autoDJ.play = false
+	if !msg.ok {
+		m.autoDJ.on = false
+		m.status.text = "Auto-DJ: no new tracks left in the library"
+		m.status.ttl = statusTTLLong
+		if play {
+			return m.playlistEnded()
+		}
+		return nil
+	}
+	msg.track.AutoDJ = true
+	m.playlist.Add(msg.track)
+	if !play || m.player.IsPlaying() {
+		return nil
+	}
+	track, ok := m.playlist.Next()
+	if !ok {
+		return nil
+	}
+	m.plCursor = m.playlist.Index()
+	m.adjustScroll()
+	cmd := m.playTrack(track)
+	m.notifyMPRIS()
+	return cmd
+}
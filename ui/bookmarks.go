@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/internal/bookmark"
+)
+
+// bookmarkRestartWindow is how close behind a bookmark the position must be
+// for "previous bookmark" to skip over it, so repeated presses keep going
+// back instead of landing on the mark just passed.
+const bookmarkRestartWindow = 3 * time.Second
+
+// SetBookmarks attaches the bookmark store.
+func (m *Model) SetBookmarks(s *bookmark.Store) {
+	m.bookmarks = s
+}
+
+// currentMarks returns the bookmarks of the playing track.
+func (m *Model) currentMarks() (path string, marks []bookmark.Mark) {
+	track, idx := m.playlist.Current()
+	if idx < 0 || m.bookmarks == nil {
+		return "", nil
+	}
+	return track.Path, m.bookmarks.Marks(track.Path)
+}
+
+// startBookmark asks for a name for a bookmark at the playback position.
+func (m *Model) startBookmark() {
+	if m.bookmarks == nil || !m.player.IsPlaying() || !m.player.Seekable() {
+		m.status.text = "Bookmarks need a track that can seek"
+		m.status.ttl = statusTTLMedium
+		return
+	}
+	m.bookmarkUI.naming = true
+	m.bookmarkUI.input = ""
+	m.bookmarkUI.at = m.player.Position()
+}
+
+// handleBookmarkNameKey processes key presses while a bookmark is named.
+// An empty name is stored as the position itself.
+func (m *Model) handleBookmarkNameKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.bookmarkUI.naming = false
+		return m.quit()
+	case tea.KeyEscape:
+		m.bookmarkUI.naming = false
+	case tea.KeyEnter:
+		m.bookmarkUI.naming = false
+		path, _ := m.currentMarks()
+		if path == "" {
+			return nil
+		}
+		name := strings.TrimSpace(m.bookmarkUI.input)
+		if name == "" {
+			name = "Bookmark at " + formatClock(m.bookmarkUI.at)
+		}
+		m.bookmarks.Add(path, bookmark.Mark{Name: name, Secs: int(m.bookmarkUI.at.Seconds())})
+		m.status.text = "Bookmarked " + name
+		m.status.ttl = statusTTLMedium
+	case tea.KeyBackspace:
+		m.bookmarkUI.input = removeLastRune(m.bookmarkUI.input)
+	case tea.KeyRunes, tea.KeySpace:
+		m.bookmarkUI.input += string(msg.Runes)
+	}
+	return nil
+}
+
+// seekBookmark jumps to mark.
+func (m *Model) seekBookmark(mark bookmark.Mark) {
+	target := time.Duration(mark.Secs) * time.Second
+	m.player.Seek(target - m.player.Position())
+	m.cachedPos = target
+	m.status.text = fmt.Sprintf("%s (%s)", mark.Name, formatClock(target))
+	m.status.ttl = statusTTLMedium
+}
+
+// nextBookmark jumps to the first bookmark after the playback position.
+func (m *Model) nextBookmark() {
+	_, marks := m.currentMarks()
+	pos := m.player.Position()
+	for _, mk := range marks {
+		if time.Duration(mk.Secs)*time.Second > pos {
+			m.seekBookmark(mk)
+			return
+		}
+	}
+}
+
+// prevBookmark jumps to the last bookmark before the playback position.
+func (m *Model) prevBookmark() {
+	_, marks := m.currentMarks()
+	pos := m.player.Position() - bookmarkRestartWindow
+	for i := len(marks) - 1; i >= 0; i-- {
+		if time.Duration(marks[i].Secs)*time.Second < pos {
+			m.seekBookmark(marks[i])
+			return
+		}
+	}
+}
+
+// openBookmarks shows the playing track's bookmarks.
+func (m *Model) openBookmarks() {
+	if _, marks := m.currentMarks(); len(marks) == 0 {
+		m.status.text = "No bookmarks in this track — press M to add one"
+		m.status.ttl = statusTTLMedium
+		return
+	}
+	m.bookmarkUI.visible = true
+	m.bookmarkUI.cursor = 0
+}
+
+// handleBookmarksKey processes key presses while the bookmark list is open.
+func (m *Model) handleBookmarksKey(msg tea.KeyMsg) tea.Cmd {
+	path, marks := m.currentMarks()
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "'":
+		m.bookmarkUI.visible = false
+	case "up", "k":
+		if m.bookmarkUI.cursor > 0 {
+			m.bookmarkUI.cursor--
+		}
+	case "down", "j":
+		if m.bookmarkUI.cursor < len(marks)-1 {
+			m.bookmarkUI.cursor++
+		}
+	case "enter":
+		m.bookmarkUI.visible = false
+		if m.bookmarkUI.cursor < len(marks) {
+			m.seekBookmark(marks[m.bookmarkUI.cursor])
+		}
+	case "d":
+		m.bookmarks.Remove(path, m.bookmarkUI.cursor)
+		if m.bookmarkUI.cursor >= len(marks)-1 && m.bookmarkUI.cursor > 0 {
+			m.bookmarkUI.cursor--
+		}
+		if len(marks) <= 1 {
+			m.bookmarkUI.visible = false
+		}
+	}
+	return nil
+}
+
+func (m Model) renderBookmarkNameOverlay() string {
+	input := dimStyle.Faint(true).Render("  Bookmark at " + formatClock(m.bookmarkUI.at))
+	if m.bookmarkUI.input != "" {
+		input = playlistSelectedStyle.Render("  " + m.bookmarkUI.input + "_")
+	}
+	lines := []string{
+		titleStyle.Render("B O O K M A R K"),
+		"",
+		dimStyle.Render("  Name for " + formatClock(m.bookmarkUI.at) + ":"),
+		"",
+		input,
+		"",
+		helpKey("Enter", "Save ") + helpKey("Esc", "Cancel"),
+	}
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
+
+func (m Model) renderBookmarksOverlay() string {
+	lines := []string{
+		titleStyle.Render("B O O K M A R K S"),
+		"",
+	}
+
+	_, marks := m.currentMarks()
+	maxVisible := 12
+	scroll := scrollStart(m.bookmarkUI.cursor, maxVisible)
+	rendered := 0
+	for i := scroll; i < len(marks) && i < scroll+maxVisible; i++ {
+		at := formatClock(time.Duration(marks[i].Secs) * time.Second)
+		name := truncate(marks[i].Name, panelWidth-10-len(at))
+		lines = append(lines, cursorLine(name+"  "+dimStyle.Render(at), i == m.bookmarkUI.cursor))
+		rendered++
+	}
+
+	lines = padLines(lines, maxVisible, rendered)
+	lines = append(lines, "", dimStyle.Render(fmt.Sprintf("  %d bookmarks", len(marks))))
+	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Enter", "Jump ")+helpKey("d", "Delete ")+helpKey("( )", "Prev/next ")+helpKey("Esc", "Close"))
+
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
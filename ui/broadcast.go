@@ -0,0 +1,28 @@
+package ui
+
+import "fmt"
+
+// updateBroadcast keeps the Icecast song title in step with what is playing
+// and reports a broadcast that dropped. Called once per tick.
+func (m *Model) updateBroadcast() {
+	if err := m.player.BroadcastErr(); err != nil {
+		m.status.text = err.Error()
+		m.status.ttl = statusTTLLong
+		return
+	}
+	if !m.player.Broadcasting() {
+		return
+	}
+	track, idx := m.playlist.Current()
+	if idx < 0 {
+		return
+	}
+	title := track.DisplayName()
+	switch {
+	case m.streamTitle != "" && track.Stream:
+		title = m.streamTitle
+	case track.Artist != "" && track.Title != "":
+		title = fmt.Sprintf("%s - %s", track.Artist, track.Title)
+	}
+	m.player.SetBroadcastTitle(title)
+}
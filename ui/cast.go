@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/cast"
+	"cliamp/player"
+)
+
+// castScanTime is how long the picker listens for Cast devices.
+const castScanTime = 3 * time.Second
+
+// castDevicesMsg carries the result of a device scan.
+type castDevicesMsg struct {
+	devices []cast.Device
+	err     error
+}
+
+// castStartedMsg signals that connecting to a Cast device completed.
+type castStartedMsg struct {
+	name string
+	err  error
+}
+
+func discoverCastCmd() tea.Cmd {
+	return func() tea.Msg {
+		devices, err := cast.Discover(castScanTime)
+		return castDevicesMsg{devices: devices, err: err}
+	}
+}
+
+func startCastCmd(p *player.Player, dev cast.Device, title, artist string) tea.Cmd {
+	return func() tea.Msg {
+		return castStartedMsg{name: dev.Name, err: p.StartCast(dev, title, artist)}
+	}
+}
+
+// openCastPicker shows the output picker and starts looking for devices.
+func (m *Model) openCastPicker() tea.Cmd {
+	m.castPicker.visible = true
+	m.castPicker.cursor = 0
+	m.castPicker.scanning = true
+	m.castPicker.err = nil
+	return discoverCastCmd()
+}
+
+// handleCastPickerKey processes key presses while the output picker is open.
+// Row 0 is the local speaker; the rest are the devices found.
+func (m *Model) handleCastPickerKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "C":
+		m.castPicker.visible = false
+	case "up", "k":
+		if m.castPicker.cursor > 0 {
+			m.castPicker.cursor--
+		}
+	case "down", "j":
+		if m.castPicker.cursor < len(m.castPicker.devices) {
+			m.castPicker.cursor++
+		}
+	case "r":
+		if !m.castPicker.scanning {
+			m.castPicker.scanning = true
+			m.castPicker.err = nil
+			return discoverCastCmd()
+		}
+	case "enter":
+		m.castPicker.visible = false
+		if m.castPicker.cursor == 0 {
+			if m.player.CastTarget() != "" {
+				m.player.StopCast()
+				m.status.text = "Playing on this computer"
+				m.status.ttl = statusTTLMedium
+			}
+			return nil
+		}
+		dev := m.castPicker.devices[m.castPicker.cursor-1]
+		title, artist := "cliamp", ""
+		if track, idx := m.playlist.Current(); idx >= 0 {
+			title, artist = track.DisplayName(), track.Artist
+		}
+		m.status.text = fmt.Sprintf("Connecting to %s...", dev.Name)
+		m.status.ttl = statusTTLDownload // cleared by castStartedMsg
+		return startCastCmd(m.player, dev, title, artist)
+	}
+	return nil
+}
+
+// castDevicesFound fills the picker with the scan result.
+func (m *Model) castDevicesFound(msg castDevicesMsg) {
+	m.castPicker.scanning = false
+	m.castPicker.devices = msg.devices
+	m.castPicker.err = msg.err
+	m.castPicker.cursor = min(m.castPicker.cursor, len(msg.devices))
+}
+
+// castStarted reports the outcome of connecting to a device.
+func (m *Model) castStarted(msg castStartedMsg) {
+	if msg.err != nil {
+		m.status.text = fmt.Sprintf("Cast failed: %s", msg.err)
+		m.status.ttl = statusTTLMedium
+		return
+	}
+	m.status.text = "Casting to " + msg.name
+	m.status.ttl = statusTTLMedium
+}
+
+// checkCastEnded tells the user when a device dropped the session and
+// output fell back to the local speaker.
+func (m *Model) checkCastEnded() {
+	if err := m.player.CastErr(); err != nil {
+		m.status.text = fmt.Sprintf("%s — playing on this computer", err)
+		m.status.ttl = statusTTLLong
+	}
+}
+
+func (m Model) renderCastPicker() string {
+	lines := []string{
+		titleStyle.Render("O U T P U T"),
+		"",
+	}
+
+	current := m.player.CastTarget()
+	mark := func(active bool) string {
+		if active {
+			return "● "
+		}
+		return "  "
+	}
+	lines = append(lines, cursorLine(mark(current == "")+"This computer", m.castPicker.cursor == 0))
+	for i, d := range m.castPicker.devices {
+		label := mark(d.Name == current) + d.Name
+		if d.Model != "" {
+			label += dimStyle.Render("  " + d.Model)
+		}
+		lines = append(lines, cursorLine(label, m.castPicker.cursor == i+1))
+	}
+
+	switch {
+	case m.castPicker.scanning:
+		lines = append(lines, "", dimStyle.Render("  Looking for Cast devices..."))
+	case m.castPicker.err != nil:
+		lines = append(lines, "", errorStyle.Render("  "+m.castPicker.err.Error()))
+	case len(m.castPicker.devices) == 0:
+		lines = append(lines, "", dimStyle.Render("  No Cast devices found"))
+	}
+
+	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Enter", "Select ")+helpKey("r", "Rescan ")+helpKey("Esc", "Close"))
+
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/playlist"
+)
+
+// chapterRestartWindow is how far into a chapter "previous chapter" first
+// restarts it, like previous track does for tracks.
+const chapterRestartWindow = 3 * time.Second
+
+// chaptersLoadedMsg carries the chapters read from a track's file.
+type chaptersLoadedMsg struct {
+	path     string
+	chapters []playlist.Chapter
+}
+
+func loadChaptersCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		return chaptersLoadedMsg{path: path, chapters: playlist.ReadChapters(path)}
+	}
+}
+
+// loadChapters clears the chapter list for a newly started track and reads
+// the new one in the background. Only local files carry chapters.
+func (m *Model) loadChapters(track playlist.Track) tea.Cmd {
+	m.chapters.path = track.Path
+	m.chapters.list = nil
+	m.chapters.visible = false
+	if track.Stream || playlist.IsURL(track.Path) {
+		return nil
+	}
+	return loadChaptersCmd(track.Path)
+}
+
+// chaptersLoaded stores a chapter list if it is still for the current track.
+func (m *Model) chaptersLoaded(msg chaptersLoadedMsg) {
+	if msg.path == m.chapters.path {
+		m.chapters.list = msg.chapters
+	}
+}
+
+// currentChapter returns the index of the chapter at the playback position,
+// or -1.
+func (m *Model) currentChapter() int {
+	return playlist.ChapterAt(m.chapters.list, m.cachedPos)
+}
+
+// seekChapter jumps to the start of chapter i.
+func (m *Model) seekChapter(i int) {
+	if i < 0 || i >= len(m.chapters.list) || !m.player.Seekable() {
+		return
+	}
+	m.player.Seek(m.chapters.list[i].Start - m.player.Position())
+	m.cachedPos = m.chapters.list[i].Start
+	m.status.text = fmt.Sprintf("Chapter %d/%d: %s", i+1, len(m.chapters.list), m.chapters.list[i].Title)
+	m.status.ttl = statusTTLMedium
+}
+
+// nextChapter jumps to the following chapter.
+func (m *Model) nextChapter() {
+	if i := m.currentChapter() + 1; i < len(m.chapters.list) {
+		m.seekChapter(i)
+	}
+}
+
+// prevChapter restarts the current chapter, or goes to the previous one
+// when already near its start.
+func (m *Model) prevChapter() {
+	i := m.currentChapter()
+	if i < 0 {
+		return
+	}
+	if m.player.Position()-m.chapters.list[i].Start < chapterRestartWindow && i > 0 {
+		i--
+	}
+	m.seekChapter(i)
+}
+
+// openChapters shows the chapter list with the playing chapter selected.
+func (m *Model) openChapters() {
+	if len(m.chapters.list) == 0 {
+		m.status.text = "No chapters in this track"
+		m.status.ttl = statusTTLMedium
+		return
+	}
+	m.chapters.visible = true
+	m.chapters.cursor = max(0, m.currentChapter())
+}
+
+// handleChaptersKey processes key presses while the chapter list is open.
+func (m *Model) handleChaptersKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "c":
+		m.chapters.visible = false
+	case "up", "k":
+		if m.chapters.cursor > 0 {
+			m.chapters.cursor--
+		}
+	case "down", "j":
+		if m.chapters.cursor < len(m.chapters.list)-1 {
+			m.chapters.cursor++
+		}
+	case "enter":
+		m.chapters.visible = false
+		m.seekChapter(m.chapters.cursor)
+	}
+	return nil
+}
+
+func (m Model) renderChaptersOverlay() string {
+	lines := []string{
+		titleStyle.Render("C H A P T E R S"),
+		"",
+	}
+
+	maxVisible := 12
+	playing := m.currentChapter()
+	scroll := scrollStart(m.chapters.cursor, maxVisible)
+	rendered := 0
+	for i := scroll; i < len(m.chapters.list) && i < scroll+maxVisible; i++ {
+		c := m.chapters.list[i]
+		mark := "  "
+		if i == playing {
+			mark = "▶ "
+		}
+		start := formatClock(c.Start)
+		name := truncate(c.Title, panelWidth-10-len(start))
+		lines = append(lines, cursorLine(mark+name+"  "+dimStyle.Render(start), i == m.chapters.cursor))
+		rendered++
+	}
+
+	lines = padLines(lines, maxVisible, rendered)
+	lines = append(lines, "", dimStyle.Render(fmt.Sprintf("  %d chapters", len(m.chapters.list))))
+	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Enter", "Jump ")+helpKey("{ }", "Prev/next ")+helpKey("Esc", "Close"))
+
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
+
+// formatClock renders a position as m:ss, or h:mm:ss past the hour.
+func formatClock(d time.Duration) string {
+	s := int(d.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
@@ -154,6 +154,15 @@ func preloadStreamCmd(p *player.Player, path string, knownDuration time.Duration
 	}
 }
 
+// prepareCmd decodes a local track in the background so skipping back to
+// it starts at once. It reports nothing; a failure just means a slower skip.
+func prepareCmd(p *player.Player, path string, knownDuration time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		p.Prepare(path, knownDuration) // errors silently ignored
+		return nil
+	}
+}
+
 func playYTDLStreamCmd(p *player.Player, pageURL string, knownDuration time.Duration) tea.Cmd {
 	return func() tea.Msg {
 		return streamPlayedMsg{err: p.PlayYTDL(pageURL, knownDuration)}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/config"
+)
+
+// effectItem is one row of the effects menu. toggle flips the effect on or
+// off; adjust (optional) nudges its amount by dir (-1 or +1). Both persist
+// the new setting to the config file.
+type effectItem struct {
+	name   string
+	on     func(m *Model) bool
+	value  func(m *Model) string // optional amount shown after the state
+	toggle func(m *Model) error
+	adjust func(m *Model, dir int) error
+}
+
+// effectItems lists the runtime-switchable DSP stages in pipeline order.
+var effectItems = []effectItem{
+	{
+		name: "Karaoke",
+		on:   func(m *Model) bool { return m.player.Karaoke() },
+		toggle: func(m *Model) error {
+			m.player.SetKaraoke(!m.player.Karaoke())
+			return config.Save("karaoke", fmt.Sprintf("%v", m.player.Karaoke()))
+		},
+	},
+	{
+		name:  "Night mode",
+		on:    func(m *Model) bool { return m.player.NightMode() },
+		value: func(m *Model) string { return m.player.NightPreset().String() },
+		toggle: func(m *Model) error {
+			return m.toggleNightMode()
+		},
+		adjust: func(m *Model, dir int) error {
+			// Only two strengths, so either direction just switches.
+			m.player.SetNightPreset(m.player.NightPreset().Next())
+			return config.Save("night_preset", fmt.Sprintf("%q", m.player.NightPreset()))
+		},
+	},
+	{
+		name: "Stereo width",
+		on:   func(m *Model) bool { return m.player.StereoWiden() },
+		value: func(m *Model) string {
+			return fmt.Sprintf("%d%%", int(math.Round(m.player.StereoWidth()*100)))
+		},
+		toggle: func(m *Model) error {
+			m.player.SetStereoWiden(!m.player.StereoWiden())
+			return config.Save("stereo_widen", fmt.Sprintf("%v", m.player.StereoWiden()))
+		},
+		adjust: func(m *Model, dir int) error {
+			pct := int(math.Round(m.player.StereoWidth()*100)) + dir*10
+			m.player.SetStereoWidth(float64(pct) / 100)
+			return config.Save("stereo_width", fmt.Sprintf("%d", int(math.Round(m.player.StereoWidth()*100))))
+		},
+	},
+	{
+		name:  "Crossfeed",
+		on:    func(m *Model) bool { return m.player.Crossfeed() },
+		value: func(m *Model) string { return m.player.CrossfeedPreset().String() },
+		toggle: func(m *Model) error {
+			m.player.SetCrossfeed(!m.player.Crossfeed())
+			return config.Save("crossfeed", fmt.Sprintf("%v", m.player.Crossfeed()))
+		},
+		adjust: func(m *Model, dir int) error {
+			// Only three presets, so either direction just cycles.
+			m.player.SetCrossfeedPreset(m.player.CrossfeedPreset().Next())
+			return config.Save("crossfeed_preset", fmt.Sprintf("%q", m.player.CrossfeedPreset()))
+		},
+	},
+	{
+		name:  "Reverb",
+		on:    func(m *Model) bool { return m.player.Reverb() },
+		value: func(m *Model) string { return m.player.ReverbPreset().String() },
+		toggle: func(m *Model) error {
+			m.player.SetReverb(!m.player.Reverb())
+			return config.Save("reverb", fmt.Sprintf("%v", m.player.Reverb()))
+		},
+		adjust: func(m *Model, dir int) error {
+			m.player.SetReverbPreset(m.player.ReverbPreset().Next())
+			return config.Save("reverb_preset", fmt.Sprintf("%q", m.player.ReverbPreset()))
+		},
+	},
+	{
+		name: "Limiter",
+		on:   func(m *Model) bool { return m.player.Limiter() },
+		toggle: func(m *Model) error {
+			m.player.SetLimiter(!m.player.Limiter())
+			return config.Save("limiter", fmt.Sprintf("%v", m.player.Limiter()))
+		},
+	},
+}
+
+// toggleNightMode switches night mode and saves the choice.
+func (m *Model) toggleNightMode() error {
+	m.player.SetNightMode(!m.player.NightMode())
+	return config.Save("night_mode", fmt.Sprintf("%v", m.player.NightMode()))
+}
+
+// openEffects shows the effects menu.
+func (m *Model) openEffects() {
+	m.effects.visible = true
+	m.effects.cursor = 0
+}
+
+// handleEffectsKey processes key presses while the effects menu is open.
+func (m *Model) handleEffectsKey(msg tea.KeyMsg) tea.Cmd {
+	var err error
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "E":
+		m.effects.visible = false
+	case "up", "k":
+		if m.effects.cursor > 0 {
+			m.effects.cursor--
+		}
+	case "down", "j":
+		if m.effects.cursor < len(effectItems)-1 {
+			m.effects.cursor++
+		}
+	case "enter", " ":
+		err = effectItems[m.effects.cursor].toggle(m)
+	case "left", "h":
+		if adj := effectItems[m.effects.cursor].adjust; adj != nil {
+			err = adj(m, -1)
+		}
+	case "right", "l":
+		if adj := effectItems[m.effects.cursor].adjust; adj != nil {
+			err = adj(m, 1)
+		}
+	}
+	if err != nil {
+		m.status.text = fmt.Sprintf("Config save failed: %s", err)
+		m.status.ttl = statusTTLDefault
+	}
+	return nil
+}
+
+func (m Model) renderEffectsOverlay() string {
+	lines := []string{
+		titleStyle.Render("E F F E C T S"),
+		"",
+	}
+
+	nameW := 0
+	for _, it := range effectItems {
+		nameW = max(nameW, len(it.name))
+	}
+	for i, it := range effectItems {
+		state := "off"
+		if it.on(&m) {
+			state = "on"
+		}
+		label := fmt.Sprintf("%-*s  %-3s", nameW, it.name, state)
+		if it.value != nil {
+			label += "  " + it.value(&m)
+		}
+		lines = append(lines, cursorLine(label, i == m.effects.cursor))
+	}
+
+	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Enter", "Toggle ")+helpKey("←→", "Adjust ")+helpKey("Esc", "Close"))
+
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/config"
+	"cliamp/player"
+)
+
+// EQ band fields edited in the expanded EQ view.
+const (
+	eqFieldFreq = iota
+	eqFieldQ
+	eqFieldGain
+	numEQFields
+)
+
+// eqFreqSteps are the center frequencies ←→ steps through: the ISO
+// third-octave series.
+var eqFreqSteps = []float64{
+	20, 25, 31.5, 40, 50, 63, 80, 100, 125, 160, 200, 250, 315, 400, 500, 630, 800,
+	1000, 1250, 1600, 2000, 2500, 3150, 4000, 5000, 6300, 8000, 10000, 12500, 16000, 20000,
+}
+
+// eqQSteps are the Qs ←→ steps through, half an octave of bandwidth apart.
+var eqQSteps = []float64{0.3, 0.5, 0.7, 1, 1.4, 2, 2.8, 4, 5.6, 8}
+
+// stepValue returns the next value in steps above v (dir > 0) or below it
+// (dir < 0), or v itself at either end, so values set in the config file
+// between steps snap onto the series from where they are.
+func stepValue(steps []float64, v float64, dir int) float64 {
+	if dir > 0 {
+		for _, s := range steps {
+			if s > v*1.001 {
+				return s
+			}
+		}
+		return v
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i] < v/1.001 {
+			return steps[i]
+		}
+	}
+	return v
+}
+
+// eqFreqLabel is a band's center frequency as the controls show it:
+// "70", "1k", "2.5k".
+func eqFreqLabel(hz float64) string {
+	if hz < 1000 {
+		return strconv.FormatFloat(hz, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(float64(int(hz/100+0.5))/10, 'f', -1, 64) + "k"
+}
+
+// eqArray formats per-band values as a TOML array for the config file.
+func eqArray(vals [10]float64) string {
+	parts := make([]string, len(vals))
+	for i, v := range vals {
+		parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// openEQBands shows the expanded EQ view on the selected band.
+func (m *Model) openEQBands() {
+	m.eqBands.visible = true
+	m.eqBands.field = eqFieldFreq
+}
+
+// saveEQShape persists every band's center frequency and Q to config.
+func (m *Model) saveEQShape() {
+	if err := config.Save("eq_freqs", eqArray(m.player.EQFreqs())); err != nil {
+		m.status.text = fmt.Sprintf("Config save failed: %s", err)
+		m.status.ttl = statusTTLDefault
+		return
+	}
+	if err := config.Save("eq_q", eqArray(m.player.EQQs())); err != nil {
+		m.status.text = fmt.Sprintf("Config save failed: %s", err)
+		m.status.ttl = statusTTLDefault
+	}
+}
+
+// adjustEQBand nudges the selected field of the selected band by dir.
+func (m *Model) adjustEQBand(dir int) {
+	band := m.eqCursor
+	switch m.eqBands.field {
+	case eqFieldFreq:
+		m.player.SetEQBandFreq(band, stepValue(eqFreqSteps, m.player.EQFreqs()[band], dir))
+		m.saveEQShape()
+	case eqFieldQ:
+		m.player.SetEQBandQ(band, stepValue(eqQSteps, m.player.EQQs()[band], dir))
+		m.saveEQShape()
+	case eqFieldGain:
+		m.player.SetEQBand(band, m.player.EQBands()[band]+float64(dir))
+		m.eqPresetIdx = -1 // manual tweak → custom
+		m.saveEQ()
+	}
+}
+
+// handleEQBandsKey processes key presses while the expanded EQ view is open.
+func (m *Model) handleEQBandsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "enter", "q":
+		m.eqBands.visible = false
+	case "up", "k":
+		if m.eqCursor > 0 {
+			m.eqCursor--
+		}
+	case "down", "j":
+		if m.eqCursor < numBands-1 {
+			m.eqCursor++
+		}
+	case "tab":
+		m.eqBands.field = (m.eqBands.field + 1) % numEQFields
+	case "shift+tab":
+		m.eqBands.field = (m.eqBands.field + numEQFields - 1) % numEQFields
+	case "left", "h":
+		m.adjustEQBand(-1)
+	case "right", "l":
+		m.adjustEQBand(1)
+	case "r":
+		m.player.SetEQBandFreq(m.eqCursor, player.DefaultEQFreqs[m.eqCursor])
+		m.player.SetEQBandQ(m.eqCursor, player.DefaultEQQ)
+		m.saveEQShape()
+	case " ":
+		return m.togglePlayPause()
+	}
+	return nil
+}
+
+func (m Model) renderEQBandsOverlay() string {
+	lines := []string{
+		titleStyle.Render("E Q   B A N D S"),
+		"",
+		dimStyle.Render(fmt.Sprintf("  %-4s  %-9s  %-6s  %s", "Band", " Freq", " Q", " Gain")),
+	}
+
+	// The selected field of the selected band is bracketed.
+	cell := func(text string, width int, selected bool) string {
+		if selected {
+			return "[" + fmt.Sprintf("%-*s", width, text) + "]"
+		}
+		return " " + fmt.Sprintf("%-*s", width, text) + " "
+	}
+	bands, freqs, qs := m.player.EQBands(), m.player.EQFreqs(), m.player.EQQs()
+	for i := range numBands {
+		sel := i == m.eqCursor
+		label := fmt.Sprintf("%-4d  %s  %s  %s", i+1,
+			cell(eqFreqLabel(freqs[i])+"Hz", 7, sel && m.eqBands.field == eqFieldFreq),
+			cell(strconv.FormatFloat(qs[i], 'f', 2, 64), 4, sel && m.eqBands.field == eqFieldQ),
+			cell(fmt.Sprintf("%+.1fdB", bands[i]), 7, sel && m.eqBands.field == eqFieldGain))
+		lines = append(lines, cursorLine(label, sel))
+	}
+
+	lines = append(lines, "",
+		dimStyle.Render("Preset: "+m.EQPresetName()+" (presets set gains only)"),
+		"",
+		helpKey("↑↓", "Band ")+helpKey("Tab", "Field ")+helpKey("←→", "Adjust ")+helpKey("r", "Reset ")+helpKey("Esc", "Close"))
+
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
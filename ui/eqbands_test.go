@@ -0,0 +1,37 @@
+package ui
+
+import "testing"
+
+func TestStepValue(t *testing.T) {
+	tests := []struct {
+		v    float64
+		dir  int
+		want float64
+	}{
+		{1000, 1, 1250},
+		{1000, -1, 800},
+		{70, 1, 80}, // a default between steps snaps onto the series
+		{70, -1, 63},
+		{20, -1, 20}, // stays put at either end
+		{20000, 1, 20000},
+	}
+	for _, tt := range tests {
+		if got := stepValue(eqFreqSteps, tt.v, tt.dir); got != tt.want {
+			t.Errorf("stepValue(%v, %+d) = %v, want %v", tt.v, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestEQFreqLabel(t *testing.T) {
+	for hz, want := range map[float64]string{
+		70:    "70",
+		1000:  "1k",
+		2500:  "2.5k",
+		3150:  "3.2k",
+		12500: "12.5k",
+	} {
+		if got := eqFreqLabel(hz); got != want {
+			t.Errorf("eqFreqLabel(%v) = %q, want %q", hz, got, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/hooks"
+	"cliamp/player"
+)
+
+// playerEventMsg carries one event from the audio engine.
+type playerEventMsg player.Event
+
+// waitPlayerEvent delivers the next player event. The handler calls it
+// again, so the model stays subscribed for the whole session.
+func waitPlayerEvent(ch <-chan player.Event) tea.Cmd {
+	return func() tea.Msg {
+		e, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return playerEventMsg(e)
+	}
+}
+
+// handlePlayerEvent reacts to a player event. Events carry the serial of
+// the pipeline they came from, so one that arrives after the user has
+// already moved on to another track is ignored.
+func (m *Model) handlePlayerEvent(e player.Event) tea.Cmd {
+	cmds := []tea.Cmd{waitPlayerEvent(m.player.Events())}
+	if e.Track != m.player.Serial() {
+		return tea.Batch(cmds...)
+	}
+	switch e.Kind {
+	case player.EventGapless:
+		cmds = append(cmds, m.gaplessAdvanced()...)
+	case player.EventDrained:
+		// Skip if already buffering a yt-dlp download or waiting to
+		// reconnect; those paths start the next stream themselves.
+		if m.player.IsPlaying() && !m.player.IsPaused() && !m.buffering && m.reconnect.at.IsZero() {
+			cmds = append(cmds, m.trackDrained())
+		}
+	case player.EventPosition:
+		if !m.buffering {
+			m.cachedPos = m.displayPosition()
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// gaplessAdvanced moves the playlist on after the audio engine has
+// already switched to the preloaded next track.
+func (m *Model) gaplessAdvanced() []tea.Cmd {
+	// Capture the track that just finished before advancing the playlist.
+	// For gapless, the track played fully (100% ≥ 50%), so elapsed = duration.
+	finishedTrack, _ := m.playlist.Current()
+	fullDur := time.Duration(finishedTrack.DurationSecs) * time.Second
+	m.maybeScrobble(finishedTrack, fullDur, fullDur)
+	m.hooks.Run(hooks.TrackEnd, finishedTrack, fullDur)
+	m.episodeFinished(finishedTrack)
+	m.bookFinished(finishedTrack)
+	m.recordListen(finishedTrack)
+
+	m.playlist.Next()
+	m.plCursor = m.playlist.Index()
+	m.adjustScroll()
+	m.titleOff = 0
+	// The preload that just fired is consumed — clear the in-flight flag
+	// so the next track can be preloaded.
+	m.preloading = false
+	// A stream decoder error at the track boundary (e.g., server closing
+	// the connection when the preload HTTP request opens) is expected and
+	// not a user-visible problem. Clear any pending error so the red
+	// message doesn't flash at every track transition.
+	m.err = nil
+	var cmds []tea.Cmd
+	// Fire now-playing notification for the track the audio engine just
+	// started. playTrack() is not called on this path, so we must notify
+	// here explicitly.
+	if newTrack, idx := m.playlist.Current(); idx >= 0 {
+		m.nowPlaying(newTrack)
+		m.restoreEpisodePosition(newTrack)
+		m.bookStarted(newTrack)
+		m.trackEQStarted(newTrack)
+		m.applyResume()
+		cmds = append(cmds, m.loadChapters(newTrack))
+	}
+	cmds = append(cmds, m.preloadNext())
+	m.notifyMPRIS()
+	return cmds
+}
+
+// trackDrained handles the end of a track with nothing preloaded after
+// it: the end of the playlist, or a next track that still has to load.
+func (m *Model) trackDrained() tea.Cmd {
+	// Track drained to end — always ≥ 50%.
+	finishedTrack, _ := m.playlist.Current()
+	drainDur := time.Duration(finishedTrack.DurationSecs) * time.Second
+	m.maybeScrobble(finishedTrack, drainDur, drainDur)
+	m.hooks.Run(hooks.TrackEnd, finishedTrack, drainDur)
+	m.episodeFinished(finishedTrack)
+	m.bookFinished(finishedTrack)
+	m.recordListen(finishedTrack)
+
+	// Stop the player before dispatching the async nextTrack command.
+	// This clears the gapless streamer so the finished track cannot
+	// replay while waiting for a yt-dlp pipe chain to spin up.
+	m.player.Stop()
+	cmd := m.nextTrack()
+	m.notifyMPRIS()
+	return cmd
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"math"
+	"math/bits"
+)
+
+// fftHalf is the length of the complex FFT that a real fftSize-point
+// transform runs on: the even samples go in the real parts and the odd
+// ones in the imaginary parts.
+const fftHalf = fftSize / 2
+
+// Tables for realFFT, filled once in init.
+var (
+	fftTwiddle [fftHalf / 2]complex128 // e^(-2πij/fftHalf)
+	fftSplit   [fftHalf]complex128     // e^(-2πik/fftSize), for unpacking the real spectrum
+	fftBitRev  [fftHalf]uint16         // bit-reversed index for the in-place FFT
+)
+
+func init() {
+	for j := range fftTwiddle {
+		s, c := math.Sincos(-2 * math.Pi * float64(j) / fftHalf)
+		fftTwiddle[j] = complex(c, s)
+	}
+	for k := range fftSplit {
+		s, c := math.Sincos(-2 * math.Pi * float64(k) / fftSize)
+		fftSplit[k] = complex(c, s)
+	}
+	shift := 16 - bits.TrailingZeros(fftHalf)
+	for i := range fftBitRev {
+		fftBitRev[i] = bits.Reverse16(uint16(i)) >> shift
+	}
+}
+
+// realFFT writes the magnitudes of bins 0…fftHalf-1 of the spectrum of
+// in (fftSize samples) to mag, using work (fftHalf values) as scratch.
+// It allocates nothing and does half the work of a complex transform.
+func realFFT(in []float64, work []complex128, mag []float64) {
+	for i, r := range fftBitRev {
+		work[r] = complex(in[2*i], in[2*i+1])
+	}
+	for size := 2; size <= fftHalf; size <<= 1 {
+		half, step := size/2, fftHalf/size
+		for start := 0; start < fftHalf; start += size {
+			for j := range half {
+				a, b := start+j, start+j+half
+				t := fftTwiddle[j*step] * work[b]
+				work[a], work[b] = work[a]+t, work[a]-t
+			}
+		}
+	}
+	// Split the packed transform into the spectra of the even and odd
+	// samples, then combine them into the real input's spectrum.
+	for k := range fftHalf {
+		z := work[k]
+		zc := work[(fftHalf-k)%fftHalf]
+		zc = complex(real(zc), -imag(zc))
+		even := (z + zc) / 2
+		odd := (z - zc) * complex(0, -0.5)
+		x := even + fftSplit[k]*odd
+		mag[k] = math.Hypot(real(x), imag(x))
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRealFFTMatchesDFT(t *testing.T) {
+	in := make([]float64, fftSize)
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range in {
+		in[i] = math.Sin(2*math.Pi*50*float64(i)/fftSize) + r.Float64() - 0.5
+	}
+	mag := make([]float64, fftHalf)
+	realFFT(in, make([]complex128, fftHalf), mag)
+
+	for _, k := range []int{0, 1, 49, 50, 51, 333, fftHalf - 1} {
+		var x complex128
+		for n, s := range in {
+			x += complex(s, 0) * cmplx.Rect(1, -2*math.Pi*float64(k*n)/fftSize)
+		}
+		if want := cmplx.Abs(x); math.Abs(mag[k]-want) > 1e-6*max(1, want) {
+			t.Errorf("bin %d = %g, want %g", k, mag[k], want)
+		}
+	}
+}
+
+func BenchmarkRealFFT(b *testing.B) {
+	in := make([]float64, fftSize)
+	for i := range in {
+		in[i] = math.Sin(float64(i) / 7)
+	}
+	work := make([]complex128, fftHalf)
+	mag := make([]float64, fftHalf)
+	b.ReportAllocs()
+	for b.Loop() {
+		realFFT(in, work, mag)
+	}
+}
+
+func BenchmarkAnalyze(b *testing.B) {
+	v := NewVisualizer(44100)
+	samples := make([]float64, fftSize)
+	for i := range samples {
+		samples[i] = math.Sin(float64(i) / 7)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		v.Analyze(samples)
+	}
+}
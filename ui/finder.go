@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/library"
+	"cliamp/playlist"
+)
+
+// finderMax caps the matches the library finder keeps for one query.
+const finderMax = 200
+
+// libraryScannedMsg reports that the library has been walked and can be
+// searched without blocking.
+type libraryScannedMsg struct{}
+
+func scanLibraryCmd(lib *library.Library) tea.Cmd {
+	return func() tea.Msg {
+		lib.Files()
+		return libraryScannedMsg{}
+	}
+}
+
+// SetLibrary attaches the music library searched by the finder.
+func (m *Model) SetLibrary(lib *library.Library) {
+	m.library = lib
+}
+
+// openFinder shows the library finder, walking the library in the
+// background the first time.
+func (m *Model) openFinder() tea.Cmd {
+	if m.library == nil {
+		return nil
+	}
+	m.finder.visible = true
+	m.finder.query = ""
+	m.finder.results = nil
+	m.finder.cursor = 0
+	if m.finder.scanned {
+		return nil
+	}
+	m.finder.loading = true
+	return scanLibraryCmd(m.library)
+}
+
+// updateFinder re-runs the finder query. Until the library has been
+// walked the query just accumulates, so typing never waits on the disk.
+func (m *Model) updateFinder() {
+	m.finder.cursor = 0
+	if m.finder.loading {
+		return
+	}
+	m.finder.results = m.library.Find(m.finder.query, finderMax)
+}
+
+// finderTrack returns the playlist index of the selected file, appending
+// it to the playlist unless it is already there.
+func (m *Model) finderTrack() (int, bool) {
+	if m.finder.cursor >= len(m.finder.results) {
+		return 0, false
+	}
+	path := m.finder.results[m.finder.cursor].Path
+	for i, t := range m.playlist.Tracks() {
+		if t.Path == path {
+			return i, true
+		}
+	}
+	m.playlist.Add(playlist.TrackFromPath(path))
+	return m.playlist.Len() - 1, true
+}
+
+// handleFinderKey processes key presses while the library finder is open.
+func (m *Model) handleFinderKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc":
+		m.finder.visible = false
+	case "enter":
+		idx, ok := m.finderTrack()
+		if !ok {
+			return nil
+		}
+		m.finder.visible = false
+		m.playlist.SetIndex(idx)
+		m.plCursor = idx
+		m.adjustScroll()
+		cmd := m.playCurrentTrack()
+		m.notifyMPRIS()
+		return cmd
+	case "tab":
+		idx, ok := m.finderTrack()
+		if !ok {
+			return nil
+		}
+		if m.playlist.QueuePosition(idx) == 0 {
+			m.playlist.Queue(idx)
+		}
+		m.status.text = "Queued: " + m.playlist.Tracks()[idx].DisplayName()
+		m.status.ttl = statusTTLDefault
+	case "up", "ctrl+p":
+		if m.finder.cursor > 0 {
+			m.finder.cursor--
+		}
+	case "down", "ctrl+n":
+		if m.finder.cursor < len(m.finder.results)-1 {
+			m.finder.cursor++
+		}
+	case "backspace":
+		if m.finder.query != "" {
+			m.finder.query = removeLastRune(m.finder.query)
+			m.updateFinder()
+		}
+	case " ":
+		m.finder.query += " "
+		m.updateFinder()
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.finder.query += string(msg.Runes)
+			m.updateFinder()
+		}
+	}
+	return nil
+}
+
+func (m Model) renderFinderOverlay() string {
+	lines := []string{
+		titleStyle.Render("F I N D   I N   L I B R A R Y"),
+		"",
+		playlistSelectedStyle.Render("  > " + m.finder.query + "_"),
+		"",
+	}
+
+	maxVisible := 12
+	rendered := 0
+	switch {
+	case m.finder.loading:
+		lines = append(lines, dimStyle.Render("  Scanning library…"))
+		rendered = 1
+	case len(m.finder.results) == 0:
+		if m.finder.query != "" {
+			lines = append(lines, dimStyle.Render("  No matches"))
+		} else {
+			lines = append(lines, dimStyle.Render("  Type a few letters of an artist, album or title…"))
+		}
+		rendered = 1
+	default:
+		scroll := scrollStart(m.finder.cursor, maxVisible)
+		for j := scroll; j < scroll+maxVisible && j < len(m.finder.results); j++ {
+			name := truncate(m.finder.results[j].Name, panelWidth-6)
+			lines = append(lines, cursorLine(name, j == m.finder.cursor))
+			rendered++
+		}
+	}
+
+	lines = padLines(lines, maxVisible, rendered)
+	count := fmt.Sprintf("  %d found", len(m.finder.results))
+	if len(m.finder.results) == finderMax {
+		count = fmt.Sprintf("  first %d shown", finderMax)
+	}
+	lines = append(lines, "", dimStyle.Render(count))
+	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Enter", "Play ")+helpKey("Tab", "Queue ")+helpKey("Esc", "Close"))
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,54 @@
+package ui
+
+// SetFollow sets whether the playlist view follows playback.
+func (m *Model) SetFollow(v bool) {
+	m.follow.on = v
+	m.follow.idx = -1
+}
+
+// toggleFollow switches follow-playback mode on or off. Switching it on
+// centres the playing track right away.
+func (m *Model) toggleFollow() {
+	m.SetFollow(!m.follow.on)
+	if m.follow.on {
+		m.status.text = "Follow playback on"
+		m.followPlayback()
+	} else {
+		m.status.text = "Follow playback off"
+	}
+	m.status.ttl = statusTTLDefault
+}
+
+// jumpToPlaying moves the playlist cursor to the playing track and centres
+// it in the view.
+func (m *Model) jumpToPlaying() {
+	idx := m.playlist.Index()
+	if idx < 0 || idx >= m.playlist.Len() {
+		return
+	}
+	m.focus = focusPlaylist
+	m.centerOn(idx)
+}
+
+// followPlayback re-centres the view on the playing track whenever it
+// changes. In between, the cursor moves freely, so browsing the playlist
+// is only interrupted by the next track.
+func (m *Model) followPlayback() {
+	if !m.follow.on {
+		return
+	}
+	idx := m.playlist.Index()
+	if idx == m.follow.idx || idx < 0 || idx >= m.playlist.Len() {
+		return
+	}
+	m.follow.idx = idx
+	m.centerOn(idx)
+}
+
+// centerOn puts the playlist cursor on track idx and scrolls it to the
+// middle of the view, or as near as the ends of the playlist allow.
+func (m *Model) centerOn(idx int) {
+	m.plCursor = idx
+	rows := m.plRows()
+	m.plScroll = max(0, min(m.cursorRow(rows)-m.plVisible/2, len(rows)-m.plVisible))
+}
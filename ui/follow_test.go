@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"cliamp/playlist"
+)
+
+func TestFollowPlaybackCentres(t *testing.T) {
+	pl := playlist.New()
+	for i := range 50 {
+		pl.Add(playlist.Track{Path: fmt.Sprintf("/m/%02d.mp3", i)})
+	}
+	m := Model{playlist: pl, plVisible: 10}
+	m.SetFollow(true)
+
+	pl.SetIndex(25)
+	m.followPlayback()
+	if m.plCursor != 25 || m.plScroll != 20 {
+		t.Fatalf("cursor %d scroll %d, want 25 and 20", m.plCursor, m.plScroll)
+	}
+
+	// Browsing away is left alone until the track changes.
+	m.plCursor, m.plScroll = 3, 0
+	m.followPlayback()
+	if m.plCursor != 3 {
+		t.Fatalf("view moved without a track change: cursor %d", m.plCursor)
+	}
+
+	pl.SetIndex(48)
+	m.followPlayback()
+	if m.plCursor != 48 || m.plScroll != 40 {
+		t.Fatalf("at the end: cursor %d scroll %d, want 48 and 40", m.plCursor, m.plScroll)
+	}
+}
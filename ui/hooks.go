@@ -0,0 +1,18 @@
+package ui
+
+import "cliamp/hooks"
+
+// SetHooks attaches the runner for user event scripts.
+func (m *Model) SetHooks(r *hooks.Runner) {
+	m.hooks = r
+}
+
+// runHook fires event for the current track at the current position.
+func (m *Model) runHook(event string) {
+	if m.hooks == nil {
+		return
+	}
+	if track, idx := m.playlist.Current(); idx >= 0 {
+		m.hooks.Run(event, track, m.player.Position())
+	}
+}
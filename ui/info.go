@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/external/acoustid"
+	"cliamp/internal/history"
+	"cliamp/internal/tagwrite"
+	"cliamp/player"
+	"cliamp/playlist"
+)
+
+// infoLoadedMsg carries the details of the track shown in the info view
+// that take disk reads to find.
+type infoLoadedMsg struct {
+	path   string
+	fields []playlist.TagField
+	tech   player.TechInfo
+	plays  int
+	last   time.Time
+}
+
+func infoCmd(track playlist.Track, tech *player.TechInfo, l *history.Log) tea.Cmd {
+	return func() tea.Msg {
+		msg := infoLoadedMsg{path: track.Path}
+		if !track.Stream {
+			msg.fields = playlist.ReadTagFields(track.Path)
+			if tech != nil {
+				msg.tech = *tech
+			} else {
+				msg.tech = player.ProbeTechInfo(track.Path)
+			}
+		}
+		if l != nil {
+			if entries, err := l.Entries(); err == nil {
+				msg.plays, msg.last = history.Plays(entries, track.Path)
+			}
+		}
+		return msg
+	}
+}
+
+// infoTrack is the track the info view describes: the one under the
+// cursor while the playlist has focus, otherwise the playing one.
+func (m *Model) infoTrack() playlist.Track {
+	if tracks := m.playlist.Tracks(); m.focus == focusPlaylist && m.plCursor >= 0 && m.plCursor < len(tracks) {
+		return tracks[m.plCursor]
+	}
+	track, _ := m.playlist.Current()
+	return track
+}
+
+// openInfo shows the track info view, filling in tags, encoding and play
+// count in the background.
+func (m *Model) openInfo() tea.Cmd {
+	track := m.infoTrack()
+	m.showInfo = true
+	m.info = infoState{track: track, loading: track.Path != ""}
+	if track.Path == "" {
+		return nil
+	}
+	var tech *player.TechInfo
+	if m.tech.path == track.Path && m.tech.info.Known() {
+		tech = &m.tech.info
+	}
+	return infoCmd(track, tech, m.history)
+}
+
+// setInfo fills in the loaded details, unless the view has moved on to
+// another track meanwhile.
+func (m *Model) setInfo(msg infoLoadedMsg) {
+	if !m.showInfo || msg.path != m.info.track.Path {
+		return
+	}
+	m.info.loading = false
+	m.info.fields = msg.fields
+	m.info.tech = msg.tech
+	m.info.plays = msg.plays
+	m.info.last = msg.last
+}
+
+// SetAcoustID enables identifying files from the info view.
+func (m *Model) SetAcoustID(c *acoustid.Client) {
+	m.acoustid = c
+}
+
+// identifiedMsg carries the recordings AcoustID proposed for a file.
+type identifiedMsg struct {
+	path    string
+	matches []acoustid.Match
+	err     error
+}
+
+// tagsWrittenMsg reports the tags of a file written from a match.
+type tagsWrittenMsg struct {
+	track playlist.Track // with the tags as written
+	err   error
+}
+
+// canIdentify reports whether the info view's track can be identified.
+func (m *Model) canIdentify() bool {
+	t := m.info.track
+	return m.acoustid != nil && t.Path != "" && !t.Stream && tagwrite.Writable(t.Path)
+}
+
+// identify looks the info view's track up on AcoustID in the background.
+func (m *Model) identify() tea.Cmd {
+	if !m.canIdentify() || m.info.identifying || m.info.writing {
+		return nil
+	}
+	m.info.identifying = true
+	c, path := m.acoustid, m.info.track.Path
+	return func() tea.Msg {
+		matches, err := c.Identify(path)
+		return identifiedMsg{path: path, matches: matches, err: err}
+	}
+}
+
+// setMatches lists the proposed recordings, unless the view has moved on
+// to another track meanwhile.
+func (m *Model) setMatches(msg identifiedMsg) {
+	if !m.showInfo || msg.path != m.info.track.Path {
+		return
+	}
+	m.info.identifying = false
+	switch {
+	case msg.err != nil:
+		m.status.text = msg.err.Error()
+	case len(msg.matches) == 0:
+		m.status.text = "AcoustID knows no recording that sounds like this"
+	default:
+		m.info.matches = msg.matches
+		m.info.cursor = 0
+		return
+	}
+	m.status.ttl = statusTTLMedium
+}
+
+// handleMatchesKey processes key presses while AcoustID's proposals are
+// listed. Enter writes the chosen one's tags into the file.
+func (m *Model) handleMatchesKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc":
+		m.info.matches = nil
+	case "up", "k":
+		if m.info.cursor > 0 {
+			m.info.cursor--
+		}
+	case "down", "j":
+		if m.info.cursor < len(m.info.matches)-1 {
+			m.info.cursor++
+		}
+	case "enter":
+		if m.info.writing {
+			return nil
+		}
+		m.info.writing = true
+		return writeMatchCmd(m.info.track, m.info.matches[m.info.cursor])
+	}
+	return nil
+}
+
+func writeMatchCmd(track playlist.Track, match acoustid.Match) tea.Cmd {
+	return func() tea.Msg {
+		fields := []string{"title=" + match.Title, "artist=" + match.Artist}
+		track.Title, track.Artist = match.Title, match.Artist
+		if match.Album != "" {
+			fields = append(fields, "album="+match.Album)
+			track.Album = match.Album
+		}
+		if match.Year > 0 {
+			fields = append(fields, "date="+strconv.Itoa(match.Year))
+			track.Year = match.Year
+		}
+		return tagsWrittenMsg{track: track, err: tagwrite.Write(track.Path, fields)}
+	}
+}
+
+// tagsWritten shows the new tags on every playlist entry of the file and
+// rereads them for the info view.
+func (m *Model) tagsWritten(msg tagsWrittenMsg) tea.Cmd {
+	m.info.writing = false
+	if msg.err != nil {
+		m.status.text = fmt.Sprintf("Writing tags failed: %v", msg.err)
+		m.status.ttl = statusTTLMedium
+		return nil
+	}
+	for i, t := range m.playlist.Tracks() {
+		if t.Path == msg.track.Path {
+			t.Title, t.Artist, t.Album, t.Year = msg.track.Title, msg.track.Artist, msg.track.Album, msg.track.Year
+			m.playlist.SetTrack(i, t)
+		}
+	}
+	m.status.text = "Tags saved: " + msg.track.DisplayName()
+	m.status.ttl = statusTTLMedium
+	if !m.showInfo || m.info.track.Path != msg.track.Path {
+		return nil
+	}
+	m.info.track = msg.track
+	m.info.matches = nil
+	m.info.loading = true
+	return infoCmd(msg.track, nil, m.history)
+}
@@ -7,6 +7,24 @@ import (
 	"time"
 )
 
+// parseSeekTarget parses an absolute seek target: a clock time accepted by
+// parseJumpTarget, or a percentage of dur such as "50%".
+func parseSeekTarget(raw string, dur time.Duration) (time.Duration, error) {
+	s := strings.TrimSpace(raw)
+	pct, ok := strings.CutSuffix(s, "%")
+	if !ok {
+		return parseJumpTarget(s)
+	}
+	if dur <= 0 {
+		return 0, fmt.Errorf("track length unknown; use mm:ss instead")
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+	if err != nil || v < 0 || v > 100 {
+		return 0, fmt.Errorf("percentage must be 0-100")
+	}
+	return time.Duration(float64(dur) * v / 100), nil
+}
+
 func parseJumpTarget(raw string) (time.Duration, error) {
 	s := strings.TrimSpace(raw)
 	if s == "" {
@@ -96,3 +96,39 @@ func TestFormatJumpPlaceholder(t *testing.T) {
 		}
 	}
 }
+
+func TestParseSeekTarget(t *testing.T) {
+	dur := 4 * time.Minute
+	tests := []struct {
+		in      string
+		dur     time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "3:45", dur: dur, want: 3*time.Minute + 45*time.Second},
+		{in: "50%", dur: dur, want: 2 * time.Minute},
+		{in: " 25 % ", dur: dur, want: time.Minute},
+		{in: "0%", dur: dur, want: 0},
+		{in: "100%", dur: dur, want: dur},
+		{in: "12.5%", dur: dur, want: 30 * time.Second},
+		{in: "101%", dur: dur, wantErr: true},
+		{in: "x%", dur: dur, wantErr: true},
+		{in: "50%", dur: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSeekTarget(tt.in, tt.dur)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("parseSeekTarget(%q): expected error, got %v", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parseSeekTarget(%q): unexpected error: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("parseSeekTarget(%q) = %v want %v", tt.in, got, tt.want)
+		}
+	}
+}
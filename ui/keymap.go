@@ -15,29 +15,56 @@ var keymapEntries = []keymapEntry{
 	{"s", "Stop"},
 	{"> .", "Next track"},
 	{"< ,", "Previous track"},
-	{"← →", "Seek ±5s"},
+	{"← →", "Seek ±step (speeds up while held)"},
 	{"Shift+← →", "Seek ±large step"},
+	{"Ctrl+← →", "Scrub (hold to hear snippets while cueing)"},
+	{"B", "Replay the last 10s (configurable)"},
 	{"+ -", "Volume up/down"},
-	{"z", "Toggle shuffle"},
+	{"[ ]", "Balance left/right"},
+	{"z", "Cycle shuffle (Off / Tracks / Albums)"},
 	{"r", "Cycle repeat"},
+	{"1-9", "With repeat one: repeat the track that many more times"},
 	{"m", "Toggle mono"},
+	{"0", "Mute / unmute"},
 	{"e", "Cycle EQ preset"},
+	{"Ctrl+E", "Remember the EQ for this track, then its album, then forget it"},
+	{"E", "Effects menu (karaoke, night, width, crossfeed, reverb, limiter)"},
+	{"n", "Night mode (even out loud and quiet passages)"},
+	{"C", "Output: cast to a Chromecast / this computer"},
 	{"t", "Choose theme"},
 	{"v", "Cycle visualizer"},
 	{"V", "Full-screen visualizer"},
+	{"Ctrl+T", "Time: elapsed / remaining / both"},
 	{"↑ ↓", "Playlist scroll / EQ adjust"},
+	{"PgUp PgDn", "Playlist page up/down"},
+	{"Home End", "Jump to first/last track"},
+	{"P", "Jump to the playing track"},
+	{"O", "Toggle follow playback (keep the playing track centred)"},
+	{"G", "Group playlist by album"},
+	{"Z", "Collapse/expand album (while grouped)"},
 	{"Shift+↑ ↓", "Move track up/down"},
 	{"h l", "EQ cursor left/right"},
-	{"Enter", "Play selected track"},
+	{"Enter", "Play selected track / edit EQ band frequency and Q"},
 	{"a", "Toggle queue (play next)"},
-	{"A", "Queue manager"},
+	{"A", "Queue manager (Tab: recently played)"},
 	{"o", "Open file browser"},
 	{"N", "Navidrome browser"},
 	{"R", "Radio catalog (search online stations)"},
-	{"J", "Jump to time"},
+	{"J g", "Jump to time (mm:ss or 50%)"},
+	{"{ }", "Previous/next chapter"},
+	{"c", "Chapter list"},
+	{"M", "Bookmark the current position"},
+	{"'", "Bookmark list"},
+	{"( )", "Previous/next bookmark"},
+	{"w", "Alarm clock (start playing at a set time)"},
+	{"W", "Stop playing at a set time"},
+	{"T", "Gain offset for the playing track (remembered)"},
+	{"D", "Toggle Auto-DJ (keep adding library tracks)"},
+	{"H", "Listening stats (top artists/tracks, hours)"},
+	{"L", "Find in library (fuzzy, plays or queues any track)"},
 	{"p", "Playlist manager"},
-	{"i", "Track info / metadata"},
-	{"S", "Save/download track to ~/Music"},
+	{"i", "Track info: tags, ReplayGain, codec, play count (a identify, o organize)"},
+	{"S", "Save/download track to ~/Music (podcast episodes: to the podcast library)"},
 	{"x", "Expand/collapse playlist"},
 	{"/", "Search playlist"},
 	{"f", "Find on YouTube (queue play next)"},
@@ -46,6 +73,7 @@ var keymapEntries = []keymapEntry{
 	{"y", "Show lyrics"},
 	{"Tab", "Toggle focus"},
 	{"Esc", "Back to provider"},
+	{"+ d", "Podcasts: subscribe to feed / unsubscribe"},
 	{"Ctrl+K", "This keymap"},
 	{"q", "Quit"},
 }
@@ -2,15 +2,17 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"cliamp/config"
+	"cliamp/hooks"
 	"cliamp/internal/fileutil"
 	"cliamp/playlist"
 )
@@ -20,25 +22,46 @@ func (m *Model) quit() tea.Cmd {
 	// Only save resume for seekable tracks:
 	// - local files (not stream)
 	// - HTTP streams with known duration (podcast MP3s, seek-by-reconnect)
-	// Exclude YTDL (position unreliable) and real-time live streams.
+	// Exclude YTDL (position unreliable), real-time live streams and stdin.
 	if track, _ := m.playlist.Current(); track.Path != "" &&
-		!playlist.IsYTDL(track.Path) && !track.IsLive() &&
+		!playlist.IsYTDL(track.Path) && !track.IsLive() && !playlist.IsStdin(track.Path) &&
 		m.player.IsPlaying() {
 		if secs := int(m.player.Position().Seconds()); secs > 0 {
 			m.exitResume.path = track.Path
 			m.exitResume.secs = secs
 		}
 	}
+	m.saveEpisodePosition()
+	m.saveBookPosition()
+	if track, _ := m.playlist.Current(); track.Path != "" {
+		m.recordListen(track)
+	}
 
 	m.player.Close()
 	m.quitting = true
 	return tea.Quit
 }
 
-// scrobbleCurrent fires a scrobble for the currently playing track if applicable.
+// saveBalance persists the stereo balance to config.
+func (m *Model) saveBalance() {
+	if err := config.Save("balance", strconv.FormatFloat(m.player.Balance(), 'f', -1, 64)); err != nil {
+		m.status.text = fmt.Sprintf("Config save failed: %s", err)
+		m.status.ttl = statusTTLDefault
+	}
+}
+
+// scrobbleCurrent fires a scrobble and the track_end hook for the currently
+// playing track if applicable, and remembers the position of a podcast
+// episode. Called when the user leaves a track early.
 func (m *Model) scrobbleCurrent() {
-	if track, _ := m.playlist.Current(); track.NavidromeID != "" {
+	m.saveEpisodePosition()
+	m.saveBookPosition()
+	if track, _ := m.playlist.Current(); track.Path != "" {
 		m.maybeScrobble(track, m.player.Position(), m.player.Duration())
+		m.recordListen(track)
+		if m.player.IsPlaying() {
+			m.hooks.Run(hooks.TrackEnd, track, m.player.Position())
+		}
 	}
 }
 
@@ -78,13 +101,76 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		return m.handleQueueKey(msg)
 	}
 
+	// Effects menu overlay
+	if m.effects.visible {
+		return m.handleEffectsKey(msg)
+	}
+
+	// Expanded EQ view
+	if m.eqBands.visible {
+		return m.handleEQBandsKey(msg)
+	}
+
+	// Output picker overlay
+	if m.castPicker.visible {
+		return m.handleCastPickerKey(msg)
+	}
+
+	// Chapter list overlay
+	if m.chapters.visible {
+		return m.handleChaptersKey(msg)
+	}
+
+	// Bookmark name prompt and list overlay
+	if m.bookmarkUI.naming {
+		return m.handleBookmarkNameKey(msg)
+	}
+	if m.bookmarkUI.visible {
+		return m.handleBookmarksKey(msg)
+	}
+
+	// Library finder overlay
+	if m.finder.visible {
+		return m.handleFinderKey(msg)
+	}
+
+	// Listening stats overlay
+	if m.stats.visible {
+		return m.handleStatsKey(msg)
+	}
+
+	// Alarm time prompt
+	if m.alarm.editing {
+		return m.handleAlarmKey(msg)
+	}
+
+	// Stop time prompt
+	if m.stopAt.editing {
+		return m.handleStopAtKey(msg)
+	}
+
+	// Track gain prompt
+	if m.trackGainUI.editing {
+		return m.handleTrackGainKey(msg)
+	}
+
 	// Track info overlay
 	if m.showInfo {
+		if len(m.info.matches) > 0 {
+			return m.handleMatchesKey(msg)
+		}
+		if m.info.moveTo != "" {
+			return m.handleOrganizeKey(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c":
 			return m.quit()
 		case "esc", "i":
 			m.showInfo = false
+		case "a":
+			return m.identify()
+		case "o":
+			m.planOrganize()
 		}
 		return nil
 	}
@@ -135,6 +221,11 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 	}
 
 	if m.focus == focusProvider {
+		if m.podcastsActive() {
+			if cmd, ok := m.handlePodcastProviderKey(msg); ok {
+				return cmd
+			}
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m.quit()
@@ -179,7 +270,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 			}
 		case "R":
 			return m.openRadioCatalog()
-		case "J":
+		case "J", "g":
 			m.openJumpMode()
 		}
 		return nil
@@ -226,6 +317,9 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		return cmd
 
 	case "s":
+		m.runHook(hooks.Stop)
+		m.saveEpisodePosition()
+		m.saveBookPosition()
 		m.player.Stop()
 		m.notifyMPRIS()
 
@@ -241,30 +335,69 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		m.notifyMPRIS()
 		return cmd
 
+	case "{":
+		m.prevChapter()
+	case "}":
+		m.nextChapter()
+	case "c":
+		m.openChapters()
+	case "M":
+		m.startBookmark()
+	case "'":
+		m.openBookmarks()
+	case "(":
+		m.prevBookmark()
+	case ")":
+		m.nextBookmark()
+	case "w":
+		m.openAlarm()
+	case "W":
+		m.openStopAt()
+	case "T":
+		m.openTrackGain()
+	case "D":
+		return m.toggleAutoDJ()
+	case "H":
+		return m.openStats()
+	case "L":
+		return m.openFinder()
+	case "O":
+		m.toggleFollow()
+	case "P":
+		m.jumpToPlaying()
 	case "left":
 		if m.focus == focusEQ {
 			if m.eqCursor > 0 {
 				m.eqCursor--
 			}
 		} else {
-			m.doSeek(-5 * time.Second)
+			m.arrowSeek(-1)
 		}
 
 	case "shift+left":
 		m.doSeek(-m.seekStepLarge)
 
+	case "ctrl+left":
+		return m.scrubSeek(-1)
+
+	case "B":
+		return m.replay()
+
 	case "right":
 		if m.focus == focusEQ {
 			if m.eqCursor < numBands-1 {
 				m.eqCursor++
 			}
 		} else {
-			m.doSeek(5 * time.Second)
+			m.arrowSeek(1)
 		}
 
 	case "shift+right":
 		m.doSeek(m.seekStepLarge)
 
+	case "ctrl+right":
+		return m.scrubSeek(1)
+
 	case "shift+up":
 		if m.focus == focusPlaylist && m.plCursor > 0 {
 			if m.playlist.Move(m.plCursor, m.plCursor-1) {
@@ -280,10 +413,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 			m.eqPresetIdx = -1 // manual tweak → custom
 			m.saveEQ()
 		} else {
-			if m.plCursor > 0 {
-				m.plCursor--
-				m.adjustScroll()
-			}
+			m.movePlCursor(-1)
 		}
 
 	case "down", "j":
@@ -293,10 +423,27 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 			m.eqPresetIdx = -1 // manual tweak → custom
 			m.saveEQ()
 		} else {
-			if m.plCursor < m.playlist.Len()-1 {
-				m.plCursor++
-				m.adjustScroll()
-			}
+			m.movePlCursor(1)
+		}
+
+	case "pgup":
+		if m.focus != focusEQ {
+			m.movePlCursor(-m.plVisible)
+		}
+
+	case "pgdown":
+		if m.focus != focusEQ {
+			m.movePlCursor(m.plVisible)
+		}
+
+	case "home":
+		if m.focus != focusEQ {
+			m.movePlCursor(-m.playlist.Len())
+		}
+
+	case "end":
+		if m.focus != focusEQ {
+			m.movePlCursor(m.playlist.Len())
 		}
 
 	case "enter":
@@ -311,15 +458,32 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 			m.notifyMPRIS()
 			return cmd
 		}
+		if m.focus == focusEQ {
+			m.openEQBands()
+		}
 
 	case "+", "=":
+		m.player.SetMuted(false)
 		m.player.SetVolume(m.player.Volume() + 1)
 		m.notifyMPRIS()
 
 	case "-":
+		m.player.SetMuted(false)
 		m.player.SetVolume(m.player.Volume() - 1)
 		m.notifyMPRIS()
 
+	case "0":
+		m.player.ToggleMute()
+		m.notifyMPRIS()
+
+	case "[":
+		m.player.SetBalance(math.Round((m.player.Balance()-0.1)*10) / 10)
+		m.saveBalance()
+
+	case "]":
+		m.player.SetBalance(math.Round((m.player.Balance()+0.1)*10) / 10)
+		m.saveBalance()
+
 	case "r":
 		m.playlist.CycleRepeat()
 		if err := config.Save("repeat", fmt.Sprintf("%q", m.playlist.Repeat().String())); err != nil {
@@ -329,9 +493,32 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		m.player.ClearPreload()
 		return m.preloadNext()
 
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		// With repeat one, a digit limits the repeats, for practice loops.
+		if m.playlist.Repeat() != playlist.RepeatOne {
+			return nil
+		}
+		n := int(msg.String()[0] - '0')
+		m.playlist.SetRepeatCount(n)
+		m.status.text = fmt.Sprintf("Repeating this track %d more times", n)
+		if n == 1 {
+			m.status.text = "Repeating this track once more"
+		}
+		m.status.ttl = statusTTLDefault
+		return nil
+
 	case "z":
-		m.playlist.ToggleShuffle()
-		if err := config.Save("shuffle", fmt.Sprintf("%v", m.playlist.Shuffled())); err != nil {
+		if track, idx := m.playlist.Current(); idx >= 0 && m.isBook(track) {
+			m.status.text = "Shuffle stays off for audiobooks"
+			m.status.ttl = statusTTLDefault
+			return nil
+		}
+		m.playlist.CycleShuffle()
+		shuffle := fmt.Sprintf("%v", m.playlist.Shuffled())
+		if m.playlist.ShuffledByAlbum() {
+			shuffle = `"album"`
+		}
+		if err := config.Save("shuffle", shuffle); err != nil {
 			m.status.text = fmt.Sprintf("Config save failed: %s", err)
 			m.status.ttl = statusTTLDefault
 		}
@@ -381,6 +568,8 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		if m.focus == focusPlaylist {
 			m.queue.visible = true
 			m.queue.cursor = 0
+			m.queue.history = false
+			m.queue.histCursor = 0
 		}
 
 	case "S":
@@ -389,6 +578,27 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 	case "m":
 		m.player.ToggleMono()
 
+	case "E":
+		m.openEffects()
+
+	case "ctrl+e":
+		m.cycleTrackEQ()
+
+	case "n":
+		if err := m.toggleNightMode(); err != nil {
+			m.status.text = fmt.Sprintf("Config save failed: %s", err)
+			m.status.ttl = statusTTLDefault
+			break
+		}
+		m.status.text = "Night mode off"
+		if m.player.NightMode() {
+			m.status.text = "Night mode on (" + m.player.NightPreset().String() + ")"
+		}
+		m.status.ttl = statusTTLMedium
+
+	case "C":
+		return m.openCastPicker()
+
 	case "/":
 		m.search.active = true
 		m.search.query = ""
@@ -412,7 +622,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 			}
 		}
 
-	case "J":
+	case "J", "g":
 		m.openJumpMode()
 	case "p":
 		if m.localProvider != nil {
@@ -423,7 +633,13 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		m.openThemePicker()
 
 	case "i":
-		m.showInfo = true
+		return m.openInfo()
+
+	case "G":
+		m.toggleGroupAlbums()
+
+	case "Z":
+		m.toggleAlbumFold()
 
 	case "y":
 		m.lyrics.visible = !m.lyrics.visible
@@ -463,6 +679,9 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 			m.status.ttl = statusTTLDefault
 		}
 
+	case "ctrl+t":
+		m.cycleTimeDisplay()
+
 	case "V":
 		m.fullVis = !m.fullVis
 		if m.fullVis {
@@ -497,6 +716,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 }
 
 // saveTrack copies the current track to ~/Music/cliamp/ with a clean filename.
+// Streamed podcast episodes are downloaded into the podcast library instead.
 // For yt-dlp tracks (piped streams), triggers an async download via yt-dlp.
 // For local temp files, copies synchronously.
 func (m *Model) saveTrack() tea.Cmd {
@@ -507,6 +727,11 @@ func (m *Model) saveTrack() tea.Cmd {
 		return nil
 	}
 
+	// Podcast episodes go to the podcast library, where the provider finds them.
+	if track.Stream && m.isEpisode(track) {
+		return m.downloadEpisode(track)
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		m.status.text = fmt.Sprintf("Save failed: %s", err)
@@ -588,8 +813,10 @@ func (m *Model) handleJumpKey(msg tea.KeyMsg) tea.Cmd {
 		m.closeJumpMode()
 		return nil
 	case tea.KeyEnter:
-		target, err := parseJumpTarget(m.jumpInput)
+		target, err := parseSeekTarget(m.jumpInput, m.player.Duration())
 		if err != nil {
+			m.status.text = err.Error()
+			m.status.ttl = statusTTLShort
 			m.resetJumpInput()
 			return nil
 		}
@@ -781,9 +1008,19 @@ func (m *Model) handleURLInputKey(msg tea.KeyMsg) tea.Cmd {
 	switch msg.Type {
 	case tea.KeyEscape:
 		m.urlInputting = false
+		m.urlSubscribe = false
 	case tea.KeyEnter:
 		m.urlInputting = false
 		input := strings.TrimSpace(m.urlInput)
+		if m.urlSubscribe {
+			m.urlSubscribe = false
+			if input == "" {
+				return nil
+			}
+			m.status.text = "Subscribing..."
+			m.status.ttl = statusTTLLong
+			return subscribePodcastCmd(m.podcasts, input)
+		}
 		if input != "" {
 			m.feedLoading = true
 			m.status.text = "Loading URL..."
@@ -1018,6 +1255,9 @@ func (m *Model) handleThemeKey(msg tea.KeyMsg) tea.Cmd {
 
 // handleQueueKey processes key presses while the queue manager overlay is open.
 func (m *Model) handleQueueKey(msg tea.KeyMsg) tea.Cmd {
+	if m.queue.history {
+		return m.handleRecentKey(msg)
+	}
 	qLen := m.playlist.QueueLen()
 
 	switch msg.String() {
@@ -1056,6 +1296,8 @@ func (m *Model) handleQueueKey(msg tea.KeyMsg) tea.Cmd {
 	case "c":
 		m.playlist.ClearQueue()
 		m.queue.visible = false
+	case "tab":
+		m.queue.history = true
 	case "esc", "A":
 		m.queue.visible = false
 	}
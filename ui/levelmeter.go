@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// levelHold is how long the meter holds a peak before it falls back to
+// the current level.
+const levelHold = time.Second
+
+// SetLevelMeter sets whether the output's peak level is shown in dBFS.
+func (m *Model) SetLevelMeter(v bool) {
+	m.level.on = v
+	m.level.held = math.Inf(-1)
+}
+
+// tickLevel reads the peak the output reached since the last tick and
+// holds the highest for a second, so short peaks stay readable.
+func (m *Model) tickLevel(now time.Time) {
+	if !m.level.on {
+		return
+	}
+	db := m.player.PeakLevel()
+	if db >= m.level.held || now.Sub(m.level.at) >= levelHold {
+		m.level.held = db
+		m.level.at = now
+	}
+}
+
+// renderLevel draws the held peak, fixed width so it does not jitter.
+func (m Model) renderLevel() string {
+	if m.level.held < -99 || !m.player.IsPlaying() || m.player.IsPaused() {
+		return "  -∞ dB"
+	}
+	return fmt.Sprintf("%5.1f dB", min(m.level.held, 99))
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"strings"
+	"time"
+)
+
+// titleScrollMode is how a title too long for the panel is shown.
+type titleScrollMode int
+
+const (
+	titleLoop   titleScrollMode = iota // scroll round and round
+	titleBounce                        // scroll to the end and back
+	titleStatic                        // stay put, cut off with "…"
+)
+
+// defaultTitleStep is how long a scrolling title rests on each column.
+const defaultTitleStep = 200 * time.Millisecond
+
+// SetTitleScroll configures long titles: mode is "loop", "bounce" or
+// "off", step the time per column, and pause how long the title rests at
+// its start (and at its end when bouncing).
+func (m *Model) SetTitleScroll(mode string, step, pause time.Duration) {
+	switch mode {
+	case "bounce":
+		m.titleScroll = titleBounce
+	case "off", "static", "none":
+		m.titleScroll = titleStatic
+	default:
+		m.titleScroll = titleLoop
+	}
+	m.titleStep = step
+	m.titlePause = pause
+}
+
+// advanceTitle moves a scrolling title on by the steps due since it last
+// moved. A step shorter than the tick takes several at once; after a gap
+// (paused playback) it restarts from now rather than catching up.
+func (m *Model) advanceTitle() {
+	if m.titleScroll == titleStatic {
+		return
+	}
+	step := m.titleStep
+	if step <= 0 {
+		step = defaultTitleStep
+	}
+	elapsed := time.Since(m.titleLastScroll)
+	if elapsed < step {
+		return
+	}
+	n := int(elapsed / step)
+	if elapsed > step+time.Second {
+		n = 1
+		m.titleLastScroll = time.Now()
+	} else {
+		m.titleLastScroll = m.titleLastScroll.Add(time.Duration(n) * step)
+	}
+	m.titleOff += n
+}
+
+// marquee renders the part of a title wider than maxW that is due at the
+// current scroll offset, padded to exactly maxW columns.
+func (m Model) marquee(name string, maxW int) string {
+	if m.titleScroll == titleStatic {
+		return truncate(name, maxW)
+	}
+	step := m.titleStep
+	if step <= 0 {
+		step = defaultTitleStep
+	}
+	rest := int((m.titlePause + step - 1) / step) // steps spent resting
+
+	if m.titleScroll == titleBounce {
+		chars := graphemes(name)
+		// span is how many characters scroll off the left before the
+		// end of the title comes into view.
+		span, w := 0, textWidth(name)
+		for span < len(chars) && w > maxW {
+			w -= textWidth(chars[span])
+			span++
+		}
+		p := m.titleOff % max(1, 2*(span+rest))
+		pos := 0
+		switch {
+		case p < rest:
+		case p < rest+span:
+			pos = p - rest
+		case p < 2*rest+span:
+			pos = span
+		default:
+			pos = 2*(span+rest) - p
+		}
+		return window(chars, pos, maxW, false)
+	}
+
+	chars := graphemes(name + titleScrollSep)
+	pos := max(0, m.titleOff%(len(chars)+rest)-rest)
+	return window(chars, pos, maxW, true)
+}
+
+// window joins the characters from pos on that fit in maxW columns,
+// wrapping round to the start when wrap is set, and pads the rest. A wide
+// character that does not fit at the right edge is left out whole.
+func window(chars []string, pos, maxW int, wrap bool) string {
+	var b strings.Builder
+	w := 0
+	for i := 0; i < len(chars)+maxW; i++ {
+		j := pos + i
+		if j >= len(chars) {
+			if !wrap {
+				break
+			}
+			j %= len(chars)
+		}
+		cw := textWidth(chars[j])
+		if w+cw > maxW {
+			break
+		}
+		b.WriteString(chars[j])
+		w += cw
+	}
+	b.WriteString(strings.Repeat(" ", maxW-w))
+	return b.String()
+}
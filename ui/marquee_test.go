@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarqueeModes(t *testing.T) {
+	m := Model{}
+	m.SetTitleScroll("bounce", 100*time.Millisecond, 200*time.Millisecond)
+	var got []string
+	for off := range 10 {
+		m.titleOff = off
+		got = append(got, m.marquee("abcdef", 4))
+	}
+	// Each end is shown for its own step plus two of rest.
+	want := []string{"abcd", "abcd", "abcd", "bcde", "cdef", "cdef", "cdef", "bcde", "abcd", "abcd"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("bounce = %v\nwant     %v", got, want)
+	}
+
+	m.SetTitleScroll("loop", 100*time.Millisecond, 100*time.Millisecond)
+	for off, want := range []string{"abcd", "abcd", "bcde", "cdef"} {
+		m.titleOff = off
+		if got := m.marquee("abcdef", 4); got != want {
+			t.Errorf("loop offset %d = %q, want %q", off, got, want)
+		}
+	}
+
+	m.SetTitleScroll("off", 0, 0)
+	m.titleOff = 3
+	if got := m.marquee("abcdef", 4); got != "abc…" {
+		t.Errorf("off = %q, want abc…", got)
+	}
+}
@@ -4,7 +4,7 @@ package ui
 import (
 	"errors"
 	"fmt"
-	"strconv"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -12,13 +12,25 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"cliamp/config"
+	"cliamp/external/acoustid"
 	"cliamp/external/local"
 	"cliamp/external/navidrome"
+	"cliamp/external/podcast"
 	"cliamp/external/radio"
+	"cliamp/hooks"
+	"cliamp/internal/audiobook"
+	"cliamp/internal/bookmark"
+	"cliamp/internal/history"
+	"cliamp/internal/scrobblequeue"
+	"cliamp/internal/trackeq"
+	"cliamp/internal/trackgain"
+	"cliamp/library"
 	"cliamp/mpris"
 	"cliamp/player"
 	"cliamp/playlist"
+	"cliamp/remote"
 	"cliamp/theme"
+	"cliamp/watch"
 )
 
 type focusArea int
@@ -74,16 +86,22 @@ type ProviderEntry struct {
 }
 
 type tickMsg time.Time
+
+// idleTickMsg is a tickMsg sent while idle. Its seq goes stale when the
+// player wakes up before it fires.
+type idleTickMsg struct{ seq int }
 type autoPlayMsg struct{}
 
-// Tick intervals: fast for visualizer animation, slow for time/seek display.
+// Tick intervals: fast for visualizer animation, slow for time/seek display,
+// idle when nothing is playing.
 const (
 	tickFast = 50 * time.Millisecond  // 20 FPS — visualizer active
 	tickSlow = 200 * time.Millisecond // 5 FPS — visualizer off or overlay
+	tickIdle = time.Second            // 1 FPS — stopped or paused
 )
 
 // statusTTL* constants define how many ticks a status message persists.
-// At tickFast (50ms), 20 ticks ≈ 1 second.
+// They count tickFast (50ms) ticks whatever the tick rate, so 20 ≈ 1 second.
 const (
 	statusTTLShort    = 40  // ~2s — brief confirmations
 	statusTTLDefault  = 60  // ~3s — standard status messages
@@ -116,21 +134,29 @@ type Model struct {
 	player        *player.Player
 	playlist      *playlist.Playlist
 	vis           *Visualizer
+	seekStep      time.Duration
 	seekStepLarge time.Duration
+	replayStep    time.Duration
 
 	// UI navigation
-	focus     focusArea
-	prevFocus focusArea // focus to restore on cancel (search, net search)
-	eqCursor  int       // selected EQ band (0-9)
-	plCursor  int       // selected playlist item
-	plScroll  int       // scroll offset for playlist view
-	plVisible int       // max visible playlist items
+	focus           focusArea
+	prevFocus       focusArea // focus to restore on cancel (search, net search)
+	eqCursor        int       // selected EQ band (0-9)
+	plCursor        int       // selected playlist item
+	plScroll        int       // first row shown in the playlist view
+	plVisible       int       // max visible playlist items
 	titleOff        int       // scroll offset for long track titles
 	titleLastScroll time.Time // last time the title scrolled
-	err       error
-	quitting  bool
-	width     int
-	height    int
+	lastTick        time.Time // when the previous tick ran
+	idleTick        bool      // the pending tick is an idle one
+	idleSeq         int       // number of the idle tick that is still wanted
+	titleScroll     titleScrollMode
+	titleStep       time.Duration // time per column of a scrolling title
+	titlePause      time.Duration // rest at the start (and end, bouncing)
+	err             error
+	quitting        bool
+	width           int
+	height          int
 
 	// Provider state
 	provider      playlist.Provider
@@ -144,22 +170,41 @@ type Model struct {
 	eqPresetIdx   int             // -1 = custom, 0+ = index into eqPresets
 
 	// Overlay / feature state (see state.go for struct definitions)
-	search      searchState
-	netSearch   netSearchState
-	provSearch  provSearchState
-	seek        seekState
-	themePicker themePickerState
-	lyrics      lyricsState
-	keymap      keymapOverlay
-	queue       queueOverlay
-	plManager   plManagerState
-	fileBrowser fileBrowserState
-	navBrowser    navBrowserState
-	radioCatalog  radioCatalogState
-	ytdlBatch     ytdlBatchState
-	reconnect   reconnectState
-	status      statusMsg
-	network     networkStats
+	search       searchState
+	netSearch    netSearchState
+	provSearch   provSearchState
+	seek         seekState
+	seekHold     seekHoldState
+	scrub        scrubState
+	themePicker  themePickerState
+	lyrics       lyricsState
+	keymap       keymapOverlay
+	queue        queueOverlay
+	effects      effectsOverlay
+	eqBands      eqBandsOverlay
+	castPicker   castPickerState
+	chapters     chapterState
+	bookmarkUI   bookmarkState
+	alarm        alarmState
+	stopAt       stopAtState
+	trackGainUI  trackGainState
+	trackEQ      trackEQState
+	autoDJ       autoDJState
+	finder       finderState
+	follow       followState
+	albums       albumState
+	level        levelState
+	tech         techState
+	stats        statsState
+	plManager    plManagerState
+	fileBrowser  fileBrowserState
+	navBrowser   navBrowserState
+	radioCatalog radioCatalogState
+	ytdlBatch    ytdlBatchState
+	reconnect    reconnectState
+	status       statusMsg
+	network      networkStats
+	remote       remoteState
 
 	// Jump to time mode
 	jumping   bool
@@ -168,6 +213,7 @@ type Model struct {
 	// URL input mode (load playlist/stream URL at runtime)
 	urlInputting bool
 	urlInput     string
+	urlSubscribe bool // Enter subscribes to the URL as a podcast instead of loading it
 
 	// Async feed/M3U URL resolution
 	pendingURLs []string
@@ -197,6 +243,16 @@ type Model struct {
 	// Live stream title from ICY metadata (e.g., "Artist - Song")
 	streamTitle string
 
+	// termTitle keeps the terminal title on the current track.
+	termTitle termTitleState
+
+	// a11y is the screen reader mode and what it last announced.
+	a11y accessibleState
+
+	// outputLost is set when the audio device stopped pulling samples;
+	// playback is paused until the user retries with Space.
+	outputLost bool
+
 	// MPRIS D-Bus service (nil on non-Linux or if D-Bus unavailable)
 	mpris *mpris.Service
 
@@ -206,20 +262,74 @@ type Model struct {
 
 	// Track info overlay (metadata details)
 	showInfo bool
+	info     infoState
+	acoustid *acoustid.Client // identifies files from the info view; nil = off
+	organize string           // organize pattern for the info view; "" = the default
 
 	// Full-screen visualizer mode (Shift+V)
 	fullVis bool
 
-	autoPlay bool // start playing immediately on launch
-	compact  bool // compact mode: cap frame width at 80 columns
+	autoPlay   bool        // start playing immediately on launch
+	exitAtEnd  bool        // quit once the playlist has played out
+	compact    bool        // compact mode: cap frame width at 80 columns
+	volPercent bool        // show the volume as 0–100% instead of dB
+	timeMode   timeDisplay // time readout: elapsed, remaining or both
+	ascii      bool        // draw with plain ASCII only, for terminals without the glyphs
+	cache      *renderCache
 
 	// Cached per-tick to avoid repeated speaker.Lock() calls in View().
 	cachedPos time.Duration
 	cachedDur time.Duration
 
 	// Navidrome client (kept separate from navBrowser for non-browser operations)
-	navClient          *navidrome.NavidromeClient
-	navScrobbleEnabled bool
+	navClient *navidrome.NavidromeClient
+
+	// scrobblers receive now-playing and listen submissions (Navidrome,
+	// ListenBrainz).
+	scrobblers []Scrobbler
+
+	// hooks runs user scripts on playback events (nil when none are configured)
+	hooks *hooks.Runner
+
+	// podcasts is the podcast library (nil when not attached); podcastUnsub
+	// holds the feed URL awaiting unsubscribe confirmation.
+	podcasts     *podcast.Provider
+	podcastUnsub string
+
+	// audiobooks marks the tracks played in audiobook mode (nil when not
+	// attached). bookSavedAt is the position last written for the playing
+	// book; bookUnshuffled is set while shuffle is off because of a book.
+	audiobooks     *audiobook.Library
+	bookSavedAt    int
+	bookUnshuffled bool
+
+	// bookmarks holds the named positions saved in tracks (nil when not
+	// attached).
+	bookmarks *bookmark.Store
+
+	// gains holds the gain offsets saved for tracks (nil when not attached).
+	gains *trackgain.Store
+
+	// eqs holds the EQ curves remembered for tracks and albums (nil when
+	// not attached).
+	eqs *trackeq.Store
+
+	// history logs every listen (nil when not attached). listened is how
+	// long the current track has been heard, counted from listenTick.
+	history    *history.Log
+	listened   time.Duration
+	listenTick time.Time
+
+	// recent lists the tracks heard this session, latest first, for the
+	// queue manager's history panel.
+	recent []playlist.Track
+
+	// smart weights track shuffle by the history; nil shuffles uniformly.
+	smart *smartShuffle
+
+	// library is the music library searched by the finder (nil when not
+	// attached).
+	library *library.Library
 }
 
 // NewModel creates a Model wired to the given player and playlist.
@@ -235,19 +345,24 @@ func NewModel(p *player.Player, pl *playlist.Playlist, providers []ProviderEntry
 		sortType = navidrome.SortAlphabeticalByName
 	}
 	m := Model{
-		player:             p,
-		playlist:           pl,
-		vis:                NewVisualizer(float64(p.SampleRate())),
-		seekStepLarge:      30 * time.Second,
-		plVisible:          5,
-		eqPresetIdx:        -1, // custom until a preset is selected
-		themes:             themes,
-		themeIdx:           -1, // Default (ANSI)
-		localProvider:      localProv,
-		providers:          providers,
-		navBrowser:         navBrowserState{sortType: sortType},
-		navClient:          nav,
-		navScrobbleEnabled: navCfg.ScrobbleEnabled(),
+		player:        p,
+		playlist:      pl,
+		vis:           NewVisualizer(float64(p.SampleRate())),
+		seekStep:      5 * time.Second,
+		seekStepLarge: 30 * time.Second,
+		replayStep:    10 * time.Second,
+		plVisible:     5,
+		eqPresetIdx:   -1, // custom until a preset is selected
+		themes:        themes,
+		themeIdx:      -1, // Default (ANSI)
+		localProvider: localProv,
+		providers:     providers,
+		navBrowser:    navBrowserState{sortType: sortType},
+		navClient:     nav,
+		cache:         &renderCache{},
+	}
+	if nav != nil && navCfg.ScrobbleEnabled() {
+		m.scrobblers = append(m.scrobblers, scrobblequeue.Wrap("navidrome", navScrobbler{nav}))
 	}
 	// Select the default provider pill.
 	for i, pe := range providers {
@@ -268,9 +383,26 @@ func NewModel(p *player.Player, pl *playlist.Playlist, providers []ProviderEntry
 // SetAutoPlay makes the player start playback immediately on Init.
 func (m *Model) SetAutoPlay(v bool) { m.autoPlay = v }
 
+// SetExitAtEnd makes cliamp quit when playback reaches the end of the
+// playlist, after the playlist_end hook has run.
+func (m *Model) SetExitAtEnd(v bool) { m.exitAtEnd = v }
+
 // SetCompact enables compact mode which caps the frame width at 80 columns.
 func (m *Model) SetCompact(v bool) { m.compact = v }
 
+// SetVolumeDisplay picks how the volume reads: "percent" shows 0–100%,
+// anything else dB.
+func (m *Model) SetVolumeDisplay(mode string) { m.volPercent = mode == "percent" }
+
+// SetSeekStep configures the base Left/Right seek step. Non-positive values
+// reset it to the 5s default.
+func (m *Model) SetSeekStep(d time.Duration) {
+	if d <= 0 {
+		d = 5 * time.Second
+	}
+	m.seekStep = d
+}
+
 // SetSeekStepLarge configures the Shift+Left/Right seek jump amount.
 func (m *Model) SetSeekStepLarge(d time.Duration) {
 	switch {
@@ -283,6 +415,15 @@ func (m *Model) SetSeekStepLarge(d time.Duration) {
 	}
 }
 
+// SetReplayStep configures how far the replay key jumps back. Non-positive
+// values reset it to the 10s default.
+func (m *Model) SetReplayStep(d time.Duration) {
+	if d <= 0 {
+		d = 10 * time.Second
+	}
+	m.replayStep = d
+}
+
 // SetTheme finds a theme by name and applies it. Returns true if found.
 func (m *Model) SetTheme(name string) bool {
 	if name == "" || strings.EqualFold(name, "default") {
@@ -340,7 +481,8 @@ func (m *Model) isOverlayActive() bool {
 	return m.keymap.visible || m.themePicker.visible ||
 		m.fileBrowser.visible || m.navBrowser.visible || m.radioCatalog.visible ||
 		m.plManager.visible ||
-		m.queue.visible || m.showInfo || m.search.active || m.netSearch.active ||
+		m.queue.visible || m.effects.visible || m.eqBands.visible || m.castPicker.visible || m.showInfo || m.search.active || m.netSearch.active ||
+		m.chapters.visible || m.stats.visible || m.finder.visible || m.bookmarkUI.visible || m.bookmarkUI.naming || m.alarm.editing || m.stopAt.editing || m.trackGainUI.editing ||
 		m.jumping || m.urlInputting
 }
 
@@ -489,20 +631,18 @@ func (m *Model) applyEQPreset() {
 	}
 }
 
-// saveEQ persists the current EQ state (preset name and band values) to config.
+// saveEQ persists the current EQ state (preset name and band values) to config,
+// or to the remembered curve of the playing track while one is applied.
 func (m *Model) saveEQ() {
+	if m.rememberTrackEQ() {
+		return
+	}
 	name := m.EQPresetName()
 	if err := config.Save("eq_preset", fmt.Sprintf("%q", name)); err != nil {
 		m.status.text = fmt.Sprintf("Config save failed: %s", err)
 		m.status.ttl = statusTTLDefault
 	}
-	bands := m.player.EQBands()
-	parts := make([]string, len(bands))
-	for i, g := range bands {
-		parts[i] = strconv.FormatFloat(g, 'f', -1, 64)
-	}
-	eqVal := "[" + strings.Join(parts, ", ") + "]"
-	if err := config.Save("eq", eqVal); err != nil {
+	if err := config.Save("eq", eqArray(m.player.EQBands())); err != nil {
 		m.status.text = fmt.Sprintf("Config save failed: %s", err)
 		m.status.ttl = statusTTLDefault
 	}
@@ -600,18 +740,25 @@ func (m *Model) openRadioCatalog() tea.Cmd {
 	return fetchRadioTopCmd()
 }
 
-// Init starts the tick timer and requests the terminal size.
+// Init starts the tick timer, subscribes to player events and requests
+// the terminal size.
 func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{tickCmd(), tea.WindowSize()}
+	if m.player != nil {
+		cmds = append(cmds, waitPlayerEvent(m.player.Events()))
+	}
 	if m.provider != nil {
 		cmds = append(cmds, fetchPlaylistsCmd(m.provider))
 	}
 	if len(m.pendingURLs) > 0 {
 		cmds = append(cmds, resolveRemoteCmd(m.pendingURLs))
 	}
-	if m.autoPlay && m.playlist.Len() > 0 {
+	if m.autoPlay && m.alarm.at.IsZero() && m.playlist.Len() > 0 {
 		cmds = append(cmds, func() tea.Msg { return autoPlayMsg{} })
 	}
+	if m.smart != nil && m.history != nil {
+		cmds = append(cmds, loadListeningCmd(m.history))
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -625,9 +772,79 @@ func tickCmdAt(d time.Duration) tea.Cmd {
 	})
 }
 
+// tickInterval picks the next tick's interval. Use fast ticks only when
+// audio is actively playing with a live visualizer; remote spectrum
+// subscribers also need the fast rate. Playing without one, slow ticks are
+// enough for the clock and seek bar. With nothing playing the idle rate
+// saves CPU/GPU repaints, unless a buffering, seeking or reconnecting
+// stream still needs watching.
+func (m Model) tickInterval() time.Duration {
+	if m.player.IsPlaying() && !m.player.IsPaused() {
+		if m.vis.Mode != VisNone && !m.isOverlayActive() || m.remoteWantsSpectrum() {
+			return tickFast
+		}
+		return tickSlow
+	}
+	if m.buffering || m.seek.active || m.seek.grace > 0 || !m.reconnect.at.IsZero() {
+		return tickSlow
+	}
+	return tickIdle
+}
+
+// scheduleTick schedules the next tick. An idle tick is numbered so that
+// wakeTick can cut it short.
+func (m *Model) scheduleTick() tea.Cmd {
+	d := m.tickInterval()
+	if d < tickIdle {
+		m.idleTick = false
+		return tickCmdAt(d)
+	}
+	m.idleTick = true
+	m.idleSeq++
+	seq := m.idleSeq
+	return tea.Tick(d, func(time.Time) tea.Msg { return idleTickMsg{seq} })
+}
+
+// wakeTick starts ticking right away when a message has started playback
+// (or anything else needing faster ticks) during an idle tick, instead of
+// leaving the display frozen for up to a second. The pending idle tick is
+// dropped when it fires.
+func (m *Model) wakeTick() tea.Cmd {
+	if !m.idleTick || m.tickInterval() >= tickIdle {
+		return nil
+	}
+	m.idleTick = false
+	m.idleSeq++
+	return func() tea.Msg { return tickMsg(time.Now()) }
+}
+
 // Update handles messages: key presses, ticks, and window resizes.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	mm, ok := next.(Model)
+	if !ok {
+		return next, cmd
+	}
+	if wake := mm.wakeTick(); wake != nil {
+		cmd = tea.Batch(cmd, wake)
+	}
+	if title := mm.updateTermTitle(); title != nil {
+		cmd = tea.Batch(cmd, title)
+	}
+	if say := mm.announce(); say != nil {
+		cmd = tea.Batch(cmd, say)
+	}
+	return mm, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case idleTickMsg:
+		if msg.seq != m.idleSeq {
+			return m, nil // superseded by wakeTick
+		}
+		return m.update(tickMsg(time.Now()))
+
 	case tea.KeyMsg:
 		cmd := m.handleKey(msg)
 		if m.quitting {
@@ -693,7 +910,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case playerEventMsg:
+		return m, m.handlePlayerEvent(player.Event(msg))
+
 	case tickMsg:
+		// Whatever was pending has fired; the paths below schedule the
+		// next tick.
+		m.idleTick = false
 		// Cache expensive player state once per tick so View() render
 		// functions don't re-acquire speaker.Lock() multiple times.
 		if !m.buffering {
@@ -704,14 +927,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cachedDur = time.Duration(track.DurationSecs) * time.Second
 			m.cachedPos = 0
 		}
+		m.tickListened(time.Now())
+		m.tickScrub(time.Now())
 		// Process debounced yt-dlp seek.
 		var seekCmd tea.Cmd
 		if cmd := m.tickSeek(); cmd != nil {
 			seekCmd = cmd
 		}
-		// Expire temporary status messages.
+		// Expire temporary status messages. TTLs count tickFast ticks, so
+		// slower ticks take off as many as have passed since the last one.
+		now := time.Time(msg)
+		elapsed := 1
+		if !m.lastTick.IsZero() {
+			elapsed = max(1, min(int(now.Sub(m.lastTick)/tickFast), int(tickIdle/tickFast)))
+		}
+		m.lastTick = now
 		if m.status.ttl > 0 {
-			m.status.ttl--
+			m.status.ttl = max(0, m.status.ttl-elapsed)
 			if m.status.ttl == 0 {
 				m.status.text = ""
 			}
@@ -725,7 +957,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// triggers a transient error that can persist for a few ticks.
 		if err := m.player.StreamErr(); err != nil && !m.seek.active && m.seek.grace == 0 {
 			track, idx := m.playlist.Current()
-			isStream := idx >= 0 && (track.Stream || playlist.IsYouTubeURL(track.Path) || playlist.IsYTDL(track.Path))
+			// Piped stdin cannot be reopened, so it never reconnects.
+			isStream := idx >= 0 && !playlist.IsStdin(track.Path) &&
+				(track.Stream || playlist.IsYouTubeURL(track.Path) || playlist.IsYTDL(track.Path))
 			if isStream && m.reconnect.attempts < 5 {
 				// Schedule reconnect with exponential backoff: 1s, 2s, 4s, 8s, 16s
 				if m.reconnect.at.IsZero() {
@@ -733,12 +967,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.reconnect.at = time.Now().Add(delay)
 					m.reconnect.attempts++
 					m.err = fmt.Errorf("Reconnecting in %s...", delay)
+					slog.Warn("stream error, reconnecting", "path", track.Path, "attempt", m.reconnect.attempts, "delay", delay, "err", err)
 				}
 			} else {
+				if m.err != err {
+					slog.Error("playback error", "path", track.Path, "err", err)
+				}
 				m.err = err
 				m.reconnect.at = time.Time{}
 			}
 		}
+		// Pause and tell the user when the audio device disappears
+		// (unplugged DAC, Bluetooth disconnect, sound server restart).
+		if !m.outputLost && !m.buffering {
+			if err := m.player.OutputErr(); err != nil {
+				m.outputLost = true
+				slog.Error("audio output lost", "err", err)
+				m.player.TogglePause()
+				m.err = fmt.Errorf("%w — press Space to retry, or restart cliamp if it stays silent", err)
+				m.notifyMPRIS()
+			}
+		}
+		m.checkCastEnded()
+		m.updateBroadcast()
 		var lyricCmd tea.Cmd
 		// Poll ICY stream title for live radio display.
 		if title := m.player.StreamTitle(); title != "" && title != m.streamTitle {
@@ -777,6 +1028,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.network.lastBytes = downloaded
 			m.network.lastTick = 0
 		}
+		if cmd := m.checkAlarm(time.Now()); cmd != nil {
+			return m, tea.Batch(cmd, tickCmd())
+		}
+		if cmd := m.checkStopAt(time.Now()); cmd != nil {
+			return m, tea.Batch(cmd, tickCmd())
+		}
 		// Fire scheduled reconnect when the timer expires.
 		if !m.reconnect.at.IsZero() && time.Now().After(m.reconnect.at) {
 			m.reconnect.at = time.Time{}
@@ -792,56 +1049,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if lyricCmd != nil {
 			cmds = append(cmds, lyricCmd)
 		}
-		// Check gapless transition (audio already playing next track)
-		if m.player.GaplessAdvanced() {
-			// Capture the track that just finished before advancing the playlist.
-			// For gapless, the track played fully (100% ≥ 50%), so elapsed = duration.
-			finishedTrack, _ := m.playlist.Current()
-			fullDur := time.Duration(finishedTrack.DurationSecs) * time.Second
-			m.maybeScrobble(finishedTrack, fullDur, fullDur)
-
-			m.playlist.Next()
-			m.plCursor = m.playlist.Index()
-			m.adjustScroll()
-			m.titleOff = 0
-			// The preload that just fired is consumed — clear the in-flight flag
-			// so the next track can be preloaded.
-			m.preloading = false
-			// A stream decoder error at the track boundary (e.g., server closing
-			// the connection when the preload HTTP request opens) is expected and
-			// not a user-visible problem. Clear any pending error so the red
-			// message doesn't flash at every track transition.
-			m.err = nil
-			// Fire now-playing notification for the track the audio engine just
-			// started. playTrack() is not called on this path, so we must notify
-			// here explicitly.
-			if newTrack, idx := m.playlist.Current(); idx >= 0 {
-				m.nowPlaying(newTrack)
-			}
-			cmds = append(cmds, m.preloadNext())
-			m.notifyMPRIS()
-		}
-		// Check if gapless drained (end of playlist, no preloaded next).
-		// Skip if already buffering a yt-dlp download to avoid advancing
-		// the playlist on every tick while waiting for the resolve.
-		if m.player.IsPlaying() && !m.player.IsPaused() && m.player.Drained() && !m.buffering && m.reconnect.at.IsZero() {
-			// Track drained to end — always ≥ 50%.
-			finishedTrack, _ := m.playlist.Current()
-			drainDur := time.Duration(finishedTrack.DurationSecs) * time.Second
-			m.maybeScrobble(finishedTrack, drainDur, drainDur)
-
-			// Stop the player before dispatching the async nextTrack command.
-			// This clears the gapless streamer so the finished track cannot
-			// replay while waiting for a yt-dlp pipe chain to spin up.
-			m.player.Stop()
-			cmds = append(cmds, m.nextTrack())
-			m.notifyMPRIS()
-		}
 		if m.player.IsPlaying() && !m.player.IsPaused() {
-			if time.Since(m.titleLastScroll) >= 200*time.Millisecond {
-				m.titleOff++
-				m.titleLastScroll = time.Now()
-			}
+			m.advanceTitle()
 		}
 		// Retry deferred stream preload: preloadNext() returns nil (defers) when
 		// the current stream has >streamPreloadLeadTime remaining. Poll every tick
@@ -854,15 +1063,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		// Use fast ticks only when audio is actively playing with a live
-		// visualizer. Paused/stopped playback has no new audio samples, so
-		// slow ticks are sufficient and save CPU/GPU repaints.
-		interval := tickSlow
-		if m.vis.Mode != VisNone && !m.isOverlayActive() &&
-			m.player.IsPlaying() && !m.player.IsPaused() {
-			interval = tickFast
+		if m.player.IsPlaying() {
+			if cmd := m.autoDJFill(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+
+		m.tickBookPosition()
+		m.tickLevel(time.Now())
+		m.regroupAlbums()
+		m.followPlayback()
+		if cmd := m.probeTechInfo(); cmd != nil {
+			cmds = append(cmds, cmd)
 		}
-		cmds = append(cmds, tickCmdAt(interval))
+		m.publishRemote()
+
+		cmds = append(cmds, m.scheduleTick())
 		return m, tea.Batch(cmds...)
 
 	case []playlist.PlaylistInfo:
@@ -1019,6 +1235,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case chaptersLoadedMsg:
+		m.chaptersLoaded(msg)
+		return m, nil
+
 	case lyricsLoadedMsg:
 		m.lyrics.loading = false
 		m.lyrics.err = msg.err
@@ -1074,6 +1294,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.preloading = false
 		return m, nil
 
+	case castDevicesMsg:
+		m.castDevicesFound(msg)
+		return m, nil
+
+	case castStartedMsg:
+		m.castStarted(msg)
+		return m, nil
+
+	case podcastSubscribedMsg:
+		if msg.err != nil {
+			m.status.text = fmt.Sprintf("Subscribe failed: %s", msg.err)
+		} else {
+			m.status.text = "Subscribed to " + msg.title
+		}
+		m.status.ttl = statusTTLMedium
+		if m.podcastsActive() {
+			m.provLoading = true
+			return m, fetchPlaylistsCmd(m.provider)
+		}
+		return m, nil
+
+	case podcastUnsubscribedMsg:
+		if msg.err != nil {
+			m.status.text = fmt.Sprintf("Unsubscribe failed: %s", msg.err)
+			m.status.ttl = statusTTLMedium
+		}
+		if m.provCursor > 0 {
+			m.provCursor--
+		}
+		m.provLoading = true
+		return m, fetchPlaylistsCmd(m.provider)
+
+	case episodeDownloadedMsg:
+		m.episodeDownloaded(msg)
+		return m, nil
+
 	case ytdlSavedMsg:
 		if msg.err != nil {
 			m.status.text = fmt.Sprintf("Download failed: %s", msg.err)
@@ -1160,19 +1416,133 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case mpris.SetVolumeMsg:
+		m.player.SetMuted(false)
 		m.player.SetVolume(mpris.LinearToDb(msg.Volume))
 		m.notifyMPRIS()
 		return m, nil
 
 	case mpris.StopMsg:
+		m.runHook(hooks.Stop)
+		m.saveEpisodePosition()
+		m.saveBookPosition()
 		m.player.Stop()
 		m.notifyMPRIS()
 		return m, nil
 
+	case remote.AddMsg:
+		return m, addPathsCmd(msg.Paths, false)
+
+	case remote.OpenMsg:
+		m.scrobbleCurrent()
+		return m, addPathsCmd(msg.Paths, true)
+
+	case statsLoadedMsg:
+		m.stats.loading = false
+		m.stats.stats = msg.stats
+		m.stats.err = msg.err
+		return m, nil
+
+	case infoLoadedMsg:
+		m.setInfo(msg)
+		return m, nil
+
+	case identifiedMsg:
+		m.setMatches(msg)
+		return m, nil
+
+	case tagsWrittenMsg:
+		return m, m.tagsWritten(msg)
+
+	case organizedMsg:
+		return m, m.organized(msg)
+
+	case listeningLoadedMsg:
+		if m.smart == nil {
+			return m, nil
+		}
+		m.smart.plays = msg
+		if !m.playlist.Shuffled() || m.playlist.ShuffledByAlbum() {
+			return m, nil
+		}
+		m.playlist.Reshuffle()
+		m.player.ClearPreload()
+		return m, m.preloadNext()
+
+	case techInfoMsg:
+		if msg.path == m.tech.path {
+			m.tech.info = msg.info
+		}
+		return m, nil
+
+	case libraryScannedMsg:
+		m.finder.loading = false
+		m.finder.scanned = true
+		m.updateFinder()
+		return m, nil
+
+	case autoDJMsg:
+		return m, m.autoDJPicked(msg)
+
+	case watch.TracksMsg:
+		// Appended quietly: unlike a manual add, focus and playback stay put.
+		m.playlist.Add(msg.Tracks...)
+		m.status.text = fmt.Sprintf("Added %d new track(s) from watched folders", len(msg.Tracks))
+		m.status.ttl = statusTTLDefault
+		return m, nil
+
+	case remote.PlayIndexMsg:
+		if msg.Index < 0 || msg.Index >= m.playlist.Len() {
+			return m, nil
+		}
+		m.scrobbleCurrent()
+		m.playlist.SetIndex(msg.Index)
+		m.plCursor = msg.Index
+		m.adjustScroll()
+		cmd := m.playCurrentTrack()
+		m.notifyMPRIS()
+		return m, cmd
+
+	case remote.PlayTrackMsg:
+		m.scrobbleCurrent()
+		m.playlist.Add(msg.Track)
+		idx := m.playlist.Len() - 1
+		m.playlist.SetIndex(idx)
+		m.plCursor = idx
+		m.adjustScroll()
+		cmd := m.playCurrentTrack()
+		m.notifyMPRIS()
+		return m, cmd
+
+	case remote.ClearMsg:
+		m.player.Stop()
+		m.player.ClearPreload()
+		m.resetYTDLBatch()
+		m.playlist.Replace(nil)
+		m.plCursor = 0
+		m.plScroll = 0
+		m.notifyMPRIS()
+		return m, nil
+
+	case remote.SetShuffleMsg:
+		if m.playlist.Shuffled() == msg.On {
+			return m, nil
+		}
+		m.playlist.ToggleShuffle()
+		m.player.ClearPreload()
+		return m, m.preloadNext()
+
+	case remote.SetRepeatMsg:
+		if m.playlist.Repeat() == msg.Mode {
+			return m, nil
+		}
+		for m.playlist.Repeat() != msg.Mode {
+			m.playlist.CycleRepeat()
+		}
+		m.player.ClearPreload()
+		return m, m.preloadNext()
+
 	case mpris.QuitMsg:
-		m.player.Close()
-		m.quitting = true
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	return m, nil
@@ -1184,13 +1554,26 @@ func (m *Model) nextTrack() tea.Cmd {
 	track, ok := m.playlist.Next()
 	if !ok {
 		m.player.Stop()
-		return nil
+		if cmd, ok := m.autoDJContinue(); ok {
+			return cmd
+		}
+		return m.playlistEnded()
 	}
 	m.plCursor = m.playlist.Index()
 	m.adjustScroll()
 	return m.playTrack(track)
 }
 
+// playlistEnded runs the playlist_end hook once playback has run out of
+// tracks, and quits when SetExitAtEnd asked for it.
+func (m *Model) playlistEnded() tea.Cmd {
+	m.runHook(hooks.PlaylistEnd)
+	if m.exitAtEnd {
+		return m.quit()
+	}
+	return nil
+}
+
 // prevTrack goes to the previous track, or restarts if >3s into the current one.
 func (m *Model) prevTrack() tea.Cmd {
 	if m.player.Position() > 3*time.Second {
@@ -1228,6 +1611,13 @@ func (m *Model) playCurrentTrack() tea.Cmd {
 // playTrack plays a track, using async HTTP for streams and sync I/O for local files.
 // yt-dlp URLs are streamed via a piped yt-dlp | ffmpeg chain for instant playback.
 func (m *Model) playTrack(track playlist.Track) tea.Cmd {
+	// A reconnect replays the current episode; only a fresh start should
+	// jump back to where it was left.
+	if m.reconnect.attempts == 0 {
+		m.restoreEpisodePosition(track)
+		m.bookStarted(track)
+		m.trackEQStarted(track)
+	}
 	m.reconnect.attempts = 0
 	m.reconnect.at = time.Time{}
 	m.streamTitle = ""
@@ -1237,6 +1627,7 @@ func (m *Model) playTrack(track playlist.Track) tea.Cmd {
 	m.lyrics.scroll = 0
 	m.seek.active = false
 	m.seek.timer = 0
+	chaptersCmd := m.loadChapters(track)
 	var fetchCmd tea.Cmd
 	if m.lyrics.visible && track.Artist != "" && track.Title != "" {
 		m.lyrics.loading = true
@@ -1264,17 +1655,26 @@ func (m *Model) playTrack(track playlist.Track) tea.Cmd {
 		m.err = nil
 		return tea.Batch(playStreamCmd(m.player, track.Path, dur), fetchCmd)
 	}
-	if err := m.player.Play(track.Path, dur); err != nil {
+	if err := m.player.Play(track.PlayPath(), dur); err != nil {
 		m.err = err
 	} else {
 		m.err = nil
 		m.applyResume()
 	}
 
-	if fetchCmd != nil {
-		return tea.Batch(m.preloadNext(), fetchCmd)
+	return tea.Batch(m.preloadNext(), m.preparePrev(), chaptersCmd, fetchCmd)
+}
+
+// preparePrev decodes the previous local track in the background, so that
+// both directions of a skip start without reopening a file. The next track
+// is already held by preloadNext.
+func (m *Model) preparePrev() tea.Cmd {
+	prev, ok := m.playlist.PeekPrev()
+	cur, _ := m.playlist.Current()
+	if !ok || prev.Stream || playlist.IsYTDL(prev.Path) || prev.PlayPath() == cur.PlayPath() {
+		return nil
 	}
-	return m.preloadNext()
+	return prepareCmd(m.player, prev.PlayPath(), time.Duration(prev.DurationSecs)*time.Second)
 }
 
 // applyResume seeks to the saved resume position if the current track matches.
@@ -1349,69 +1749,55 @@ func (m *Model) preloadNext() tea.Cmd {
 		return preloadStreamCmd(m.player, next.Path, nextDur)
 	}
 	nextDur := time.Duration(next.DurationSecs) * time.Second
-	m.player.Preload(next.Path, nextDur)
+	m.player.Preload(next.PlayPath(), nextDur)
 	return nil
 }
 
-// renderedLineCount returns how many rendered lines tracks[from..to) would
-// take, including album separator lines between different albums.
-func renderedLineCount(tracks []playlist.Track, from, to int) int {
-	lines := 0
-	prevAlbum := ""
-	if from > 0 {
-		prevAlbum = tracks[from-1].Album
+// adjustScroll ensures the cursor row is visible in the playlist view,
+// along with the album header above it when the cursor is on an album's
+// first track.
+func (m *Model) adjustScroll() {
+	rows := m.plRows()
+	if len(rows) == 0 {
+		return
 	}
-	for i := from; i < to && i < len(tracks); i++ {
-		if album := tracks[i].Album; album != "" && album != prevAlbum {
-			lines++ // album separator
-		}
-		prevAlbum = tracks[i].Album
-		lines++ // track line
+	rc := m.cursorRow(rows)
+	top := rc
+	if rc > 0 && rows[rc-1].header && !rows[rc].header {
+		top = rc - 1
 	}
-	return lines
+	if top < m.plScroll {
+		m.plScroll = top
+	} else if rc >= m.plScroll+m.plVisible {
+		m.plScroll = rc - m.plVisible + 1
+	}
+	m.plScroll = max(0, min(m.plScroll, len(rows)-1))
 }
 
-// adjustScroll ensures plCursor is visible in the playlist view.
-// It accounts for album separator lines that reduce the number of
-// tracks that fit in the visible window.
-func (m *Model) adjustScroll() {
-	tracks := m.playlist.Tracks()
-	if len(tracks) == 0 {
+// movePlCursor moves the playlist cursor by delta rows, stopping at either
+// end. Album headers are skipped unless the album is collapsed.
+func (m *Model) movePlCursor(delta int) {
+	rows := m.plRows()
+	if len(rows) == 0 {
 		return
 	}
-	// Scrolling up: cursor above the scroll window.
-	if m.plCursor < m.plScroll {
-		m.plScroll = m.plCursor
-		return
+	r := m.cursorRow(rows)
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
 	}
-	// Scrolling down: check if cursor is still within the visible area.
-	// Count rendered lines from plScroll up to and including plCursor.
-	lines := renderedLineCount(tracks, m.plScroll, m.plCursor+1)
-	if lines <= m.plVisible {
-		return // cursor is visible, nothing to do
-	}
-	// Cursor has scrolled past the visible area. Walk backward from
-	// plCursor to find the scroll offset that fits it on screen.
-	m.plScroll = m.plCursor
-	lines = 1 // the cursor track itself
-	for i := m.plCursor - 1; i >= 0; i-- {
-		add := 1 // track line
-		if tracks[i+1].Album != "" && tracks[i+1].Album != tracks[i].Album {
-			add++ // separator above track i+1
-		}
-		if lines+add > m.plVisible {
-			break
+	for ; delta > 0; delta-- {
+		next := r + step
+		for next >= 0 && next < len(rows) && !m.selectable(rows[next]) {
+			next += step
 		}
-		lines += add
-		m.plScroll = i
-	}
-	// Account for separator at the top of the window.
-	if m.plScroll > 0 && tracks[m.plScroll].Album != "" && tracks[m.plScroll].Album != tracks[m.plScroll-1].Album {
-		// There's a separator above plScroll — if it would overflow, bump scroll down.
-		if lines+1 > m.plVisible {
-			m.plScroll++
+		if next < 0 || next >= len(rows) {
+			break
 		}
+		r = next
 	}
+	m.plCursor = rows[r].track
+	m.adjustScroll()
 }
 
 // notifyMPRIS sends the current playback state to the MPRIS service
@@ -1447,7 +1833,11 @@ func (m *Model) notifyMPRIS() {
 			info.Title = m.streamTitle
 		}
 	}
-	m.mpris.Update(status, info, m.player.Volume(),
+	vol := m.player.Volume()
+	if m.player.Muted() {
+		vol = -30 // MPRIS has no mute; report silence
+	}
+	m.mpris.Update(status, info, vol,
 		m.player.Position().Microseconds(), m.player.Seekable())
 }
 
@@ -1458,6 +1848,14 @@ func (m *Model) togglePlayPause() tea.Cmd {
 	if m.buffering {
 		return nil
 	}
+	if m.outputLost {
+		if err := m.player.RecoverOutput(); err != nil {
+			m.err = err
+			return nil
+		}
+		m.outputLost = false
+		m.err = nil
+	}
 	if !m.player.IsPlaying() {
 		return m.playCurrentTrack()
 	}
@@ -1469,6 +1867,11 @@ func (m *Model) togglePlayPause() tea.Cmd {
 		}
 	}
 	m.player.TogglePause()
+	if m.player.IsPaused() {
+		m.runHook(hooks.Pause)
+	} else {
+		m.runHook(hooks.Resume)
+	}
 	return nil
 }
 
@@ -1541,40 +1944,3 @@ func (m *Model) updateSearch() {
 		}
 	}
 }
-
-// maybeScrobble fires a submission scrobble for the given track if all
-// conditions are met:
-//   - navClient is configured
-//   - scrobbling is enabled in config
-//   - the track has a NavidromeID (i.e. it came from Navidrome)
-//   - elapsed is at least 50% of the track's known duration
-//
-// The call is dispatched in a goroutine so it never blocks the UI.
-func (m *Model) maybeScrobble(track playlist.Track, elapsed, duration time.Duration) {
-	if m.navClient == nil || !m.navScrobbleEnabled {
-		return
-	}
-	if track.NavidromeID == "" {
-		return
-	}
-	if duration <= 0 {
-		// Unknown duration: use DurationSecs metadata as fallback.
-		duration = time.Duration(track.DurationSecs) * time.Second
-	}
-	if duration <= 0 {
-		return // still unknown — skip
-	}
-	if elapsed < duration/2 {
-		return // less than 50% played
-	}
-	id := track.NavidromeID
-	go m.navClient.Scrobble(id, true)
-}
-
-// nowPlaying fires a now-playing notification for the given track if configured.
-func (m *Model) nowPlaying(track playlist.Track) {
-	if m.navClient == nil || !m.navScrobbleEnabled || track.NavidromeID == "" {
-		return
-	}
-	go m.navClient.Scrobble(track.NavidromeID, false)
-}
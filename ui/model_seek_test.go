@@ -33,3 +33,61 @@ func TestSetSeekStepLarge(t *testing.T) {
 		}
 	})
 }
+
+func TestSeekHoldAccelerates(t *testing.T) {
+	var h seekHoldState
+	base := 5 * time.Second
+	now := time.Now()
+
+	if got := h.next(1, base, now); got != base {
+		t.Fatalf("first press step = %v, want %v", got, base)
+	}
+	var got time.Duration
+	for range seekRepeatsPerX {
+		now = now.Add(30 * time.Millisecond)
+		got = h.next(1, base, now)
+	}
+	if want := 2 * base; got != want {
+		t.Fatalf("held step = %v, want %v", got, want)
+	}
+	for range 5 * seekRepeatsPerX {
+		now = now.Add(30 * time.Millisecond)
+		got = h.next(1, base, now)
+	}
+	if want := 8 * base; got != want {
+		t.Fatalf("long hold step = %v, want %v (capped at 8x)", got, want)
+	}
+
+	if got := h.next(-1, base, now.Add(30*time.Millisecond)); got != base {
+		t.Fatalf("direction change step = %v, want %v", got, base)
+	}
+	if got := h.next(-1, base, now.Add(time.Second)); got != base {
+		t.Fatalf("step after release = %v, want %v", got, base)
+	}
+}
+
+func TestSeekHoldCapsStep(t *testing.T) {
+	var h seekHoldState
+	now := time.Now()
+	var got time.Duration
+	for range 4 * seekRepeatsPerX {
+		now = now.Add(30 * time.Millisecond)
+		got = h.next(1, 30*time.Second, now)
+	}
+	if got != seekMaxStep {
+		t.Fatalf("step = %v, want cap %v", got, seekMaxStep)
+	}
+}
+
+func TestFormatSeekStep(t *testing.T) {
+	tests := map[time.Duration]string{
+		5 * time.Second:  "5s",
+		2 * time.Minute:  "2m",
+		90 * time.Second: "1m30s",
+	}
+	for in, want := range tests {
+		if got := formatSeekStep(in); got != want {
+			t.Fatalf("formatSeekStep(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
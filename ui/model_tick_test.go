@@ -24,10 +24,10 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-// TestTickIntervalStoppedUsesSlow verifies that when the player is stopped,
-// the tick interval is tickSlow (~200ms) not tickFast (~50ms), regardless of
+// TestTickIntervalStoppedUsesIdle verifies that when the player is stopped,
+// the tick interval is tickIdle (~1s) not tickFast (~50ms), regardless of
 // the visualizer mode. This matters for CPU usage (issue #92).
-func TestTickIntervalStoppedUsesSlow(t *testing.T) {
+func TestTickIntervalStoppedUsesIdle(t *testing.T) {
 	if sharedPlayer == nil {
 		t.Skip("audio hardware unavailable")
 	}
@@ -52,14 +52,43 @@ func TestTickIntervalStoppedUsesSlow(t *testing.T) {
 	}
 
 	start := time.Now()
-	cmd() // blocks until the tick timer fires
+	msg := cmd() // blocks until the tick timer fires
 	elapsed := time.Since(start)
 
-	// tickSlow=200ms, tickFast=50ms. With tolerance for scheduling jitter.
+	// tickIdle=1s, tickFast=50ms. With tolerance for scheduling jitter.
 	const tolerance = 80 * time.Millisecond
-	if elapsed < tickSlow-tolerance {
-		t.Errorf("tick fired after %v, want ~%v (tickSlow); got tickFast instead — CPU fix not working",
-			elapsed, tickSlow)
+	if elapsed < tickIdle-tolerance {
+		t.Errorf("tick fired after %v, want ~%v (tickIdle) — CPU fix not working",
+			elapsed, tickIdle)
+	}
+	if _, ok := msg.(idleTickMsg); !ok {
+		t.Errorf("tick sent %T, want idleTickMsg", msg)
+	}
+	t.Logf("tick interval when stopped: %v (want ~%v tickIdle)", elapsed.Round(time.Millisecond), tickIdle)
+}
+
+// TestWakeTickDropsIdleTick verifies that an idle tick is cut short once
+// something needs faster ticks, and that the superseded tick is dropped.
+func TestWakeTickDropsIdleTick(t *testing.T) {
+	if sharedPlayer == nil {
+		t.Skip("audio hardware unavailable")
+	}
+	m := Model{
+		player:   sharedPlayer,
+		vis:      NewVisualizer(float64(sharedPlayer.SampleRate())),
+		playlist: playlist.New(),
+	}
+	m.scheduleTick()
+	stale := idleTickMsg{m.idleSeq}
+	if m.wakeTick() != nil {
+		t.Fatal("woke up with nothing to do")
+	}
+
+	m.buffering = true
+	if m.wakeTick() == nil {
+		t.Fatal("no wake-up tick while buffering")
+	}
+	if _, cmd := m.Update(stale); cmd != nil {
+		t.Fatal("stale idle tick was not dropped")
 	}
-	t.Logf("tick interval when stopped: %v (want ~%v tickSlow)", elapsed.Round(time.Millisecond), tickSlow)
 }
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/external/local"
+	"cliamp/internal/organize"
+	"cliamp/internal/resume"
+)
+
+// SetOrganizePattern sets the layout the info view's organize action moves
+// files into, as `cliamp organize` takes it; "" uses the default.
+func (m *Model) SetOrganizePattern(pattern string) {
+	m.organize = pattern
+}
+
+// organizedMsg reports a file moved from the info view.
+type organizedMsg struct {
+	move      organize.Move
+	moved     bool // the file is at its new path, even if err says moving it back failed
+	playlists int  // saved playlists pointed at the new path
+	err       error
+}
+
+// canOrganize reports whether the info view's track can be organized.
+func (m *Model) canOrganize() bool {
+	t := m.info.track
+	return m.library != nil && len(m.library.Dirs()) > 0 && t.Path != "" && !t.Stream && !t.Ranged()
+}
+
+// organizeRoot returns the library folder path is organized within: the
+// one holding it, or else the first.
+func (m *Model) organizeRoot(path string) string {
+	dirs := m.library.Dirs()
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(abs, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return abs
+		}
+	}
+	return dirs[0]
+}
+
+// planOrganize works out where the info view's track belongs and asks for
+// confirmation before moving it there.
+func (m *Model) planOrganize() {
+	if !m.canOrganize() || m.info.moving {
+		return
+	}
+	from, err := filepath.Abs(m.info.track.Path)
+	if err == nil {
+		var to string
+		if to, err = organize.Path(m.organize, m.organizeRoot(from), m.info.track); err == nil {
+			if to == from {
+				m.status.text = "Already organized"
+				m.status.ttl = statusTTLMedium
+				return
+			}
+			m.info.moveTo = to
+			return
+		}
+	}
+	m.status.text = fmt.Sprintf("Organize failed: %v", err)
+	m.status.ttl = statusTTLMedium
+}
+
+// handleOrganizeKey processes key presses while the info view asks to
+// confirm a move.
+func (m *Model) handleOrganizeKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc":
+		if !m.info.moving {
+			m.info.moveTo = ""
+		}
+	case "enter":
+		if m.info.moving {
+			return nil
+		}
+		from, err := filepath.Abs(m.info.track.Path)
+		if err != nil {
+			return nil
+		}
+		m.info.moving = true
+		return organizeCmd(organize.Move{From: from, To: m.info.moveTo}, m.localProvider, m.organizeIndexes())
+	}
+	return nil
+}
+
+// organizeIndexes lists what the player remembers files by their path in,
+// other than the saved playlists, so a move re-points all of it.
+func (m *Model) organizeIndexes() []organize.Index {
+	var idx []organize.Index
+	if m.bookmarks != nil {
+		idx = append(idx, organize.Index{Name: "bookmarks", Rename: m.bookmarks.Rename})
+	}
+	if m.gains != nil {
+		idx = append(idx, organize.Index{Name: "track gains", Rename: m.gains.Rename})
+	}
+	if m.eqs != nil {
+		idx = append(idx, organize.Index{Name: "track EQ curves", Rename: m.eqs.Rename})
+	}
+	if m.audiobooks != nil {
+		idx = append(idx, organize.Index{Name: "audiobook positions", Rename: m.audiobooks.Rename})
+	}
+	if m.history != nil {
+		idx = append(idx, organize.Index{Name: "history", Rename: m.history.Rename})
+	}
+	return append(idx, organize.Index{Name: "resume point", Rename: resume.Rename})
+}
+
+func organizeCmd(mv organize.Move, prov *local.Provider, indexes []organize.Index) tea.Cmd {
+	return func() tea.Msg {
+		msg := organizedMsg{move: mv}
+		if prov != nil {
+			playlists := organize.Index{Name: "playlists", Rename: func(moved map[string]string) error {
+				n, err := prov.RenamePaths(moved)
+				msg.playlists += n
+				return err
+			}}
+			indexes = append([]organize.Index{playlists}, indexes...)
+		}
+		var moveErr error
+		err := organize.Apply([]organize.Move{mv}, indexes, func(_ organize.Move, err error) { moveErr = err })
+		switch {
+		case moveErr != nil:
+			msg.err = moveErr
+		case err != nil:
+			// Moved back, unless that failed too, which err then says.
+			_, serr := os.Stat(mv.To)
+			msg.moved = serr == nil
+			msg.err = err
+		default:
+			msg.moved = true
+		}
+		return msg
+	}
+}
+
+// organized points the playlist, the library and the info view at the
+// moved file.
+func (m *Model) organized(msg organizedMsg) tea.Cmd {
+	m.info.moving = false
+	m.info.moveTo = ""
+	if !msg.moved {
+		m.status.text = fmt.Sprintf("Organize failed: %v", msg.err)
+		m.status.ttl = statusTTLMedium
+		return nil
+	}
+	for i, t := range m.playlist.Tracks() {
+		if abs, err := filepath.Abs(t.Path); err == nil && abs == msg.move.From {
+			t.Path = msg.move.To
+			m.playlist.SetTrack(i, t)
+		}
+	}
+	m.library.Rename(map[string]string{msg.move.From: msg.move.To})
+	if abs, err := filepath.Abs(m.info.track.Path); err == nil && abs == msg.move.From {
+		m.info.track.Path = msg.move.To
+	}
+	switch {
+	case msg.err != nil:
+		m.status.text = fmt.Sprintf("Moved, but %v", msg.err)
+	case msg.playlists > 0:
+		m.status.text = fmt.Sprintf("Moved to %s (%d playlists updated)", msg.move.To, msg.playlists)
+	default:
+		m.status.text = "Moved to " + msg.move.To
+	}
+	m.status.ttl = statusTTLMedium
+	return nil
+}
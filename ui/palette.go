@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"cliamp/theme"
+)
+
+// ansiPalette is the set of terminal colors the default theme draws with,
+// so it stays readable on the terminal's background.
+type ansiPalette struct {
+	title, text, dim, accent, playing, seek, volume, err lipgloss.ANSIColor
+	low, mid, high                                       lipgloss.ANSIColor // spectrum
+}
+
+// ansiPalettes are the default theme's palettes by name. "dark" is the
+// original: light gray for secondary text, which vanishes on white, so
+// "light" uses dark gray and blue instead. The high-contrast palettes
+// drop the grays altogether for the brightest or darkest colors.
+var ansiPalettes = map[string]ansiPalette{
+	"dark":                {title: 10, text: 15, dim: 7, accent: 11, playing: 10, seek: 11, volume: 2, err: 9, low: 10, mid: 11, high: 9},
+	"light":               {title: 2, text: 0, dim: 8, accent: 4, playing: 2, seek: 4, volume: 2, err: 1, low: 2, mid: 3, high: 1},
+	"high-contrast":       {title: 14, text: 15, dim: 15, accent: 11, playing: 10, seek: 14, volume: 10, err: 9, low: 10, mid: 11, high: 9},
+	"high-contrast-light": {title: 4, text: 0, dim: 0, accent: 5, playing: 4, seek: 4, volume: 4, err: 1, low: 4, mid: 5, high: 1},
+}
+
+// paletteName is the palette the default theme uses.
+var paletteName = "dark"
+
+// SetPalette picks the default theme's palette: "dark", "light",
+// "high-contrast" (white on dark) or "high-contrast-light" (black on
+// light). It reports whether name was one of them.
+func (m *Model) SetPalette(name string) bool {
+	name = strings.ToLower(name)
+	if _, ok := ansiPalettes[name]; !ok {
+		return false
+	}
+	paletteName = name
+	if m.themeIdx < 0 {
+		applyTheme(theme.Default())
+	}
+	return true
+}
+
+// DetectPalette asks the terminal for its background color (OSC 11, or
+// COLORFGBG where the terminal does not answer) and returns the palette
+// that reads best on it: "light" on a light background, a high-contrast
+// one on a mid-tone background where neither gray stands out, and "dark"
+// otherwise, including when stdout is not a terminal.
+func DetectPalette() string {
+	bg := termenv.NewOutput(os.Stdout).BackgroundColor()
+	_, _, l := termenv.ConvertToRGB(bg).Hsl()
+	switch {
+	case l >= 0.65:
+		return "light"
+	case l >= 0.5:
+		return "high-contrast-light"
+	case l > 0.35:
+		return "high-contrast"
+	}
+	return "dark"
+}
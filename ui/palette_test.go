@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSetPalette(t *testing.T) {
+	m := Model{themeIdx: -1}
+	defer m.SetPalette("dark")
+
+	if !m.SetPalette("Light") {
+		t.Fatal("SetPalette(Light) = false")
+	}
+	if colorText != lipgloss.ANSIColor(0) || colorDim != lipgloss.ANSIColor(8) {
+		t.Errorf("light palette text, dim = %v, %v; want black and dark gray", colorText, colorDim)
+	}
+	if m.SetPalette("sepia") {
+		t.Error("SetPalette(sepia) = true")
+	}
+	if paletteName != "light" {
+		t.Errorf("an unknown palette changed it to %q", paletteName)
+	}
+
+	// A hex theme keeps its colors; the palette waits for the default.
+	m.themeIdx = 0
+	m.SetPalette("high-contrast")
+	if colorText != lipgloss.ANSIColor(0) {
+		t.Errorf("palette applied over a hex theme: text = %v", colorText)
+	}
+}
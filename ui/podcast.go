@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/external/podcast"
+	"cliamp/playlist"
+)
+
+// podcastSubscribedMsg signals that a feed subscription attempt completed.
+type podcastSubscribedMsg struct {
+	title string
+	err   error
+}
+
+// podcastUnsubscribedMsg signals that a feed was removed.
+type podcastUnsubscribedMsg struct{ err error }
+
+// episodeDownloadedMsg signals that an episode download completed.
+type episodeDownloadedMsg struct {
+	url   string // enclosure URL the episode streamed from
+	path  string // local file it was saved to
+	title string
+	err   error
+}
+
+func subscribePodcastCmd(p *podcast.Provider, feedURL string) tea.Cmd {
+	return func() tea.Msg {
+		title, err := p.Subscribe(feedURL)
+		return podcastSubscribedMsg{title: title, err: err}
+	}
+}
+
+func unsubscribePodcastCmd(p *podcast.Provider, feedURL string) tea.Cmd {
+	return func() tea.Msg {
+		return podcastUnsubscribedMsg{err: p.Unsubscribe(feedURL)}
+	}
+}
+
+func downloadEpisodeCmd(p *podcast.Provider, track playlist.Track) tea.Cmd {
+	return func() tea.Msg {
+		path, err := p.Download(track.Path)
+		return episodeDownloadedMsg{url: track.Path, path: path, title: track.Title, err: err}
+	}
+}
+
+// SetPodcasts attaches the podcast library so the provider panel can manage
+// subscriptions and episode positions are remembered across sessions.
+func (m *Model) SetPodcasts(p *podcast.Provider) {
+	m.podcasts = p
+}
+
+// podcastsActive reports whether the provider panel is showing podcasts.
+func (m Model) podcastsActive() bool {
+	return m.podcasts != nil && m.provider == playlist.Provider(m.podcasts)
+}
+
+// handlePodcastProviderKey handles the subscription keys of the podcast
+// provider panel. It reports whether the key was consumed.
+func (m *Model) handlePodcastProviderKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.podcastUnsub != "" {
+		feedURL := m.podcastUnsub
+		m.podcastUnsub = ""
+		if msg.String() == "y" || msg.String() == "Y" {
+			return unsubscribePodcastCmd(m.podcasts, feedURL), true
+		}
+		m.status.text = "Kept subscription"
+		m.status.ttl = statusTTLShort
+		return nil, true
+	}
+	switch msg.String() {
+	case "+":
+		m.urlInputting = true
+		m.urlSubscribe = true
+		m.urlInput = ""
+		return nil, true
+	case "d":
+		if m.provCursor < len(m.providerLists) {
+			pl := m.providerLists[m.provCursor]
+			m.podcastUnsub = pl.ID
+			m.status.text = fmt.Sprintf("Unsubscribe from %s? (y/n)", pl.Name)
+			m.status.ttl = statusTTLLong
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// downloadEpisode saves the current podcast episode into the podcast library.
+func (m *Model) downloadEpisode(track playlist.Track) tea.Cmd {
+	m.status.text = "Downloading episode..."
+	m.status.ttl = statusTTLDownload // cleared by episodeDownloadedMsg
+	return downloadEpisodeCmd(m.podcasts, track)
+}
+
+// episodeDownloaded points every playlist entry for the episode at the
+// downloaded file, so later plays and seeks work from disk.
+func (m *Model) episodeDownloaded(msg episodeDownloadedMsg) {
+	if msg.err != nil {
+		m.status.text = fmt.Sprintf("Download failed: %s", msg.err)
+		m.status.ttl = statusTTLMedium
+		return
+	}
+	for i, t := range m.playlist.Tracks() {
+		if t.Path == msg.url {
+			t.Path = msg.path
+			t.Stream = false
+			m.playlist.SetTrack(i, t)
+		}
+	}
+	m.status.text = "Downloaded " + strings.TrimSpace(msg.title)
+	m.status.ttl = statusTTLMedium
+}
+
+// isEpisode reports whether track belongs to a subscribed podcast.
+func (m Model) isEpisode(track playlist.Track) bool {
+	return m.podcasts != nil && m.podcasts.IsEpisode(track.Path)
+}
+
+// saveEpisodePosition remembers how far playback got in the current
+// episode. Called whenever playback leaves a track before its end.
+func (m *Model) saveEpisodePosition() {
+	track, idx := m.playlist.Current()
+	if idx < 0 || !m.player.IsPlaying() || !m.isEpisode(track) {
+		return
+	}
+	m.podcasts.SetPosition(track.Path, int(m.player.Position().Seconds()))
+}
+
+// episodeFinished forgets the position of an episode that played to the end.
+func (m *Model) episodeFinished(track playlist.Track) {
+	if m.isEpisode(track) {
+		m.podcasts.SetPosition(track.Path, 0)
+	}
+}
+
+// restoreEpisodePosition arms the one-shot resume seek for an episode that
+// was left part-way through.
+func (m *Model) restoreEpisodePosition(track playlist.Track) {
+	if !m.isEpisode(track) {
+		return
+	}
+	if secs := m.podcasts.Position(track.Path); secs > 0 {
+		m.resume.path = track.Path
+		m.resume.secs = secs
+	}
+}
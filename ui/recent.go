@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/playlist"
+)
+
+// maxRecent is how many tracks the recently played panel remembers.
+const maxRecent = 100
+
+// addRecent puts track at the top of the recently played list, moving it
+// there if it was heard before.
+func (m *Model) addRecent(track playlist.Track) {
+	for i, t := range m.recent {
+		if t.PlayPath() == track.PlayPath() {
+			m.recent = append(m.recent[:i], m.recent[i+1:]...)
+			break
+		}
+	}
+	m.recent = append([]playlist.Track{track}, m.recent...)
+	if len(m.recent) > maxRecent {
+		m.recent = m.recent[:maxRecent]
+	}
+}
+
+// recentTrack returns the playlist index of the selected recently played
+// track, appending it to the playlist when it has since been removed.
+func (m *Model) recentTrack() (int, bool) {
+	if m.queue.histCursor >= len(m.recent) {
+		return 0, false
+	}
+	track := m.recent[m.queue.histCursor]
+	for i, t := range m.playlist.Tracks() {
+		if t.PlayPath() == track.PlayPath() {
+			return i, true
+		}
+	}
+	m.playlist.Add(track)
+	return m.playlist.Len() - 1, true
+}
+
+// handleRecentKey processes key presses while the queue manager shows the
+// recently played panel.
+func (m *Model) handleRecentKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		m.queue.visible = false
+		return m.quit()
+	case "ctrl+k":
+		m.keymap.visible = true
+	case "up", "k":
+		if m.queue.histCursor > 0 {
+			m.queue.histCursor--
+		}
+	case "down", "j":
+		if m.queue.histCursor < len(m.recent)-1 {
+			m.queue.histCursor++
+		}
+	case "enter", "a":
+		idx, ok := m.recentTrack()
+		if !ok {
+			return nil
+		}
+		if m.playlist.QueuePosition(idx) == 0 {
+			m.playlist.Queue(idx)
+		}
+		m.status.text = "Queued: " + m.playlist.Tracks()[idx].DisplayName()
+		m.status.ttl = statusTTLDefault
+	case "d":
+		if i := m.queue.histCursor; i < len(m.recent) {
+			m.recent = append(m.recent[:i], m.recent[i+1:]...)
+			if m.queue.histCursor >= len(m.recent) && m.queue.histCursor > 0 {
+				m.queue.histCursor--
+			}
+		}
+	case "c":
+		m.recent = nil
+		m.queue.histCursor = 0
+	case "tab":
+		m.queue.history = false
+	case "esc", "A":
+		m.queue.visible = false
+	}
+	return nil
+}
+
+func (m Model) renderRecentOverlay() string {
+	lines := []string{
+		titleStyle.Render("R E C E N T L Y  P L A Y E D"),
+		"",
+	}
+
+	maxVisible := 12
+	rendered := 0
+
+	if len(m.recent) == 0 {
+		lines = append(lines, dimStyle.Render("  (nothing yet)"))
+		rendered = 1
+	} else {
+		scroll := scrollStart(m.queue.histCursor, maxVisible)
+		for i := scroll; i < len(m.recent) && i < scroll+maxVisible; i++ {
+			name := truncate(m.recent[i].DisplayName(), panelWidth-8)
+			lines = append(lines, cursorLine(name, i == m.queue.histCursor))
+			rendered++
+		}
+	}
+
+	lines = padLines(lines, maxVisible, rendered)
+	lines = append(lines, "", dimStyle.Render(fmt.Sprintf("  %d played this session", len(m.recent))))
+	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Enter", "Re-queue ")+helpKey("d", "Remove ")+helpKey("c", "Clear ")+helpKey("Tab", "Queue ")+helpKey("Esc", "Close"))
+
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
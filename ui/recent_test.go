@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"testing"
+
+	"cliamp/playlist"
+)
+
+func TestRecentRequeuesRemovedTracks(t *testing.T) {
+	pl := playlist.New()
+	a, b := playlist.Track{Path: "/m/a.mp3"}, playlist.Track{Path: "/m/b.mp3"}
+	pl.Add(a, b)
+	m := Model{playlist: pl}
+
+	m.addRecent(a)
+	m.addRecent(b)
+	m.addRecent(a)
+	if len(m.recent) != 2 || m.recent[0].Path != a.Path {
+		t.Fatalf("recent = %v, want a then b", m.recent)
+	}
+
+	// b has left the playlist since it played: queueing it adds it back.
+	pl.Replace([]playlist.Track{a})
+	m.queue.histCursor = 1
+	idx, ok := m.recentTrack()
+	if !ok || idx != 1 || pl.Len() != 2 || pl.Tracks()[1].Path != b.Path {
+		t.Fatalf("recentTrack = %d, %v with %d tracks, want b appended", idx, ok, pl.Len())
+	}
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/playlist"
+	"cliamp/remote"
+	"cliamp/resolve"
+)
+
+// remotePositionInterval is how often position events are sent while
+// playing.
+const remotePositionInterval = time.Second
+
+// SetRemote attaches a hub that the remote-control servers read state and
+// events from.
+func (m *Model) SetRemote(h *remote.Hub) {
+	m.remote.hub = h
+}
+
+// remoteWantsSpectrum reports whether a remote client subscribed to
+// spectrum frames.
+func (m *Model) remoteWantsSpectrum() bool {
+	return m.remote.hub != nil && m.remote.hub.WantsSpectrum()
+}
+
+// remoteStatus builds a snapshot of the player for remote clients.
+func (m *Model) remoteStatus() remote.Status {
+	st := remote.Status{
+		State:    "stopped",
+		Index:    m.playlist.Index(),
+		Length:   m.playlist.Len(),
+		Position: m.cachedPos.Seconds(),
+		Duration: m.cachedDur.Seconds(),
+		Volume:   m.player.Volume(),
+		Seekable: m.player.Seekable(),
+		Shuffle:  m.playlist.Shuffled(),
+		Repeat:   strings.ToLower(m.playlist.Repeat().String()),
+
+		PlaylistVersion: m.playlist.Version(),
+	}
+	if m.player.IsPlaying() {
+		st.State = "playing"
+		if m.player.IsPaused() {
+			st.State = "paused"
+		}
+	}
+	if track, idx := m.playlist.Current(); idx >= 0 {
+		st.Track = remoteTrack(track)
+		// Radio streams carry the current song in ICY metadata.
+		if m.streamTitle != "" && track.Stream {
+			if artist, title, ok := strings.Cut(m.streamTitle, " - "); ok {
+				st.Track.Artist = artist
+				st.Track.Title = title
+			} else {
+				st.Track.Title = m.streamTitle
+			}
+		}
+	}
+	return st
+}
+
+func remoteTrack(t playlist.Track) remote.Track {
+	return remote.Track{
+		Path:        t.Path,
+		Title:       t.Title,
+		Artist:      t.Artist,
+		Album:       t.Album,
+		Genre:       t.Genre,
+		TrackNumber: t.TrackNumber,
+		Duration:    t.DurationSecs,
+		Stream:      t.Stream,
+	}
+}
+
+// publishRemote refreshes the hub snapshot and turns what changed since the
+// last tick into events. Called once per tick.
+func (m *Model) publishRemote() {
+	h := m.remote.hub
+	if h == nil {
+		return
+	}
+	st := m.remoteStatus()
+	last := m.remote.last
+	if st.PlaylistVersion != last.PlaylistVersion {
+		tracks := make([]remote.Track, m.playlist.Len())
+		for i, t := range m.playlist.Tracks() {
+			tracks[i] = remoteTrack(t)
+		}
+		h.SetPlaylist(tracks)
+	}
+	h.SetStatus(st)
+	m.remote.last = st
+
+	if st.PlaylistVersion != last.PlaylistVersion {
+		h.Publish(remote.Event{Type: remote.EventPlaylist, Data: st.PlaylistVersion})
+	}
+	if st.Shuffle != last.Shuffle || st.Repeat != last.Repeat {
+		h.Publish(remote.Event{Type: remote.EventOptions, Data: remote.Options{Shuffle: st.Shuffle, Repeat: st.Repeat}})
+	}
+	if st.Track != last.Track {
+		h.Publish(remote.Event{Type: remote.EventTrack, Data: st.Track})
+	}
+	if st.State != last.State {
+		h.Publish(remote.Event{Type: remote.EventState, Data: st.State})
+	}
+	if st.Volume != last.Volume {
+		h.Publish(remote.Event{Type: remote.EventVolume, Data: st.Volume})
+	}
+	// Position moves every tick; throttle it, but report seeks and track
+	// changes right away.
+	now := time.Now()
+	jumped := st.Track != last.Track || st.Position < last.Position ||
+		st.Position-last.Position > 2*remotePositionInterval.Seconds()
+	if jumped || st.State != last.State ||
+		(st.State == "playing" && now.Sub(m.remote.posAt) >= remotePositionInterval) {
+		m.remote.posAt = now
+		h.Publish(remote.Event{Type: remote.EventPosition, Data: remote.Position{
+			Position: st.Position,
+			Duration: st.Duration,
+		}})
+	}
+
+	if st.State == "playing" && h.WantsSpectrum() {
+		if m.remote.vis == nil {
+			m.remote.vis = NewVisualizer(float64(m.player.SampleRate()))
+		}
+		v := m.remote.vis
+		n := m.player.SamplesInto(v.sampleBuf)
+		bands := v.Analyze(v.sampleBuf[:n])
+		h.Publish(remote.Event{Type: remote.EventSpectrum, Data: bands[:]})
+	}
+}
+
+// addPathsCmd resolves paths sent by a remote client into tracks and
+// appends them like the file browser does, or with replace swaps the
+// playlist for them. Feeds and remote playlists are fetched in the same
+// step.
+func addPathsCmd(paths []string, replace bool) tea.Cmd {
+	return func() tea.Msg {
+		r, err := resolve.Args(paths)
+		if err != nil {
+			return err
+		}
+		tracks := r.Tracks
+		if len(r.Pending) > 0 {
+			more, err := resolve.Remote(r.Pending)
+			if err != nil {
+				return err
+			}
+			tracks = append(tracks, more...)
+		}
+		return fbTracksResolvedMsg{tracks: tracks, replace: replace}
+	}
+}
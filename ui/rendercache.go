@@ -0,0 +1,133 @@
+package ui
+
+// renderCache keeps sections of the main view that stay the same from one
+// frame to the next, so View only rebuilds the ones whose state changed.
+// The Model copies share it through a pointer.
+type renderCache struct {
+	controls section[controlsKey]
+	playlist section[playlistKey]
+	help     section[helpRowKey]
+}
+
+// section is one cached rendering and the state it was rendered from.
+type section[K comparable] struct {
+	key K
+	out string
+	ok  bool
+}
+
+// render returns the cached output while key is unchanged, and calls draw
+// to rebuild it otherwise.
+func (s *section[K]) render(key K, draw func() string) string {
+	if !s.ok || s.key != key {
+		s.key, s.out, s.ok = key, draw(), true
+	}
+	return s.out
+}
+
+// Every key holds panelWidth and themeGen: a resize or a theme change
+// invalidates all sections.
+
+type controlsKey struct {
+	width, theme int
+	bands        [10]float64
+	freqs        [10]float64
+	preset       string
+	eqScope      string
+	eqFocus      bool
+	eqCursor     int
+	preamp       float64
+	trackGain    float64
+	playing      bool
+	volume       float64
+	volPercent   bool
+	balance      float64
+	mono         bool
+	muted        bool
+	clipping     bool
+}
+
+type playlistKey struct {
+	width, theme int
+	version      uint64
+	queue        uint64
+	index        int
+	playing      bool
+	focus        focusArea
+	cursor       int
+	scroll       int
+	visible      int
+	albums       bool
+	folds        int
+	streamTitle  string
+	feedLoading  bool
+}
+
+type helpRowKey struct {
+	width, theme int
+	focus        focusArea
+	podcasts     bool
+	seekable     bool
+}
+
+func (m Model) cachedControls() string {
+	if m.cache == nil {
+		return m.renderControls()
+	}
+	return m.cache.controls.render(controlsKey{
+		width:      panelWidth,
+		theme:      themeGen,
+		bands:      m.player.EQBands(),
+		freqs:      m.player.EQFreqs(),
+		preset:     m.EQPresetName(),
+		eqScope:    m.trackEQ.scope,
+		eqFocus:    m.focus == focusEQ,
+		eqCursor:   m.eqCursor,
+		preamp:     m.player.EQPreamp(),
+		trackGain:  m.player.TrackGain(),
+		playing:    m.player.IsPlaying(),
+		volume:     m.player.Volume(),
+		volPercent: m.volPercent,
+		balance:    m.player.Balance(),
+		mono:       m.player.Mono(),
+		muted:      m.player.Muted(),
+		clipping:   m.player.Clipping(),
+	}, m.renderControls)
+}
+
+func (m Model) cachedPlaylist() string {
+	// The provider list has state of its own; it is cheap to draw.
+	if m.cache == nil || m.focus == focusProvider {
+		return m.renderPlaylist()
+	}
+	return m.cache.playlist.render(playlistKey{
+		width:       panelWidth,
+		theme:       themeGen,
+		version:     m.playlist.Version(),
+		queue:       m.playlist.QueueVersion(),
+		index:       m.playlist.Index(),
+		playing:     m.player.IsPlaying(),
+		focus:       m.focus,
+		cursor:      m.plCursor,
+		scroll:      m.plScroll,
+		visible:     m.plVisible,
+		albums:      m.albums.on,
+		folds:       m.albums.folds,
+		streamTitle: m.streamTitle,
+		feedLoading: m.feedLoading,
+	}, m.renderPlaylist)
+}
+
+func (m Model) cachedHelp() string {
+	if m.cache == nil {
+		return m.renderHelp()
+	}
+	track, _ := m.playlist.Current()
+	return m.cache.help.render(helpRowKey{
+		width:    panelWidth,
+		theme:    themeGen,
+		focus:    m.focus,
+		podcasts: m.podcastsActive(),
+		seekable: !track.Stream || m.player.Seekable(),
+	}, m.renderHelp)
+}
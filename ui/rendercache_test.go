@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"cliamp/playlist"
+)
+
+func TestCachedPlaylistFollowsChanges(t *testing.T) {
+	if sharedPlayer == nil {
+		t.Skip("audio hardware unavailable")
+	}
+	pl := playlist.New()
+	pl.Replace([]playlist.Track{{Title: "One"}, {Title: "Two"}})
+	m := Model{player: sharedPlayer, playlist: pl, plVisible: 5, focus: focusPlaylist, cache: &renderCache{}}
+
+	first := m.cachedPlaylist()
+	if first != m.renderPlaylist() || m.cachedPlaylist() != first {
+		t.Fatal("cached playlist differs from a fresh render")
+	}
+
+	pl.SetTrack(1, playlist.Track{Title: "Renamed"})
+	if got := m.cachedPlaylist(); !strings.Contains(got, "Renamed") {
+		t.Fatalf("edit not redrawn:\n%s", got)
+	}
+	pl.Queue(0)
+	if got := m.cachedPlaylist(); !strings.Contains(got, "[Q1]") {
+		t.Fatalf("queue not redrawn:\n%s", got)
+	}
+	m.plCursor = 1
+	if got := m.cachedPlaylist(); got != m.renderPlaylist() {
+		t.Fatalf("cursor move not redrawn:\n%s", got)
+	}
+}
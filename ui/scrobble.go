@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"time"
+
+	"cliamp/external/navidrome"
+	"cliamp/hooks"
+	"cliamp/playlist"
+)
+
+// Scrobbler receives playback reports for a listening-history service.
+// Both methods are called in their own goroutine, so they may block on the
+// network; errors are ignored.
+type Scrobbler interface {
+	NowPlaying(track playlist.Track) error
+	Scrobble(track playlist.Track, listenedAt time.Time) error
+}
+
+// navScrobbler reports Navidrome tracks back to their server through the
+// Subsonic scrobble endpoint. Tracks from other sources are ignored.
+type navScrobbler struct {
+	client *navidrome.NavidromeClient
+}
+
+func (n navScrobbler) NowPlaying(track playlist.Track) error {
+	if track.NavidromeID != "" {
+		return n.client.Scrobble(track.NavidromeID, false, time.Time{})
+	}
+	return nil
+}
+
+func (n navScrobbler) Scrobble(track playlist.Track, listenedAt time.Time) error {
+	if track.NavidromeID != "" {
+		return n.client.Scrobble(track.NavidromeID, true, listenedAt)
+	}
+	return nil
+}
+
+// AddScrobbler registers an additional listening-history service.
+func (m *Model) AddScrobbler(s Scrobbler) {
+	m.scrobblers = append(m.scrobblers, s)
+}
+
+// maybeScrobble submits a listen for the given track to every scrobbler if
+// all conditions are met:
+//   - at least one scrobbler is configured
+//   - the track's duration is known
+//   - elapsed is at least 50% of that duration
+//
+// Each submission is dispatched in a goroutine so it never blocks the UI.
+func (m *Model) maybeScrobble(track playlist.Track, elapsed, duration time.Duration) {
+	if len(m.scrobblers) == 0 {
+		return
+	}
+	if duration <= 0 {
+		// Unknown duration: use DurationSecs metadata as fallback.
+		duration = time.Duration(track.DurationSecs) * time.Second
+	}
+	if duration <= 0 {
+		return // still unknown — skip
+	}
+	if elapsed < duration/2 {
+		return // less than 50% played
+	}
+	listenedAt := time.Now().Add(-elapsed)
+	for _, s := range m.scrobblers {
+		go s.Scrobble(track, listenedAt)
+	}
+}
+
+// nowPlaying sends a now-playing notification for the given track to every
+// scrobbler and runs the track_start hook.
+func (m *Model) nowPlaying(track playlist.Track) {
+	for _, s := range m.scrobblers {
+		go s.NowPlaying(track)
+	}
+	m.hooks.Run(hooks.TrackStart, track, 0)
+}
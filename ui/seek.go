@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -46,6 +47,9 @@ func (m *Model) doSeek(d time.Duration) tea.Cmd {
 
 // displayPosition returns the position to show in the UI.
 func (m *Model) displayPosition() time.Duration {
+	if m.scrub.active {
+		return m.scrub.target
+	}
 	if m.seek.active {
 		return m.seek.targetPos
 	}
@@ -89,3 +93,116 @@ func (m *Model) tickSeek() tea.Cmd {
 		return seekTickMsg{}
 	}
 }
+
+// Arrow-key seek acceleration.
+const (
+	seekRepeatWindow = 250 * time.Millisecond // presses closer than this count as a held key
+	seekRepeatsPerX  = 10                     // repeats before the step doubles
+	seekMaxStep      = 2 * time.Minute
+	seekHintDuration = time.Second
+)
+
+// arrowSeek seeks one step in dir (-1 or +1), growing the step while the key
+// is held, and briefly shows the step next to the seek bar.
+func (m *Model) arrowSeek(dir int) tea.Cmd {
+	now := time.Now()
+	step := m.seekHold.next(dir, m.seekStep, now)
+	m.seekHold.shownUntil = now.Add(seekHintDuration)
+	return m.doSeek(time.Duration(dir) * step)
+}
+
+// next records an arrow press at now and returns the step to use. The base
+// step doubles every seekRepeatsPerX auto-repeats, up to 8× or seekMaxStep,
+// and resets when the direction changes or the key is released.
+func (h *seekHoldState) next(dir int, base time.Duration, now time.Time) time.Duration {
+	if dir == h.dir && now.Sub(h.last) <= seekRepeatWindow {
+		h.repeats++
+	} else {
+		h.repeats = 0
+	}
+	h.dir = dir
+	h.last = now
+	mult := time.Duration(1) << min(h.repeats/seekRepeatsPerX, 3)
+	h.step = max(base, min(base*mult, seekMaxStep))
+	return h.step
+}
+
+// Scrubbing: Ctrl+Left/Right cue through the track like a hand on a record.
+const (
+	scrubStep    = time.Second            // the cue moves this far per press, growing while held
+	scrubSnippet = 150 * time.Millisecond // audio heard at each cue point
+	scrubRelease = 600 * time.Millisecond // no presses for this long lets go
+)
+
+// scrubSeek moves the cue point one step in dir (-1 or +1) while a scrub
+// key is held. Playback jumps to the cue at most every scrubSnippet and
+// plays from there in between, so the auto-repeating key turns into a run
+// of short snippets; a paused track plays while scrubbed. Letting go
+// (see tickScrub) settles on the cue point. Streams seeking by restart
+// can't cue quickly and seek in plain steps instead.
+func (m *Model) scrubSeek(dir int) tea.Cmd {
+	if !m.player.IsPlaying() || !m.player.Seekable() {
+		return nil
+	}
+	if m.player.IsYTDLSeek() {
+		return m.arrowSeek(dir)
+	}
+	now := time.Now()
+	if !m.scrub.active {
+		m.scrub = scrubState{active: true, target: m.player.Position(), paused: m.player.IsPaused()}
+		if m.scrub.paused {
+			m.player.TogglePause()
+		}
+	}
+	step := m.seekHold.next(dir, scrubStep, now)
+	m.scrub.target = m.clampPosition(m.scrub.target + time.Duration(dir)*step)
+	m.scrub.last = now
+	if now.Sub(m.scrub.seekedAt) >= scrubSnippet {
+		m.player.Seek(m.scrub.target - m.player.Position())
+		m.scrub.seekedAt = now
+	}
+	m.cachedPos = m.scrub.target
+	return nil
+}
+
+// tickScrub is called from the main tick loop. Once the scrub key has been
+// let go it jumps to the final cue point and, if the track was paused
+// before, pauses it there.
+func (m *Model) tickScrub(now time.Time) {
+	if !m.scrub.active || now.Sub(m.scrub.last) < scrubRelease {
+		return
+	}
+	m.scrub.active = false
+	if m.player.IsPlaying() {
+		m.player.Seek(m.scrub.target - m.player.Position())
+		if m.scrub.paused && !m.player.IsPaused() {
+			m.player.TogglePause()
+		}
+		if m.mpris != nil {
+			m.mpris.EmitSeeked(m.player.Position().Microseconds())
+		}
+	}
+}
+
+// replay jumps back by the replay step, for catching a line just missed.
+func (m *Model) replay() tea.Cmd {
+	if !m.player.IsPlaying() || !m.player.Seekable() {
+		return nil
+	}
+	m.status.text = "Replay -" + formatSeekStep(m.replayStep)
+	m.status.ttl = statusTTLShort
+	return m.doSeek(-m.replayStep)
+}
+
+// formatSeekStep renders a step as "5s", "2m" or "1m30s".
+func formatSeekStep(d time.Duration) string {
+	secs := int(d.Round(time.Second).Seconds())
+	switch {
+	case secs < 60:
+		return fmt.Sprintf("%ds", secs)
+	case secs%60 == 0:
+		return fmt.Sprintf("%dm", secs/60)
+	default:
+		return fmt.Sprintf("%dm%02ds", secs/60, secs%60)
+	}
+}
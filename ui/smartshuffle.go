@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/internal/history"
+	"cliamp/playlist"
+)
+
+// smartRecency is the time scale over which a played track recovers its
+// chance to come up again: after it, the recency penalty has mostly gone.
+const smartRecency = 7 * 24 * time.Hour
+
+// smartShuffle weights track shuffle by the listening history. It is held
+// by pointer because the playlist's weight function outlives Model copies.
+type smartShuffle struct {
+	rating  float64 // exponent on how fully a track is usually heard
+	recency float64 // exponent on how long ago it was last played
+	plays   map[string]history.Listening
+}
+
+// listeningLoadedMsg carries the listening history summed up per track.
+type listeningLoadedMsg map[string]history.Listening
+
+func loadListeningCmd(l *history.Log) tea.Cmd {
+	return func() tea.Msg {
+		entries, _ := l.Entries()
+		return listeningLoadedMsg(history.ByTrack(entries))
+	}
+}
+
+// SetSmartShuffle makes track shuffle prefer tracks that are usually heard
+// to the end and have not been played lately, rather than drawing all
+// tracks alike. rating and recency weigh the two preferences; 0 ignores
+// one. The history is read in the background when the program starts.
+func (m *Model) SetSmartShuffle(on bool, rating, recency float64) {
+	if !on {
+		m.smart = nil
+		m.playlist.SetShuffleWeight(nil)
+		return
+	}
+	s := &smartShuffle{rating: rating, recency: recency}
+	m.smart = s
+	m.playlist.SetShuffleWeight(func(t playlist.Track) float64 { return s.weight(t, time.Now()) })
+}
+
+// weight scores t for shuffling. Tracks never played score 1. A played
+// track's rating runs from 0.5, when it is always skipped early, to 1.5,
+// when it is always heard through; its freshness from 0 just after a
+// listen back towards 1 over smartRecency.
+func (s *smartShuffle) weight(t playlist.Track, now time.Time) float64 {
+	l, ok := s.plays[t.Path]
+	if !ok || l.Plays == 0 {
+		return 1
+	}
+	rating := 1.0
+	if t.DurationSecs > 0 {
+		rating = 0.5 + min(1, float64(l.Secs)/float64(l.Plays*t.DurationSecs))
+	}
+	fresh := 1 - math.Exp(-float64(now.Sub(l.Last))/float64(smartRecency))
+	return math.Pow(rating, s.rating) * math.Pow(max(fresh, 0.01), s.recency)
+}
+
+// smartListened notes a listen just written to the history, so the next
+// shuffle holds the track back without rereading the log.
+func (m *Model) smartListened(path string, at time.Time, secs int) {
+	if m.smart == nil || m.smart.plays == nil {
+		return
+	}
+	l := m.smart.plays[path]
+	l.Plays++
+	l.Secs += secs
+	l.Last = at
+	m.smart.plays[path] = l
+}
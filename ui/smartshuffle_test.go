@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"cliamp/internal/history"
+	"cliamp/playlist"
+)
+
+func TestSmartShuffleWeight(t *testing.T) {
+	now := time.Now()
+	s := &smartShuffle{rating: 1, recency: 1, plays: map[string]history.Listening{
+		"/heard":   {Plays: 2, Secs: 400, Last: now.Add(-60 * 24 * time.Hour)},
+		"/skipped": {Plays: 2, Secs: 40, Last: now.Add(-60 * 24 * time.Hour)},
+		"/today":   {Plays: 1, Secs: 200, Last: now.Add(-time.Hour)},
+	}}
+	w := func(path string) float64 { return s.weight(playlist.Track{Path: path, DurationSecs: 200}, now) }
+
+	if got := w("/new"); got != 1 {
+		t.Errorf("unplayed weight = %v, want 1", got)
+	}
+	if heard, skipped := w("/heard"), w("/skipped"); heard <= 1 || skipped >= 1 {
+		t.Errorf("heard-through %v should beat unplayed, skipped %v should trail it", heard, skipped)
+	}
+	if got := w("/today"); got > 0.05 {
+		t.Errorf("track played an hour ago weighs %v, want it held back", got)
+	}
+}
@@ -6,10 +6,17 @@ package ui
 import (
 	"time"
 
+	"cliamp/autodj"
+	"cliamp/cast"
+	"cliamp/external/acoustid"
 	"cliamp/external/navidrome"
 	"cliamp/external/radio"
+	"cliamp/internal/history"
+	"cliamp/library"
 	"cliamp/lyrics"
+	"cliamp/player"
 	"cliamp/playlist"
+	"cliamp/remote"
 )
 
 // searchState holds state for the playlist search overlay.
@@ -43,6 +50,41 @@ type seekState struct {
 	grace     int           // ticks to suppress reconnect after seek completes
 }
 
+// seekHoldState tracks a held Left/Right key so the seek step can grow
+// while the key auto-repeats, and when to stop showing the step label.
+type seekHoldState struct {
+	dir        int       // -1 or +1 for the last arrow seek
+	last       time.Time // time of the last arrow seek
+	repeats    int       // consecutive presses in the same direction
+	step       time.Duration
+	shownUntil time.Time
+}
+
+// scrubState tracks a held scrub key: the position being cued, and whether
+// playback was paused before scrubbing started and goes back to it.
+type scrubState struct {
+	active   bool
+	target   time.Duration
+	last     time.Time // time of the last scrub press
+	seekedAt time.Time // when playback last jumped to the target
+	paused   bool
+}
+
+// termTitleState holds the terminal title setting and what was set last.
+type termTitleState struct {
+	format string // "" leaves the title alone
+	last   string
+}
+
+// accessibleState holds the screen reader mode and what it announced last.
+type accessibleState struct {
+	on     bool
+	track  string // name of the track last announced
+	state  string // "playing", "paused" or "stopped"; "" before the first
+	status string
+	err    string
+}
+
 // themePickerState holds state for the theme picker overlay.
 type themePickerState struct {
 	visible  bool
@@ -68,12 +110,160 @@ type keymapOverlay struct {
 	filtered []int // indices into keymapEntries
 }
 
-// queueOverlay holds state for the queue manager overlay.
+// queueOverlay holds state for the queue manager overlay and its recently
+// played panel.
 type queueOverlay struct {
+	visible    bool
+	cursor     int
+	history    bool // showing the recently played panel
+	histCursor int
+}
+
+// effectsOverlay holds state for the effects (DSP) menu.
+type effectsOverlay struct {
+	visible bool
+	cursor  int
+}
+
+// eqBandsOverlay holds state for the expanded EQ view, which edits each
+// band's center frequency, Q and gain. The band is Model.eqCursor.
+type eqBandsOverlay struct {
+	visible bool
+	field   int // eqFieldFreq, eqFieldQ or eqFieldGain
+}
+
+// castPickerState holds state for the output (Chromecast) picker.
+type castPickerState struct {
+	visible  bool
+	scanning bool
+	cursor   int // 0 is this computer, i+1 is devices[i]
+	devices  []cast.Device
+	err      error
+}
+
+// chapterState holds the current track's chapters and the chapter list
+// overlay.
+type chapterState struct {
+	path    string // track the list was read from
+	list    []playlist.Chapter
+	visible bool
+	cursor  int
+}
+
+// bookmarkState holds the bookmark name prompt and the bookmark list
+// overlay for the playing track.
+type bookmarkState struct {
+	visible bool
+	cursor  int
+	naming  bool          // name prompt is open
+	input   string        // name typed so far
+	at      time.Duration // position the new bookmark marks
+}
+
+// alarmState holds the alarm clock and the prompt that sets it.
+type alarmState struct {
+	at      time.Time     // when playback starts; zero when no alarm is set
+	fade    time.Duration // fade-in from silence when it goes off
+	editing bool          // time prompt is open
+	input   string        // time typed so far
+}
+
+// stopAtState holds the scheduled stop and the prompt that sets it.
+type stopAtState struct {
+	at      time.Time // when playback stops; zero when no stop is scheduled
+	editing bool      // time prompt is open
+	input   string    // time typed so far
+}
+
+// levelState holds the peak level meter.
+type levelState struct {
+	on   bool
+	held float64   // peak shown, in dBFS
+	at   time.Time // when held was taken
+}
+
+// trackGainState holds the prompt that sets the playing track's gain
+// offset.
+type trackGainState struct {
+	editing bool
+	input   string // offset typed so far
+}
+
+// trackEQState holds the EQ curve remembered for the playing track, while
+// one is applied.
+type trackEQState struct {
+	scope        string // "track" or "album"; "" when the usual curve is on
+	key          string // the curve's key in the store
+	global       [10]float64
+	globalPreset int // the usual curve and preset, to return to
+}
+
+// autoDJState holds Auto-DJ, which appends library tracks when the
+// playlist runs out.
+type autoDJState struct {
+	dj      *autodj.DJ // nil when no library is attached
+	on      bool
+	pending bool // a pick is in flight
+	play    bool // playback reached the end and waits for the pick
+}
+
+// followState holds follow-playback mode, which keeps the playing track
+// centred in the playlist view.
+type followState struct {
+	on  bool
+	idx int // track last centred, so the view only moves when it changes
+}
+
+// albumState holds the album grouping of the playlist view.
+type albumState struct {
+	on        bool
+	n         int             // playlist length when last grouped
+	collapsed map[string]bool // playlist.AlbumKey of albums folded to their header
+	folds     int             // bumped on every fold and unfold
+}
+
+// techState holds the encoding details of the current track.
+type techState struct {
+	path string // track the details were requested for
+	info player.TechInfo
+}
+
+// infoState holds the track info overlay.
+type infoState struct {
+	track   playlist.Track
+	loading bool // tags and play count are still being read
+	fields  []playlist.TagField
+	tech    player.TechInfo
+	plays   int
+	last    time.Time // latest listen in the history
+
+	identifying bool             // an AcoustID lookup is running
+	matches     []acoustid.Match // recordings the lookup proposed
+	cursor      int              // selected match
+	writing     bool             // the chosen match's tags are being written
+
+	moveTo string // where organizing would move the file, awaiting confirmation
+	moving bool   // the file is being moved
+}
+
+// finderState holds the library finder overlay.
+type finderState struct {
 	visible bool
+	loading bool // the library is still being walked
+	scanned bool
+	query   string
+	results []library.File
 	cursor  int
 }
 
+// statsState holds the listening statistics overlay.
+type statsState struct {
+	visible bool
+	loading bool
+	stats   history.Stats
+	err     error
+}
+
 // plManagerState holds state for the playlist manager overlay.
 type plManagerState struct {
 	visible     bool
@@ -158,3 +348,12 @@ type networkStats struct {
 	lastBytes int64
 	lastTick  int // tick counter for sampling interval
 }
+
+// remoteState holds the hub that external clients read and what was last
+// published to it, so that only changes become events.
+type remoteState struct {
+	hub   *remote.Hub // nil when no remote server is running
+	last  remote.Status
+	posAt time.Time   // when the last position event was sent
+	vis   *Visualizer // spectrum analyzer for subscribers, independent of the on-screen mode
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/internal/history"
+	"cliamp/playlist"
+)
+
+// minListen is the least listening time recorded in the history, so tracks
+// skipped straight past do not count as plays.
+const minListen = 10 * time.Second
+
+// statsTop is how many artists and tracks the stats view ranks.
+const statsTop = 5
+
+// statsLoadedMsg carries the summarised listening history.
+type statsLoadedMsg struct {
+	stats history.Stats
+	err   error
+}
+
+func loadStatsCmd(l *history.Log) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := l.Entries()
+		return statsLoadedMsg{stats: history.Summarize(entries, statsTop, time.Now()), err: err}
+	}
+}
+
+// SetHistory attaches the listening history log.
+func (m *Model) SetHistory(l *history.Log) {
+	m.history = l
+}
+
+// tickListened adds the time since the last tick to the time heard of the
+// current track. Gaps longer than a second (a suspended laptop) are dropped.
+func (m *Model) tickListened(now time.Time) {
+	if m.player.IsPlaying() && !m.player.IsPaused() && !m.buffering {
+		if d := now.Sub(m.listenTick); d > 0 && d < time.Second {
+			m.listened += d
+		}
+	}
+	m.listenTick = now
+}
+
+// recordListen writes the time heard of track to the history and starts
+// counting afresh. Called whenever playback leaves a track.
+func (m *Model) recordListen(track playlist.Track) {
+	d := m.listened
+	m.listened = 0
+	if track.Path == "" || d < minListen {
+		return
+	}
+	m.addRecent(track)
+	if m.history == nil {
+		return
+	}
+	title := track.Title
+	if title == "" {
+		title = track.DisplayName()
+	}
+	e := history.Entry{
+		Time:   time.Now().Add(-d),
+		Path:   track.Path,
+		Title:  title,
+		Artist: track.Artist,
+		Album:  track.Album,
+		Secs:   int(d.Seconds()),
+	}
+	m.history.Add(e)
+	m.smartListened(e.Path, e.Time, e.Secs)
+}
+
+// openStats shows the listening statistics, reading the history in the
+// background.
+func (m *Model) openStats() tea.Cmd {
+	if m.history == nil {
+		return nil
+	}
+	m.stats.visible = true
+	m.stats.loading = true
+	return loadStatsCmd(m.history)
+}
+
+// handleStatsKey processes key presses while the stats view is open.
+func (m *Model) handleStatsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "H":
+		m.stats.visible = false
+	}
+	return nil
+}
+
+func (m Model) renderStatsOverlay() string {
+	lines := []string{
+		titleStyle.Render("S T A T S"),
+		"",
+	}
+
+	st := m.stats.stats
+	switch {
+	case m.stats.loading:
+		lines = append(lines, dimStyle.Render("  Reading history…"))
+	case m.stats.err != nil:
+		lines = append(lines, errorStyle.Render("  "+m.stats.err.Error()))
+	case st.Plays == 0:
+		lines = append(lines, dimStyle.Render("  Nothing listened to yet"))
+	default:
+		lines = append(lines,
+			trackStyle.Render(fmt.Sprintf("  %s listened", history.Hours(st.Listened)))+
+				dimStyle.Render(fmt.Sprintf(" · %d plays since %s", st.Plays, st.Since.Format("Jan 2, 2006"))),
+			dimStyle.Render("  Last 7 days: "+history.Hours(st.Week)),
+		)
+		for _, list := range []struct {
+			title string
+			rows  []history.Count
+		}{{"Top artists", st.Artists}, {"Top tracks", st.Tracks}} {
+			if len(list.rows) == 0 {
+				continue
+			}
+			lines = append(lines, "", activeToggle.Render("  "+list.title))
+			for i, c := range list.rows {
+				plays := fmt.Sprintf("%d plays", c.Plays)
+				name := truncate(c.Name, panelWidth-12-len(plays))
+				lines = append(lines, fmt.Sprintf("  %d. %s  %s", i+1, name, dimStyle.Render(plays)))
+			}
+		}
+	}
+
+	lines = append(lines, "", dimStyle.Render("  Export: cliamp history --csv / --json"))
+	lines = append(lines, "", helpKey("Esc", "Close"))
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/hooks"
+)
+
+// SetStopAt makes playback stop when the clock next reaches "HH:MM",
+// today or tomorrow, whatever is playing.
+func (m *Model) SetStopAt(clock string) error {
+	at, err := nextAlarm(clock, time.Now())
+	if err != nil {
+		return err
+	}
+	m.stopAt.at = at
+	return nil
+}
+
+// checkStopAt stops playback once the stop time has passed, quitting
+// instead when SetExitAtEnd asked cliamp to exit once it is done.
+func (m *Model) checkStopAt(now time.Time) tea.Cmd {
+	if m.stopAt.at.IsZero() || now.Before(m.stopAt.at) {
+		return nil
+	}
+	m.stopAt.at = time.Time{}
+	if m.exitAtEnd {
+		m.runHook(hooks.Stop)
+		return m.quit()
+	}
+	if !m.player.IsPlaying() {
+		return nil
+	}
+	m.runHook(hooks.Stop)
+	m.saveEpisodePosition()
+	m.saveBookPosition()
+	m.player.Stop()
+	m.notifyMPRIS()
+	m.status.text = "Stopped at " + now.Format("15:04")
+	m.status.ttl = statusTTLLong
+	return nil
+}
+
+// openStopAt opens the stop time prompt.
+func (m *Model) openStopAt() {
+	m.stopAt.editing = true
+	m.stopAt.input = ""
+}
+
+// handleStopAtKey processes key presses while the stop time prompt is
+// open. Entering nothing cancels the scheduled stop.
+func (m *Model) handleStopAtKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.stopAt.editing = false
+		return m.quit()
+	case tea.KeyEscape:
+		m.stopAt.editing = false
+	case tea.KeyEnter:
+		if strings.TrimSpace(m.stopAt.input) == "" {
+			m.stopAt.editing = false
+			if !m.stopAt.at.IsZero() {
+				m.stopAt.at = time.Time{}
+				m.status.text = "Scheduled stop off"
+				m.status.ttl = statusTTLMedium
+			}
+			return nil
+		}
+		if err := m.SetStopAt(m.stopAt.input); err != nil {
+			m.status.text = err.Error()
+			m.status.ttl = statusTTLShort
+			m.stopAt.input = ""
+			return nil
+		}
+		m.stopAt.editing = false
+		in := time.Until(m.stopAt.at).Round(time.Minute)
+		m.status.text = fmt.Sprintf("Stopping at %s (in %s)", m.stopAt.at.Format("15:04"), strings.TrimSuffix(in.String(), "0s"))
+		m.status.ttl = statusTTLMedium
+	case tea.KeyBackspace:
+		m.stopAt.input = removeLastRune(m.stopAt.input)
+	case tea.KeyRunes:
+		m.stopAt.input += string(msg.Runes)
+	}
+	return nil
+}
+
+func (m Model) renderStopAtOverlay() string {
+	current := "No stop scheduled"
+	if !m.stopAt.at.IsZero() {
+		current = "Stopping at " + m.stopAt.at.Format("15:04")
+	}
+	input := dimStyle.Faint(true).Render("  23:00")
+	if m.stopAt.input != "" {
+		input = playlistSelectedStyle.Render("  " + m.stopAt.input + "_")
+	}
+	lines := []string{
+		titleStyle.Render("S T O P   A T"),
+		"",
+		dimStyle.Render("  " + current),
+		"",
+		input,
+		dimStyle.Render("  24-hour time; empty cancels the stop"),
+		"",
+		helpKey("Enter", "Set ") + helpKey("Esc", "Cancel"),
+	}
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
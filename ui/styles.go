@@ -29,6 +29,10 @@ var (
 // Default: 74 (80 frame - 6 padding).
 var panelWidth = 74
 
+// themeGen counts applyTheme calls, so cached sections know when the
+// styles they were rendered with have changed.
+var themeGen int
+
 // Lip Gloss styles
 var (
 	frameStyle = lipgloss.NewStyle().
@@ -84,19 +88,21 @@ var (
 // applyTheme updates all color variables and rebuilds derived styles.
 // If the theme is the default (empty hex values), ANSI fallback colors are restored.
 func applyTheme(t theme.Theme) {
+	themeGen++
 	if t.IsDefault() {
-		// Restore ANSI defaults.
-		colorTitle = lipgloss.ANSIColor(10)
-		colorText = lipgloss.ANSIColor(15)
-		colorDim = lipgloss.ANSIColor(7)
-		colorAccent = lipgloss.ANSIColor(11)
-		colorPlaying = lipgloss.ANSIColor(10)
-		colorSeekBar = lipgloss.ANSIColor(11)
-		colorVolume = lipgloss.ANSIColor(2)
-		colorError = lipgloss.ANSIColor(9)
-		spectrumLow = lipgloss.ANSIColor(10)
-		spectrumMid = lipgloss.ANSIColor(11)
-		spectrumHigh = lipgloss.ANSIColor(9)
+		// Restore the ANSI colors of the palette for the background.
+		p := ansiPalettes[paletteName]
+		colorTitle = p.title
+		colorText = p.text
+		colorDim = p.dim
+		colorAccent = p.accent
+		colorPlaying = p.playing
+		colorSeekBar = p.seek
+		colorVolume = p.volume
+		colorError = p.err
+		spectrumLow = p.low
+		spectrumMid = p.mid
+		spectrumHigh = p.high
 	} else {
 		colorTitle = lipgloss.Color(t.Accent)
 		colorText = lipgloss.Color(t.BrightFG)
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/player"
+)
+
+// techInfoMsg carries the encoding details of the file at path.
+type techInfoMsg struct {
+	path string
+	info player.TechInfo
+}
+
+func techInfoCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		return techInfoMsg{path: path, info: player.ProbeTechInfo(path)}
+	}
+}
+
+// probeTechInfo reads the codec, bitrate and sample rate of the current
+// track in the background whenever the track changes. Streams are skipped.
+func (m *Model) probeTechInfo() tea.Cmd {
+	track, _ := m.playlist.Current()
+	if track.Path == m.tech.path {
+		return nil
+	}
+	m.tech.path = track.Path
+	m.tech.info = player.TechInfo{}
+	if track.Path == "" || track.Stream {
+		return nil
+	}
+	return techInfoCmd(track.Path)
+}
+
+// techFields returns the info overlay rows describing an encoding,
+// leaving out anything unknown.
+func techFields(t player.TechInfo) [][2]string {
+	var rows [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			rows = append(rows, [2]string{label, value})
+		}
+	}
+	codec := t.Codec
+	if t.VBR {
+		codec += " (VBR)"
+	}
+	add("Codec", codec)
+	if t.Bitrate > 0 {
+		br := strconv.Itoa(t.Bitrate) + " kbps"
+		if t.VBR {
+			br += " average"
+		}
+		add("Bitrate", br)
+	}
+	if t.SampleRate > 0 {
+		add("Sample rate", fmt.Sprintf("%g kHz", float64(t.SampleRate)/1000))
+	}
+	if t.BitDepth > 0 {
+		add("Bit depth", fmt.Sprintf("%d-bit", t.BitDepth))
+	}
+	add("Channels", player.Channels(t.Channels))
+	if t.Size > 0 {
+		add("Size", fmt.Sprintf("%.1f MB", float64(t.Size)/(1024*1024)))
+	}
+	return rows
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultTermTitle is the title format used for `terminal_title = true`.
+const defaultTermTitle = "{state} {artist} - {title}"
+
+// SetTerminalTitle sets the format of the terminal title kept up to date
+// with the current track: placeholders {state} (▶, ⏸ or ■), {artist},
+// {title} and {album}. "true" uses the default format; "" and "false"
+// leave the title alone. It reports whether the title is kept.
+func (m *Model) SetTerminalTitle(format string) bool {
+	switch format {
+	case "true":
+		format = defaultTermTitle
+	case "false":
+		format = ""
+	}
+	m.termTitle.format = format
+	return format != ""
+}
+
+// terminalTitle renders the title for the current state: the track with
+// its play state, or just "cliamp" with nothing loaded.
+func (m *Model) terminalTitle() string {
+	track, idx := m.playlist.Current()
+	if idx < 0 || m.quitting {
+		return "cliamp"
+	}
+	state := "■"
+	switch {
+	case m.player.IsPlaying() && m.player.IsPaused():
+		state = "⏸"
+	case m.player.IsPlaying():
+		state = "▶"
+	}
+	artist, title := track.Artist, track.Title
+	if m.streamTitle != "" && track.Stream {
+		if a, t, ok := strings.Cut(m.streamTitle, " - "); ok {
+			artist, title = a, t
+		} else {
+			artist, title = "", m.streamTitle
+		}
+	}
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(track.Path), filepath.Ext(track.Path))
+	}
+	format := m.termTitle.format
+	if artist == "" {
+		// A track without an artist would otherwise read "▶  - Title".
+		format = strings.ReplaceAll(format, "{artist} - ", "")
+	}
+	text := strings.NewReplacer(
+		"{state}", state,
+		"{artist}", artist,
+		"{title}", title,
+		"{album}", track.Album,
+	).Replace(format)
+	// Control characters in tags would end the escape sequence early.
+	text = strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+	if m.ascii {
+		text = toASCII(text)
+	}
+	return strings.TrimSpace(text)
+}
+
+// updateTermTitle returns a command setting the terminal title when it
+// has changed: on a new track, a new stream title or a change of play
+// state.
+func (m *Model) updateTermTitle() tea.Cmd {
+	if m.termTitle.format == "" {
+		return nil
+	}
+	title := m.terminalTitle()
+	if title == m.termTitle.last {
+		return nil
+	}
+	m.termTitle.last = title
+	return tea.SetWindowTitle(title)
+}
+
+// PushTerminalTitle saves the terminal's title on the title stack that
+// xterm-compatible terminals keep, for PopTerminalTitle to restore.
+func PushTerminalTitle(w io.Writer) {
+	io.WriteString(w, "\x1b[22;2t")
+}
+
+// PopTerminalTitle clears the title cliamp set and restores the one saved
+// by PushTerminalTitle, where the terminal keeps a title stack. tmux takes
+// the title as the pane title.
+func PopTerminalTitle(w io.Writer) {
+	io.WriteString(w, "\x1b]2;\x07\x1b[23;2t")
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"cliamp/config"
+)
+
+// timeDisplay is what the time readout shows of a track with a known
+// length.
+type timeDisplay int
+
+const (
+	timeElapsed   timeDisplay = iota // "02:11 / 03:59"
+	timeRemaining                    // "-01:48 / 03:59"
+	timeBoth                         // "02:11 / -01:48"
+)
+
+// timeDisplayNames are the time_display config values, by timeDisplay.
+var timeDisplayNames = [...]string{"elapsed", "remaining", "both"}
+
+// SetTimeDisplay picks what the time readout shows: "elapsed",
+// "remaining" or "both". It reports whether name was one of them.
+func (m *Model) SetTimeDisplay(name string) bool {
+	for i, n := range timeDisplayNames {
+		if strings.EqualFold(name, n) {
+			m.timeMode = timeDisplay(i)
+			return true
+		}
+	}
+	return false
+}
+
+// cycleTimeDisplay switches the time readout to the next mode and saves
+// the choice.
+func (m *Model) cycleTimeDisplay() {
+	m.timeMode = (m.timeMode + 1) % timeDisplay(len(timeDisplayNames))
+	name := timeDisplayNames[m.timeMode]
+	m.status.text = "Time: " + name
+	m.status.ttl = statusTTLShort
+	if err := config.Save("time_display", fmt.Sprintf("%q", name)); err != nil {
+		m.status.text = fmt.Sprintf("Config save failed: %s", err)
+		m.status.ttl = statusTTLDefault
+	}
+}
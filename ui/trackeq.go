@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"cliamp/internal/appdir"
+	"cliamp/internal/trackeq"
+	"cliamp/playlist"
+)
+
+// SetTrackEQs attaches the store of EQ curves remembered for tracks and
+// albums.
+func (m *Model) SetTrackEQs(s *trackeq.Store) {
+	m.eqs = s
+}
+
+// trackEQStarted applies the curve remembered for track, its own before
+// its album's, or returns to the usual curve when it has none.
+func (m *Model) trackEQStarted(track playlist.Track) {
+	if m.eqs == nil {
+		return
+	}
+	scope, key := "track", appdir.TrackKey(track.Path)
+	c, ok := m.eqs.Curve(key)
+	if !ok {
+		scope, key = "album", trackeq.AlbumKey(track.Artist, track.Album)
+		c, ok = m.eqs.Curve(key)
+	}
+	if !ok || m.player.DSPBypass() {
+		m.restoreGlobalEQ()
+		return
+	}
+	if m.trackEQ.scope == "" {
+		m.trackEQ.global = m.player.EQBands()
+		m.trackEQ.globalPreset = m.eqPresetIdx
+	}
+	m.trackEQ.scope, m.trackEQ.key = scope, key
+	m.applyEQCurve(c.Bands, presetIndex(c.Preset))
+}
+
+// restoreGlobalEQ puts back the usual curve after a remembered one.
+func (m *Model) restoreGlobalEQ() {
+	if m.trackEQ.scope == "" {
+		return
+	}
+	m.trackEQ.scope, m.trackEQ.key = "", ""
+	m.applyEQCurve(m.trackEQ.global, m.trackEQ.globalPreset)
+}
+
+func (m *Model) applyEQCurve(bands [10]float64, preset int) {
+	for i, gain := range bands {
+		m.player.SetEQBand(i, gain)
+	}
+	m.eqPresetIdx = preset
+}
+
+// presetIndex returns the index of the preset called name, or -1 (custom).
+func presetIndex(name string) int {
+	for i, p := range eqPresets {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// rememberTrackEQ saves a change to the EQ as the playing track's
+// remembered curve, when one is applied, and reports whether it did: the
+// usual curve is then left as it was.
+func (m *Model) rememberTrackEQ() bool {
+	if m.eqs == nil || m.trackEQ.scope == "" {
+		return false
+	}
+	m.eqs.Set(m.trackEQ.key, trackeq.Curve{Preset: m.EQPresetName(), Bands: m.player.EQBands()})
+	return true
+}
+
+// cycleTrackEQ moves the playing track's EQ memory on a step: the current
+// curve is remembered for the track, then for its whole album instead,
+// then forgotten, returning to the usual curve.
+func (m *Model) cycleTrackEQ() {
+	track, idx := m.playlist.Current()
+	if m.eqs == nil || idx < 0 || !m.player.IsPlaying() {
+		return
+	}
+	trackKey := appdir.TrackKey(track.Path)
+	albumKey := trackeq.AlbumKey(track.Artist, track.Album)
+	curve := trackeq.Curve{Preset: m.EQPresetName(), Bands: m.player.EQBands()}
+
+	m.status.ttl = statusTTLMedium
+	switch {
+	case m.trackEQ.scope == "":
+		m.trackEQ.global = curve.Bands
+		m.trackEQ.globalPreset = m.eqPresetIdx
+		m.eqs.Set(trackKey, curve)
+		m.trackEQ.scope, m.trackEQ.key = "track", trackKey
+		m.status.text = "EQ remembered for this track"
+	case m.trackEQ.scope == "track" && albumKey != "":
+		m.eqs.Forget(trackKey)
+		m.eqs.Set(albumKey, curve)
+		m.trackEQ.scope, m.trackEQ.key = "album", albumKey
+		m.status.text = "EQ remembered for " + track.Album
+	default:
+		m.eqs.Forget(m.trackEQ.key)
+		m.restoreGlobalEQ()
+		m.status.text = "EQ no longer remembered"
+	}
+}
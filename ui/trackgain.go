@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"cliamp/internal/trackgain"
+)
+
+// SetTrackGains attaches the store of per-track gain offsets. The player
+// looks offsets up in it itself, as each track starts.
+func (m *Model) SetTrackGains(s *trackgain.Store) {
+	m.gains = s
+}
+
+// openTrackGain opens the gain offset prompt for the playing track.
+func (m *Model) openTrackGain() {
+	if m.gains == nil {
+		return
+	}
+	if _, idx := m.playlist.Current(); idx < 0 || !m.player.IsPlaying() {
+		return
+	}
+	m.trackGainUI.editing = true
+	m.trackGainUI.input = ""
+}
+
+// parseGain reads an offset such as "+4", "-2.5" or "3 dB". Empty means 0.
+func parseGain(s string) (float64, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "db"))
+	if s == "" {
+		return 0, nil
+	}
+	db, err := strconv.ParseFloat(s, 64)
+	if err != nil || db < -trackgain.MaxDB || db > trackgain.MaxDB {
+		return 0, fmt.Errorf("use a gain in dB from -%d to +%d", trackgain.MaxDB, trackgain.MaxDB)
+	}
+	return db, nil
+}
+
+// handleTrackGainKey processes key presses while the gain prompt is open.
+// Entering nothing or 0 forgets the track's offset.
+func (m *Model) handleTrackGainKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.trackGainUI.editing = false
+		return m.quit()
+	case tea.KeyEscape:
+		m.trackGainUI.editing = false
+	case tea.KeyEnter:
+		db, err := parseGain(m.trackGainUI.input)
+		if err != nil {
+			m.status.text = err.Error()
+			m.status.ttl = statusTTLShort
+			m.trackGainUI.input = ""
+			return nil
+		}
+		m.trackGainUI.editing = false
+		track, idx := m.playlist.Current()
+		if idx < 0 {
+			return nil
+		}
+		m.gains.Set(track.Path, db)
+		m.player.SetTrackGain(db)
+		if db == 0 {
+			m.status.text = "Track gain cleared"
+		} else {
+			m.status.text = fmt.Sprintf("Track gain %+gdB, applied whenever it plays", db)
+		}
+		m.status.ttl = statusTTLMedium
+	case tea.KeyBackspace:
+		m.trackGainUI.input = removeLastRune(m.trackGainUI.input)
+	case tea.KeyRunes:
+		m.trackGainUI.input += string(msg.Runes)
+	}
+	return nil
+}
+
+func (m Model) renderTrackGainOverlay() string {
+	track, _ := m.playlist.Current()
+	current := "No gain offset"
+	if db := m.player.TrackGain(); db != 0 {
+		current = fmt.Sprintf("Gain offset %+gdB", db)
+	}
+	input := dimStyle.Faint(true).Render("  +4")
+	if m.trackGainUI.input != "" {
+		input = playlistSelectedStyle.Render("  " + m.trackGainUI.input + "_")
+	}
+	lines := []string{
+		titleStyle.Render("T R A C K   G A I N"),
+		"",
+		dimStyle.Render("  " + truncate(track.DisplayName(), panelWidth-4)),
+		dimStyle.Render("  " + current),
+		"",
+		input,
+		dimStyle.Render(fmt.Sprintf("  dB, -%d to +%d; empty or 0 clears it", trackgain.MaxDB, trackgain.MaxDB)),
+		"",
+		helpKey("Enter", "Set ") + helpKey("Esc", "Cancel"),
+	}
+	return m.centerOverlay(strings.Join(lines, "\n"))
+}
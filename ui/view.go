@@ -3,9 +3,9 @@ package ui
 import (
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"time"
-	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -13,9 +13,8 @@ import (
 	"cliamp/theme"
 )
 
-// titleScrollSep is the separator runes for cyclic title scrolling,
-// pre-allocated to avoid per-frame conversion.
-var titleScrollSep = []rune("   ♫   ")
+// titleScrollSep separates the end of a scrolling title from its start.
+const titleScrollSep = "   ♫   "
 
 // Pre-built styles for elements created per-render to avoid repeated allocation.
 var (
@@ -35,8 +34,19 @@ func playlistLabel(prefix string, p playlist.PlaylistInfo) string {
 	return prefix + p.Name
 }
 
-// View renders the full TUI frame.
+// View renders the full TUI frame, in plain ASCII when asked to, and for a
+// screen reader in accessible mode.
 func (m Model) View() string {
+	if m.a11y.on {
+		return plainText(m.view())
+	}
+	if m.ascii {
+		return toASCII(m.view())
+	}
+	return m.view()
+}
+
+func (m Model) view() string {
 	if m.quitting {
 		return ""
 	}
@@ -69,6 +79,50 @@ func (m Model) View() string {
 		return m.renderQueueOverlay()
 	}
 
+	if m.effects.visible {
+		return m.renderEffectsOverlay()
+	}
+
+	if m.eqBands.visible {
+		return m.renderEQBandsOverlay()
+	}
+
+	if m.castPicker.visible {
+		return m.renderCastPicker()
+	}
+
+	if m.chapters.visible {
+		return m.renderChaptersOverlay()
+	}
+
+	if m.bookmarkUI.naming {
+		return m.renderBookmarkNameOverlay()
+	}
+
+	if m.bookmarkUI.visible {
+		return m.renderBookmarksOverlay()
+	}
+
+	if m.alarm.editing {
+		return m.renderAlarmOverlay()
+	}
+
+	if m.stopAt.editing {
+		return m.renderStopAtOverlay()
+	}
+
+	if m.trackGainUI.editing {
+		return m.renderTrackGainOverlay()
+	}
+
+	if m.stats.visible {
+		return m.renderStatsOverlay()
+	}
+
+	if m.finder.visible {
+		return m.renderFinderOverlay()
+	}
+
 	if m.showInfo {
 		return m.renderInfoOverlay()
 	}
@@ -108,15 +162,15 @@ func (m Model) View() string {
 		m.renderSeekBar(),
 		"",
 		// Controls
-		m.renderControls(),
+		m.cachedControls(),
 		m.renderProviderPill(),
 		"",
 		// Playlist
 		m.renderPlaylistHeader(),
-		m.renderPlaylist(),
+		m.cachedPlaylist(),
 		"",
 		// Help
-		m.renderHelp(),
+		m.cachedHelp(),
 		m.renderStreamStatus(),
 	}
 
@@ -159,6 +213,9 @@ func (m Model) centerOverlay(content string) string {
 }
 
 func (m Model) renderTitle() string {
+	if m.a11y.on {
+		return titleStyle.Render("cliamp")
+	}
 	return titleStyle.Render("C L I A M P")
 }
 
@@ -181,24 +238,16 @@ func (m Model) renderTrackInfo() string {
 	if album != "" {
 		name += " · " + album
 	}
+	if i := m.currentChapter(); i >= 0 {
+		name += " · " + m.chapters.list[i].Title
+	}
 
 	maxW := panelWidth - 4
-	runes := []rune(name)
-
-	if len(runes) <= maxW {
+	if textWidth(name) <= maxW {
 		return trackStyle.Render("♫ " + name)
 	}
 
-	// Cyclic scrolling for long titles
-	padded := append(runes, titleScrollSep...)
-	total := len(padded)
-	off := m.titleOff % total
-
-	display := make([]rune, maxW)
-	for i := range maxW {
-		display[i] = padded[(off+i)%total]
-	}
-	return trackStyle.Render("♫ " + string(display))
+	return trackStyle.Render("♫ " + m.marquee(name, maxW))
 }
 
 func (m Model) renderTimeStatus() string {
@@ -206,17 +255,16 @@ func (m Model) renderTimeStatus() string {
 	pos := m.cachedPos
 	dur := m.cachedDur
 
-	posMin := int(pos.Minutes())
-	posSec := int(pos.Seconds()) % 60
-	durMin := int(dur.Minutes())
-	durSec := int(dur.Seconds()) % 60
-
-	timeStr := fmt.Sprintf("%02d:%02d / %02d:%02d", posMin, posSec, durMin, durSec)
-
 	track, _ := m.playlist.Current()
+	timeStr := formatTimeStr(pos, dur, track.Stream && dur <= 0, m.timeMode)
+	if m.a11y.on {
+		timeStr = accessibleTime(dur, track.Stream && dur <= 0)
+	}
 
 	var status string
 	switch {
+	case m.scrub.active:
+		status = statusStyle.Render("⇆ Scrubbing")
 	case m.seek.active:
 		status = statusStyle.Render("⟳ Seeking...")
 	case m.buffering:
@@ -236,7 +284,40 @@ func (m Model) renderTimeStatus() string {
 		status = dimStyle.Render("■ Stopped")
 	}
 
+	if m.player.DSPBypass() {
+		if m.player.Resampling() {
+			status = dimStyle.Render("DSP BYPASS · RESAMPLED") + " " + status
+		} else {
+			status = statusStyle.Render("DSP BYPASS") + " " + status
+		}
+	}
+	if m.player.LimiterActive() {
+		status = errorStyle.Render("LIM") + " " + status
+	}
+	if m.player.NightMode() && !m.player.DSPBypass() {
+		status = activeToggle.Render("NIGHT") + " " + status
+	}
+	if m.level.on {
+		status = dimStyle.Render(m.renderLevel()) + "  " + status
+	}
+	if m.player.Recording() {
+		status = errorStyle.Render("● REC") + " " + status
+	}
+	if m.player.Broadcasting() {
+		status = errorStyle.Render("● LIVE") + " " + status
+	}
+	if target := m.player.CastTarget(); target != "" {
+		status = statusStyle.Render("⇢ "+target) + " " + status
+	}
+
 	left := timeStyle.Render(timeStr)
+	// The codec and rate sit beside the time while there is room for them.
+	if m.tech.path == track.Path && m.tech.info.Known() {
+		tech := "  " + dimStyle.Render(m.tech.info.Short())
+		if lipgloss.Width(left)+lipgloss.Width(tech)+1+lipgloss.Width(status) <= panelWidth {
+			left += tech
+		}
+	}
 	gap := panelWidth - lipgloss.Width(left) - lipgloss.Width(status)
 	if gap < 1 {
 		gap = 1
@@ -245,6 +326,25 @@ func (m Model) renderTimeStatus() string {
 	return left + strings.Repeat(" ", gap) + status
 }
 
+// formatTimeStr renders "elapsed / total", "-remaining / total" or
+// "elapsed / -remaining" as mode asks. Live streams have no end, so they
+// show the elapsed time against ∞ rather than a misleading 00:00.
+func formatTimeStr(pos, dur time.Duration, live bool, mode timeDisplay) string {
+	clock := func(secs int) string { return fmt.Sprintf("%02d:%02d", secs/60, secs%60) }
+	elapsed, total := int(pos.Seconds()), int(dur.Seconds())
+	if live {
+		return clock(elapsed) + " / ∞"
+	}
+	remaining := "-" + clock(max(total-elapsed, 0))
+	switch mode {
+	case timeRemaining:
+		return remaining + " / " + clock(total)
+	case timeBoth:
+		return clock(elapsed) + " / " + remaining
+	}
+	return clock(elapsed) + " / " + clock(total)
+}
+
 func (m Model) renderSpectrum() string {
 	if m.vis.Mode == VisNone {
 		return ""
@@ -271,7 +371,7 @@ func (m Model) renderFullVisualizer() string {
 }
 
 func (m Model) renderSeekBar() string {
-	if panelWidth <= 0 {
+	if panelWidth <= 0 || m.a11y.on {
 		return ""
 	}
 	// During buffering, show a dim bar — avoids speaker.Lock() contention.
@@ -279,8 +379,12 @@ func (m Model) renderSeekBar() string {
 		return seekDimStyle.Render(strings.Repeat("━", panelWidth))
 	}
 	// Show a static streaming bar for non-seekable streams with no known duration.
+	// Piped stdin has no position to seek to either and is labelled as such.
 	if !m.player.Seekable() && m.player.IsPlaying() && m.cachedDur == 0 {
 		label := " STREAMING "
+		if track, _ := m.playlist.Current(); playlist.IsStdin(track.Path) {
+			label = " STDIN "
+		}
 		pad := panelWidth - lipgloss.Width(label)
 		if pad < 0 {
 			return seekFillStyle.Render(label[:panelWidth])
@@ -299,11 +403,52 @@ func (m Model) renderSeekBar() string {
 	}
 	progress = max(0, min(1, progress))
 
-	filled := int(progress * float64(max(1, panelWidth-1)))
+	// While arrow-seeking, the current step is shown at the right end.
+	width := panelWidth
+	var hint string
+	if time.Now().Before(m.seekHold.shownUntil) {
+		sign := "+"
+		if m.seekHold.dir < 0 {
+			sign = "-"
+		}
+		hint = " " + sign + formatSeekStep(m.seekHold.step)
+		width = max(1, panelWidth-lipgloss.Width(hint))
+	}
+
+	filled := int(progress * float64(max(1, width-1)))
 
 	return seekFillStyle.Render(strings.Repeat("━", filled)) +
 		seekFillStyle.Render("●") +
-		seekDimStyle.Render(strings.Repeat("━", max(0, panelWidth-filled-1)))
+		seekDimStyle.Render(m.renderSeekRest(filled+1, width)) +
+		statusStyle.Render(hint)
+}
+
+// renderSeekRest draws the unplayed part of the seek bar, cells from..width.
+// For buffered network files the parts not downloaded yet are drawn thin,
+// so the heavy line shows how far playback can run or jump without waiting.
+func (m Model) renderSeekRest(from, width int) string {
+	n := max(0, width-from)
+	ranges := m.player.StreamBuffered()
+	if ranges == nil {
+		return strings.Repeat("━", n)
+	}
+	var b strings.Builder
+	for i := from; i < width; i++ {
+		f := (float64(i) + 0.5) / float64(width)
+		held := false
+		for _, r := range ranges {
+			if f >= r[0] && f < r[1] {
+				held = true
+				break
+			}
+		}
+		if held {
+			b.WriteString("━")
+		} else {
+			b.WriteString("─")
+		}
+	}
+	return b.String()
 }
 
 func (m Model) renderControls() string {
@@ -313,8 +458,8 @@ func (m Model) renderControls() string {
 	presetName := m.EQPresetName()
 
 	eqParts := make([]string, 10)
-	eqLabels := [10]string{"70", "180", "320", "600", "1k", "3k", "6k", "12k", "14k", "16k"}
-	for i, label := range eqLabels {
+	for i, hz := range m.player.EQFreqs() {
+		label := eqFreqLabel(hz)
 		style := eqInactiveStyle
 		if bands[i] != 0 {
 			label = fmt.Sprintf("%+.0f", bands[i])
@@ -329,19 +474,37 @@ func (m Model) renderControls() string {
 	if m.focus == focusEQ {
 		eqLabel = activeToggle.Render("EQ ▸ ")
 	}
+	// A curve remembered for the playing track or album says so.
+	if m.trackEQ.scope != "" {
+		presetName += " · " + m.trackEQ.scope
+	}
 	left := eqLabel + dimStyle.Render("[") + activeToggle.Render(presetName) + dimStyle.Render("] ") + strings.Join(eqParts, " ")
+	if pre := m.player.EQPreamp(); pre <= -0.05 {
+		left += dimStyle.Render(fmt.Sprintf(" PRE %.1fdB", pre))
+	}
+	if trk := m.player.TrackGain(); trk != 0 && m.player.IsPlaying() {
+		left += dimStyle.Render(fmt.Sprintf(" TRK %+gdB", trk))
+	}
 
 	vol := m.player.Volume()
-	frac := max(0, min(1, (vol+30)/36))
-	dbStr := fmt.Sprintf(" %+.0fdB", vol)
+	frac := volumeFraction(vol)
+	volStr := dimStyle.Render(" " + m.formatVolume(vol))
 	monoStr := ""
 	if m.player.Mono() {
 		monoStr = " " + activeToggle.Render("[M]")
 	}
+	if m.player.Muted() {
+		volStr = " " + activeToggle.Render("MUTE")
+	}
+	// CLIP flashes in a slot kept free, so the bar does not jump.
+	clipStr := "     "
+	if m.player.Clipping() {
+		clipStr = " " + errorStyle.Render("CLIP")
+	}
 
 	leftW := lipgloss.Width(left)
 	volLabel := labelStyle.Render("VOL ")
-	volSuffix := dimStyle.Render(dbStr) + monoStr
+	volSuffix := volStr + clipStr + monoStr + " " + renderBalance(m.player.Balance())
 	volLabelW := lipgloss.Width(volLabel)
 	volSuffixW := lipgloss.Width(volSuffix)
 	barW := max(6, (panelWidth-leftW-2-volLabelW-volSuffixW)*3/4)
@@ -357,6 +520,36 @@ func (m Model) renderControls() string {
 	return left + strings.Repeat(" ", gap) + right
 }
 
+// volumeFraction maps a volume in dB onto the 0–1 span of the volume bar,
+// from the -30 dB floor to the +6 dB ceiling.
+func volumeFraction(db float64) float64 {
+	return max(0, min(1, (db+30)/36))
+}
+
+// formatVolume renders a volume as "-17dB", or in percent mode as the
+// bar's fill, "36%".
+func (m Model) formatVolume(db float64) string {
+	if m.volPercent {
+		return fmt.Sprintf("%.0f%%", volumeFraction(db)*100)
+	}
+	return fmt.Sprintf("%+.0fdB", db)
+}
+
+// renderBalance draws a compact L···●···R balance indicator.
+func renderBalance(b float64) string {
+	const half = 3
+	pos := half + int(math.Round(b*half))
+	cells := make([]string, 2*half+1)
+	for i := range cells {
+		if i == pos {
+			cells[i] = activeToggle.Render("●")
+		} else {
+			cells[i] = dimStyle.Render("·")
+		}
+	}
+	return dimStyle.Render("L") + strings.Join(cells, "") + dimStyle.Render("R")
+}
+
 func (m Model) renderProviderPill() string {
 	if len(m.providers) <= 1 {
 		return ""
@@ -387,13 +580,21 @@ func (m Model) renderPlaylistHeader() string {
 	}
 
 	var shuffle string
-	if m.playlist.Shuffled() {
+	switch {
+	case m.playlist.ShuffledByAlbum():
+		shuffle = activeToggle.Render("[Shuffle: Albums]")
+	case m.playlist.Shuffled() && m.smart != nil:
+		shuffle = activeToggle.Render("[Shuffle: Smart]")
+	case m.playlist.Shuffled():
 		shuffle = activeToggle.Render("[Shuffle]")
-	} else {
+	default:
 		shuffle = dimStyle.Render("[") + trackStyle.Render("Shuffle") + dimStyle.Render("]")
 	}
 
 	repeatVal := m.playlist.Repeat().String()
+	if n := m.playlist.RepeatsLeft(); n > 0 {
+		repeatVal += fmt.Sprintf(" ×%d", n)
+	}
 	if m.playlist.Repeat() != 0 {
 		repeatStr := fmt.Sprintf("[Repeat: %s]", repeatVal)
 		repeatStr = activeToggle.Render(repeatStr)
@@ -408,6 +609,31 @@ func (m Model) renderPlaylistHeader() string {
 		queueStr = " " + activeToggle.Render(fmt.Sprintf("[Queue: %d]", qLen))
 	}
 
+	var djStr string
+	if m.autoDJ.on {
+		djStr = " " + activeToggle.Render("[Auto-DJ]")
+	}
+
+	var followStr string
+	if m.follow.on {
+		followStr = " " + activeToggle.Render("[Follow]")
+	}
+	if m.albums.on {
+		followStr += " " + activeToggle.Render("[Albums]")
+	}
+
+	var alarmStr string
+	if !m.alarm.at.IsZero() {
+		alarmStr = " " + activeToggle.Render("[Alarm: "+m.alarm.at.Format("15:04")+"]")
+	}
+	if !m.stopAt.at.IsZero() {
+		alarmStr += " " + activeToggle.Render("[Stop: "+m.stopAt.at.Format("15:04")+"]")
+	}
+
+	if end, ok := m.playlistEnd(); ok {
+		alarmStr += " " + dimStyle.Render("[Ends: "+end.Format("15:04")+"]")
+	}
+
 	var themeStr string
 	if name := m.ThemeName(); name != theme.DefaultName {
 		themeStr = " " + activeToggle.Render("[Theme: "+name+"]")
@@ -419,7 +645,21 @@ func (m Model) renderPlaylistHeader() string {
 		headerStyle = activeToggle
 		headerLabel = "▸─ Playlist ── "
 	}
-	return headerStyle.Render(headerLabel) + shuffle + queueStr + themeStr + " " + dimStyle.Render("──")
+	return headerStyle.Render(headerLabel) + shuffle + queueStr + djStr + followStr + alarmStr + themeStr + " " + dimStyle.Render("──")
+}
+
+// playlistEnd returns when playback reaches the end of the playlist: the
+// rest of the current track, then the known durations of those after it.
+// ok is false while nothing plays or the end can't be known.
+func (m Model) playlistEnd() (time.Time, bool) {
+	if !m.player.IsPlaying() || m.player.IsPaused() || m.cachedDur <= 0 {
+		return time.Time{}, false
+	}
+	rest, ok := m.playlist.Remaining()
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Now().Add(max(0, m.cachedDur-m.cachedPos) + rest), true
 }
 
 func (m Model) renderProviderList() string {
@@ -430,6 +670,9 @@ func (m Model) renderProviderList() string {
 		return dimStyle.Render(fmt.Sprintf("  Loading %s...", m.provider.Name()))
 	}
 	if len(m.providerLists) == 0 {
+		if m.podcastsActive() {
+			return dimStyle.Render("  No podcasts yet.\n  Press + to subscribe to a feed URL.")
+		}
 		return dimStyle.Render("  No playlists found.\n  Add playlists to ~/.config/cliamp/playlists/")
 	}
 
@@ -489,10 +732,15 @@ func (m Model) renderPlaylist() string {
 	}
 
 	currentIdx := m.playlist.Index()
+	rows := m.plRows()
+	cursor := -1
+	if m.focus == focusPlaylist {
+		cursor = m.cursorRow(rows)
+	}
 
 	scroll := max(0, m.plScroll)
-	if scroll >= len(tracks) {
-		scroll = max(0, len(tracks)-1)
+	if scroll >= len(rows) {
+		scroll = max(0, len(rows)-1)
 	}
 
 	// plVisible is the number of rendered lines available for tracks.
@@ -500,8 +748,26 @@ func (m Model) renderPlaylist() string {
 	// so the playlist never overflows its area.
 	budget := m.plVisible
 
+	// A playlist longer than the window gets a scrollbar down the right
+	// edge, which takes two columns from the track names.
+	barW := 0
+	if len(rows) > budget {
+		barW = 2
+	}
+
 	lines := make([]string, 0, budget) // tracks
-	for i := scroll; i < len(tracks) && len(lines) < budget; i++ {
+	for r := scroll; r < len(rows) && len(lines) < budget; r++ {
+		i := rows[r].track
+		if rows[r].header {
+			collapsed := m.collapsedHeader(rows[r])
+			style := activeToggle
+			if r == cursor {
+				style = playlistSelectedStyle
+			}
+			lines = append(lines, style.Render(truncate(albumHeader(tracks, i, collapsed), panelWidth-barW-2)))
+			continue
+		}
+
 		prefix := "  "
 		style := playlistItemStyle
 
@@ -510,7 +776,7 @@ func (m Model) renderPlaylist() string {
 			style = playlistActiveStyle
 		}
 
-		if m.focus == focusPlaylist && i == m.plCursor {
+		if r == cursor {
 			style = playlistSelectedStyle
 		}
 
@@ -520,23 +786,46 @@ func (m Model) renderPlaylist() string {
 			queueSuffix = fmt.Sprintf(" [Q%d]", qp)
 		}
 		albumSuffix := ""
-		if album := tracks[i].Album; album != "" {
+		if album := tracks[i].Album; album != "" && !m.albums.on {
 			albumSuffix = " · " + album
 		}
-		suffixLen := utf8.RuneCountInString(queueSuffix) + utf8.RuneCountInString(albumSuffix)
-		name = truncate(name, panelWidth-6-suffixLen)
+		// The playing station shows the song it is airing right now.
+		if i == currentIdx && tracks[i].Stream && m.streamTitle != "" {
+			albumSuffix = " · " + truncate(m.streamTitle, (panelWidth-6)/2)
+		}
+		djSuffix := ""
+		if tracks[i].AutoDJ {
+			djSuffix = " ✦"
+		}
+		suffixLen := textWidth(queueSuffix) + textWidth(albumSuffix) + textWidth(djSuffix)
+		if barW > 0 {
+			name = truncate(name, panelWidth-barW-4-len(strconv.Itoa(i+1))-suffixLen)
+		} else {
+			name = truncate(name, panelWidth-6-suffixLen)
+		}
 
 		line := fmt.Sprintf("%s%d. %s", prefix, i+1, name)
 		line = style.Render(line)
 		if albumSuffix != "" {
 			line += dimStyle.Render(albumSuffix)
 		}
+		if djSuffix != "" {
+			line += dimStyle.Render(djSuffix)
+		}
 		if queueSuffix != "" {
 			line += activeToggle.Render(queueSuffix)
 		}
 		lines = append(lines, line)
 	}
 
+	if barW > 0 {
+		bar := scrollbar(len(lines), scroll, len(rows))
+		for i, line := range lines {
+			pad := max(0, panelWidth-barW-lipgloss.Width(line))
+			lines[i] = line + strings.Repeat(" ", pad) + " " + bar[i]
+		}
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -556,6 +845,9 @@ func (m Model) renderJumpOverlay() string {
 		"",
 		inputLine,
 	}
+	if dur > 0 {
+		lines = append(lines, dimStyle.Render("  mm:ss, h:mm:ss or a percentage (50%)"))
+	}
 
 	lines = append(lines, "", helpKey("Enter", "Jump ")+helpKey("Esc", "Cancel"))
 	return m.centerOverlay(strings.Join(lines, "\n"))
@@ -563,6 +855,9 @@ func (m Model) renderJumpOverlay() string {
 
 func (m Model) renderHelp() string {
 	if m.focus == focusProvider {
+		if m.podcastsActive() {
+			return helpKey("↑↓", "Navigate ") + helpKey("Enter", "Episodes ") + helpKey("+", "Subscribe ") + helpKey("d", "Unsubscribe ") + helpKey("Ctrl+K", "Keys")
+		}
 		return helpKey("↑↓", "Navigate ") + helpKey("Enter", "Load ") + helpKey("Tab", "Focus ") + helpKey("Ctrl+K", "Keys")
 	}
 	if m.focus == focusProvPill {
@@ -576,6 +871,7 @@ func (m Model) renderHelp() string {
 		hints = append(hints,
 			helpHint{helpKey("←→", "Band "), 100},
 			helpHint{helpKey("↑↓", "Gain "), 100},
+			helpHint{helpKey("Enter", "Bands "), 85},
 			helpHint{helpKey("e", "Preset "), 90},
 			helpHint{helpKey("Spc", "⏯ "), 80},
 			helpHint{helpKey("Tab", "Focus "), 70},
@@ -3,24 +3,48 @@ package ui
 import (
 	"fmt"
 	"strings"
-	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
 )
 
-// truncate shortens s to maxW runes, appending "…" if truncated.
-// Uses RuneCountInString first to avoid rune slice allocation in the common
-// case where the string is already short enough.
+// textWidth returns the number of terminal columns s takes up. CJK
+// characters and most emoji take two.
+func textWidth(s string) int {
+	return uniseg.StringWidth(s)
+}
+
+// graphemes splits s into user-perceived characters, so an emoji sequence
+// or a letter with combining marks is never cut apart.
+func graphemes(s string) []string {
+	var out []string
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		out = append(out, g.Str())
+	}
+	return out
+}
+
+// truncate shortens s to maxW columns, appending "…" if truncated. A
+// string takes no more columns than it has bytes, so short strings return
+// without measuring.
 func truncate(s string, maxW int) string {
 	if maxW <= 0 {
 		return ""
 	}
-	if utf8.RuneCountInString(s) <= maxW {
+	if len(s) <= maxW || textWidth(s) <= maxW {
 		return s
 	}
-	if maxW == 1 {
-		return "…"
+	var b strings.Builder
+	w := 0
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		if w+g.Width() > maxW-1 {
+			break
+		}
+		b.WriteString(g.Str())
+		w += g.Width()
 	}
-	r := []rune(s)
-	return string(r[:maxW-1]) + "…"
+	return b.String() + "…"
 }
 
 // cursorLine renders a list item with "> " prefix when active, "  " otherwise.
@@ -31,6 +55,29 @@ func cursorLine(label string, active bool) string {
 	return dimStyle.Render("  " + label)
 }
 
+// scrollbar returns one scrollbar cell for each of rows visible lines of a
+// list of total items whose first visible item is first. The thumb covers
+// the visible share of the list.
+func scrollbar(rows, first, total int) []string {
+	cells := make([]string, rows)
+	if rows == 0 || total <= 0 {
+		return cells
+	}
+	size := max(1, rows*rows/total)
+	top := min(first*rows/total, rows-size)
+	if first+rows >= total {
+		top = rows - size // the end is in view
+	}
+	for i := range cells {
+		if i >= top && i < top+size {
+			cells[i] = trackStyle.Render("┃")
+		} else {
+			cells[i] = dimStyle.Render("│")
+		}
+	}
+	return cells
+}
+
 // scrollStart returns the scroll offset so that cursor remains visible
 // within a window of maxVisible items.
 func scrollStart(cursor, maxVisible int) int {
@@ -60,7 +107,7 @@ func albumSeparator(album string, year int) string {
 		label += fmt.Sprintf(" (%d)", year)
 	}
 	label += " "
-	if labelLen := utf8.RuneCountInString(label); labelLen < panelWidth {
+	if labelLen := textWidth(label); labelLen < panelWidth {
 		label += strings.Repeat("─", panelWidth-labelLen)
 	}
 	return dimStyle.Render(label)
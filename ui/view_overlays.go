@@ -184,6 +184,9 @@ func (m Model) renderPlMgrNewName() []string {
 }
 
 func (m Model) renderQueueOverlay() string {
+	if m.queue.history {
+		return m.renderRecentOverlay()
+	}
 	lines := []string{
 		titleStyle.Render("Q U E U E"),
 		"",
@@ -208,13 +211,13 @@ func (m Model) renderQueueOverlay() string {
 
 	lines = padLines(lines, maxVisible, rendered)
 	lines = append(lines, "", dimStyle.Render(fmt.Sprintf("  %d queued", len(tracks))))
-	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Shift+↑↓", "Reorder ")+helpKey("d", "Remove ")+helpKey("c", "Clear ")+helpKey("Esc", "Close"))
+	lines = append(lines, "", helpKey("↑↓", "Navigate ")+helpKey("Shift+↑↓", "Reorder ")+helpKey("d", "Remove ")+helpKey("c", "Clear ")+helpKey("Tab", "History ")+helpKey("Esc", "Close"))
 
 	return m.centerOverlay(strings.Join(lines, "\n"))
 }
 
 func (m Model) renderInfoOverlay() string {
-	track, _ := m.playlist.Current()
+	track := m.info.track
 
 	lines := []string{
 		titleStyle.Render("T R A C K  I N F O"),
@@ -238,8 +241,72 @@ func (m Model) renderInfoOverlay() string {
 		field("Track", fmt.Sprintf("%d", track.TrackNumber))
 	}
 	field("Path", track.Path)
+	if m.info.loading {
+		lines = append(lines, "", dimStyle.Render("  Reading tags…"))
+	}
+	if len(m.info.fields) > 0 {
+		lines = append(lines, "")
+		for _, f := range m.info.fields {
+			field(f.Name, truncate(f.Value, panelWidth-len(f.Name)-6))
+		}
+	}
+	if rows := techFields(m.info.tech); len(rows) > 0 {
+		lines = append(lines, "")
+		for _, r := range rows {
+			field(r[0], r[1])
+		}
+	}
+	if !m.info.loading && track.Path != "" && m.history != nil {
+		plays := "never"
+		if m.info.plays > 0 {
+			plays = fmt.Sprintf("%d, last %s", m.info.plays, m.info.last.Format("Jan 2, 2006"))
+		}
+		lines = append(lines, "")
+		field("Plays", plays)
+	}
+
+	if m.info.identifying {
+		lines = append(lines, "", dimStyle.Render("  Identifying with AcoustID…"))
+	}
+	if len(m.info.matches) > 0 {
+		lines = append(lines, "", dimStyle.Render("  AcoustID proposes:"))
+		const maxVisible = 6
+		scroll := scrollStart(m.info.cursor, maxVisible)
+		for i := scroll; i < len(m.info.matches) && i < scroll+maxVisible; i++ {
+			mt := m.info.matches[i]
+			label := fmt.Sprintf("%3.0f%%  %s – %s", mt.Score*100, mt.Artist, mt.Title)
+			if mt.Album != "" {
+				label += " · " + mt.Album
+				if mt.Year > 0 {
+					label += fmt.Sprintf(" (%d)", mt.Year)
+				}
+			}
+			lines = append(lines, cursorLine(truncate(label, panelWidth-6), i == m.info.cursor))
+		}
+		if m.info.writing {
+			lines = append(lines, "", dimStyle.Render("  Writing tags…"))
+		}
+		lines = append(lines, "", helpKey("↑↓", "Choose ")+helpKey("Enter", "Write tags ")+helpKey("Esc", "Back"))
+		return m.centerOverlay(strings.Join(lines, "\n"))
+	}
+
+	if m.info.moveTo != "" {
+		lines = append(lines, "", dimStyle.Render("  Move to:"), "  "+trackStyle.Render(truncate(m.info.moveTo, panelWidth-4)))
+		if m.info.moving {
+			lines = append(lines, "", dimStyle.Render("  Moving…"))
+		}
+		lines = append(lines, "", helpKey("Enter", "Move ")+helpKey("Esc", "Back"))
+		return m.centerOverlay(strings.Join(lines, "\n"))
+	}
 
-	lines = append(lines, "", helpKey("Esc/i", "Close"))
+	help := helpKey("Esc/i", "Close")
+	if m.canOrganize() {
+		help = helpKey("o", "Organize ") + help
+	}
+	if m.canIdentify() {
+		help = helpKey("a", "Identify ") + help
+	}
+	lines = append(lines, "", help)
 
 	return m.centerOverlay(strings.Join(lines, "\n"))
 }
@@ -317,12 +384,16 @@ func (m Model) renderNetSearchOverlay() string {
 }
 
 func (m Model) renderURLInputOverlay() string {
+	title, action := "L O A D   U R L", "Load"
+	if m.urlSubscribe {
+		title, action = "S U B S C R I B E", "Subscribe"
+	}
 	lines := []string{
-		titleStyle.Render("L O A D   U R L"),
+		titleStyle.Render(title),
 		"",
 		playlistSelectedStyle.Render("  URL: " + m.urlInput + "_"),
 		"",
-		helpKey("Enter", "Load") + " " + helpKey("Esc", "Cancel"),
+		helpKey("Enter", action) + " " + helpKey("Esc", "Cancel"),
 	}
 	return m.centerOverlay(strings.Join(lines, "\n"))
 }
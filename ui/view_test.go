@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cliamp/playlist"
+)
+
+func TestFormatTimeStr(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  time.Duration
+		dur  time.Duration
+		live bool
+		mode timeDisplay
+		want string
+	}{
+		{"local file", 83 * time.Second, 241 * time.Second, false, timeElapsed, "01:23 / 04:01"},
+		{"remaining", 83*time.Second + 600*time.Millisecond, 241 * time.Second, false, timeRemaining, "-02:38 / 04:01"},
+		{"both", 131 * time.Second, 239 * time.Second, false, timeBoth, "02:11 / -01:48"},
+		{"live stream", 83 * time.Second, 0, true, timeRemaining, "01:23 / ∞"},
+		{"long live stream", 2*time.Hour + 5*time.Second, 0, true, timeElapsed, "120:05 / ∞"},
+	}
+	for _, tt := range tests {
+		if got := formatTimeStr(tt.pos, tt.dur, tt.live, tt.mode); got != tt.want {
+			t.Errorf("%s: formatTimeStr() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScrollbar(t *testing.T) {
+	thumb := func(cells []string) (top, size int) {
+		top = -1
+		for i, c := range cells {
+			if strings.Contains(c, "┃") {
+				if top < 0 {
+					top = i
+				}
+				size++
+			}
+		}
+		return top, size
+	}
+	tests := []struct {
+		name               string
+		rows, first, total int
+		wantTop, wantSize  int
+	}{
+		{"start", 10, 0, 100, 0, 1},
+		{"middle", 10, 45, 100, 4, 1},
+		{"end", 10, 90, 100, 9, 1},
+		{"half visible", 10, 0, 20, 0, 5},
+		{"half visible at end", 10, 10, 20, 5, 5},
+	}
+	for _, tt := range tests {
+		top, size := thumb(scrollbar(tt.rows, tt.first, tt.total))
+		if top != tt.wantTop || size != tt.wantSize {
+			t.Errorf("%s: thumb at %d size %d, want %d size %d", tt.name, top, size, tt.wantTop, tt.wantSize)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		maxW int
+		want string
+	}{
+		{"Song", 4, "Song"},
+		{"Songs", 4, "Son…"},
+		{"夜に駆ける", 10, "夜に駆ける"},
+		{"夜に駆ける", 6, "夜に…"},
+		{"夜に駆ける", 5, "夜に…"},
+		{"👩‍👩‍👧 Family", 4, "👩‍👩‍👧 …"},
+		{"Café del Mar", 5, "Café…"},
+	} {
+		got := truncate(tc.in, tc.maxW)
+		if got != tc.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tc.in, tc.maxW, got, tc.want)
+		}
+		if w := textWidth(got); w > tc.maxW {
+			t.Errorf("truncate(%q, %d) is %d columns wide", tc.in, tc.maxW, w)
+		}
+	}
+}
+
+func TestTrackInfoScrollKeepsWidth(t *testing.T) {
+	pl := playlist.New()
+	pl.Add(playlist.Track{Title: strings.Repeat("東京事変 ", 20)})
+	m := Model{playlist: pl}
+	want := lipgloss.Width(m.renderTrackInfo())
+	for off := range 12 {
+		m.titleOff = off
+		if got := lipgloss.Width(m.renderTrackInfo()); got != want {
+			t.Fatalf("offset %d: %d columns, want %d", off, got, want)
+		}
+	}
+}
+
+func BenchmarkView(b *testing.B) {
+	if sharedPlayer == nil {
+		b.Skip("audio hardware unavailable")
+	}
+	pl := playlist.New()
+	tracks := make([]playlist.Track, 200)
+	for i := range tracks {
+		tracks[i] = playlist.Track{Artist: "Artist", Title: strings.Repeat("Title ", i%7+1), Album: "Album"}
+	}
+	pl.Replace(tracks)
+	m := Model{
+		player:    sharedPlayer,
+		playlist:  pl,
+		vis:       NewVisualizer(float64(sharedPlayer.SampleRate())),
+		plVisible: 10,
+		focus:     focusPlaylist,
+		width:     100,
+		height:    40,
+		cache:     &renderCache{},
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = m.View()
+	}
+}
+
+func TestFormatVolume(t *testing.T) {
+	m := Model{}
+	if got := m.formatVolume(-17); got != "-17dB" {
+		t.Errorf("dB readout = %q", got)
+	}
+	m.SetVolumeDisplay("percent")
+	for db, want := range map[float64]string{-30: "0%", -12: "50%", 6: "100%"} {
+		if got := m.formatVolume(db); got != want {
+			t.Errorf("formatVolume(%v) = %q, want %q", db, got, want)
+		}
+	}
+}
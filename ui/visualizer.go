@@ -2,11 +2,9 @@ package ui
 
 import (
 	"math"
-	"math/cmplx"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/madelynnblue/go-dsp/fft"
 )
 
 const (
@@ -28,25 +26,25 @@ const (
 	VisRain                       // falling rain droplets within bar shapes
 	VisBarsOutline                // top-edge outline of bars
 	VisBricks                     // solid bricks with gaps
-	VisColumns                // many thin columns
-	VisWave                   // braille waveform oscilloscope
-	VisScatter                // braille particle sparkle
-	VisFlame                  // braille rising flame tendrils
-	VisRetro                  // 80s synthwave perspective grid with wave
-	VisPulse                  // braille pulsating circle
-	VisMatrix                 // falling matrix rain characters
-	VisBinary                 // streaming binary 0s and 1s
-	VisSakura                 // falling cherry blossom petals
-	VisFirework               // exploding firework bursts
-	VisLogo                   // CLIAMP pixel text
-	VisTerrain                // scrolling side-view mountain range
-	VisGlitch                 // random block corruption driven by energy
-	VisScope                  // Lissajous XY oscilloscope
-	VisHeartbeat              // ECG pulse monitor trace
-	VisButterfly              // mirrored Rorschach spectrum
-	VisLightning              // electric bolts from treble energy
-	VisNone                   // hidden — no visualizer
-	visCount                  // sentinel for cycling
+	VisColumns                    // many thin columns
+	VisWave                       // braille waveform oscilloscope
+	VisScatter                    // braille particle sparkle
+	VisFlame                      // braille rising flame tendrils
+	VisRetro                      // 80s synthwave perspective grid with wave
+	VisPulse                      // braille pulsating circle
+	VisMatrix                     // falling matrix rain characters
+	VisBinary                     // streaming binary 0s and 1s
+	VisSakura                     // falling cherry blossom petals
+	VisFirework                   // exploding firework bursts
+	VisLogo                       // CLIAMP pixel text
+	VisTerrain                    // scrolling side-view mountain range
+	VisGlitch                     // random block corruption driven by energy
+	VisScope                      // Lissajous XY oscilloscope
+	VisHeartbeat                  // ECG pulse monitor trace
+	VisButterfly                  // mirrored Rorschach spectrum
+	VisLightning                  // electric bolts from treble energy
+	VisNone                       // hidden — no visualizer
+	visCount                      // sentinel for cycling
 )
 
 // Unicode block elements for bar height (9 levels including space)
@@ -85,12 +83,14 @@ var (
 
 // Visualizer performs FFT analysis and renders spectrum bars.
 type Visualizer struct {
-	prev      [numBands]float64 // previous frame for temporal smoothing
-	sr        float64
-	buf       []float64 // reusable FFT buffer to avoid per-frame allocation
-	Mode      VisMode
-	Rows      int       // display height in terminal rows (default 5)
-	waveBuf   []float64 // raw samples for wave mode
+	prev       [numBands]float64 // previous frame for temporal smoothing
+	sr         float64
+	buf        []float64    // reusable FFT buffer to avoid per-frame allocation
+	fftWork    []complex128 // realFFT scratch
+	mag        []float64    // spectrum magnitudes of the last frame
+	Mode       VisMode
+	Rows       int       // display height in terminal rows (default 5)
+	waveBuf    []float64 // raw samples for wave mode
 	frame      uint64    // frame counter for scatter animation
 	sampleBuf  []float64 // reusable buffer for reading audio tap samples
 	terrainBuf []float64 // height history for terrain scrolling mode
@@ -101,6 +101,8 @@ func NewVisualizer(sampleRate float64) *Visualizer {
 	return &Visualizer{
 		sr:        sampleRate,
 		buf:       make([]float64, fftSize),
+		fftWork:   make([]complex128, fftHalf),
+		mag:       make([]float64, fftHalf),
 		sampleBuf: make([]float64, fftSize),
 		Rows:      defaultVisRows,
 	}
@@ -125,24 +127,24 @@ var visModes = [visCount]visEntry{
 	VisRain:        {"Rain", (*Visualizer).renderRain},
 	VisBarsOutline: {"BarsOutline", (*Visualizer).renderBarsOutline},
 	VisBricks:      {"Bricks", (*Visualizer).renderBricks},
-	VisColumns: {"Columns", (*Visualizer).renderColumns},
-	VisWave:    {"Wave", func(v *Visualizer, _ [numBands]float64) string { return v.renderWave() }},
-	VisScatter: {"Scatter", (*Visualizer).renderScatter},
-	VisFlame:   {"Flame", (*Visualizer).renderFlame},
-	VisRetro:   {"Retro", (*Visualizer).renderRetro},
-	VisPulse:   {"Pulse", (*Visualizer).renderPulse},
-	VisMatrix:  {"Matrix", (*Visualizer).renderMatrix},
-	VisBinary:  {"Binary", (*Visualizer).renderBinary},
-	VisSakura:   {"Sakura", (*Visualizer).renderSakura},
-	VisFirework: {"Firework", (*Visualizer).renderFirework},
-	VisLogo:     {"Logo", (*Visualizer).renderLogo},
-	VisTerrain:  {"Terrain", (*Visualizer).renderTerrain},
-	VisGlitch:   {"Glitch", (*Visualizer).renderGlitch},
-	VisScope:           {"Scope", func(v *Visualizer, _ [numBands]float64) string { return v.renderScope() }},
-	VisHeartbeat:       {"Heartbeat", func(v *Visualizer, _ [numBands]float64) string { return v.renderHeartbeat() }},
-	VisButterfly:       {"Butterfly", (*Visualizer).renderButterfly},
-	VisLightning:       {"Lightning", (*Visualizer).renderLightning},
-	VisNone:            {"None", nil},
+	VisColumns:     {"Columns", (*Visualizer).renderColumns},
+	VisWave:        {"Wave", func(v *Visualizer, _ [numBands]float64) string { return v.renderWave() }},
+	VisScatter:     {"Scatter", (*Visualizer).renderScatter},
+	VisFlame:       {"Flame", (*Visualizer).renderFlame},
+	VisRetro:       {"Retro", (*Visualizer).renderRetro},
+	VisPulse:       {"Pulse", (*Visualizer).renderPulse},
+	VisMatrix:      {"Matrix", (*Visualizer).renderMatrix},
+	VisBinary:      {"Binary", (*Visualizer).renderBinary},
+	VisSakura:      {"Sakura", (*Visualizer).renderSakura},
+	VisFirework:    {"Firework", (*Visualizer).renderFirework},
+	VisLogo:        {"Logo", (*Visualizer).renderLogo},
+	VisTerrain:     {"Terrain", (*Visualizer).renderTerrain},
+	VisGlitch:      {"Glitch", (*Visualizer).renderGlitch},
+	VisScope:       {"Scope", func(v *Visualizer, _ [numBands]float64) string { return v.renderScope() }},
+	VisHeartbeat:   {"Heartbeat", func(v *Visualizer, _ [numBands]float64) string { return v.renderHeartbeat() }},
+	VisButterfly:   {"Butterfly", (*Visualizer).renderButterfly},
+	VisLightning:   {"Lightning", (*Visualizer).renderLightning},
+	VisNone:        {"None", nil},
 }
 
 var visNameMap map[string]VisMode
@@ -207,8 +209,8 @@ func (v *Visualizer) Analyze(samples []float64) [numBands]float64 {
 	}
 
 	// Compute FFT
-	spectrum := fft.FFTReal(v.buf)
-	halfLen := len(spectrum) / 2
+	realFFT(v.buf, v.fftWork, v.mag)
+	halfLen := fftHalf
 
 	binHz := v.sr / float64(fftSize)
 
@@ -226,7 +228,7 @@ func (v *Visualizer) Analyze(samples []float64) [numBands]float64 {
 		var sum float64
 		count := 0
 		for i := loIdx; i <= hiIdx; i++ {
-			sum += cmplx.Abs(spectrum[i])
+			sum += v.mag[i]
 			count++
 		}
 		if count > 0 {
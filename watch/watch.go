@@ -0,0 +1,179 @@
+// Package watch notices audio files that appear in music directories while
+// cliamp is running and hands them to the TUI to append to the playlist.
+//
+// Directories are polled rather than subscribed to with inotify/FSEvents,
+// which keeps it portable and working on network mounts that never deliver
+// change events. Only directories whose modification time changed are
+// re-read, so a poll of a large library costs one stat per directory.
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cliamp/player"
+	"cliamp/playlist"
+)
+
+// TracksMsg carries newly found tracks, in path order.
+type TracksMsg struct{ Tracks []playlist.Track }
+
+// pollInterval is how often the directories are checked. A file is only
+// reported once its size has held steady for a full interval, so copies
+// in progress are not picked up half-written.
+var pollInterval = 2 * time.Second
+
+// Watcher polls a set of directory trees for new audio files.
+type Watcher struct {
+	send    func(interface{})
+	dirs    map[string]time.Time // watched directory → last seen mtime
+	known   map[string]bool      // audio files already present or reported
+	pending map[string]int64     // new files waiting for their size to settle
+	stop    chan struct{}
+	done    sync.WaitGroup
+}
+
+// Start snapshots the audio files under dirs and begins polling for new
+// ones, delivering each batch as a TracksMsg through send. Files present at
+// start are never reported. A leading "~/" is expanded to the home
+// directory; missing directories are skipped. Returns nil when no
+// directory could be watched.
+func Start(dirs []string, send func(interface{})) *Watcher {
+	w := &Watcher{
+		send:    send,
+		dirs:    make(map[string]time.Time),
+		known:   make(map[string]bool),
+		pending: make(map[string]int64),
+		stop:    make(chan struct{}),
+	}
+	for _, d := range dirs {
+		if info, err := os.Stat(expandHome(d)); err == nil && info.IsDir() {
+			w.addTree(expandHome(d), false)
+		}
+	}
+	if len(w.dirs) == 0 {
+		return nil
+	}
+	w.done.Add(1)
+	go w.loop()
+	return w
+}
+
+// Close stops polling and waits for the poller to exit.
+func (w *Watcher) Close() {
+	if w == nil {
+		return
+	}
+	close(w.stop)
+	w.done.Wait()
+}
+
+func (w *Watcher) loop() {
+	defer w.done.Done()
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-t.C:
+			if tracks := w.poll(); len(tracks) > 0 {
+				w.send(TracksMsg{Tracks: tracks})
+			}
+		}
+	}
+}
+
+// poll re-reads changed directories and returns tracks for new files that
+// have finished growing.
+func (w *Watcher) poll() []playlist.Track {
+	for dir, mtime := range w.dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			delete(w.dirs, dir)
+			continue
+		}
+		if !info.ModTime().Equal(mtime) {
+			w.dirs[dir] = info.ModTime()
+			w.readDir(dir, true)
+		}
+	}
+
+	var ready []string
+	for path, size := range w.pending {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			delete(w.pending, path)
+		case info.Size() > 0 && info.Size() == size:
+			delete(w.pending, path)
+			w.known[path] = true
+			ready = append(ready, path)
+		default:
+			w.pending[path] = info.Size()
+		}
+	}
+	slices.Sort(ready)
+
+	tracks := make([]playlist.Track, len(ready))
+	for i, path := range ready {
+		tracks[i] = playlist.TrackFromPath(path)
+	}
+	return tracks
+}
+
+// addTree starts watching dir and everything below it. With report set,
+// the audio files found are queued as new; otherwise they are recorded as
+// already known.
+func (w *Watcher) addTree(dir string, report bool) {
+	if _, ok := w.dirs[dir]; ok {
+		return
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return
+	}
+	w.dirs[dir] = info.ModTime()
+	w.readDir(dir, report)
+}
+
+// readDir scans one directory level, descending into unseen subdirectories.
+func (w *Watcher) readDir(dir string, report bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		// Dotfiles are usually partial downloads or sync temp files.
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			w.addTree(path, report)
+			continue
+		}
+		if w.known[path] || !player.SupportedExts[strings.ToLower(filepath.Ext(path))] {
+			continue
+		}
+		if !report {
+			w.known[path] = true
+		} else if _, ok := w.pending[path]; !ok {
+			w.pending[path] = -1
+		}
+	}
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,62 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPollReportsNewFilesOnce(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.mp3")
+	if err := os.WriteFile(old, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &Watcher{
+		dirs:    make(map[string]time.Time),
+		known:   make(map[string]bool),
+		pending: make(map[string]int64),
+	}
+	w.addTree(dir, false)
+
+	sub := filepath.Join(dir, "Album")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b.flac", "a.flac", "cover.jpg", ".partial.mp3"} {
+		if err := os.WriteFile(filepath.Join(sub, name), []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Make sure the directory mtime differs from the snapshot even on
+	// filesystems with coarse timestamps.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(dir, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	// First poll discovers the files; they are reported once their size
+	// is seen unchanged on the next one.
+	if got := w.poll(); len(got) != 0 {
+		t.Fatalf("first poll reported %d tracks, want 0", len(got))
+	}
+	got := w.poll()
+	if len(got) != 2 {
+		t.Fatalf("second poll reported %d tracks, want 2", len(got))
+	}
+	if got[0].Path != filepath.Join(sub, "a.flac") || got[1].Path != filepath.Join(sub, "b.flac") {
+		t.Fatalf("tracks = %q, %q; want a.flac, b.flac in order", got[0].Path, got[1].Path)
+	}
+	if got := w.poll(); len(got) != 0 {
+		t.Fatalf("third poll reported %d tracks, want 0", len(got))
+	}
+}
+
+func TestStartSkipsMissingDirs(t *testing.T) {
+	if w := Start([]string{filepath.Join(t.TempDir(), "missing")}, func(interface{}) {}); w != nil {
+		w.Close()
+		t.Fatal("Start returned a watcher for a missing directory")
+	}
+}